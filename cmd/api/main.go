@@ -1,28 +1,53 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/subtle"
+	_ "embed"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/mmcdole/gofeed"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/nats-io/nats.go"
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/zyrak/flux/internal/briefing"
 	"github.com/zyrak/flux/internal/config"
+	"github.com/zyrak/flux/internal/dedup"
 	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/feeds"
+	"github.com/zyrak/flux/internal/llm"
 	"github.com/zyrak/flux/internal/models"
 	"github.com/zyrak/flux/internal/profile"
+	"github.com/zyrak/flux/internal/queue"
+	"github.com/zyrak/flux/internal/ratelimit"
+	"github.com/zyrak/flux/internal/relevance"
+	"github.com/zyrak/flux/internal/render"
 	"github.com/zyrak/flux/internal/store"
 )
 
@@ -70,6 +95,13 @@ type articleResponse struct {
 	Section        *articleSectionResponse `json:"section,omitempty"`
 	Source         articleSourceResponse   `json:"source"`
 	Feedback       articleFeedbackResponse `json:"feedback"`
+	Note           *articleNoteResponse    `json:"note,omitempty"`
+}
+
+type articleNoteResponse struct {
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type sourceStatsResponse struct {
@@ -106,20 +138,127 @@ type briefingResponse struct {
 	Articles    []articleResponse `json:"articles"`
 }
 
+// briefingExportSchemaVersion is bumped whenever briefingExport changes
+// incompatibly.
+const briefingExportSchemaVersion = 1
+
+// briefingExport is the self-contained archival form of a briefing. Unlike
+// briefingResponse it embeds section metadata and carries no per-user
+// feedback state.
+type briefingExport struct {
+	SchemaVersion int                     `json:"schema_version"`
+	ExportedAt    time.Time               `json:"exported_at"`
+	Briefing      briefingExportBriefing  `json:"briefing"`
+	Sections      []briefingExportSection `json:"sections"`
+	Articles      []briefingExportArticle `json:"articles"`
+}
+
+type briefingExportBriefing struct {
+	ID          string          `json:"id"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Content     string          `json:"content"`
+	ArticleIDs  []string        `json:"article_ids"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+}
+
+type briefingExportSection struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+type briefingExportArticle struct {
+	ID             string                `json:"id"`
+	SourceType     string                `json:"source_type"`
+	SourceID       string                `json:"source_id"`
+	SectionID      *string               `json:"section_id,omitempty"`
+	URL            string                `json:"url"`
+	Title          string                `json:"title"`
+	Content        *string               `json:"content,omitempty"`
+	Summary        *string               `json:"summary,omitempty"`
+	Author         *string               `json:"author,omitempty"`
+	PublishedAt    *time.Time            `json:"published_at,omitempty"`
+	IngestedAt     time.Time             `json:"ingested_at"`
+	ProcessedAt    *time.Time            `json:"processed_at,omitempty"`
+	RelevanceScore *float64              `json:"relevance_score,omitempty"`
+	Categories     []string              `json:"categories,omitempty"`
+	Status         string                `json:"status"`
+	Metadata       json.RawMessage       `json:"metadata,omitempty"`
+	Source         articleSourceResponse `json:"source"`
+}
+
 type rssSourceConfig struct {
 	URL string `json:"url"`
 }
 
+type redditSourceConfig struct {
+	Subreddit string `json:"subreddit"`
+}
+
+type githubSourceConfig struct {
+	Repo  string `json:"repo"`
+	Owner string `json:"owner,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Mode  string `json:"mode,omitempty"`
+}
+
+// Backoff between attempts to reach a dependency at startup.
+var (
+	dependencyRetryInitial = 2 * time.Second
+	dependencyRetryMax     = 20 * time.Second
+)
+
+// waitForDependency calls connect until it succeeds, backing off
+// exponentially between attempts, so the API rides out Postgres, NATS or
+// Redis coming up a few seconds after it. It gives up once another attempt
+// would start more than wait after the first, returning the last error.
+func waitForDependency(ctx context.Context, name string, wait time.Duration, connect func(context.Context) error) error {
+	logger := log.WithField("dependency", name)
+	start := time.Now()
+	backoff := dependencyRetryInitial
+	for attempt := 1; ; attempt++ {
+		err := connect(ctx)
+		if err == nil {
+			if attempt > 1 {
+				logger.WithFields(log.Fields{"attempts": attempt, "waited": time.Since(start).Round(time.Millisecond)}).Info("Dependency ready")
+			}
+			return nil
+		}
+		if time.Since(start)+backoff > wait {
+			logger.WithError(err).WithField("attempts", attempt).Error("Dependency not ready, giving up")
+			return fmt.Errorf("%s not ready after %d attempts: %w", name, attempt, err)
+		}
+
+		logger.WithError(err).WithFields(log.Fields{"attempt": attempt, "retry_in": backoff}).Warn("Dependency not ready, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, dependencyRetryMax)
+	}
+}
+
 func main() {
 	cfg := config.Load()
 	setupLogging(cfg.LogLevel)
+	if err := cfg.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid configuration")
+	}
+	dedup.SetTrackingParams(dedup.NewTrackingParams(cfg.DedupTrackingParams, cfg.DedupTrackingDefaults))
 
 	log.Info("Starting Flux API server")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	var db *store.Store
+	err := waitForDependency(ctx, "postgres", cfg.APIDependencyWait, func(ctx context.Context) error {
+		var err error
+		db, err = store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
+		return err
+	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to database")
 	}
@@ -129,11 +268,16 @@ func main() {
 	if migrationsDir == "" {
 		migrationsDir = "migrations"
 	}
-	if err := db.RunMigrations(ctx, migrationsDir); err != nil {
+	if err := db.RunMigrations(ctx, migrationsDir, cfg.MigrationsStrictChecksums); err != nil {
 		log.WithError(err).Fatal("Failed to run migrations")
 	}
 
-	nc, err := nats.Connect(cfg.NatsURL, nats.Timeout(5*time.Second))
+	var nc *nats.Conn
+	err = waitForDependency(ctx, "nats", cfg.APIDependencyWait, func(context.Context) error {
+		var err error
+		nc, err = nats.Connect(cfg.NatsURL, nats.Timeout(5*time.Second))
+		return err
+	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to NATS")
 	}
@@ -143,6 +287,11 @@ func main() {
 		}
 	}()
 
+	q, err := queue.NewWithConn(nc)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to set up NATS JetStream")
+	}
+
 	redisOpts, err := redis.ParseURL(cfg.RedisURL)
 	if err != nil {
 		log.WithError(err).Fatal("Failed to parse REDIS_URL")
@@ -150,46 +299,88 @@ func main() {
 	rdb := redis.NewClient(redisOpts)
 	defer func() { _ = rdb.Close() }()
 
-	if err := rdb.Ping(ctx).Err(); err != nil {
+	err = waitForDependency(ctx, "redis", cfg.APIDependencyWait, func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	})
+	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to Redis")
 	}
 
-	embedClient := embeddings.NewClient(cfg.EmbeddingsURL)
-	profileRecalc := profile.NewRecalculator(db, embedClient, 0.7)
+	apiLimiter, err := ratelimit.NewRequestLimiter(rdb, cfg.APIRateLimit)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid API_RATE_LIMIT")
+	}
+	apiCache := newResponseCache(rdb, cfg.APICacheTTL)
+	compress, err := compressMiddleware(cfg.APICompressionLevel)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid API_COMPRESSION_LEVEL")
+	}
+	realIP, err := realIPMiddleware(cfg.APITrustedProxies)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid API_TRUSTED_PROXIES")
+	}
+	cors, err := corsMiddleware(cfg.CORSAllowedOrigins, cfg.CORSAllowedMethods, cfg.CORSAllowedHeaders, cfg.CORSAllowCredentials)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid CORS configuration")
+	}
+
+	embedOpts := embeddings.Options{
+		BatchSize:         cfg.EmbeddingsBatchSize,
+		Provider:          cfg.EmbeddingsProvider,
+		APIKey:            cfg.EmbeddingsAPIKey,
+		Model:             cfg.EmbeddingsModel,
+		Dimensions:        cfg.EmbeddingsRequestDimension,
+		ExpectedDimension: cfg.EmbeddingsStoreDimension,
+	}
+	if err := embedOpts.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid embeddings configuration")
+	}
+	embedClient := embeddings.NewClientWithOptions(cfg.EmbeddingsURL, embedOpts)
+	profileRecalc := profile.NewRecalculator(db, embedClient, 0.7, cfg.ProfileHalfLife)
+	sourceValidator, err := newSourceValidator(cfg.UserAgent, cfg.OutboundProxy)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid FLUX_OUTBOUND_PROXY")
+	}
+	relevanceCfg := relevance.Config{
+		DefaultThreshold:  cfg.RelevanceThresholdDefault,
+		MinThreshold:      cfg.RelevanceThresholdMin,
+		MaxThreshold:      cfg.RelevanceThresholdMax,
+		ThresholdStep:     cfg.RelevanceThresholdStep,
+		SourceBoosts:      cfg.SourceBoosts,
+		FreshnessWeight:   cfg.RelevanceFreshnessWeight,
+		FreshnessHalfLife: cfg.RelevanceFreshnessHalfLife,
+		EmbeddingDim:      cfg.EmbeddingsStoreDimension,
+	}
+	engines := newCachedEngine(func(ctx context.Context) (articleExplainer, error) {
+		return relevance.NewEngine(ctx, db, embedClient, relevanceCfg)
+	}, relevanceEngineTTL)
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(realIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(compress)
 	r.Use(middleware.Timeout(30 * time.Second))
 
-	r.Get("/healthz", healthzHandler(db, nc, rdb))
-
-	r.Route("/api", func(r chi.Router) {
-		r.Use(bearerAuthMiddleware(cfg.AuthToken))
-
-		r.Get("/articles", listArticlesHandler(db))
-		r.Get("/articles/{id}", getArticleHandler(db))
-
-		r.Get("/sources", listSourcesHandler(db))
-		r.Post("/sources", createSourceHandler(db))
-		r.Patch("/sources/{id}", updateSourceHandler(db))
-		r.Post("/sources/validate-rss", validateRSSHandler())
-
-		r.Get("/sections", listSectionsHandler(db))
-		r.Post("/sections", createSectionHandler(db))
-		r.Patch("/sections/{id}", updateSectionHandler(db))
-		r.Post("/sections/reorder", reorderSectionsHandler(db))
-
-		r.Get("/briefings/latest", latestBriefingHandler(db))
-		r.Get("/briefings", listBriefingsHandler(db))
-		r.Get("/briefings/{id}", getBriefingHandler(db))
-
-		r.Post("/feedback", createFeedbackHandler(db, profileRecalc, cfg))
-		r.Get("/feedback/stats", feedbackStatsHandler(db))
-		r.Delete("/feedback/{id}", deleteFeedbackHandler(db, profileRecalc, cfg))
-	})
+	var shuttingDown atomic.Bool
+	readiness := healthzHandler(db, nc, rdb, q, buildHealthProbes(cfg, embedClient))
+	r.Get("/livez", livezHandler(&shuttingDown))
+	r.Get("/healthz", readiness)
+	r.Get("/readyz", readiness)
+
+	r.Route("/api", apiRoutes(apiDeps{
+		cfg:             cfg,
+		db:              db,
+		queue:           q,
+		rdb:             rdb,
+		engines:         engines,
+		profileRecalc:   profileRecalc,
+		sourceValidator: sourceValidator,
+		limiter:         apiLimiter,
+		cache:           apiCache,
+		cors:            cors,
+	}))
 
 	addr := fmt.Sprintf(":%d", cfg.APIPort)
 	srv := &http.Server{
@@ -209,6 +400,7 @@ func main() {
 	<-quit
 
 	log.Info("Shutting down API server...")
+	shuttingDown.Store(true)
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 	if err := srv.Shutdown(shutdownCtx); err != nil {
@@ -216,6 +408,112 @@ func main() {
 	}
 }
 
+// apiDeps are what the /api handlers are built from.
+type apiDeps struct {
+	cfg             *config.Config
+	db              *store.Store
+	queue           *queue.Queue
+	rdb             redis.Cmdable
+	engines         *cachedEngine
+	profileRecalc   *profile.Recalculator
+	sourceValidator *sourceValidator
+	limiter         *ratelimit.RequestLimiter
+	cache           *responseCache
+	cors            func(http.Handler) http.Handler
+}
+
+// apiRoutes registers every /api route. openapi.json must describe each one;
+// TestOpenAPISpecCoversRoutes fails otherwise.
+func apiRoutes(d apiDeps) func(chi.Router) {
+	// Section, source, article status and feedback changes show up in both
+	// cached aggregates.
+	invalidateAggregates := d.cache.invalidates(cacheKeySections, cacheKeyStats)
+
+	return func(r chi.Router) {
+		// CORS goes first so preflights skip auth and rate limiting.
+		if d.cors != nil {
+			r.Use(d.cors)
+		}
+		// The per-IP budget runs before auth so unauthenticated floods are
+		// throttled too; each token then has a budget of its own.
+		r.Use(apiRateLimitMiddleware(d.limiter, rateLimitKeyIP))
+		r.Use(bearerAuthMiddleware(d.cfg.AuthTokens))
+		r.Use(apiRateLimitMiddleware(d.limiter, rateLimitKeyToken))
+
+		// Side-effect-free tools are the only non-GET routes read tokens
+		// may call.
+		r.Post("/tools/normalize-url", normalizeURLHandler())
+		r.Post("/tools/validate-cron", validateCronHandler())
+
+		// Everything else needs an admin token for anything but a read, so
+		// new mutating routes are protected without opting in.
+		r.Group(func(r chi.Router) {
+			r.Use(requireAdminScope)
+
+			r.Get("/openapi.json", openAPIHandler())
+
+			r.Get("/articles", listArticlesHandler(d.db))
+			r.Get("/articles/saved", savedArticlesHandler(d.db))
+			r.Get("/articles/clustered", listClusteredArticlesHandler(d.db))
+			r.With(invalidateAggregates).Post("/articles/bulk-status", bulkArticleStatusHandler(d.db))
+			r.Get("/articles/{id}", getArticleHandler(d.db))
+			r.Get("/articles/{id}/relevance", explainArticleHandler(d.db, d.engines))
+			r.Patch("/articles/{id}/note", updateArticleNoteHandler(d.db))
+
+			r.Get("/categories", listCategoriesHandler(d.db))
+
+			r.Get("/sources", listSourcesHandler(d.db))
+			r.With(invalidateAggregates).Post("/sources", createSourceHandler(d.db, d.sourceValidator))
+			r.With(invalidateAggregates).Patch("/sources/{id}", updateSourceHandler(d.db, d.sourceValidator))
+			r.With(invalidateAggregates).Post("/sources/bulk-toggle", bulkToggleSourcesHandler(d.db))
+			r.Get("/sources/{id}/history", sourceHistoryHandler(d.db))
+			r.Post("/sources/validate", validateSourceHandler(d.sourceValidator))
+			r.Post("/sources/validate-rss", validateRSSHandler(d.sourceValidator))
+
+			r.Get("/sections", listSectionsHandler(d.db, d.cfg, d.cache))
+			r.With(invalidateAggregates).Post("/sections", createSectionHandler(d.db))
+			r.With(invalidateAggregates).Patch("/sections/{id}", updateSectionHandler(d.db))
+			r.With(invalidateAggregates).Patch("/sections/{id}/threshold", updateSectionThresholdHandler(d.db, d.cfg))
+			r.With(invalidateAggregates).Post("/sections/reorder", reorderSectionsHandler(d.db))
+			r.With(invalidateAggregates).Post("/sections/{id}/mark-processed", markSectionProcessedHandler(d.db))
+			r.With(invalidateAggregates).Post("/sections/{id}/reset-profile", resetSectionProfileHandler(d.db))
+			r.Get("/sections/{id}/score-histogram", sectionScoreHistogramHandler(d.db, d.cfg))
+
+			r.Get("/briefings/latest", latestBriefingHandler(d.db))
+			r.Post("/briefings/generate", generateBriefingHandler(d.queue, func(ctx context.Context) (bool, error) {
+				return briefing.DaemonAlive(ctx, d.rdb)
+			}))
+			r.Get("/briefings", listBriefingsHandler(d.db))
+			r.Get("/briefings/{id}", getBriefingHandler(d.db, briefingPageLang(d.cfg.BriefingLanguage)))
+			r.Get("/briefings/{id}/export", exportBriefingHandler(d.db))
+
+			r.With(invalidateAggregates).Post("/feedback", createFeedbackHandler(d.db, d.profileRecalc, d.cfg))
+			r.Get("/feedback", listFeedbackHandler(d.db, false))
+			r.Get("/feedback/export", listFeedbackHandler(d.db, true))
+			r.Get("/feedback/export.csv", listFeedbackHandler(d.db, true))
+			r.Get("/feedback/stats", feedbackStatsHandler(d.db))
+			r.With(invalidateAggregates).Delete("/feedback/{id}", deleteFeedbackHandler(d.db, d.profileRecalc, d.cfg))
+
+			r.Get("/stats", dashboardStatsHandler(d.db, d.queue, d.cache))
+			r.Get("/stats/llm-usage", llmUsageHandler(d.db))
+
+			r.Get("/admin/dead-letters", listDeadLettersHandler(d.queue))
+		})
+	}
+}
+
+// openAPISpec is the hand-written OpenAPI 3 description of /api.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func openAPIHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPISpec)
+	}
+}
+
 func setupLogging(level string) {
 	log.SetFormatter(&log.JSONFormatter{})
 	lvl, err := log.ParseLevel(level)
@@ -225,9 +523,130 @@ func setupLogging(level string) {
 	log.SetLevel(lvl)
 }
 
-func bearerAuthMiddleware(authToken string) func(http.Handler) http.Handler {
-	authToken = strings.TrimSpace(authToken)
-	if authToken == "" {
+type authContextKey struct{}
+
+type authInfo struct {
+	Label string
+	Scope string
+}
+
+// authLabel returns the label of the token that authenticated the request, or
+// "" when auth is disabled.
+func authLabel(ctx context.Context) string {
+	info, _ := ctx.Value(authContextKey{}).(authInfo)
+	return info.Label
+}
+
+// authScope returns the scope of the authenticating token, or "" when auth is
+// disabled.
+func authScope(ctx context.Context) string {
+	info, _ := ctx.Value(authContextKey{}).(authInfo)
+	return info.Scope
+}
+
+// compressibleTypes are the response types worth compressing: JSON and the
+// briefing markdown, HTML and CSV exports.
+var compressibleTypes = []string{"application/json", "text/markdown", "text/html", "text/csv", "text/plain"}
+
+// compressMiddleware gzip- or deflate-encodes responses for clients that
+// accept it. Responses that already set Content-Encoding are passed through
+// untouched. Level 0 disables compression.
+func compressMiddleware(level int) (func(http.Handler) http.Handler, error) {
+	if level == 0 {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+	if level < 1 || level > 9 {
+		return nil, fmt.Errorf("compression level %d is outside 1-9 (0 disables)", level)
+	}
+	return middleware.Compress(level, compressibleTypes...), nil
+}
+
+// CORS response settings shared by every allowed origin.
+const (
+	// corsExposedHeaders lets browser clients read the export filename and
+	// the rate limit backoff.
+	corsExposedHeaders = "Content-Disposition, Retry-After"
+	// corsMaxAge is how long, in seconds, browsers may cache a preflight.
+	corsMaxAge = "600"
+)
+
+// corsMiddleware lets pages on the allowed origins call the API from a
+// browser. It answers preflight OPTIONS requests itself, since browsers send
+// them without the Authorization header, so it must run before auth. No
+// origins disables it and leaves the API same-origin only.
+func corsMiddleware(origins, methods, headers []string, credentials bool) (func(http.Handler) http.Handler, error) {
+	if len(origins) == 0 {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+
+	anyOrigin := false
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		if origin == "*" {
+			anyOrigin = true
+			continue
+		}
+		allowed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	if anyOrigin && credentials {
+		return nil, errors.New(`CORS_ALLOWED_ORIGINS "*" cannot be combined with CORS_ALLOW_CREDENTIALS; list the origins instead`)
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			originAllowed := anyOrigin || allowed[strings.ToLower(origin)]
+			allowOrigin := func() {
+				if anyOrigin {
+					h.Set("Access-Control-Allow-Origin", "*")
+				} else {
+					h.Set("Access-Control-Allow-Origin", origin)
+				}
+				if credentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+
+			requestMethod := r.Header.Get("Access-Control-Request-Method")
+			if r.Method == http.MethodOptions && requestMethod != "" {
+				// A refused preflight still ends here, just without the
+				// headers, so the browser blocks the actual request.
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				if originAllowed && slices.Contains(methods, strings.ToUpper(requestMethod)) {
+					allowOrigin()
+					h.Set("Access-Control-Allow-Methods", allowMethods)
+					if allowHeaders != "" {
+						h.Set("Access-Control-Allow-Headers", allowHeaders)
+					}
+					h.Set("Access-Control-Max-Age", corsMaxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if originAllowed {
+				allowOrigin()
+				h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// bearerAuthMiddleware accepts any of the labelled tokens and stores the
+// matching label and scope in the request context. An empty map disables auth.
+func bearerAuthMiddleware(tokens map[string]config.APIToken) func(http.Handler) http.Handler {
+	if len(tokens) == 0 {
 		return func(next http.Handler) http.Handler { return next }
 	}
 
@@ -235,13 +654,152 @@ func bearerAuthMiddleware(authToken string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
 			if !strings.HasPrefix(authHeader, "Bearer ") {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				respondError(w, http.StatusUnauthorized, errCodeUnauthorized, "unauthorized")
+				return
+			}
+
+			provided := []byte(strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer ")))
+			var matched authInfo
+			// Compare against every token so timing does not reveal which label matched.
+			for label, token := range tokens {
+				if subtle.ConstantTimeCompare(provided, []byte(token.Token)) == 1 {
+					matched = authInfo{Label: label, Scope: token.Scope}
+				}
+			}
+			if matched.Label == "" {
+				respondError(w, http.StatusUnauthorized, errCodeUnauthorized, "unauthorized")
+				return
+			}
+
+			log.WithFields(log.Fields{
+				"auth_label": matched.Label,
+				"auth_scope": matched.Scope,
+				"method":     r.Method,
+				"path":       r.URL.Path,
+			}).Debug("Authenticated API request")
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authContextKey{}, matched)))
+		})
+	}
+}
+
+// requireAdminScope rejects requests other than GET, HEAD and OPTIONS made
+// with a read-only token. Requests without auth (auth disabled) pass through.
+func requireAdminScope(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authScope(r.Context()) == config.ScopeRead && !isReadMethod(r.Method) {
+			respondError(w, http.StatusForbidden, errCodeForbidden, "forbidden: read-only token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isReadMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// Redis keys of the aggregate responses responseCache holds.
+const (
+	cacheKeySections = "flux:apicache:sections"
+	cacheKeyStats    = "flux:apicache:stats"
+)
+
+// responseCache keeps short-lived copies of slow aggregate query results in
+// Redis. A nil cache caches nothing, and Redis errors fall through to the
+// database, so the cache can only make a response staler by up to ttl.
+type responseCache struct {
+	rdb redis.Cmdable
+	ttl time.Duration
+}
+
+// newResponseCache returns nil, disabling caching, when ttl is not positive.
+func newResponseCache(rdb redis.Cmdable, ttl time.Duration) *responseCache {
+	if rdb == nil || ttl <= 0 {
+		return nil
+	}
+	return &responseCache{rdb: rdb, ttl: ttl}
+}
+
+// cachedLoad returns the value cached under key, or calls fetch and caches
+// its result.
+func cachedLoad[T any](ctx context.Context, c *responseCache, key string, fetch func(context.Context) (T, error)) (T, error) {
+	if c == nil {
+		return fetch(ctx)
+	}
+
+	raw, err := c.rdb.Get(ctx, key).Bytes()
+	switch {
+	case err == nil:
+		var cached T
+		if err := json.Unmarshal(raw, &cached); err == nil {
+			return cached, nil
+		}
+		log.WithField("key", key).WithError(err).Warn("Discarding unreadable cached response")
+	case !errors.Is(err, redis.Nil):
+		log.WithField("key", key).WithError(err).Warn("API cache read failed, querying database")
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+	if raw, err := json.Marshal(value); err != nil {
+		log.WithField("key", key).WithError(err).Warn("Failed to encode response for cache")
+	} else if err := c.rdb.Set(ctx, key, raw, c.ttl).Err(); err != nil {
+		log.WithField("key", key).WithError(err).Warn("API cache write failed")
+	}
+	return value, nil
+}
+
+// invalidates returns middleware that drops keys after a successful request,
+// so a mutation shows up on the next read rather than after the TTL.
+func (c *responseCache) invalidates(keys ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if c == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+			if ww.Status() >= http.StatusBadRequest {
+				return
+			}
+			if err := c.rdb.Del(context.WithoutCancel(r.Context()), keys...).Err(); err != nil {
+				log.WithField("keys", keys).WithError(err).Warn("API cache invalidation failed")
+			}
+		})
+	}
+}
+
+// apiRateLimitMiddleware throttles requests by the client key returned by
+// key; an empty key is not limited. Redis errors fail open.
+func apiRateLimitMiddleware(limiter *ratelimit.RequestLimiter, key func(*http.Request) string) func(http.Handler) http.Handler {
+	if limiter == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := key(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			provided := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
-			if subtle.ConstantTimeCompare([]byte(provided), []byte(authToken)) != 1 {
-				http.Error(w, "unauthorized", http.StatusUnauthorized)
+			allowed, retryAfter, err := limiter.Take(r.Context(), key)
+			if err != nil {
+				log.WithField("client", key).WithError(err).Warn("API rate limit check failed, allowing request")
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed {
+				seconds := int(math.Ceil(retryAfter.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				respondError(w, http.StatusTooManyRequests, errCodeRateLimited, "too many requests")
 				return
 			}
 
@@ -250,7 +808,209 @@ func bearerAuthMiddleware(authToken string) func(http.Handler) http.Handler {
 	}
 }
 
-func healthzHandler(db *store.Store, nc *nats.Conn, rdb *redis.Client) http.HandlerFunc {
+// rateLimitKeyIP keys the rate limit by client IP.
+func rateLimitKeyIP(r *http.Request) string {
+	return "ip:" + clientIP(r)
+}
+
+// rateLimitKeyToken keys the rate limit by token label, skipping
+// unauthenticated requests.
+func rateLimitKeyToken(r *http.Request) string {
+	if label := authLabel(r.Context()); label != "" {
+		return "token:" + label
+	}
+	return ""
+}
+
+// clientIP returns the request's remote IP; realIPMiddleware has already
+// applied X-Forwarded-For/X-Real-IP when the peer is a trusted proxy.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// realIPMiddleware replaces RemoteAddr with the client IP named by
+// X-Forwarded-For (the nearest address that is not itself a trusted proxy)
+// or X-Real-IP, but only for requests from a trusted proxy. Anyone else
+// could rotate those headers to dodge the per-IP rate limit.
+func realIPMiddleware(trusted []string) (func(http.Handler) http.Handler, error) {
+	if len(trusted) == 0 {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+	nets := make([]*net.IPNet, 0, len(trusted))
+	for _, entry := range trusted {
+		cidr := entry
+		if !strings.Contains(cidr, "/") {
+			if ip := net.ParseIP(cidr); ip != nil && ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", entry)
+		}
+		nets = append(nets, ipNet)
+	}
+	isTrusted := func(raw string) bool {
+		ip := net.ParseIP(strings.TrimSpace(raw))
+		if ip == nil {
+			return false
+		}
+		for _, n := range nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isTrusted(clientIP(r)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			client := strings.TrimSpace(r.Header.Get("X-Real-IP"))
+			if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+				hops := strings.Split(forwarded, ",")
+				client = ""
+				for i := len(hops) - 1; i >= 0; i-- {
+					hop := strings.TrimSpace(hops[i])
+					if net.ParseIP(hop) == nil {
+						break
+					}
+					client = hop
+					if !isTrusted(hop) {
+						break
+					}
+				}
+			}
+			if net.ParseIP(client) != nil {
+				r.RemoteAddr = client
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+const (
+	healthProbeSoft     = "soft"
+	healthProbeHard     = "hard"
+	healthProbeTimeout  = 2 * time.Second
+	healthProbeCacheTTL = 30 * time.Second
+)
+
+// healthProbe checks a dependency outside the API's own stack. Results are
+// cached so frequent liveness probes don't hammer the embeddings service or
+// the LLM provider.
+type healthProbe struct {
+	name  string
+	hard  bool
+	check func(context.Context) error
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	lastErr   error
+}
+
+// newHealthProbe returns nil when mode disables the probe.
+func newHealthProbe(name, mode string, check func(context.Context) error) *healthProbe {
+	switch mode {
+	case healthProbeSoft, healthProbeHard:
+		return &healthProbe{name: name, hard: mode == healthProbeHard, check: check}
+	default:
+		return nil
+	}
+}
+
+func (p *healthProbe) result(ctx context.Context, now time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.checkedAt.IsZero() && now.Sub(p.checkedAt) < healthProbeCacheTTL {
+		return p.lastErr
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+	p.lastErr = p.check(probeCtx)
+	p.checkedAt = now
+	return p.lastErr
+}
+
+func buildHealthProbes(cfg *config.Config, embedClient *embeddings.Client) []*healthProbe {
+	var probes []*healthProbe
+	if p := newHealthProbe("embeddings", cfg.HealthProbeEmbeddings, embedClient.Ping); p != nil {
+		probes = append(probes, p)
+	}
+
+	if cfg.HealthProbeLLM == healthProbeSoft || cfg.HealthProbeLLM == healthProbeHard {
+		analyzer, err := llm.NewAnalyzerWithOptions(cfg.LLMProvider, cfg.LLMEndpoint, cfg.LLMModel, cfg.LLMAPIKey, cfg.BriefingLanguage,
+			llm.Options{Timeout: cfg.LLMTimeout, MaxRetries: cfg.LLMMaxRetries})
+		if err != nil {
+			log.WithError(err).Warn("LLM health probe disabled: invalid LLM configuration")
+		} else if pinger, ok := analyzer.(llm.Pinger); !ok {
+			log.WithField("provider", analyzer.Provider()).Warn("LLM health probe disabled: provider has no ping")
+		} else {
+			probes = append(probes, newHealthProbe("llm", cfg.HealthProbeLLM, pinger.Ping))
+		}
+	}
+	return probes
+}
+
+// runHealthProbes records each probe in services and reports whether every
+// hard probe passed. Soft failures are reported without affecting health.
+func runHealthProbes(ctx context.Context, probes []*healthProbe, services map[string]string) bool {
+	healthy := true
+	now := time.Now()
+	for _, p := range probes {
+		if err := p.result(ctx, now); err != nil {
+			services[p.name] = "error: " + err.Error()
+			if p.hard {
+				healthy = false
+			}
+			continue
+		}
+		services[p.name] = "ok"
+	}
+	return healthy
+}
+
+// livezHandler reports whether the process is up, ignoring dependencies, so
+// liveness probes don't restart the API during a transient outage.
+func livezHandler(shuttingDown *atomic.Bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if shuttingDown.Load() {
+			respondJSONWithStatus(w, http.StatusServiceUnavailable, map[string]string{"status": "shutting_down"})
+			return
+		}
+		respondJSON(w, map[string]string{"status": "ok"})
+	}
+}
+
+// queueDepth reports the processor backlog; *queue.Queue implements it.
+type queueDepth interface {
+	ArticlesPending() (uint64, error)
+}
+
+// articlesQueuePending reads the processor backlog, returning nil when it is
+// unavailable so a JetStream hiccup never fails a health or stats response.
+func articlesQueuePending(q queueDepth) *uint64 {
+	if q == nil {
+		return nil
+	}
+	pending, err := q.ArticlesPending()
+	if err != nil {
+		log.WithError(err).Warn("Failed to read articles queue depth")
+		return nil
+	}
+	return &pending
+}
+
+func healthzHandler(db *store.Store, nc *nats.Conn, rdb *redis.Client, q queueDepth, probes []*healthProbe) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 		defer cancel()
@@ -282,6 +1042,10 @@ func healthzHandler(db *store.Store, nc *nats.Conn, rdb *redis.Client) http.Hand
 			services["nats"] = "ok"
 		}
 
+		if !runHealthProbes(r.Context(), probes, services) {
+			healthy = false
+		}
+
 		statusCode := http.StatusOK
 		status := "ok"
 		if !healthy {
@@ -289,73 +1053,103 @@ func healthzHandler(db *store.Store, nc *nats.Conn, rdb *redis.Client) http.Hand
 			status = "degraded"
 		}
 
-		respondJSONWithStatus(w, statusCode, map[string]interface{}{
+		body := map[string]interface{}{
 			"status":   status,
 			"services": services,
-		})
+		}
+		if services["nats"] == "ok" {
+			if pending := articlesQueuePending(q); pending != nil {
+				body["articles_queue_pending"] = *pending
+			}
+		}
+		respondJSONWithStatus(w, statusCode, body)
 	}
 }
 
-func listArticlesHandler(db *store.Store) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		page := parsePositiveInt(r.URL.Query().Get("page"), 1)
-		perPage := parsePositiveInt(r.URL.Query().Get("per_page"), 20)
-		if perPage > 100 {
-			perPage = 100
-		}
+// parseArticleListQuery reads the article listing filters and pagination
+// shared by the flat and clustered listings.
+func parseArticleListQuery(r *http.Request) (store.ArticleListQuery, int, int, error) {
+	page := parsePositiveInt(r.URL.Query().Get("page"), 1)
+	perPage := parsePositiveInt(r.URL.Query().Get("per_page"), 20)
+	if perPage > 100 {
+		perPage = 100
+	}
 
-		filter := store.ArticleListQuery{
-			Limit:  perPage,
-			Offset: (page - 1) * perPage,
-		}
+	filter := store.ArticleListQuery{
+		Limit:  perPage,
+		Offset: (page - 1) * perPage,
+	}
 
-		if section := strings.TrimSpace(r.URL.Query().Get("section")); section != "" {
-			filter.SectionName = &section
-		}
-		if sectionsRaw := strings.TrimSpace(r.URL.Query().Get("sections")); sectionsRaw != "" {
-			parts := strings.Split(sectionsRaw, ",")
-			filter.SectionNames = make([]string, 0, len(parts))
-			for _, part := range parts {
-				part = strings.TrimSpace(part)
-				if part != "" {
-					filter.SectionNames = append(filter.SectionNames, part)
-				}
-			}
-			if len(filter.SectionNames) > 0 {
-				filter.SectionName = nil
+	if section := strings.TrimSpace(r.URL.Query().Get("section")); section != "" {
+		filter.SectionName = &section
+	}
+	if sectionsRaw := strings.TrimSpace(r.URL.Query().Get("sections")); sectionsRaw != "" {
+		parts := strings.Split(sectionsRaw, ",")
+		filter.SectionNames = make([]string, 0, len(parts))
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part != "" {
+				filter.SectionNames = append(filter.SectionNames, part)
 			}
 		}
-		if sourceType := strings.TrimSpace(r.URL.Query().Get("source_type")); sourceType != "" {
-			filter.SourceType = &sourceType
-		}
-		if sourceRef := strings.TrimSpace(r.URL.Query().Get("source_ref")); sourceRef != "" {
-			filter.SourceRef = &sourceRef
+		if len(filter.SectionNames) > 0 {
+			filter.SectionName = nil
 		}
-		if status := strings.TrimSpace(r.URL.Query().Get("status")); status != "" {
-			filter.Status = &status
-		}
-		filter.LikedOnly = parseBool(r.URL.Query().Get("liked_only"))
+	}
+	if sourceType := strings.TrimSpace(r.URL.Query().Get("source_type")); sourceType != "" {
+		filter.SourceType = &sourceType
+	}
+	if sourceRef := strings.TrimSpace(r.URL.Query().Get("source_ref")); sourceRef != "" {
+		filter.SourceRef = &sourceRef
+	}
+	if category := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("category"))); category != "" {
+		filter.Category = &category
+	}
+	filter.SourceRefs = splitCommaList(r.URL.Query().Get("source_refs"))
+	filter.ExcludeSourceRefs = splitCommaList(r.URL.Query().Get("exclude_source_refs"))
+	filter.Langs = splitCommaList(strings.ToLower(r.URL.Query().Get("lang")))
+	statuses, err := parseStatusFilter(r.URL.Query().Get("status"))
+	if err != nil {
+		return filter, 0, 0, err
+	}
+	switch len(statuses) {
+	case 0:
+	case 1:
+		filter.Status = &statuses[0]
+	default:
+		filter.Statuses = statuses
+	}
+	filter.SortBy, filter.SortAsc, err = parseArticleSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		return filter, 0, 0, err
+	}
+	filter.LikedOnly = parseBool(r.URL.Query().Get("liked_only"))
+	filter.SavedOnly = parseBool(r.URL.Query().Get("saved_only"))
+	filter.HideDisliked = parseBool(r.URL.Query().Get("hide_disliked"))
+	filter.DislikedOnly = parseBool(r.URL.Query().Get("disliked_only"))
+	if filter.HideDisliked && filter.DislikedOnly {
+		return filter, 0, 0, errors.New("hide_disliked and disliked_only cannot be combined")
+	}
 
-		if from := strings.TrimSpace(r.URL.Query().Get("from")); from != "" {
-			t, err := parseISO8601(from)
-			if err != nil {
-				http.Error(w, "invalid 'from' datetime (use ISO 8601)", http.StatusBadRequest)
-				return
-			}
-			filter.From = &t
-		}
-		if to := strings.TrimSpace(r.URL.Query().Get("to")); to != "" {
-			t, err := parseISO8601(to)
-			if err != nil {
-				http.Error(w, "invalid 'to' datetime (use ISO 8601)", http.StatusBadRequest)
-				return
-			}
-			filter.To = &t
+	filter.From, filter.To, err = parseTimeRange(r.URL.Query())
+	if err != nil {
+		return filter, 0, 0, err
+	}
+
+	return filter, page, perPage, nil
+}
+
+func listArticlesHandler(db *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, page, perPage, err := parseArticleListQuery(r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
 		}
 
 		articles, total, err := db.ListArticlesWithRelations(r.Context(), filter)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 
@@ -380,22 +1174,271 @@ func listArticlesHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
+type clusteredArticleLister interface {
+	ListClusteredArticles(ctx context.Context, q store.ArticleListQuery) ([]store.ArticleCluster, int, error)
+}
+
+type articleClusterResponse struct {
+	ClusterID string          `json:"cluster_id"`
+	Article   articleResponse `json:"article"`
+	// SeenIn and ReportedBy list the sources that covered the story, as
+	// in the briefing's multi-source lines.
+	SeenIn     []string          `json:"seen_in"`
+	ReportedBy []string          `json:"reported_by"`
+	Duplicates []articleResponse `json:"duplicates"`
+}
+
+// listClusteredArticlesHandler is listArticlesHandler grouped by semantic
+// dedup cluster: each entry is the cluster's primary article plus the
+// duplicates it suppresses, paginated by cluster.
+func listClusteredArticlesHandler(db clusteredArticleLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, page, perPage, err := parseArticleListQuery(r)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+
+		clusters, total, err := db.ListClusteredArticles(r.Context(), filter)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+
+		out := make([]articleClusterResponse, 0, len(clusters))
+		for _, cluster := range clusters {
+			out = append(out, mapArticleClusterResponse(cluster))
+		}
+
+		totalPages := 0
+		if perPage > 0 {
+			totalPages = (total + perPage - 1) / perPage
+		}
+
+		respondJSON(w, map[string]interface{}{
+			"data":        out,
+			"total":       total,
+			"page":        page,
+			"per_page":    perPage,
+			"total_pages": totalPages,
+		})
+	}
+}
+
+func mapArticleClusterResponse(cluster store.ArticleCluster) articleClusterResponse {
+	members := make([]*models.Article, 0, len(cluster.Articles))
+	for _, a := range cluster.Articles {
+		members = append(members, &a.Article)
+	}
+	primary := dedup.PickClusterPrimary(members)
+	seenIn, reportedBy := dedup.ClusterCoverage(members)
+
+	resp := articleClusterResponse{
+		ClusterID:  cluster.ClusterID,
+		SeenIn:     seenIn,
+		ReportedBy: reportedBy,
+		Duplicates: make([]articleResponse, 0, len(cluster.Articles)-1),
+	}
+	for _, a := range cluster.Articles {
+		if a.ID == primary.ID {
+			resp.Article = mapArticleResponse(a)
+			continue
+		}
+		resp.Duplicates = append(resp.Duplicates, mapArticleResponse(a))
+	}
+	return resp
+}
+
+func listCategoriesHandler(db *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		categories, err := db.ListCategoriesWithCounts(r.Context())
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		respondJSON(w, categories)
+	}
+}
+
+// splitCommaList splits a comma-separated query value, dropping blanks.
+func splitCommaList(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// parseStatusFilter splits a comma-separated status filter, e.g.
+// "pending,processed", rejecting values that are not article statuses.
+func parseStatusFilter(raw string) ([]string, error) {
+	var statuses []string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		switch part {
+		case models.StatusPending, models.StatusProcessed, models.StatusBriefed, models.StatusArchived, models.StatusNeedsEmbedding:
+		default:
+			return nil, fmt.Errorf("invalid status %q (use pending, processed, briefed, archived or needs_embedding)", part)
+		}
+		statuses = append(statuses, part)
+	}
+	return statuses, nil
+}
+
+// parseArticleSort parses "field" or "field:asc|desc" into a store sort
+// field and direction. Direction defaults to descending.
+func parseArticleSort(raw string) (string, bool, error) {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return store.ArticleSortIngestedAt, false, nil
+	}
+	field, dir, _ := strings.Cut(raw, ":")
+	switch field {
+	case store.ArticleSortIngestedAt, store.ArticleSortPublishedAt, store.ArticleSortRelevance:
+	default:
+		return "", false, fmt.Errorf("invalid sort field %q (use ingested_at, published_at or relevance_score)", field)
+	}
+	switch dir {
+	case "", "desc":
+		return field, false, nil
+	case "asc":
+		return field, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid sort direction %q (use asc or desc)", dir)
+	}
+}
+
+// savedArticlesHandler is listArticlesHandler with saved_only forced on, so it
+// accepts the same pagination and section filters.
+func savedArticlesHandler(db *store.Store) http.HandlerFunc {
+	list := listArticlesHandler(db)
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		query.Set("saved_only", "true")
+		saved := r.Clone(r.Context())
+		saved.URL.RawQuery = query.Encode()
+		list(w, saved)
+	}
+}
+
 func getArticleHandler(db *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "id")
 		article, err := db.GetArticleWithRelationsByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if article == nil {
-			http.Error(w, "not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
 			return
 		}
 		respondJSON(w, mapArticleResponse(article))
 	}
 }
 
+// relevanceEngineTTL is how long article explanations reuse a relevance
+// engine. Building one embeds every section's seed keywords, so it is not
+// done per request; section edits show up once the engine is rebuilt.
+const relevanceEngineTTL = time.Minute
+
+type articleExplainer interface {
+	ExplainArticle(ctx context.Context, article *models.Article, articleEmbedding []float32) (*relevance.Explanation, error)
+}
+
+// cachedEngine builds an articleExplainer on first use and rebuilds it once
+// ttl has passed. A failed build is not cached, so the next request retries.
+type cachedEngine struct {
+	build func(context.Context) (articleExplainer, error)
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu      sync.Mutex
+	engine  articleExplainer
+	builtAt time.Time
+}
+
+func newCachedEngine(build func(context.Context) (articleExplainer, error), ttl time.Duration) *cachedEngine {
+	return &cachedEngine{build: build, ttl: ttl, now: time.Now}
+}
+
+// get returns the cached engine, building it first when missing or stale.
+// Concurrent callers wait for a single build.
+func (c *cachedEngine) get(ctx context.Context) (articleExplainer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.engine != nil && c.now().Sub(c.builtAt) < c.ttl {
+		return c.engine, nil
+	}
+	engine, err := c.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.engine, c.builtAt = engine, c.now()
+	return engine, nil
+}
+
+type articleGetter interface {
+	GetArticleByID(ctx context.Context, id string) (*models.Article, error)
+}
+
+// explainArticleHandler recomputes an article's relevance with the sections,
+// profiles and thresholds of an engine at most relevanceEngineTTL old and
+// returns the score breakdown.
+func explainArticleHandler(db articleGetter, engines *cachedEngine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		article, err := db.GetArticleByID(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if article == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+		if len(article.Embedding) == 0 {
+			respondError(w, http.StatusConflict, errCodeConflict, "article has no embedding yet")
+			return
+		}
+
+		engine, err := engines.get(r.Context())
+		if err != nil {
+			log.WithError(err).Warn("Failed to build relevance engine")
+			respondError(w, http.StatusServiceUnavailable, errCodeUnavailable, "relevance engine unavailable")
+			return
+		}
+		exp, err := engine.ExplainArticle(r.Context(), article, article.Embedding)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+
+		respondJSON(w, map[string]interface{}{
+			"article_id":        article.ID,
+			"section_id":        exp.SectionID,
+			"section_name":      exp.SectionName,
+			"source_id":         exp.SourceID,
+			"positive_score":    exp.PositiveScore,
+			"positive_source":   exp.PositiveSource,
+			"negative_score":    exp.NegativeScore,
+			"source_boost":      exp.SourceBoost,
+			"freshness_boost":   exp.FreshnessBoost,
+			"relevance_score":   exp.RelevanceScore,
+			"threshold":         exp.Threshold,
+			"status":            exp.Status,
+			"stored_section_id": article.SectionID,
+			"stored_score":      article.RelevanceScore,
+			"stored_status":     article.Status,
+		})
+	}
+}
+
 func mapArticleResponse(a *store.ArticleWithRelations) articleResponse {
 	var section *articleSectionResponse
 	if a.SectionID != nil {
@@ -449,14 +1492,22 @@ func mapArticleResponse(a *store.ArticleWithRelations) articleResponse {
 			DislikeID: a.LatestDislikeID,
 			SaveID:    a.LatestSaveID,
 		},
+		Note: mapArticleNoteResponse(a.Note),
 	}
 }
 
+func mapArticleNoteResponse(n *store.ArticleNote) *articleNoteResponse {
+	if n == nil {
+		return nil
+	}
+	return &articleNoteResponse{Text: n.Note, CreatedAt: n.CreatedAt, UpdatedAt: n.UpdatedAt}
+}
+
 func listSourcesHandler(db *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		sources, err := db.ListSourcesWithSections(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 
@@ -468,7 +1519,7 @@ func listSourcesHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
-func createSourceHandler(db *store.Store) http.HandlerFunc {
+func createSourceHandler(db *store.Store, validator *sourceValidator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			SourceType string          `json:"source_type"`
@@ -477,21 +1528,26 @@ func createSourceHandler(db *store.Store) http.HandlerFunc {
 			SectionIDs []string        `json:"section_ids"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
 			return
 		}
 
 		req.SourceType = strings.TrimSpace(req.SourceType)
 		req.Name = strings.TrimSpace(req.Name)
 		if req.SourceType == "" || req.Name == "" || len(req.Config) == 0 {
-			http.Error(w, "source_type, name and config are required", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "source_type, name and config are required")
 			return
 		}
-		if req.SourceType == "rss" {
-			if err := validateRSSConfig(req.Config); err != nil {
-				http.Error(w, "invalid RSS feed URL: "+err.Error(), http.StatusBadRequest)
-				return
-			}
+		result := validator.Validate(r.Context(), req.SourceType, req.Config)
+		if !result.Valid {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid "+req.SourceType+" source: "+result.Details)
+			return
+		}
+		if result.Unverified {
+			log.WithFields(log.Fields{"source_type": req.SourceType, "details": result.Details}).Warn("Saving source that could not be verified")
+		}
+		if result.Config != nil {
+			req.Config = result.Config
 		}
 
 		src := &models.Source{
@@ -502,17 +1558,17 @@ func createSourceHandler(db *store.Store) http.HandlerFunc {
 		}
 
 		if err := db.CreateSource(r.Context(), src, req.SectionIDs); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 
 		created, err := db.GetSourceWithSectionsByID(r.Context(), src.ID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if created == nil {
-			http.Error(w, "created source not found", http.StatusInternalServerError)
+			respondError(w, http.StatusInternalServerError, errCodeInternal, "created source not found")
 			return
 		}
 
@@ -520,7 +1576,7 @@ func createSourceHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
-func updateSourceHandler(db *store.Store) http.HandlerFunc {
+func updateSourceHandler(db *store.Store, validator *sourceValidator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "id")
 
@@ -531,22 +1587,22 @@ func updateSourceHandler(db *store.Store) http.HandlerFunc {
 			SectionIDs *[]string        `json:"section_ids,omitempty"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
 			return
 		}
 
 		if req.Name == nil && req.Config == nil && req.Enabled == nil && req.SectionIDs == nil {
-			http.Error(w, "empty patch body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "empty patch body")
 			return
 		}
 
 		src, err := db.GetSourceByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if src == nil {
-			http.Error(w, "not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
 			return
 		}
 
@@ -554,36 +1610,41 @@ func updateSourceHandler(db *store.Store) http.HandlerFunc {
 			src.Name = strings.TrimSpace(*req.Name)
 		}
 		if req.Config != nil {
-			if src.SourceType == "rss" {
-				if err := validateRSSConfig(*req.Config); err != nil {
-					http.Error(w, "invalid RSS feed URL: "+err.Error(), http.StatusBadRequest)
-					return
-				}
+			result := validator.Validate(r.Context(), src.SourceType, *req.Config)
+			if !result.Valid {
+				respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid "+src.SourceType+" source: "+result.Details)
+				return
+			}
+			if result.Unverified {
+				log.WithFields(log.Fields{"source_id": id, "source_type": src.SourceType, "details": result.Details}).Warn("Saving source that could not be verified")
 			}
 			src.Config = *req.Config
+			if result.Config != nil {
+				src.Config = result.Config
+			}
 		}
 		if req.Enabled != nil {
 			src.Enabled = *req.Enabled
 		}
 
 		if err := db.UpdateSource(r.Context(), src); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if req.SectionIDs != nil {
 			if err := db.ReplaceSourceSections(r.Context(), id, *req.SectionIDs); err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				respondFailure(w, r, err)
 				return
 			}
 		}
 
 		updated, err := db.GetSourceWithSectionsByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if updated == nil {
-			http.Error(w, "not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
 			return
 		}
 
@@ -591,6 +1652,52 @@ func updateSourceHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
+// sourceToggler is the slice of store.Store bulk source toggles need.
+type sourceToggler interface {
+	SetSourcesEnabled(ctx context.Context, sel store.SourceSelector, enabled bool) (int64, error)
+}
+
+// bulkToggleSourcesHandler enables or disables every source of a type and/or
+// in a list of ids, e.g. pausing all Reddit sources during an API outage.
+func bulkToggleSourcesHandler(db sourceToggler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SourceType string   `json:"source_type"`
+			IDs        []string `json:"ids"`
+			Enabled    *bool    `json:"enabled"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		if req.Enabled == nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "enabled is required")
+			return
+		}
+
+		var sel store.SourceSelector
+		if sourceType := strings.ToLower(strings.TrimSpace(req.SourceType)); sourceType != "" {
+			sel.SourceType = &sourceType
+		}
+		for _, id := range req.IDs {
+			if id = strings.TrimSpace(id); id != "" {
+				sel.IDs = append(sel.IDs, id)
+			}
+		}
+		if sel.SourceType == nil && len(sel.IDs) == 0 {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "source_type or ids is required")
+			return
+		}
+
+		updated, err := db.SetSourcesEnabled(r.Context(), sel, *req.Enabled)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		respondJSON(w, map[string]any{"updated": updated, "enabled": *req.Enabled})
+	}
+}
+
 func mapSourceResponse(src *store.SourceWithSections) sourceResponse {
 	return sourceResponse{
 		ID:            src.Source.ID,
@@ -610,61 +1717,605 @@ func mapSourceResponse(src *store.SourceWithSections) sourceResponse {
 	}
 }
 
-func validateRSSHandler() http.HandlerFunc {
+// normalizeURLHandler previews how dedup normalizes and hashes a URL, to
+// debug why two articles did or did not collide.
+func normalizeURLHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
 			URL string `json:"url"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
 			return
 		}
 		req.URL = strings.TrimSpace(req.URL)
 		if req.URL == "" {
-			http.Error(w, "url is required", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "url is required")
+			return
+		}
+
+		respondJSON(w, map[string]string{
+			"url":        req.URL,
+			"normalized": dedup.NormalizeURL(req.URL),
+			"hash":       dedup.HashURL(req.URL),
+		})
+	}
+}
+
+// cronPreviewRuns is how many upcoming fire times validate-cron returns.
+const cronPreviewRuns = 5
+
+// validateCronHandler checks a BRIEFING_SCHEDULE-style expression and
+// previews its next fire times in UTC, so a schedule can be confirmed before
+// deploying it.
+func validateCronHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Schedule string `json:"schedule"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		req.Schedule = strings.TrimSpace(req.Schedule)
+		if req.Schedule == "" {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "schedule is required")
+			return
+		}
+
+		schedule, err := config.ValidateCronSchedule(req.Schedule)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+		runs := config.NextCronRuns(schedule, time.Now(), cronPreviewRuns)
+		next := make([]string, 0, len(runs))
+		for _, run := range runs {
+			next = append(next, run.Format(time.RFC3339))
+		}
+		respondJSON(w, map[string]any{"valid": true, "schedule": req.Schedule, "next_runs": next})
+	}
+}
+
+func sourceHistoryHandler(db *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		limit := parsePositiveInt(r.URL.Query().Get("limit"), 50)
+		if limit > 500 {
+			limit = 500
+		}
+
+		src, err := db.GetSourceByID(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if src == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+
+		runs, err := db.ListSourceFetchLog(r.Context(), id, limit)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		respondJSON(w, map[string]interface{}{
+			"source_id":            src.ID,
+			"consecutive_failures": consecutiveFetchFailures(runs),
+			"runs":                 runs,
+		})
+	}
+}
+
+// consecutiveFetchFailures counts the failed runs at the head of a
+// newest-first fetch log.
+func consecutiveFetchFailures(runs []store.SourceFetchRun) int {
+	n := 0
+	for _, run := range runs {
+		if run.Error == nil {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// validateSourceHandler probes a source config without saving it and
+// returns a few sample titles so the user can confirm it is the right feed.
+// An unreachable or malformed source is reported as valid=false, not an error.
+func validateSourceHandler(validator *sourceValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SourceType string          `json:"source_type"`
+			Config     json.RawMessage `json:"config"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		req.SourceType = strings.TrimSpace(req.SourceType)
+		if req.SourceType == "" {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "source_type is required")
+			return
+		}
+		if len(req.Config) == 0 {
+			req.Config = json.RawMessage("{}")
+		}
+
+		respondJSON(w, validator.Validate(r.Context(), req.SourceType, req.Config))
+	}
+}
+
+func validateRSSHandler(validator *sourceValidator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			URL string `json:"url"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		req.URL = strings.TrimSpace(req.URL)
+		if req.URL == "" {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "url is required")
 			return
 		}
 
 		cfg, _ := json.Marshal(rssSourceConfig{URL: req.URL})
-		if err := validateRSSConfig(cfg); err != nil {
-			http.Error(w, "invalid RSS feed URL: "+err.Error(), http.StatusBadRequest)
+		result := validator.Validate(r.Context(), "rss", cfg)
+		if !result.Valid {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid RSS feed URL: "+result.Details)
 			return
 		}
+		if result.Config != nil {
+			var resolved rssSourceConfig
+			if err := json.Unmarshal(result.Config, &resolved); err == nil {
+				req.URL = resolved.URL
+			}
+		}
+
+		respondJSON(w, map[string]any{"valid": true, "url": req.URL})
+	}
+}
+
+// sourceValidationSamples caps the sample titles returned by a validation.
+const sourceValidationSamples = 5
+
+type sourceValidation struct {
+	Valid        bool     `json:"valid"`
+	Details      string   `json:"details"`
+	SampleTitles []string `json:"sample_titles"`
+	// Config replaces the submitted config when validation resolved it, e.g.
+	// a homepage URL to the feed the page advertises.
+	Config json.RawMessage `json:"config,omitempty"`
+	// Unverified marks a source that could not be checked right now, e.g.
+	// after a timeout, 429 or 5xx. It is still Valid so saving goes through;
+	// Details says why.
+	Unverified bool `json:"unverified,omitempty"`
+}
+
+// unverifiedError wraps a validation failure that says nothing about the
+// source itself: a network error, a rate limit, an upstream outage or
+// missing API credentials.
+type unverifiedError struct{ err error }
 
-		respondJSON(w, map[string]any{"valid": true})
+func (e *unverifiedError) Error() string { return e.err.Error() }
+func (e *unverifiedError) Unwrap() error { return e.err }
+
+func unverified(format string, args ...any) error {
+	return &unverifiedError{err: fmt.Errorf(format, args...)}
+}
+
+// sourceValidator fetches a source the way its worker would, so a config is
+// rejected before it is saved rather than on the next worker run. Only
+// definite answers reject a source; see unverifiedError. The base URLs are
+// fields so tests can point them at a fake upstream.
+type sourceValidator struct {
+	client        *http.Client
+	userAgent     string
+	redditAuthURL string
+	redditBase    string
+	githubBase    string
+}
+
+// newSourceValidator builds a validator that reaches upstreams through
+// proxy (FLUX_OUTBOUND_PROXY) when set, like the workers do.
+func newSourceValidator(userAgent, proxy string) (*sourceValidator, error) {
+	proxyURL, err := ratelimit.ParseProxy(proxy)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &sourceValidator{
+		client: &http.Client{
+			Timeout:   15 * time.Second,
+			Transport: transport,
+			// Reddit redirects unknown subreddits to its search page.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if strings.HasSuffix(via[0].URL.Hostname(), "reddit.com") {
+					return http.ErrUseLastResponse
+				}
+				if len(via) >= 10 {
+					return errors.New("stopped after 10 redirects")
+				}
+				return nil
+			},
+		},
+		userAgent:     userAgent,
+		redditAuthURL: "https://www.reddit.com/api/v1/access_token",
+		redditBase:    "https://oauth.reddit.com",
+		githubBase:    "https://api.github.com",
+	}, nil
+}
+
+// Validate dispatches to the validator for sourceType. Failures are folded
+// into the result so callers can both report and reject them.
+func (v *sourceValidator) Validate(ctx context.Context, sourceType string, raw json.RawMessage) sourceValidation {
+	var (
+		titles   []string
+		details  string
+		resolved json.RawMessage
+		err      error
+	)
+	switch sourceType {
+	case "rss":
+		var feedURL string
+		titles, feedURL, err = v.validateRSS(ctx, raw)
+		details = "feed parsed"
+		if err == nil && feedURL != "" {
+			details = "feed discovered at " + feedURL
+			resolved, err = setConfigField(raw, "url", feedURL)
+		}
+	case "reddit":
+		titles, err = v.validateReddit(ctx, raw)
+		details = "subreddit found"
+	case "github":
+		titles, err = v.validateGitHub(ctx, raw)
+		details = "repository reachable"
+	case "hn":
+		return sourceValidation{Valid: true, Details: "Hacker News needs no configuration", SampleTitles: []string{}}
+	default:
+		err = fmt.Errorf("unsupported source_type %q (want rss, reddit, github or hn)", sourceType)
 	}
+	var unverifiedErr *unverifiedError
+	if errors.As(err, &unverifiedErr) {
+		return sourceValidation{Valid: true, Unverified: true, Details: "could not verify the source: " + err.Error(), SampleTitles: []string{}}
+	}
+	if err != nil {
+		return sourceValidation{Details: err.Error(), SampleTitles: []string{}}
+	}
+	if titles == nil {
+		titles = []string{}
+	}
+	return sourceValidation{Valid: true, Details: details, SampleTitles: titles, Config: resolved}
 }
 
-func validateRSSConfig(raw json.RawMessage) error {
+// validateRSS parses the configured feed. When the URL is a web page rather
+// than a feed, the feed it advertises is used instead and its URL returned.
+func (v *sourceValidator) validateRSS(ctx context.Context, raw json.RawMessage) ([]string, string, error) {
 	var cfg rssSourceConfig
 	if err := json.Unmarshal(raw, &cfg); err != nil {
-		return fmt.Errorf("invalid config JSON")
+		return nil, "", errors.New("invalid config JSON")
 	}
 	cfg.URL = strings.TrimSpace(cfg.URL)
 	if cfg.URL == "" {
-		return fmt.Errorf("missing config.url")
+		return nil, "", errors.New("missing config.url")
 	}
 
-	client := &http.Client{Timeout: 15 * time.Second}
-	parser := gofeed.NewParser()
-	parser.Client = client
-	if _, err := parser.ParseURL(cfg.URL); err != nil {
-		return err
+	fetcher := feeds.Fetcher{Client: v.client, UserAgent: v.userAgent, MaxBytes: 5 << 20}
+	res, err := fetcher.Fetch(ctx, cfg.URL, nil)
+	var statusErr *feeds.StatusError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &statusErr):
+		if statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone {
+			return nil, "", err
+		}
+		return nil, "", &unverifiedError{err: err}
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded):
+		return nil, "", &unverifiedError{err: err}
+	case err != nil:
+		return nil, "", err
+	}
+	discovered := ""
+	if res.URL != cfg.URL {
+		discovered = res.URL
+	}
+
+	titles := make([]string, 0, sourceValidationSamples)
+	for _, item := range res.Feed.Items {
+		titles = appendSampleTitle(titles, item.Title)
+	}
+	return titles, discovered, nil
+}
+
+// setConfigField returns raw with key set to value, keeping every other
+// field as submitted.
+func setConfigField(raw json.RawMessage, key, value string) (json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, errors.New("invalid config JSON")
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields[key] = encoded
+	return json.Marshal(fields)
+}
+
+func (v *sourceValidator) validateReddit(ctx context.Context, raw json.RawMessage) ([]string, error) {
+	var cfg redditSourceConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, errors.New("invalid config JSON")
+	}
+	subreddit := strings.Trim(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(cfg.Subreddit)), "r/"), "/")
+	if subreddit == "" {
+		return nil, errors.New("missing config.subreddit")
+	}
+
+	token, err := v.redditToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var listing struct {
+		Data struct {
+			Children []struct {
+				Data struct {
+					Title string `json:"title"`
+				} `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	listingURL := fmt.Sprintf("%s/r/%s/hot.json?limit=%d&raw_json=1", v.redditBase, url.PathEscape(subreddit), sourceValidationSamples)
+	headers := http.Header{"Authorization": []string{"Bearer " + token}}
+	status, err := v.getJSON(ctx, listingURL, headers, &listing)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case status == http.StatusOK:
+	case status == http.StatusNotFound, status >= 300 && status < 400:
+		return nil, fmt.Errorf("subreddit r/%s not found (status %d)", subreddit, status)
+	case status == http.StatusForbidden:
+		// Authenticated, so this is the subreddit refusing the worker too.
+		return nil, fmt.Errorf("subreddit r/%s is private or quarantined", subreddit)
+	default:
+		return nil, unverified("reddit answered status %d for r/%s", status, subreddit)
+	}
+
+	titles := make([]string, 0, sourceValidationSamples)
+	for _, child := range listing.Data.Children {
+		titles = appendSampleTitle(titles, child.Data.Title)
+	}
+	return titles, nil
+}
+
+// redditToken signs in with the worker's REDDIT_* OAuth credentials. Missing
+// credentials or a failed sign-in leave the subreddit unverified rather than
+// invalid, since neither says anything about the subreddit.
+func (v *sourceValidator) redditToken(ctx context.Context) (string, error) {
+	creds := map[string]string{}
+	var missing []string
+	for _, key := range []string{"REDDIT_CLIENT_ID", "REDDIT_CLIENT_SECRET", "REDDIT_USERNAME", "REDDIT_PASSWORD"} {
+		creds[key] = strings.TrimSpace(os.Getenv(key))
+		if creds[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return "", unverified("reddit OAuth credentials not configured: %s", strings.Join(missing, ", "))
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "password")
+	form.Set("username", creds["REDDIT_USERNAME"])
+	form.Set("password", creds["REDDIT_PASSWORD"])
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.redditAuthURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("building request: %w", err)
+	}
+	req.SetBasicAuth(creds["REDDIT_CLIENT_ID"], creds["REDDIT_CLIENT_SECRET"])
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if v.userAgent != "" {
+		req.Header.Set("User-Agent", v.userAgent)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", unverified("reddit oauth: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", unverified("reddit oauth status %d", resp.StatusCode)
+	}
+	var out struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&out); err != nil || strings.TrimSpace(out.AccessToken) == "" {
+		return "", unverified("reddit oauth response missing access_token")
 	}
-	return nil
+	return strings.TrimSpace(out.AccessToken), nil
 }
 
-func listSectionsHandler(db *store.Store) http.HandlerFunc {
+func (v *sourceValidator) validateGitHub(ctx context.Context, raw json.RawMessage) ([]string, error) {
+	var cfg githubSourceConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, errors.New("invalid config JSON")
+	}
+	repo := strings.TrimSpace(cfg.Repo)
+	if repo == "" && cfg.Owner != "" && cfg.Name != "" {
+		repo = strings.TrimSpace(cfg.Owner) + "/" + strings.TrimSpace(cfg.Name)
+	}
+	parts := strings.Split(strings.Trim(repo, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.New("config.repo must be in owner/repo format")
+	}
+	repo = parts[0] + "/" + parts[1]
+
+	headers := http.Header{"Accept": []string{"application/vnd.github+json"}}
+	if token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN")); token != "" {
+		headers.Set("Authorization", "Bearer "+token)
+	}
+	repoURL := fmt.Sprintf("%s/repos/%s", v.githubBase, repo)
+	status, err := v.getJSON(ctx, repoURL, headers, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("repository %s not found (status %d)", repo, status)
+	default:
+		// 401 is our token, 403 and 429 rate limits, 5xx an outage.
+		return nil, unverified("github answered status %d for %s", status, repo)
+	}
+
+	var entries []struct {
+		Name    string `json:"name"`
+		TagName string `json:"tag_name"`
+		Commit  struct {
+			Message string `json:"message"`
+		} `json:"commit"`
+	}
+	mode := strings.ToLower(strings.TrimSpace(cfg.Mode))
+	switch mode {
+	case "":
+		mode = "releases"
+	case "releases", "tags", "commits":
+	default:
+		return nil, fmt.Errorf("unknown config.mode %q (want releases, tags or commits)", cfg.Mode)
+	}
+	status, err = v.getJSON(ctx, fmt.Sprintf("%s/%s?per_page=%d", repoURL, mode, sourceValidationSamples), headers, &entries)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, unverified("listing %s for %s failed (status %d)", mode, repo, status)
+	}
+
+	titles := make([]string, 0, sourceValidationSamples)
+	for _, e := range entries {
+		title := e.Name
+		if title == "" {
+			title = e.TagName
+		}
+		if title == "" {
+			title, _, _ = strings.Cut(e.Commit.Message, "\n")
+		}
+		titles = appendSampleTitle(titles, title)
+	}
+	return titles, nil
+}
+
+// getJSON fetches rawURL and decodes a 200 response into out, if given. Other
+// statuses are returned for the caller to explain; network and decoding
+// failures are unverified.
+func (v *sourceValidator) getJSON(ctx context.Context, rawURL string, headers http.Header, out any) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("building request: %w", err)
+	}
+	for key, values := range headers {
+		req.Header[key] = values
+	}
+	if v.userAgent != "" {
+		req.Header.Set("User-Agent", v.userAgent)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return 0, unverified("fetching %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || out == nil {
+		return resp.StatusCode, nil
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(out); err != nil {
+		return resp.StatusCode, unverified("decoding response from %s: %w", req.URL.Host, err)
+	}
+	return resp.StatusCode, nil
+}
+
+func appendSampleTitle(titles []string, title string) []string {
+	title = strings.TrimSpace(title)
+	if title == "" || len(titles) >= sourceValidationSamples {
+		return titles
+	}
+	return append(titles, title)
+}
+
+type sectionStatsLister interface {
+	ListSectionsWithStats(ctx context.Context) ([]*store.SectionStats, error)
+}
+
+func listSectionsHandler(db sectionStatsLister, cfg *config.Config, cache *responseCache) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		sections, err := db.ListSectionsWithStats(r.Context())
+		sections, err := cachedLoad(r.Context(), cache, cacheKeySections, db.ListSectionsWithStats)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
+		for _, sec := range sections {
+			if sec.RelevanceThreshold == nil {
+				threshold := cfg.RelevanceThresholdDefault
+				sec.RelevanceThreshold = &threshold
+			}
+		}
 		respondJSON(w, sections)
 	}
 }
 
+func updateSectionThresholdHandler(db *store.Store, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+
+		var req struct {
+			Threshold *float64 `json:"threshold"`
+			Locked    *bool    `json:"locked,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		if req.Threshold == nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "threshold is required")
+			return
+		}
+		if *req.Threshold < cfg.RelevanceThresholdMin || *req.Threshold > cfg.RelevanceThresholdMax {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("threshold must be between %.2f and %.2f",
+				cfg.RelevanceThresholdMin, cfg.RelevanceThresholdMax))
+			return
+		}
+
+		// Setting a threshold pins it unless the caller explicitly unlocks.
+		locked := true
+		if req.Locked != nil {
+			locked = *req.Locked
+		}
+		sec, err := db.SetSectionThreshold(r.Context(), id, *req.Threshold, locked)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if sec == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+
+		respondJSON(w, map[string]any{
+			"id":               sec.ID,
+			"threshold":        *req.Threshold,
+			"threshold_locked": locked,
+		})
+	}
+}
+
 func createSectionHandler(db *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
@@ -677,24 +2328,24 @@ func createSectionHandler(db *store.Store) http.HandlerFunc {
 			Config              json.RawMessage `json:"config,omitempty"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
 			return
 		}
 
 		name := strings.TrimSpace(strings.ToLower(req.Name))
 		displayName := strings.TrimSpace(req.DisplayName)
 		if name == "" || displayName == "" {
-			http.Error(w, "name and display_name are required", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "name and display_name are required")
 			return
 		}
 
 		existing, err := db.GetSectionByName(r.Context(), name)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if existing != nil {
-			http.Error(w, "section already exists", http.StatusConflict)
+			respondError(w, http.StatusConflict, errCodeConflict, "section already exists")
 			return
 		}
 
@@ -709,7 +2360,7 @@ func createSectionHandler(db *store.Store) http.HandlerFunc {
 		} else {
 			nextOrder, err := db.NextSectionSortOrder(r.Context())
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				respondFailure(w, r, err)
 				return
 			}
 			sortOrder = nextOrder
@@ -720,6 +2371,11 @@ func createSectionHandler(db *store.Store) http.HandlerFunc {
 			maxBriefing = *req.MaxBriefingArticles
 		}
 
+		if err := relevance.ValidateSeedGroups(req.Config); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+
 		sec := &models.Section{
 			Name:                name,
 			DisplayName:         displayName,
@@ -734,7 +2390,7 @@ func createSectionHandler(db *store.Store) http.HandlerFunc {
 		}
 
 		if err := db.CreateSection(r.Context(), sec); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 
@@ -742,62 +2398,326 @@ func createSectionHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
-func updateSectionHandler(db *store.Store) http.HandlerFunc {
+func updateSectionHandler(db *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		sec, err := db.GetSectionByID(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if sec == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+
+		var req struct {
+			DisplayName         *string          `json:"display_name,omitempty"`
+			Enabled             *bool            `json:"enabled,omitempty"`
+			SortOrder           *int             `json:"sort_order,omitempty"`
+			MaxBriefingArticles *int             `json:"max_briefing_articles,omitempty"`
+			SeedKeywords        *[]string        `json:"seed_keywords,omitempty"`
+			Config              *json.RawMessage `json:"config,omitempty"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+
+		if req.DisplayName == nil && req.Enabled == nil && req.SortOrder == nil && req.MaxBriefingArticles == nil && req.SeedKeywords == nil && req.Config == nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "empty patch body")
+			return
+		}
+
+		if req.DisplayName != nil {
+			sec.DisplayName = strings.TrimSpace(*req.DisplayName)
+		}
+		if req.Enabled != nil {
+			sec.Enabled = *req.Enabled
+		}
+		if req.SortOrder != nil {
+			sec.SortOrder = *req.SortOrder
+		}
+		if req.MaxBriefingArticles != nil && *req.MaxBriefingArticles > 0 {
+			sec.MaxBriefingArticles = *req.MaxBriefingArticles
+		}
+		if req.SeedKeywords != nil {
+			sec.SeedKeywords = *req.SeedKeywords
+		}
+		if req.Config != nil {
+			if err := relevance.ValidateSeedGroups(*req.Config); err != nil {
+				respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+				return
+			}
+			sec.Config = *req.Config
+		}
+
+		if err := db.UpdateSection(r.Context(), sec); err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+
+		respondJSON(w, sec)
+	}
+}
+
+// maxBulkStatusIDs caps how many articles one bulk status update may touch.
+const maxBulkStatusIDs = 500
+
+// articleStatusUpdater is the slice of store.Store bulk status updates need.
+type articleStatusUpdater interface {
+	UpdateArticleStatusBatch(ctx context.Context, ids []string, status string) (int64, error)
+}
+
+// bulkArticleStatusHandler applies one status to a batch of articles, e.g.
+// archiving everything uninteresting on a page. Only statuses a reader sets
+// are accepted; pending and needs_embedding belong to the pipeline.
+func bulkArticleStatusHandler(db articleStatusUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IDs    []string `json:"ids"`
+			Status string   `json:"status"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		ids := make([]string, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "ids are required")
+			return
+		}
+		if len(ids) > maxBulkStatusIDs {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("at most %d ids per request", maxBulkStatusIDs))
+			return
+		}
+		switch req.Status {
+		case models.StatusProcessed, models.StatusBriefed, models.StatusArchived:
+		default:
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("invalid status %q (use processed, briefed or archived)", req.Status))
+			return
+		}
+
+		updated, err := db.UpdateArticleStatusBatch(r.Context(), ids, req.Status)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		respondJSON(w, map[string]any{"updated": updated, "status": req.Status})
+	}
+}
+
+// maxArticleNoteRunes caps the length of an article note.
+const maxArticleNoteRunes = 4000
+
+// articleNoteWriter is the slice of store.Store editing article notes needs.
+type articleNoteWriter interface {
+	GetArticleByID(ctx context.Context, id string) (*models.Article, error)
+	SetArticleNote(ctx context.Context, articleID, note string) (*store.ArticleNote, error)
+	DeleteArticleNote(ctx context.Context, articleID string) (bool, error)
+}
+
+// updateArticleNoteHandler sets the free-text note on an article; a blank
+// note removes it. Notes never touch feedback, so they do not move the
+// section's relevance profile.
+func updateArticleNoteHandler(db articleNoteWriter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		var req struct {
+			Note *string `json:"note"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
+			return
+		}
+		if req.Note == nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "note is required")
+			return
+		}
+		note := strings.TrimSpace(*req.Note)
+		if utf8.RuneCountInString(note) > maxArticleNoteRunes {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, fmt.Sprintf("note must be at most %d characters", maxArticleNoteRunes))
+			return
+		}
+
+		if note == "" {
+			article, err := db.GetArticleByID(r.Context(), id)
+			if err != nil {
+				respondFailure(w, r, err)
+				return
+			}
+			if article == nil {
+				respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+				return
+			}
+			if _, err := db.DeleteArticleNote(r.Context(), id); err != nil {
+				respondFailure(w, r, err)
+				return
+			}
+			respondJSON(w, map[string]any{"article_id": id, "note": nil})
+			return
+		}
+
+		saved, err := db.SetArticleNote(r.Context(), id, note)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if saved == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+		respondJSON(w, map[string]any{"article_id": id, "note": mapArticleNoteResponse(saved)})
+	}
+}
+
+// maxUnconfirmedSectionClear is the largest pending backlog
+// mark-processed clears without ?confirm=true.
+const maxUnconfirmedSectionClear = 100
+
+// sectionClearer is the slice of store.Store clearing a section needs.
+type sectionClearer interface {
+	GetSectionByID(ctx context.Context, id string) (*models.Section, error)
+	CountSectionPending(ctx context.Context, sectionID string) (int, error)
+	MarkSectionProcessed(ctx context.Context, sectionID string) (int, error)
+}
+
+// markSectionProcessedHandler clears a section by moving all its pending
+// articles to processed. With ?dry_run=true it only reports how many would
+// change; sections with more than maxUnconfirmedSectionClear pending
+// articles are only cleared with ?confirm=true.
+func markSectionProcessedHandler(db sectionClearer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "id")
 		sec, err := db.GetSectionByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if sec == nil {
-			http.Error(w, "not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
 			return
 		}
 
-		var req struct {
-			DisplayName         *string          `json:"display_name,omitempty"`
-			Enabled             *bool            `json:"enabled,omitempty"`
-			SortOrder           *int             `json:"sort_order,omitempty"`
-			MaxBriefingArticles *int             `json:"max_briefing_articles,omitempty"`
-			SeedKeywords        *[]string        `json:"seed_keywords,omitempty"`
-			Config              *json.RawMessage `json:"config,omitempty"`
+		pending, err := db.CountSectionPending(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
 		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+		if parseBool(r.URL.Query().Get("dry_run")) {
+			respondJSON(w, map[string]any{"section_id": id, "dry_run": true, "updated": pending})
 			return
 		}
-
-		if req.DisplayName == nil && req.Enabled == nil && req.SortOrder == nil && req.MaxBriefingArticles == nil && req.SeedKeywords == nil && req.Config == nil {
-			http.Error(w, "empty patch body", http.StatusBadRequest)
+		if pending > maxUnconfirmedSectionClear && !parseBool(r.URL.Query().Get("confirm")) {
+			respondError(w, http.StatusConflict, errCodeConflict,
+				fmt.Sprintf("section has %d pending articles; repeat with confirm=true to clear them", pending))
 			return
 		}
 
-		if req.DisplayName != nil {
-			sec.DisplayName = strings.TrimSpace(*req.DisplayName)
+		updated, err := db.MarkSectionProcessed(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
 		}
-		if req.Enabled != nil {
-			sec.Enabled = *req.Enabled
+		log.WithFields(log.Fields{
+			"section_id": id,
+			"updated":    updated,
+			"auth_label": authLabel(r.Context()),
+		}).Info("Section marked processed")
+		respondJSON(w, map[string]any{"section_id": id, "dry_run": false, "updated": updated})
+	}
+}
+
+// sectionProfileResetter is the slice of store.Store resetting a section's
+// profile needs.
+type sectionProfileResetter interface {
+	GetSectionByID(ctx context.Context, id string) (*models.Section, error)
+	DeleteSectionProfile(ctx context.Context, sectionID string) (bool, error)
+	DeleteSectionTrainingFeedback(ctx context.Context, sectionID string) (int64, error)
+}
+
+// resetSectionProfileHandler drops a section's learned profile so relevance
+// falls back to its seed keywords. With ?clear_feedback=true the section's
+// likes and dislikes go too; otherwise the next recalculation rebuilds the
+// profile from the feedback that is still there.
+func resetSectionProfileHandler(db sectionProfileResetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		sec, err := db.GetSectionByID(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
 		}
-		if req.SortOrder != nil {
-			sec.SortOrder = *req.SortOrder
+		if sec == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
 		}
-		if req.MaxBriefingArticles != nil && *req.MaxBriefingArticles > 0 {
-			sec.MaxBriefingArticles = *req.MaxBriefingArticles
+
+		var feedbackDeleted int64
+		if parseBool(r.URL.Query().Get("clear_feedback")) {
+			feedbackDeleted, err = db.DeleteSectionTrainingFeedback(r.Context(), id)
+			if err != nil {
+				respondFailure(w, r, err)
+				return
+			}
 		}
-		if req.SeedKeywords != nil {
-			sec.SeedKeywords = *req.SeedKeywords
+		profileDeleted, err := db.DeleteSectionProfile(r.Context(), id)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
 		}
-		if req.Config != nil {
-			sec.Config = *req.Config
+
+		log.WithFields(log.Fields{
+			"section_id":       id,
+			"profile_deleted":  profileDeleted,
+			"feedback_deleted": feedbackDeleted,
+			"auth_label":       authLabel(r.Context()),
+		}).Info("Section profile reset")
+		respondJSON(w, map[string]any{
+			"section_id":       id,
+			"profile_deleted":  profileDeleted,
+			"feedback_deleted": feedbackDeleted,
+		})
+	}
+}
+
+// Bucket counts accepted by GET /api/sections/{id}/score-histogram.
+const (
+	defaultScoreHistogramBuckets = 20
+	maxScoreHistogramBuckets     = 100
+)
+
+// sectionScoreHistogramGetter is the slice of store.Store the score
+// histogram endpoint needs.
+type sectionScoreHistogramGetter interface {
+	SectionScoreHistogram(ctx context.Context, sectionID string, buckets int, defaultThreshold float64) (*store.SectionScoreHistogram, error)
+}
+
+// sectionScoreHistogramHandler shows where a section's threshold sits in the
+// relevance score distribution of its pending articles, so users can see how
+// many articles a threshold change would admit or reject.
+func sectionScoreHistogramHandler(db sectionScoreHistogramGetter, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		buckets := parsePositiveInt(r.URL.Query().Get("buckets"), defaultScoreHistogramBuckets)
+		if buckets > maxScoreHistogramBuckets {
+			buckets = maxScoreHistogramBuckets
 		}
 
-		if err := db.UpdateSection(r.Context(), sec); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		hist, err := db.SectionScoreHistogram(r.Context(), chi.URLParam(r, "id"), buckets, cfg.RelevanceThresholdDefault)
+		if err != nil {
+			respondFailure(w, r, err)
 			return
 		}
-
-		respondJSON(w, sec)
+		if hist == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+		respondJSON(w, hist)
 	}
 }
 
@@ -807,36 +2727,48 @@ func reorderSectionsHandler(db *store.Store) http.HandlerFunc {
 			SectionIDs []string `json:"section_ids"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
 			return
 		}
 		if len(req.SectionIDs) == 0 {
-			http.Error(w, "section_ids are required", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "section_ids are required")
 			return
 		}
 		if err := db.ReorderSections(r.Context(), req.SectionIDs); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		respondJSON(w, map[string]any{"ok": true})
 	}
 }
 
+// latestBriefingHandler serves the latest full briefing by default, so an
+// hourly section-only run does not hide the morning's briefing. Deployments
+// where every section has its own schedule never write a full one and get
+// the latest of any scope instead.
 func latestBriefingHandler(db *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		briefing, err := db.GetLatestBriefing(r.Context())
+		scope, err := parseBriefingScope(r.URL.Query(), models.BriefingScopeFull)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+		briefing, err := db.GetLatestBriefing(r.Context(), scope)
+		if err == nil && briefing == nil && r.URL.Query().Get("scope") == "" {
+			briefing, err = db.GetLatestBriefing(r.Context(), "")
+		}
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if briefing == nil {
-			http.Error(w, "no briefings generated yet", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "no briefings generated yet")
 			return
 		}
 
 		resp, err := buildBriefingResponse(r.Context(), db, briefing)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		respondJSON(w, resp)
@@ -845,9 +2777,25 @@ func latestBriefingHandler(db *store.Store) http.HandlerFunc {
 
 func listBriefingsHandler(db *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		briefings, err := db.ListBriefings(r.Context(), 20, 0)
+		page := parsePositiveInt(r.URL.Query().Get("page"), 1)
+		perPage := parsePositiveInt(r.URL.Query().Get("per_page"), 20)
+		if perPage > 100 {
+			perPage = 100
+		}
+		from, to, err := parseTimeRange(r.URL.Query())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+		scope, err := parseBriefingScope(r.URL.Query(), "")
+		if err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+
+		briefings, err := db.ListBriefingsInRange(r.Context(), from, to, scope, perPage, (page-1)*perPage)
+		if err != nil {
+			respondFailure(w, r, err)
 			return
 		}
 
@@ -864,28 +2812,194 @@ func listBriefingsHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
-func getBriefingHandler(db *store.Store) http.HandlerFunc {
+// getBriefingHandler serves a briefing as JSON, markdown, HTML or chat
+// messages; lang is the HTML page's language.
+func getBriefingHandler(db *store.Store, lang string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
+		id, format := splitBriefingFormat(chi.URLParam(r, "id"))
+		if format == briefingFormatJSON {
+			format = strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+			if format != briefingFormatJSON && format != briefingFormatSlack && format != briefingFormatDiscord {
+				respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "format must be slack or discord")
+				return
+			}
+		}
 		briefing, err := db.GetBriefingByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if briefing == nil {
-			http.Error(w, "not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+
+		switch format {
+		case briefingFormatMarkdown:
+			w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+			_, _ = w.Write([]byte(briefing.Content))
+			return
+		case briefingFormatHTML:
+			page, err := renderBriefingHTML(briefing, lang)
+			if err != nil {
+				respondFailure(w, r, err)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write(page)
+			return
+		case briefingFormatSlack, briefingFormatDiscord:
+			messages := render.ToSlack(briefing.Content)
+			if format == briefingFormatDiscord {
+				messages = render.ToDiscord(briefing.Content)
+			}
+			respondJSON(w, map[string]interface{}{
+				"id":       briefing.ID,
+				"format":   format,
+				"messages": messages,
+			})
 			return
 		}
 
 		resp, err := buildBriefingResponse(r.Context(), db, briefing)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		respondJSON(w, resp)
 	}
 }
 
+const (
+	briefingFormatJSON     = ""
+	briefingFormatMarkdown = "md"
+	briefingFormatHTML     = "html"
+	// Chat flavors, selected with ?format= and returned as JSON messages
+	// sized for the platform.
+	briefingFormatSlack   = "slack"
+	briefingFormatDiscord = "discord"
+)
+
+// splitBriefingFormat strips a .md or .html extension from a briefing id so
+// the same route serves JSON, raw markdown and rendered HTML.
+func splitBriefingFormat(raw string) (id, format string) {
+	for _, f := range []string{briefingFormatMarkdown, briefingFormatHTML} {
+		if trimmed, ok := strings.CutSuffix(raw, "."+f); ok {
+			return trimmed, f
+		}
+	}
+	return raw, briefingFormatJSON
+}
+
+var briefingMarkdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+var briefingPage = template.Must(template.New("briefing").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Flux briefing {{.Date}}</title>
+<style>
+body { max-width: 46rem; margin: 2rem auto; padding: 0 1rem; font: 16px/1.6 system-ui, sans-serif; color: #1f2328; }
+h1, h2, h3 { line-height: 1.25; }
+a { color: #0969da; }
+code { background: #f6f8fa; padding: 0 .2em; border-radius: 3px; }
+header { color: #59636e; font-size: .9em; border-bottom: 1px solid #d1d9e0; margin-bottom: 1.5rem; }
+@media print { a { color: inherit; } }
+</style>
+</head>
+<body>
+<header>Flux &middot; {{.Date}}</header>
+<main>
+{{.Body}}
+</main>
+</body>
+</html>
+`))
+
+// briefingPageLang is the language briefings are written in:
+// BRIEFING_LANGUAGE, or English when it is unset.
+func briefingPageLang(language string) string {
+	if lang := llm.NormalizeLanguage(language); lang != llm.DefaultLanguage {
+		return lang
+	}
+	return "en"
+}
+
+// renderBriefingHTML renders briefing markdown into a standalone printable
+// page in language lang. Raw HTML embedded in the markdown is not passed
+// through.
+func renderBriefingHTML(b *models.Briefing, lang string) ([]byte, error) {
+	var body bytes.Buffer
+	if err := briefingMarkdown.Convert([]byte(b.Content), &body); err != nil {
+		return nil, fmt.Errorf("rendering briefing markdown: %w", err)
+	}
+
+	var page bytes.Buffer
+	err := briefingPage.Execute(&page, struct {
+		Lang string
+		Date string
+		Body template.HTML
+	}{
+		Lang: lang,
+		Date: b.GeneratedAt.UTC().Format("2006-01-02 15:04 MST"),
+		Body: template.HTML(body.String()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rendering briefing page: %w", err)
+	}
+	return page.Bytes(), nil
+}
+
+// eventPublisher is the subset of queue.Queue handlers need to emit events.
+type eventPublisher interface {
+	Publish(subject string, data interface{}) error
+}
+
+// generateBriefingHandler queues a manual run for a daemon-mode briefing-gen.
+// Cronjob deployments never consume the request, so it is refused with 409
+// when daemonAlive finds no daemon heartbeat. A heartbeat that cannot be read
+// does not block the request.
+func generateBriefingHandler(q eventPublisher, daemonAlive func(context.Context) (bool, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		alive, err := daemonAlive(r.Context())
+		if err != nil {
+			log.WithError(err).Warn("Failed to check for a briefing-gen daemon, queueing the request anyway")
+			alive = true
+		}
+		if !alive {
+			respondError(w, http.StatusConflict, errCodeConflict,
+				"no briefing-gen daemon is consuming requests; manual runs need BRIEFING_MODE=daemon")
+			return
+		}
+
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			respondFailure(w, r, fmt.Errorf("generating request id: %w", err))
+			return
+		}
+
+		evt := queue.BriefingGenerateEvent{
+			RequestID:   hex.EncodeToString(buf),
+			RequestedBy: authLabel(r.Context()),
+			RequestedAt: time.Now().UTC(),
+		}
+		if err := q.Publish(queue.SubjectBriefingGenerate, evt); err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+
+		log.WithFields(log.Fields{
+			"request_id":   evt.RequestID,
+			"requested_by": evt.RequestedBy,
+		}).Info("Briefing generation requested")
+		respondJSONWithStatus(w, http.StatusAccepted, map[string]string{
+			"request_id": evt.RequestID,
+			"status":     "queued",
+		})
+	}
+}
+
 func buildBriefingResponse(ctx context.Context, db *store.Store, b *models.Briefing) (*briefingResponse, error) {
 	articles, err := db.ListArticlesWithRelationsByIDs(ctx, b.ArticleIDs)
 	if err != nil {
@@ -907,6 +3021,100 @@ func buildBriefingResponse(ctx context.Context, db *store.Store, b *models.Brief
 	}, nil
 }
 
+func exportBriefingHandler(db *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		briefing, err := db.GetBriefingByID(r.Context(), chi.URLParam(r, "id"))
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if briefing == nil {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+
+		articles, err := db.ListArticlesWithRelationsByIDs(r.Context(), briefing.ArticleIDs)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		sections, err := db.ListSections(r.Context())
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+
+		filename := fmt.Sprintf("flux-briefing-%s-%s.json", briefing.GeneratedAt.UTC().Format("2006-01-02"), briefing.ID)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		respondJSON(w, buildBriefingExport(briefing, articles, sections, time.Now().UTC()))
+	}
+}
+
+// buildBriefingExport bundles a briefing with its full articles and the
+// sections those articles belong to.
+func buildBriefingExport(b *models.Briefing, articles []*store.ArticleWithRelations, sections []*models.Section, now time.Time) briefingExport {
+	articleIDs := b.ArticleIDs
+	if articleIDs == nil {
+		articleIDs = []string{}
+	}
+	out := briefingExport{
+		SchemaVersion: briefingExportSchemaVersion,
+		ExportedAt:    now,
+		Briefing: briefingExportBriefing{
+			ID:          b.ID,
+			GeneratedAt: b.GeneratedAt,
+			Content:     b.Content,
+			ArticleIDs:  articleIDs,
+			Metadata:    b.Metadata,
+		},
+		Sections: []briefingExportSection{},
+		Articles: make([]briefingExportArticle, 0, len(articles)),
+	}
+
+	used := make(map[string]bool)
+	for _, a := range articles {
+		if a.SectionID != nil {
+			used[*a.SectionID] = true
+		}
+		out.Articles = append(out.Articles, briefingExportArticle{
+			ID:             a.ID,
+			SourceType:     a.SourceType,
+			SourceID:       a.SourceID,
+			SectionID:      a.SectionID,
+			URL:            a.URL,
+			Title:          a.Title,
+			Content:        a.Content,
+			Summary:        a.Summary,
+			Author:         a.Author,
+			PublishedAt:    a.PublishedAt,
+			IngestedAt:     a.IngestedAt,
+			ProcessedAt:    a.ProcessedAt,
+			RelevanceScore: a.RelevanceScore,
+			Categories:     a.Categories,
+			Status:         a.Status,
+			Metadata:       a.Metadata,
+			Source: articleSourceResponse{
+				Type: a.SourceType,
+				ID:   a.SourceID,
+				Name: a.SourceName,
+				Ref:  a.SourceRef,
+			},
+		})
+	}
+	for _, sec := range sections {
+		if !used[sec.ID] {
+			continue
+		}
+		out.Sections = append(out.Sections, briefingExportSection{
+			ID:          sec.ID,
+			Name:        sec.Name,
+			DisplayName: sec.DisplayName,
+			SortOrder:   sec.SortOrder,
+		})
+	}
+	return out
+}
+
 func createFeedbackHandler(db *store.Store, recalc *profile.Recalculator, cfg *config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req struct {
@@ -914,24 +3122,24 @@ func createFeedbackHandler(db *store.Store, recalc *profile.Recalculator, cfg *c
 			Action    string `json:"action"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "invalid request body")
 			return
 		}
 
 		req.ArticleID = strings.TrimSpace(req.ArticleID)
 		req.Action = strings.TrimSpace(strings.ToLower(req.Action))
 		if req.ArticleID == "" || !validFeedbackAction(req.Action) {
-			http.Error(w, "article_id and action (like|dislike|save) are required", http.StatusBadRequest)
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "article_id and action (like|dislike|save) are required")
 			return
 		}
 
 		article, err := db.GetArticleByID(r.Context(), req.ArticleID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if article == nil {
-			http.Error(w, "article not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "article not found")
 			return
 		}
 
@@ -940,7 +3148,7 @@ func createFeedbackHandler(db *store.Store, recalc *profile.Recalculator, cfg *c
 			Action:    req.Action,
 		}
 		if err := db.CreateFeedback(r.Context(), fb); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 
@@ -964,16 +3172,114 @@ func createFeedbackHandler(db *store.Store, recalc *profile.Recalculator, cfg *c
 	}
 }
 
+// Page sizes for GET /api/feedback; exports are not capped.
+const (
+	defaultFeedbackListLimit = 100
+	maxFeedbackListLimit     = 1000
+)
+
+// feedbackLister is the slice of store.Store the feedback history needs.
+type feedbackLister interface {
+	ListFeedback(ctx context.Context, q store.FeedbackListQuery) ([]store.FeedbackRecord, error)
+}
+
+// listFeedbackHandler serves the feedback history. With export set it
+// returns every matching row as a download, in CSV when the path ends in
+// .csv or the client accepts text/csv and in JSON otherwise.
+func listFeedbackHandler(db feedbackLister, export bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := store.FeedbackListQuery{}
+		if articleID := strings.TrimSpace(query.Get("article_id")); articleID != "" {
+			filter.ArticleID = &articleID
+		}
+		if section := strings.TrimSpace(query.Get("section")); section != "" {
+			filter.SectionName = &section
+		}
+		if action := strings.TrimSpace(query.Get("action")); action != "" {
+			if !validFeedbackAction(action) {
+				respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "action must be like, dislike or save")
+				return
+			}
+			filter.Action = &action
+		}
+		var err error
+		filter.From, filter.To, err = parseTimeRange(query)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, err.Error())
+			return
+		}
+		if !export {
+			filter.Limit = parsePositiveInt(query.Get("limit"), defaultFeedbackListLimit)
+			if filter.Limit > maxFeedbackListLimit {
+				filter.Limit = maxFeedbackListLimit
+			}
+		}
+
+		records, err := db.ListFeedback(r.Context(), filter)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		if !export {
+			respondJSON(w, records)
+			return
+		}
+
+		filename := "flux-feedback-" + time.Now().UTC().Format("2006-01-02")
+		if strings.HasSuffix(r.URL.Path, ".csv") || strings.Contains(r.Header.Get("Accept"), "text/csv") {
+			w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+			if err := writeFeedbackCSV(w, records); err != nil {
+				log.WithError(err).Warn("Failed to write feedback CSV export")
+			}
+			return
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+		respondJSON(w, records)
+	}
+}
+
+// writeFeedbackCSV writes feedback records as CSV with a header row. Text
+// from feeds goes through csvCell so it cannot become a spreadsheet formula.
+func writeFeedbackCSV(w io.Writer, records []store.FeedbackRecord) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"id", "article_id", "action", "created_at", "section", "article_title", "article_url"}); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		section := ""
+		if rec.SectionName != nil {
+			section = *rec.SectionName
+		}
+		row := []string{rec.ID, rec.ArticleID, rec.Action, rec.CreatedAt.UTC().Format(time.RFC3339), csvCell(section), csvCell(rec.ArticleTitle), csvCell(rec.ArticleURL)}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvCell prefixes values that spreadsheets would evaluate as a formula
+// with a single quote, so they open as plain text.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
 func deleteFeedbackHandler(db *store.Store, recalc *profile.Recalculator, cfg *config.Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		id := chi.URLParam(r, "id")
 		deleted, err := db.DeleteFeedbackByID(r.Context(), id)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 		if deleted == nil {
-			http.Error(w, "not found", http.StatusNotFound)
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
 			return
 		}
 
@@ -981,7 +3287,7 @@ func deleteFeedbackHandler(db *store.Store, recalc *profile.Recalculator, cfg *c
 		if shouldRecalculateAfterFeedback(cfg, deleted.Action) {
 			article, err := db.GetArticleByID(r.Context(), deleted.ArticleID)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				respondFailure(w, r, err)
 				return
 			}
 			if article != nil && article.SectionID != nil {
@@ -1008,7 +3314,7 @@ func feedbackStatsHandler(db *store.Store) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		sections, err := db.ListSections(r.Context())
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			respondFailure(w, r, err)
 			return
 		}
 
@@ -1016,7 +3322,7 @@ func feedbackStatsHandler(db *store.Store) http.HandlerFunc {
 		for _, sec := range sections {
 			likes, dislikes, err := db.CountFeedbackBySection(r.Context(), sec.ID)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				respondFailure(w, r, err)
 				return
 			}
 			stats[sec.Name] = map[string]int{
@@ -1029,6 +3335,84 @@ func feedbackStatsHandler(db *store.Store) http.HandlerFunc {
 	}
 }
 
+type dashboardStatsGetter interface {
+	GetDashboardStats(ctx context.Context) (*store.DashboardStats, error)
+}
+
+type dashboardStatsResponse struct {
+	*store.DashboardStats
+	ArticlesQueuePending *uint64 `json:"articles_queue_pending"`
+}
+
+// dashboardStatsHandler serves the dashboard aggregate, cached for the
+// cache's TTL; the queue depth is always read live.
+func dashboardStatsHandler(db dashboardStatsGetter, q queueDepth, cache *responseCache) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats, err := cachedLoad(r.Context(), cache, cacheKeyStats, db.GetDashboardStats)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		respondJSON(w, dashboardStatsResponse{stats, articlesQueuePending(q)})
+	}
+}
+
+func llmUsageHandler(db *store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		days := parsePositiveInt(r.URL.Query().Get("days"), 30)
+		if days > 365 {
+			days = 365
+		}
+		now := time.Now().UTC()
+		since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -(days - 1))
+
+		daily, err := db.ListLLMUsageByDay(r.Context(), since)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+
+		var totals store.LLMUsageDay
+		for _, d := range daily {
+			totals.Runs += d.Runs
+			totals.Calls += d.Calls
+			totals.PromptTokens += d.PromptTokens
+			totals.CompletionTokens += d.CompletionTokens
+			totals.TotalTokens += d.TotalTokens
+			totals.EstimatedTokens += d.EstimatedTokens
+		}
+		respondJSON(w, map[string]interface{}{
+			"days":  days,
+			"since": since.Format("2006-01-02"),
+			"totals": map[string]int{
+				"runs":              totals.Runs,
+				"calls":             totals.Calls,
+				"prompt_tokens":     totals.PromptTokens,
+				"completion_tokens": totals.CompletionTokens,
+				"total_tokens":      totals.TotalTokens,
+				"estimated_tokens":  totals.EstimatedTokens,
+			},
+			"daily": daily,
+		})
+	}
+}
+
+func listDeadLettersHandler(q *queue.Queue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := parsePositiveInt(r.URL.Query().Get("limit"), 50)
+		if limit > 500 {
+			limit = 500
+		}
+
+		letters, err := q.ListDeadLetters(limit)
+		if err != nil {
+			respondFailure(w, r, err)
+			return
+		}
+		respondJSON(w, letters)
+	}
+}
+
 func shouldRecalculateAfterFeedback(cfg *config.Config, action string) bool {
 	if cfg.ProfileRecalcTrigger != "immediate" {
 		return false
@@ -1064,6 +3448,41 @@ func parseBool(raw string) bool {
 	return b
 }
 
+// parseTimeRange reads the optional ISO 8601 from/to query params shared by
+// the list endpoints.
+func parseTimeRange(query url.Values) (from, to *time.Time, err error) {
+	if raw := strings.TrimSpace(query.Get("from")); raw != "" {
+		t, err := parseISO8601(raw)
+		if err != nil {
+			return nil, nil, errors.New("invalid 'from' datetime (use ISO 8601)")
+		}
+		from = &t
+	}
+	if raw := strings.TrimSpace(query.Get("to")); raw != "" {
+		t, err := parseISO8601(raw)
+		if err != nil {
+			return nil, nil, errors.New("invalid 'to' datetime (use ISO 8601)")
+		}
+		to = &t
+	}
+	return from, to, nil
+}
+
+// parseBriefingScope reads ?scope=full|sections|all, returning fallback when
+// it is absent and "" (any scope) for all.
+func parseBriefingScope(query url.Values, fallback string) (string, error) {
+	switch raw := strings.ToLower(strings.TrimSpace(query.Get("scope"))); raw {
+	case "":
+		return fallback, nil
+	case "all":
+		return "", nil
+	case models.BriefingScopeFull, models.BriefingScopeSections:
+		return raw, nil
+	default:
+		return "", errors.New("scope must be full, sections or all")
+	}
+}
+
 func parseISO8601(raw string) (time.Time, error) {
 	layouts := []string{
 		time.RFC3339,
@@ -1077,6 +3496,57 @@ func parseISO8601(raw string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("invalid datetime %q", raw)
 }
 
+// Machine-readable error codes returned in {"error":{"code":...}}.
+const (
+	errCodeInvalidRequest = "invalid_request"
+	errCodeNotFound       = "not_found"
+	errCodeConflict       = "conflict"
+	errCodeUnauthorized   = "unauthorized"
+	errCodeForbidden      = "forbidden"
+	errCodeRateLimited    = "rate_limited"
+	errCodeInternal       = "internal_error"
+	errCodeUnavailable    = "unavailable"
+)
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// respondError writes {"error":{"code":...,"message":...}} with the given status.
+func respondError(w http.ResponseWriter, statusCode int, code, message string) {
+	respondJSONWithStatus(w, statusCode, map[string]errorBody{
+		"error": {Code: code, Message: message},
+	})
+}
+
+// respondFailure maps err to an error response. Postgres constraint and input
+// errors become conflict/validation responses; anything else is logged and
+// returned as a generic 500 so SQL details never reach the client.
+func respondFailure(w http.ResponseWriter, r *http.Request, err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			respondError(w, http.StatusConflict, errCodeConflict, "resource already exists")
+			return
+		case "23503": // foreign_key_violation
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "referenced resource does not exist")
+			return
+		case "22P02": // invalid_text_representation, e.g. malformed UUID
+			respondError(w, http.StatusBadRequest, errCodeInvalidRequest, "malformed identifier")
+			return
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"request_id": middleware.GetReqID(r.Context()),
+	}).WithError(err).Error("API request failed")
+	respondError(w, http.StatusInternalServerError, errCodeInternal, "internal server error")
+}
+
 func respondJSON(w http.ResponseWriter, data interface{}) {
 	respondJSONWithStatus(w, http.StatusOK, data)
 }
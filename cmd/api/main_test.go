@@ -0,0 +1,1735 @@
+package main
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/config"
+	"github.com/zyrak/flux/internal/dedup"
+	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/queue"
+	"github.com/zyrak/flux/internal/ratelimit"
+	"github.com/zyrak/flux/internal/relevance"
+	"github.com/zyrak/flux/internal/store"
+)
+
+func TestBearerAuthMiddleware(t *testing.T) {
+	var gotLabel string
+	handler := bearerAuthMiddleware(map[string]config.APIToken{
+		"laptop": {Token: "token-a", Scope: config.ScopeAdmin},
+		"phone":  {Token: "token-b", Scope: config.ScopeRead},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotLabel = authLabel(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name      string
+		header    string
+		wantCode  int
+		wantLabel string
+	}{
+		{"first token", "Bearer token-a", http.StatusNoContent, "laptop"},
+		{"second token", "Bearer token-b", http.StatusNoContent, "phone"},
+		{"unknown token", "Bearer token-c", http.StatusUnauthorized, ""},
+		{"missing header", "", http.StatusUnauthorized, ""},
+		{"wrong scheme", "Basic token-a", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLabel = ""
+			req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantLabel, gotLabel)
+		})
+	}
+}
+
+func TestBearerAuthMiddlewareDisabled(t *testing.T) {
+	handler := bearerAuthMiddleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	compress, err := compressMiddleware(5)
+	require.NoError(t, err)
+
+	articles := make([]articleResponse, 50)
+	for i := range articles {
+		content := strings.Repeat("Kubernetes release notes and CVE details. ", 40)
+		articles[i] = articleResponse{ID: fmt.Sprintf("a%d", i), Title: "Article", Content: &content, Status: models.StatusPending}
+	}
+	router := chi.NewRouter()
+	router.Use(compress)
+	router.Get("/api/articles", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, map[string]any{"data": articles})
+	})
+	router.Get("/api/precompressed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write([]byte("already gzipped"))
+	})
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	plain := get("/api/articles", "")
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	var want map[string]any
+	require.NoError(t, json.Unmarshal(plain.Body.Bytes(), &want))
+
+	for encoding, newReader := range map[string]func(io.Reader) (io.Reader, error){
+		"gzip":    func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"deflate": func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil },
+	} {
+		rec := get("/api/articles", encoding)
+		assert.Equal(t, encoding, rec.Header().Get("Content-Encoding"))
+		assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Less(t, rec.Body.Len(), plain.Body.Len()/4, encoding)
+
+		zr, err := newReader(rec.Body)
+		require.NoError(t, err)
+		raw, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(raw, &got), encoding)
+		assert.Equal(t, want, got, encoding)
+	}
+
+	rec := get("/api/precompressed", "gzip")
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "already gzipped", rec.Body.String(), "an encoded response is not compressed again")
+}
+
+func TestCompressMiddlewareLevel(t *testing.T) {
+	disabled, err := compressMiddleware(0)
+	require.NoError(t, err)
+	handler := disabled(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, map[string]string{"status": "ok"})
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/stats", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	for _, level := range []int{-1, 10} {
+		_, err := compressMiddleware(level)
+		assert.Error(t, err, "level %d", level)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	cors, err := corsMiddleware([]string{"https://app.example.com/"}, []string{"GET", "POST", "PATCH", "DELETE"}, []string{"Authorization", "Content-Type"}, true)
+	require.NoError(t, err)
+	router := chi.NewRouter()
+	router.Route("/api", apiRoutes(apiDeps{
+		cfg:  &config.Config{AuthTokens: map[string]config.APIToken{"ui": {Token: "secret", Scope: config.ScopeAdmin}}},
+		cors: cors,
+	}))
+
+	preflight := func(origin, method string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/api/sources", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Preflights carry no token, so they must not reach auth.
+	rec := preflight("https://app.example.com", http.MethodPost)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "GET, POST, PATCH, DELETE", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "Authorization, Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, corsMaxAge, rec.Header().Get("Access-Control-Max-Age"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+
+	for name, rec := range map[string]*httptest.ResponseRecorder{
+		"unknown origin":    preflight("https://evil.example.com", http.MethodPost),
+		"disallowed method": preflight("https://app.example.com", http.MethodPut),
+	} {
+		assert.Equal(t, http.StatusNoContent, rec.Code, name)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), name)
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Methods"), name)
+	}
+
+	// Actual requests still need the token; errors carry the CORS headers so
+	// the page can read them.
+	req := httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, corsExposedHeaders, rec.Header().Get("Access-Control-Expose-Headers"))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"), "same-origin requests get no CORS headers")
+}
+
+func TestCORSMiddlewareConfig(t *testing.T) {
+	_, err := corsMiddleware([]string{"*"}, []string{"GET"}, nil, true)
+	assert.Error(t, err, "any origin with credentials")
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+	preflight := httptest.NewRequest(http.MethodOptions, "/api/articles", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	anyOrigin, err := corsMiddleware([]string{"*"}, []string{"GET"}, nil, false)
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	anyOrigin(next).ServeHTTP(rec, preflight)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Headers"))
+
+	disabled, err := corsMiddleware(nil, []string{"GET"}, nil, false)
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	disabled(next).ServeHTTP(rec, preflight)
+	assert.Equal(t, http.StatusTeapot, rec.Code, "without origins preflights reach the router")
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestReadOnlyTokenCannotMutate(t *testing.T) {
+	router := chi.NewRouter()
+	router.Route("/api", apiRoutes(apiDeps{
+		cfg: &config.Config{AuthTokens: map[string]config.APIToken{
+			"dashboard": {Token: "ro-token", Scope: config.ScopeRead},
+			"owner":     {Token: "rw-token", Scope: config.ScopeAdmin},
+		}},
+	}))
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		token    string
+		wantCode int
+	}{
+		{"read token cannot create sources", http.MethodPost, "/api/sources", `{}`, "ro-token", http.StatusForbidden},
+		{"read token cannot edit sections", http.MethodPatch, "/api/sections/abc", `{}`, "ro-token", http.StatusForbidden},
+		{"read token cannot delete feedback", http.MethodDelete, "/api/feedback/abc", "", "ro-token", http.StatusForbidden},
+		{"read token cannot write notes", http.MethodPatch, "/api/articles/abc/note", `{"text":"x"}`, "ro-token", http.StatusForbidden},
+		{"read token cannot trigger briefings", http.MethodPost, "/api/briefings/generate", "", "ro-token", http.StatusForbidden},
+		{"read token reads the spec", http.MethodGet, "/api/openapi.json", "", "ro-token", http.StatusOK},
+		{"read token uses tools", http.MethodPost, "/api/tools/normalize-url", `{"url":"https://example.com/?utm_source=x"}`, "ro-token", http.StatusOK},
+		{"admin token uses tools", http.MethodPost, "/api/tools/normalize-url", `{"url":"https://example.com/"}`, "rw-token", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+func TestRespondFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{"unique violation", &pgconn.PgError{Code: "23505"}, http.StatusConflict, errCodeConflict},
+		{"foreign key", fmt.Errorf("creating source: %w", &pgconn.PgError{Code: "23503"}), http.StatusBadRequest, errCodeInvalidRequest},
+		{"malformed uuid", &pgconn.PgError{Code: "22P02"}, http.StatusBadRequest, errCodeInvalidRequest},
+		{"internal", errors.New(`relation "articles" does not exist`), http.StatusInternalServerError, errCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			respondFailure(rec, httptest.NewRequest(http.MethodGet, "/api/articles", nil), tt.err)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var body map[string]errorBody
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantBody, body["error"].Code)
+			assert.NotContains(t, body["error"].Message, "relation")
+		})
+	}
+}
+
+func TestAPIRateLimitMiddleware(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	limiter, err := ratelimit.NewRequestLimiter(rdb, "2/min")
+	require.NoError(t, err)
+
+	handler := apiRateLimitMiddleware(limiter, rateLimitKeyIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+		req.RemoteAddr = remoteAddr
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:5678").Code)
+
+	limited := do("10.0.0.1:9999")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, do("10.0.0.2:1234").Code)
+
+	// Unauthenticated requests carry no token key and are left to the IP limit.
+	byToken := apiRateLimitMiddleware(limiter, rateLimitKeyToken)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		byToken.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
+
+func TestAPIRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	limiter, err := ratelimit.NewRequestLimiter(rdb, "2/min")
+	require.NoError(t, err)
+
+	realIP, err := realIPMiddleware([]string{"10.1.0.0/16", "192.168.0.5"})
+	require.NoError(t, err)
+	handler := realIP(apiRateLimitMiddleware(limiter, rateLimitKeyIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	do := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// A direct client rotating X-Forwarded-For still spends its own bucket.
+	codes := []int{do("203.0.113.7:1000", "1.1.1.1"), do("203.0.113.7:1001", "2.2.2.2"), do("203.0.113.7:1002", "3.3.3.3")}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+
+	// Through trusted proxies the nearest untrusted hop is the client, so
+	// a spoofed leftmost entry does not open a new bucket either.
+	codes = []int{
+		do("10.1.2.3:1000", "198.51.100.4"),
+		do("10.1.2.3:1001", "9.9.9.9, 198.51.100.4, 192.168.0.5"),
+		do("10.1.2.3:1002", "8.8.8.8, 198.51.100.4"),
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, http.StatusOK, do("10.1.2.3:1003", "198.51.100.5"))
+
+	_, err = realIPMiddleware([]string{"not-an-ip"})
+	assert.EqualError(t, err, `invalid trusted proxy "not-an-ip"`)
+}
+
+func TestAPIRateLimitRunsBeforeAuth(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	limiter, err := ratelimit.NewRequestLimiter(rdb, "2/min")
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Route("/api", apiRoutes(apiDeps{
+		cfg:     &config.Config{AuthTokens: map[string]config.APIToken{"owner": {Token: "s3cret", Scope: config.ScopeAdmin}}},
+		limiter: limiter,
+	}))
+
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+		req.RemoteAddr = "10.0.0.9:1234"
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+}
+
+func TestNormalizeURLHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/tools/normalize-url",
+		strings.NewReader(`{"url":"https://www.Example.com/post/?utm_source=x&b=2&a=1"}`))
+	rec := httptest.NewRecorder()
+	normalizeURLHandler()(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "https://example.com/post?a=1&b=2", body["normalized"])
+	assert.Equal(t, dedup.HashURL("https://example.com/post?a=1&b=2"), body["hash"])
+
+	rec = httptest.NewRecorder()
+	normalizeURLHandler()(rec, httptest.NewRequest(http.MethodPost, "/api/tools/normalize-url", strings.NewReader(`{}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestValidateCronHandler(t *testing.T) {
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		validateCronHandler()(rec, httptest.NewRequest(http.MethodPost, "/api/tools/validate-cron", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"schedule":" 0 3 * * * "}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Valid    bool     `json:"valid"`
+		Schedule string   `json:"schedule"`
+		NextRuns []string `json:"next_runs"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.True(t, body.Valid)
+	assert.Equal(t, "0 3 * * *", body.Schedule)
+	require.Len(t, body.NextRuns, cronPreviewRuns)
+	var prev time.Time
+	for _, raw := range body.NextRuns {
+		run, err := time.Parse(time.RFC3339, raw)
+		require.NoError(t, err)
+		assert.Equal(t, time.UTC, run.Location())
+		assert.Equal(t, 3, run.Hour())
+		assert.True(t, run.After(prev))
+		prev = run
+	}
+
+	assert.Equal(t, http.StatusOK, post(`{"schedule":"@hourly"}`).Code)
+	for _, bad := range []string{`{}`, `{"schedule":"0 3 * *"}`, `{"schedule":"61 * * * *"}`, `{"schedule":`} {
+		assert.Equal(t, http.StatusBadRequest, post(bad).Code, bad)
+	}
+}
+
+func TestSplitBriefingFormat(t *testing.T) {
+	cases := map[string][2]string{
+		"abc":      {"abc", briefingFormatJSON},
+		"abc.md":   {"abc", briefingFormatMarkdown},
+		"abc.html": {"abc", briefingFormatHTML},
+		"abc.pdf":  {"abc.pdf", briefingFormatJSON},
+	}
+	for in, want := range cases {
+		id, format := splitBriefingFormat(in)
+		assert.Equal(t, want[0], id, in)
+		assert.Equal(t, want[1], format, in)
+	}
+}
+
+func TestGetBriefingRejectsUnknownChatFormat(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/api/briefings/{id}", getBriefingHandler(nil, "en"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/briefings/abc?format=teams", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestBriefingPageLang(t *testing.T) {
+	assert.Equal(t, "en", briefingPageLang(""))
+	assert.Equal(t, "es", briefingPageLang("es-ES"))
+	assert.Equal(t, "fr", briefingPageLang("FR"))
+}
+
+func TestRenderBriefingHTML(t *testing.T) {
+	page, err := renderBriefingHTML(&models.Briefing{
+		GeneratedAt: time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC),
+		Content:     "# Daily\n\n- [Go 1.24](https://go.dev) is out\n\n<script>alert(1)</script>\n",
+	}, "es")
+	require.NoError(t, err)
+
+	html := string(page)
+	assert.Contains(t, html, `<html lang="es">`)
+	assert.Contains(t, html, "<h1>Daily</h1>")
+	assert.Contains(t, html, `<a href="https://go.dev">Go 1.24</a>`)
+	assert.Contains(t, html, "2026-03-02 07:00 UTC")
+	assert.NotContains(t, html, "<script>alert(1)</script>")
+}
+
+type recordingPublisher struct {
+	subject string
+	data    interface{}
+	err     error
+}
+
+func (p *recordingPublisher) Publish(subject string, data interface{}) error {
+	p.subject = subject
+	p.data = data
+	return p.err
+}
+
+func daemonAlive(alive bool, err error) func(context.Context) (bool, error) {
+	return func(context.Context) (bool, error) { return alive, err }
+}
+
+func TestGenerateBriefingHandler(t *testing.T) {
+	pub := &recordingPublisher{}
+	rec := httptest.NewRecorder()
+	generateBriefingHandler(pub, daemonAlive(true, nil)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/briefings/generate", nil))
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Len(t, body["request_id"], 32)
+	assert.Equal(t, "queued", body["status"])
+
+	assert.Equal(t, queue.SubjectBriefingGenerate, pub.subject)
+	evt, ok := pub.data.(queue.BriefingGenerateEvent)
+	require.True(t, ok)
+	assert.Equal(t, body["request_id"], evt.RequestID)
+
+	pub.err = errors.New("nats down")
+	rec = httptest.NewRecorder()
+	generateBriefingHandler(pub, daemonAlive(true, nil)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/briefings/generate", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestGenerateBriefingHandlerWithoutDaemon(t *testing.T) {
+	pub := &recordingPublisher{}
+	rec := httptest.NewRecorder()
+	generateBriefingHandler(pub, daemonAlive(false, nil)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/briefings/generate", nil))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Empty(t, pub.subject, "nothing is published without a consumer")
+
+	rec = httptest.NewRecorder()
+	generateBriefingHandler(pub, daemonAlive(false, errors.New("redis down"))).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/briefings/generate", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code, "an unreadable heartbeat does not refuse the request")
+	assert.Equal(t, queue.SubjectBriefingGenerate, pub.subject)
+}
+
+func TestRunHealthProbes(t *testing.T) {
+	calls := 0
+	failing := func(context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	}
+	ok := func(context.Context) error { return nil }
+
+	assert.Nil(t, newHealthProbe("embeddings", "off", ok))
+	assert.Nil(t, newHealthProbe("embeddings", "bogus", ok))
+
+	services := map[string]string{}
+	soft := []*healthProbe{newHealthProbe("embeddings", "soft", failing), newHealthProbe("llm", "hard", ok)}
+	assert.True(t, runHealthProbes(context.Background(), soft, services), "soft failures keep the API healthy")
+	assert.Equal(t, "error: connection refused", services["embeddings"])
+	assert.Equal(t, "ok", services["llm"])
+
+	// Results are cached between liveness probes.
+	runHealthProbes(context.Background(), soft, services)
+	assert.Equal(t, 1, calls)
+
+	hard := []*healthProbe{newHealthProbe("embeddings", "hard", failing)}
+	assert.False(t, runHealthProbes(context.Background(), hard, map[string]string{}))
+}
+
+type fakeQueueDepth struct {
+	pending uint64
+	err     error
+}
+
+func (f fakeQueueDepth) ArticlesPending() (uint64, error) { return f.pending, f.err }
+
+func TestArticlesQueuePending(t *testing.T) {
+	pending := articlesQueuePending(fakeQueueDepth{pending: 42})
+	require.NotNil(t, pending)
+	assert.Equal(t, uint64(42), *pending)
+
+	assert.Nil(t, articlesQueuePending(fakeQueueDepth{err: errors.New("nats: timeout")}))
+	assert.Nil(t, articlesQueuePending(nil))
+}
+
+func TestLivezHandler(t *testing.T) {
+	var shuttingDown atomic.Bool
+	handler := livezHandler(&shuttingDown)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	shuttingDown.Store(true)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "shutting_down")
+}
+
+func TestConsecutiveFetchFailures(t *testing.T) {
+	failed := "status 503"
+	runs := []store.SourceFetchRun{{Error: &failed}, {Error: &failed}, {}, {Error: &failed}}
+	assert.Equal(t, 2, consecutiveFetchFailures(runs))
+	assert.Equal(t, 0, consecutiveFetchFailures(runs[2:]))
+	assert.Equal(t, 0, consecutiveFetchFailures(nil))
+}
+
+func TestParseStatusFilter(t *testing.T) {
+	statuses, err := parseStatusFilter(" pending, processed ,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pending", "processed"}, statuses)
+
+	statuses, err = parseStatusFilter("archived")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"archived"}, statuses)
+
+	statuses, err = parseStatusFilter("")
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+
+	_, err = parseStatusFilter("pending,deleted")
+	assert.ErrorContains(t, err, `"deleted"`)
+}
+
+func TestParseArticleSort(t *testing.T) {
+	field, asc, err := parseArticleSort("")
+	require.NoError(t, err)
+	assert.Equal(t, "ingested_at", field)
+	assert.False(t, asc)
+
+	field, asc, err = parseArticleSort("Published_At:ASC")
+	require.NoError(t, err)
+	assert.Equal(t, "published_at", field)
+	assert.True(t, asc)
+
+	field, asc, err = parseArticleSort("relevance_score")
+	require.NoError(t, err)
+	assert.Equal(t, "relevance_score", field)
+	assert.False(t, asc)
+
+	_, _, err = parseArticleSort("title")
+	assert.Error(t, err)
+	_, _, err = parseArticleSort("ingested_at:sideways")
+	assert.Error(t, err)
+	_, _, err = parseArticleSort("ingested_at; DROP TABLE articles")
+	assert.Error(t, err)
+}
+
+func TestBuildBriefingExport(t *testing.T) {
+	generated := time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC)
+	tech := "sec-tech"
+	b := &models.Briefing{ID: "b1", GeneratedAt: generated, Content: "## Tech", ArticleIDs: []string{"a1"}}
+	articles := []*store.ArticleWithRelations{{
+		Article:    models.Article{ID: "a1", SectionID: &tech, Title: "Go 1.24", Status: models.StatusBriefed},
+		SourceName: "Go Blog",
+		LikeCount:  3,
+		Liked:      true,
+	}}
+	sections := []*models.Section{
+		{ID: "sec-tech", Name: "tech", DisplayName: "Tech", SortOrder: 2},
+		{ID: "sec-world", Name: "world", DisplayName: "World", SortOrder: 1},
+	}
+
+	export := buildBriefingExport(b, articles, sections, generated.Add(time.Hour))
+	assert.Equal(t, 1, export.SchemaVersion)
+	assert.Equal(t, "b1", export.Briefing.ID)
+	require.Len(t, export.Sections, 1)
+	assert.Equal(t, "tech", export.Sections[0].Name)
+	require.Len(t, export.Articles, 1)
+	assert.Equal(t, "Go Blog", export.Articles[0].Source.Name)
+
+	// Per-user feedback state stays out of the archive.
+	raw, err := json.Marshal(export)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "liked")
+	assert.NotContains(t, string(raw), "feedback")
+}
+
+func newTestSourceValidator(t *testing.T) *sourceValidator {
+	t.Helper()
+	v, err := newSourceValidator("FluxTest/1.0", "")
+	require.NoError(t, err)
+	return v
+}
+
+func TestSourceValidator(t *testing.T) {
+	var gotUserAgent, gotAuth, gotRedditAuth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/r/") {
+			gotRedditAuth = r.Header.Get("Authorization")
+		}
+		switch r.URL.Path {
+		case "/api/v1/access_token":
+			if user, pass, _ := r.BasicAuth(); user != "client" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"reddit-token","expires_in":3600}`))
+		case "/feed.xml":
+			gotUserAgent = r.Header.Get("User-Agent")
+			w.Header().Set("Content-Type", "application/rss+xml")
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Blog</title>
+<item><title>First post</title><link>https://example.com/1</link></item>
+<item><title> </title><link>https://example.com/2</link></item>
+<item><title>Second post</title><link>https://example.com/3</link></item>
+</channel></rss>`))
+		case "/r/golang/hot.json":
+			_, _ = w.Write([]byte(`{"data":{"children":[{"data":{"title":"Go 1.30 released"}},{"data":{"title":"Generics tips"}}]}}`))
+		case "/r/private/hot.json":
+			w.WriteHeader(http.StatusForbidden)
+		case "/r/busy/hot.json", "/repos/acme/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/down.xml":
+			w.WriteHeader(http.StatusBadGateway)
+		case "/repos/acme/tool":
+			gotAuth = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"full_name":"acme/tool"}`))
+		case "/repos/acme/tool/releases":
+			_, _ = w.Write([]byte(`[{"name":"","tag_name":"v1.2.0"},{"name":"Spring release","tag_name":"v1.1.0"}]`))
+		case "/repos/acme/tool/commits":
+			_, _ = w.Write([]byte(`[{"commit":{"message":"Fix crash on empty feed\n\nLong description"}}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	v := newTestSourceValidator(t)
+	v.redditAuthURL = upstream.URL + "/api/v1/access_token"
+	v.redditBase = upstream.URL
+	v.githubBase = upstream.URL
+	ctx := context.Background()
+
+	t.Run("rss", func(t *testing.T) {
+		got := v.Validate(ctx, "rss", json.RawMessage(`{"url":"`+upstream.URL+`/feed.xml"}`))
+		assert.True(t, got.Valid, got.Details)
+		assert.Equal(t, []string{"First post", "Second post"}, got.SampleTitles)
+		assert.Equal(t, "FluxTest/1.0", gotUserAgent)
+
+		got = v.Validate(ctx, "rss", json.RawMessage(`{"url":""}`))
+		assert.False(t, got.Valid)
+		assert.Equal(t, "missing config.url", got.Details)
+		assert.NotNil(t, got.SampleTitles)
+
+		got = v.Validate(ctx, "rss", json.RawMessage(`{"url":"`+upstream.URL+`/gone.xml"}`))
+		assert.False(t, got.Valid, "a 404 is a definite answer")
+
+		got = v.Validate(ctx, "rss", json.RawMessage(`{"url":"`+upstream.URL+`/down.xml"}`))
+		assert.True(t, got.Valid, "an upstream outage does not block the save")
+		assert.True(t, got.Unverified)
+		assert.Contains(t, got.Details, "502")
+	})
+
+	t.Run("reddit", func(t *testing.T) {
+		for _, key := range []string{"REDDIT_CLIENT_ID", "REDDIT_CLIENT_SECRET", "REDDIT_USERNAME", "REDDIT_PASSWORD"} {
+			t.Setenv(key, "")
+		}
+		got := v.Validate(ctx, "reddit", json.RawMessage(`{"subreddit":"golang"}`))
+		assert.True(t, got.Valid)
+		assert.True(t, got.Unverified, "missing API credentials say nothing about the subreddit")
+		assert.Contains(t, got.Details, "REDDIT_CLIENT_ID")
+
+		t.Setenv("REDDIT_CLIENT_ID", "client")
+		t.Setenv("REDDIT_CLIENT_SECRET", "secret")
+		t.Setenv("REDDIT_USERNAME", "user")
+		t.Setenv("REDDIT_PASSWORD", "pass")
+		got = v.Validate(ctx, "reddit", json.RawMessage(`{"subreddit":"r/GoLang"}`))
+		assert.True(t, got.Valid, got.Details)
+		assert.False(t, got.Unverified)
+		assert.Equal(t, []string{"Go 1.30 released", "Generics tips"}, got.SampleTitles)
+		assert.Equal(t, "Bearer reddit-token", gotRedditAuth)
+
+		got = v.Validate(ctx, "reddit", json.RawMessage(`{"subreddit":"busy"}`))
+		assert.True(t, got.Valid)
+		assert.True(t, got.Unverified, "a 429 does not block the save")
+
+		got = v.Validate(ctx, "reddit", json.RawMessage(`{"subreddit":"private"}`))
+		assert.False(t, got.Valid)
+		assert.Contains(t, got.Details, "private")
+
+		got = v.Validate(ctx, "reddit", json.RawMessage(`{"subreddit":"doesnotexist"}`))
+		assert.False(t, got.Valid)
+		assert.Contains(t, got.Details, "not found")
+	})
+
+	t.Run("github", func(t *testing.T) {
+		t.Setenv("GITHUB_TOKEN", "gh-token")
+		got := v.Validate(ctx, "github", json.RawMessage(`{"owner":"acme","name":"tool"}`))
+		assert.True(t, got.Valid, got.Details)
+		assert.Equal(t, []string{"v1.2.0", "Spring release"}, got.SampleTitles)
+		assert.Equal(t, "Bearer gh-token", gotAuth)
+
+		got = v.Validate(ctx, "github", json.RawMessage(`{"repo":"acme/tool","mode":"commits"}`))
+		assert.True(t, got.Valid, got.Details)
+		assert.Equal(t, []string{"Fix crash on empty feed"}, got.SampleTitles)
+
+		got = v.Validate(ctx, "github", json.RawMessage(`{"repo":"acme/missing"}`))
+		assert.False(t, got.Valid)
+		assert.Contains(t, got.Details, "status 404")
+
+		got = v.Validate(ctx, "github", json.RawMessage(`{"repo":"acme/busy"}`))
+		assert.True(t, got.Valid)
+		assert.True(t, got.Unverified)
+
+		got = v.Validate(ctx, "github", json.RawMessage(`{"repo":"acme"}`))
+		assert.False(t, got.Valid)
+
+		got = v.Validate(ctx, "github", json.RawMessage(`{"repo":"acme/tool","mode":"issues"}`))
+		assert.False(t, got.Valid)
+	})
+
+	t.Run("hn and unknown types", func(t *testing.T) {
+		got := v.Validate(ctx, "hn", json.RawMessage(`{}`))
+		assert.True(t, got.Valid)
+		assert.Empty(t, got.SampleTitles)
+
+		got = v.Validate(ctx, "mastodon", json.RawMessage(`{}`))
+		assert.False(t, got.Valid)
+		assert.Contains(t, got.Details, "unsupported source_type")
+	})
+}
+
+func TestValidateSourceHandler(t *testing.T) {
+	handler := validateSourceHandler(newTestSourceValidator(t))
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/sources/validate", strings.NewReader(`{"source_type":"hn"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body sourceValidation
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+	assert.True(t, body.Valid)
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/api/sources/validate", strings.NewReader(`{"config":{}}`)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestParseTimeRange(t *testing.T) {
+	from, to, err := parseTimeRange(url.Values{"from": {"2026-10-13"}, "to": {"2026-10-14T06:00:00Z"}})
+	require.NoError(t, err)
+	require.NotNil(t, from)
+	require.NotNil(t, to)
+	assert.Equal(t, time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), *from)
+	assert.Equal(t, time.Date(2026, 10, 14, 6, 0, 0, 0, time.UTC), *to)
+
+	from, to, err = parseTimeRange(url.Values{})
+	require.NoError(t, err)
+	assert.Nil(t, from)
+	assert.Nil(t, to)
+
+	_, _, err = parseTimeRange(url.Values{"to": {"last tuesday"}})
+	assert.EqualError(t, err, "invalid 'to' datetime (use ISO 8601)")
+}
+
+func TestParseBriefingScope(t *testing.T) {
+	scope, err := parseBriefingScope(url.Values{}, models.BriefingScopeFull)
+	require.NoError(t, err)
+	assert.Equal(t, models.BriefingScopeFull, scope)
+
+	scope, err = parseBriefingScope(url.Values{"scope": {"Sections"}}, models.BriefingScopeFull)
+	require.NoError(t, err)
+	assert.Equal(t, models.BriefingScopeSections, scope)
+
+	scope, err = parseBriefingScope(url.Values{"scope": {"all"}}, models.BriefingScopeFull)
+	require.NoError(t, err)
+	assert.Empty(t, scope, "all lifts the filter")
+
+	_, err = parseBriefingScope(url.Values{"scope": {"daily"}}, "")
+	assert.EqualError(t, err, "scope must be full, sections or all")
+}
+
+func TestSourceValidatorUnreachable(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	upstream.Close()
+
+	got := newTestSourceValidator(t).Validate(context.Background(), "rss", json.RawMessage(`{"url":"`+upstream.URL+`/feed.xml"}`))
+	assert.True(t, got.Valid, "a network error does not block the save")
+	assert.True(t, got.Unverified)
+}
+
+func TestNewSourceValidatorUsesProxy(t *testing.T) {
+	v, err := newSourceValidator("FluxTest/1.0", "http://proxy.internal:3128")
+	require.NoError(t, err)
+	proxy, err := v.client.Transport.(*http.Transport).Proxy(httptest.NewRequest(http.MethodGet, "https://www.reddit.com/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.internal:3128", proxy.Host)
+
+	_, err = newSourceValidator("FluxTest/1.0", "not a url")
+	assert.Error(t, err)
+}
+
+func TestSourceValidatorDiscoversFeed(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blog/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head>
+<link rel="alternate" type="application/atom+xml" href="/blog/atom.xml">
+</head><body><h1>Blog</h1></body></html>`))
+		case "/blog/atom.xml":
+			w.Header().Set("Content-Type", "application/atom+xml")
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Blog</title>
+<entry><title>Discovered entry</title><link href="https://example.com/1"/><id>1</id></entry>
+</feed>`))
+		case "/plain/":
+			_, _ = w.Write([]byte(`<html><head><title>No feeds</title></head></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer upstream.Close()
+
+	v := newTestSourceValidator(t)
+	got := v.Validate(context.Background(), "rss", json.RawMessage(`{"url":"`+upstream.URL+`/blog/","title_dedup":true}`))
+	require.True(t, got.Valid, got.Details)
+	assert.Equal(t, []string{"Discovered entry"}, got.SampleTitles)
+	assert.JSONEq(t, `{"url":"`+upstream.URL+`/blog/atom.xml","title_dedup":true}`, string(got.Config))
+
+	got = v.Validate(context.Background(), "rss", json.RawMessage(`{"url":"`+upstream.URL+`/plain/"}`))
+	assert.False(t, got.Valid)
+	assert.Contains(t, got.Details, "no feed links")
+
+	rec := httptest.NewRecorder()
+	validateRSSHandler(v)(rec, httptest.NewRequest(http.MethodPost, "/api/sources/validate-rss",
+		strings.NewReader(`{"url":"`+upstream.URL+`/blog/"}`)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"valid":true,"url":"`+upstream.URL+`/blog/atom.xml"}`, rec.Body.String())
+}
+
+type recordingStatusUpdater struct {
+	ids    []string
+	status string
+}
+
+func (u *recordingStatusUpdater) UpdateArticleStatusBatch(_ context.Context, ids []string, status string) (int64, error) {
+	u.ids = ids
+	u.status = status
+	return int64(len(ids)) - 1, nil
+}
+
+func TestBulkArticleStatusHandler(t *testing.T) {
+	post := func(db articleStatusUpdater, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		bulkArticleStatusHandler(db).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/articles/bulk-status", strings.NewReader(body)))
+		return rec
+	}
+
+	db := &recordingStatusUpdater{}
+	rec := post(db, `{"ids":["a1"," ","a2"],"status":"archived"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"updated":1,"status":"archived"}`, rec.Body.String())
+	assert.Equal(t, []string{"a1", "a2"}, db.ids)
+	assert.Equal(t, models.StatusArchived, db.status)
+
+	tooMany := make([]string, maxBulkStatusIDs+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("%q", fmt.Sprintf("a%d", i))
+	}
+	for name, body := range map[string]string{
+		"malformed":      `{"ids":`,
+		"no ids":         `{"ids":[],"status":"archived"}`,
+		"unknown status": `{"ids":["a1"],"status":"deleted"}`,
+		"pipeline owned": `{"ids":["a1"],"status":"pending"}`,
+		"too many":       `{"ids":[` + strings.Join(tooMany, ",") + `],"status":"archived"}`,
+	} {
+		db := &recordingStatusUpdater{}
+		rec := post(db, body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+		assert.Nil(t, db.ids, name)
+	}
+}
+
+type fakeArticleNotes struct {
+	articles map[string]bool
+	notes    map[string]string
+}
+
+func (f *fakeArticleNotes) GetArticleByID(_ context.Context, id string) (*models.Article, error) {
+	if !f.articles[id] {
+		return nil, nil
+	}
+	return &models.Article{ID: id}, nil
+}
+
+func (f *fakeArticleNotes) SetArticleNote(_ context.Context, id, note string) (*store.ArticleNote, error) {
+	if !f.articles[id] {
+		return nil, nil
+	}
+	f.notes[id] = note
+	at := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	return &store.ArticleNote{ArticleID: id, Note: note, CreatedAt: at, UpdatedAt: at}, nil
+}
+
+func (f *fakeArticleNotes) DeleteArticleNote(_ context.Context, id string) (bool, error) {
+	_, ok := f.notes[id]
+	delete(f.notes, id)
+	return ok, nil
+}
+
+func TestUpdateArticleNoteHandler(t *testing.T) {
+	patch := func(db articleNoteWriter, id, body string) *httptest.ResponseRecorder {
+		r := chi.NewRouter()
+		r.Patch("/api/articles/{id}/note", updateArticleNoteHandler(db))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/api/articles/"+id+"/note", strings.NewReader(body)))
+		return rec
+	}
+
+	db := &fakeArticleNotes{articles: map[string]bool{"a1": true}, notes: map[string]string{}}
+	rec := patch(db, "a1", `{"note":"  compare with last year's CVE  "}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"article_id":"a1","note":{"text":"compare with last year's CVE","created_at":"2026-10-14T09:00:00Z","updated_at":"2026-10-14T09:00:00Z"}}`, rec.Body.String())
+	assert.Equal(t, "compare with last year's CVE", db.notes["a1"])
+
+	rec = patch(db, "a1", `{"note":" "}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"article_id":"a1","note":null}`, rec.Body.String())
+	assert.Empty(t, db.notes)
+
+	assert.Equal(t, http.StatusNotFound, patch(db, "missing", `{"note":"x"}`).Code)
+	assert.Equal(t, http.StatusNotFound, patch(db, "missing", `{"note":""}`).Code)
+
+	for name, body := range map[string]string{
+		"malformed": `{"note":`,
+		"no note":   `{}`,
+		"too long":  `{"note":"` + strings.Repeat("é", maxArticleNoteRunes+1) + `"}`,
+	} {
+		assert.Equal(t, http.StatusBadRequest, patch(db, "a1", body).Code, name)
+	}
+	assert.Empty(t, db.notes)
+}
+
+type fakeArticleGetter map[string]*models.Article
+
+func (f fakeArticleGetter) GetArticleByID(_ context.Context, id string) (*models.Article, error) {
+	return f[id], nil
+}
+
+type fakeExplainer struct{ threshold float64 }
+
+func (f fakeExplainer) ExplainArticle(_ context.Context, article *models.Article, _ []float32) (*relevance.Explanation, error) {
+	return &relevance.Explanation{
+		Result:         relevance.Result{SectionID: "s1", SectionName: "tech", RelevanceScore: 0.6, Threshold: f.threshold, Status: models.StatusPending},
+		PositiveScore:  0.6,
+		PositiveSource: "seed",
+	}, nil
+}
+
+func TestExplainArticleHandler(t *testing.T) {
+	db := fakeArticleGetter{
+		"a1": {ID: "a1", Embedding: []float32{1, 0}, Status: models.StatusPending},
+		"a2": {ID: "a2"},
+	}
+	builds := 0
+	buildErr := errors.New("embeddings unavailable")
+	engines := newCachedEngine(func(context.Context) (articleExplainer, error) {
+		builds++
+		if buildErr != nil {
+			return nil, buildErr
+		}
+		return fakeExplainer{threshold: float64(builds) / 10}, nil
+	}, time.Minute)
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	engines.now = func() time.Time { return now }
+
+	router := chi.NewRouter()
+	router.Get("/api/articles/{id}/relevance", explainArticleHandler(db, engines))
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/articles/"+id+"/relevance", nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("missing").Code)
+	assert.Equal(t, http.StatusConflict, get("a2").Code)
+	assert.Zero(t, builds, "the engine is only built for explainable articles")
+
+	// A failed build is reported and retried on the next request.
+	assert.Equal(t, http.StatusServiceUnavailable, get("a1").Code)
+	buildErr = nil
+	rec := get("a1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, builds)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "tech", body["section_name"])
+	assert.Equal(t, "seed", body["positive_source"])
+	assert.Equal(t, 0.2, body["threshold"])
+
+	// Later requests reuse the engine until it is ttl old.
+	now = now.Add(59 * time.Second)
+	require.Equal(t, http.StatusOK, get("a1").Code)
+	assert.Equal(t, 2, builds)
+
+	now = now.Add(time.Second)
+	rec = get("a1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 3, builds)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, 0.3, body["threshold"])
+}
+
+type recordingSourceToggler struct {
+	sel     *store.SourceSelector
+	enabled bool
+}
+
+func (r *recordingSourceToggler) SetSourcesEnabled(_ context.Context, sel store.SourceSelector, enabled bool) (int64, error) {
+	r.sel, r.enabled = &sel, enabled
+	return 3, nil
+}
+
+func TestBulkToggleSourcesHandler(t *testing.T) {
+	post := func(db sourceToggler, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		bulkToggleSourcesHandler(db).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sources/bulk-toggle", strings.NewReader(body)))
+		return rec
+	}
+
+	db := &recordingSourceToggler{}
+	rec := post(db, `{"source_type":" Reddit ","enabled":false}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"updated":3,"enabled":false}`, rec.Body.String())
+	assert.Equal(t, "reddit", *db.sel.SourceType)
+	assert.Empty(t, db.sel.IDs)
+	assert.False(t, db.enabled)
+
+	db = &recordingSourceToggler{}
+	rec = post(db, `{"ids":["s1"," ","s2"],"enabled":true}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, db.sel.SourceType)
+	assert.Equal(t, []string{"s1", "s2"}, db.sel.IDs)
+	assert.True(t, db.enabled)
+
+	for name, body := range map[string]string{
+		"malformed":   `{"ids":`,
+		"no enabled":  `{"source_type":"reddit"}`,
+		"no selector": `{"ids":[" "],"enabled":false}`,
+	} {
+		db := &recordingSourceToggler{}
+		rec := post(db, body)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, name)
+		assert.Nil(t, db.sel, name)
+	}
+}
+
+type fakeSectionClearer struct {
+	section *models.Section
+	pending int
+	cleared bool
+}
+
+func (f *fakeSectionClearer) GetSectionByID(context.Context, string) (*models.Section, error) {
+	return f.section, nil
+}
+
+func (f *fakeSectionClearer) CountSectionPending(context.Context, string) (int, error) {
+	return f.pending, nil
+}
+
+func (f *fakeSectionClearer) MarkSectionProcessed(context.Context, string) (int, error) {
+	f.cleared = true
+	return f.pending, nil
+}
+
+func TestMarkSectionProcessedHandler(t *testing.T) {
+	post := func(db sectionClearer, query string) *httptest.ResponseRecorder {
+		r := chi.NewRouter()
+		r.Post("/api/sections/{id}/mark-processed", markSectionProcessedHandler(db))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sections/s1/mark-processed"+query, nil))
+		return rec
+	}
+
+	rec := post(&fakeSectionClearer{}, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	db := &fakeSectionClearer{section: &models.Section{ID: "s1"}, pending: 12}
+	rec = post(db, "?dry_run=true")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"section_id":"s1","dry_run":true,"updated":12}`, rec.Body.String())
+	assert.False(t, db.cleared)
+
+	rec = post(db, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"section_id":"s1","dry_run":false,"updated":12}`, rec.Body.String())
+	assert.True(t, db.cleared)
+
+	big := &fakeSectionClearer{section: &models.Section{ID: "s1"}, pending: maxUnconfirmedSectionClear + 1}
+	rec = post(big, "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.False(t, big.cleared)
+	rec = post(big, "?confirm=true")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, big.cleared)
+}
+
+type fakeFeedbackLister struct {
+	query   store.FeedbackListQuery
+	records []store.FeedbackRecord
+}
+
+func (f *fakeFeedbackLister) ListFeedback(_ context.Context, q store.FeedbackListQuery) ([]store.FeedbackRecord, error) {
+	f.query = q
+	return f.records, nil
+}
+
+func TestListFeedbackHandler(t *testing.T) {
+	section := "tech"
+	db := &fakeFeedbackLister{records: []store.FeedbackRecord{{
+		Feedback: models.Feedback{
+			ID:        "f1",
+			ArticleID: "a1",
+			Action:    models.ActionLike,
+			CreatedAt: time.Date(2026, 10, 14, 8, 30, 0, 0, time.UTC),
+		},
+		ArticleTitle: `Go 1.26, "finally"`,
+		ArticleURL:   "https://example.com/go",
+		SectionName:  &section,
+	}}}
+	get := func(export bool, target string, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		listFeedbackHandler(db, export).ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(false, "/api/feedback?article_id=a1&action=like&limit=5000", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "a1", *db.query.ArticleID)
+	assert.Equal(t, models.ActionLike, *db.query.Action)
+	assert.Equal(t, maxFeedbackListLimit, db.query.Limit)
+	assert.Contains(t, rec.Body.String(), `"article_title":"Go 1.26, \"finally\""`)
+	assert.Empty(t, rec.Header().Get("Content-Disposition"))
+
+	rec = get(true, "/api/feedback/export?section=tech", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "tech", *db.query.SectionName)
+	assert.Zero(t, db.query.Limit)
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), ".json")
+
+	wantCSV := "id,article_id,action,created_at,section,article_title,article_url\n" +
+		`f1,a1,like,2026-10-14T08:30:00Z,tech,"Go 1.26, ""finally""",https://example.com/go` + "\n"
+	for _, tc := range []struct{ target, accept string }{
+		{"/api/feedback/export.csv", ""},
+		{"/api/feedback/export", "text/csv"},
+	} {
+		rec = get(true, tc.target, tc.accept)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, wantCSV, rec.Body.String())
+	}
+
+	rec = get(false, "/api/feedback?action=shrug", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestWriteFeedbackCSVNeutralisesFormulas(t *testing.T) {
+	var buf strings.Builder
+	require.NoError(t, writeFeedbackCSV(&buf, []store.FeedbackRecord{{
+		Feedback:     models.Feedback{ID: "f1", ArticleID: "a1", Action: models.ActionLike},
+		ArticleTitle: `=HYPERLINK("https://evil.example","click")`,
+		ArticleURL:   "@SUM(1+1)",
+	}}))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"'=HYPERLINK(""https://evil.example"",""click"")"`)
+	assert.True(t, strings.HasSuffix(lines[1], ",'@SUM(1+1)"))
+
+	for _, v := range []string{"+1", "-1", "\tx", "\rx"} {
+		assert.Equal(t, "'"+v, csvCell(v))
+	}
+	assert.Equal(t, "Go 1.26", csvCell("Go 1.26"))
+	assert.Empty(t, csvCell(""))
+}
+
+type fakeProfileResetter struct {
+	section         *models.Section
+	hasProfile      bool
+	feedback        int64
+	feedbackCleared bool
+}
+
+func (f *fakeProfileResetter) GetSectionByID(context.Context, string) (*models.Section, error) {
+	return f.section, nil
+}
+
+func (f *fakeProfileResetter) DeleteSectionProfile(context.Context, string) (bool, error) {
+	existed := f.hasProfile
+	f.hasProfile = false
+	return existed, nil
+}
+
+func (f *fakeProfileResetter) DeleteSectionTrainingFeedback(context.Context, string) (int64, error) {
+	f.feedbackCleared = true
+	return f.feedback, nil
+}
+
+func TestResetSectionProfileHandler(t *testing.T) {
+	post := func(db sectionProfileResetter, query string) *httptest.ResponseRecorder {
+		r := chi.NewRouter()
+		r.Post("/api/sections/{id}/reset-profile", resetSectionProfileHandler(db))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/sections/s1/reset-profile"+query, nil))
+		return rec
+	}
+
+	rec := post(&fakeProfileResetter{}, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	db := &fakeProfileResetter{section: &models.Section{ID: "s1"}, hasProfile: true, feedback: 7}
+	rec = post(db, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"section_id":"s1","profile_deleted":true,"feedback_deleted":0}`, rec.Body.String())
+	assert.False(t, db.feedbackCleared)
+
+	rec = post(db, "?clear_feedback=true")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"section_id":"s1","profile_deleted":false,"feedback_deleted":7}`, rec.Body.String())
+	assert.True(t, db.feedbackCleared)
+}
+
+type fakeScoreHistogramGetter struct {
+	buckets          int
+	defaultThreshold float64
+	hist             *store.SectionScoreHistogram
+}
+
+func (f *fakeScoreHistogramGetter) SectionScoreHistogram(_ context.Context, _ string, buckets int, defaultThreshold float64) (*store.SectionScoreHistogram, error) {
+	f.buckets = buckets
+	f.defaultThreshold = defaultThreshold
+	return f.hist, nil
+}
+
+func TestSectionScoreHistogramHandler(t *testing.T) {
+	cfg := &config.Config{RelevanceThresholdDefault: 0.3}
+	get := func(db sectionScoreHistogramGetter, query string) *httptest.ResponseRecorder {
+		r := chi.NewRouter()
+		r.Get("/api/sections/{id}/score-histogram", sectionScoreHistogramHandler(db, cfg))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/sections/s1/score-histogram"+query, nil))
+		return rec
+	}
+
+	rec := get(&fakeScoreHistogramGetter{}, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	db := &fakeScoreHistogramGetter{hist: &store.SectionScoreHistogram{
+		SectionID: "s1",
+		Threshold: 0.3,
+		Buckets:   []store.ScoreHistogramBucket{{Min: 0, Max: 0.5}, {Min: 0.5, Max: 1}},
+	}}
+	rec = get(db, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, defaultScoreHistogramBuckets, db.buckets)
+	assert.Equal(t, 0.3, db.defaultThreshold)
+	assert.JSONEq(t, `{"section_id":"s1","threshold":0.3,"threshold_locked":false,"total":0,"above_threshold":0,
+		"buckets":[{"min":0,"max":0.5,"count":0},{"min":0.5,"max":1,"count":0}]}`, rec.Body.String())
+
+	get(db, "?buckets=10")
+	assert.Equal(t, 10, db.buckets)
+	get(db, "?buckets=5000")
+	assert.Equal(t, maxScoreHistogramBuckets, db.buckets)
+	get(db, "?buckets=-1")
+	assert.Equal(t, defaultScoreHistogramBuckets, db.buckets)
+}
+
+type fakeClusteredArticleLister struct {
+	query    store.ArticleListQuery
+	clusters []store.ArticleCluster
+}
+
+func (f *fakeClusteredArticleLister) ListClusteredArticles(_ context.Context, q store.ArticleListQuery) ([]store.ArticleCluster, int, error) {
+	f.query = q
+	return f.clusters, len(f.clusters), nil
+}
+
+func TestListClusteredArticlesHandler(t *testing.T) {
+	now := time.Date(2026, 10, 14, 8, 0, 0, 0, time.UTC)
+	member := func(id, sourceType, meta string) *store.ArticleWithRelations {
+		return &store.ArticleWithRelations{Article: models.Article{
+			ID: id, SourceType: sourceType, Title: id, IngestedAt: now, Metadata: json.RawMessage(meta),
+		}}
+	}
+	db := &fakeClusteredArticleLister{clusters: []store.ArticleCluster{
+		{ClusterID: "c1", Articles: []*store.ArticleWithRelations{
+			member("a1", "reddit", `{"cluster_id":"c1","reddit_score":40,"subreddit":"golang"}`),
+			member("a2", "hn", `{"cluster_id":"c1","hn_score":300}`),
+		}},
+		{ClusterID: "a3", Articles: []*store.ArticleWithRelations{member("a3", "rss", `{"source_name":"LWN"}`)}},
+	}}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/clustered?section=tech&per_page=10&lang=EN,es", nil)
+	rec := httptest.NewRecorder()
+	listClusteredArticlesHandler(db).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "tech", *db.query.SectionName)
+	assert.Equal(t, 10, db.query.Limit)
+	assert.Equal(t, []string{"en", "es"}, db.query.Langs)
+
+	var body struct {
+		Data  []articleClusterResponse `json:"data"`
+		Total int                      `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2)
+	assert.Equal(t, 2, body.Total)
+
+	assert.Equal(t, "a2", body.Data[0].Article.ID)
+	assert.Equal(t, []string{"HN", "r/golang"}, body.Data[0].SeenIn)
+	assert.Equal(t, []string{"HN (300 pts)", "Reddit r/golang (40 pts)"}, body.Data[0].ReportedBy)
+	require.Len(t, body.Data[0].Duplicates, 1)
+	assert.Equal(t, "a1", body.Data[0].Duplicates[0].ID)
+
+	assert.Equal(t, "a3", body.Data[1].Article.ID)
+	assert.Equal(t, []string{"LWN"}, body.Data[1].SeenIn)
+	assert.Empty(t, body.Data[1].Duplicates)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/articles/clustered?hide_disliked=true&disliked_only=true", nil)
+	rec = httptest.NewRecorder()
+	listClusteredArticlesHandler(db).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+type countingSectionLister struct {
+	calls    int
+	sections []*store.SectionStats
+}
+
+func (c *countingSectionLister) ListSectionsWithStats(context.Context) ([]*store.SectionStats, error) {
+	c.calls++
+	return c.sections, nil
+}
+
+func TestListSectionsHandlerCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	cache := newResponseCache(rdb, time.Minute)
+	db := &countingSectionLister{sections: []*store.SectionStats{{
+		Section:      models.Section{ID: "s1", Name: "tech", DisplayName: "Tech"},
+		ArticleCount: 3,
+	}}}
+	cfg := &config.Config{RelevanceThresholdDefault: 0.3}
+
+	router := chi.NewRouter()
+	router.Get("/api/sections", listSectionsHandler(db, cfg, cache))
+	router.With(cache.invalidates(cacheKeySections, cacheKeyStats)).Patch("/api/sections/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "missing" {
+			respondError(w, http.StatusNotFound, errCodeNotFound, "not found")
+			return
+		}
+		respondJSON(w, map[string]string{"status": "ok"})
+	})
+	do := func(method, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+		return rec
+	}
+
+	first := do(http.MethodGet, "/api/sections")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 1, db.calls)
+	assert.True(t, mr.Exists(cacheKeySections))
+	assert.Contains(t, first.Body.String(), `"relevance_threshold":0.3`)
+
+	// Served from Redis, identical to the uncached response.
+	second := do(http.MethodGet, "/api/sections")
+	assert.Equal(t, 1, db.calls)
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+
+	// A failed mutation keeps the cache.
+	require.Equal(t, http.StatusNotFound, do(http.MethodPatch, "/api/sections/missing").Code)
+	do(http.MethodGet, "/api/sections")
+	assert.Equal(t, 1, db.calls)
+
+	// A successful one drops it, so the next read hits the database.
+	mr.Set(cacheKeyStats, "{}")
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, "/api/sections/s1").Code)
+	assert.False(t, mr.Exists(cacheKeySections))
+	assert.False(t, mr.Exists(cacheKeyStats))
+	do(http.MethodGet, "/api/sections")
+	assert.Equal(t, 2, db.calls)
+
+	// Entries expire after the TTL.
+	mr.FastForward(2 * time.Minute)
+	do(http.MethodGet, "/api/sections")
+	assert.Equal(t, 3, db.calls)
+
+	// With Redis gone every request falls through to the database.
+	mr.Close()
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/sections").Code)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/api/sections").Code)
+	assert.Equal(t, 5, db.calls)
+	assert.Equal(t, http.StatusOK, do(http.MethodPatch, "/api/sections/s1").Code)
+}
+
+type countingStatsGetter struct{ calls int }
+
+func (c *countingStatsGetter) GetDashboardStats(context.Context) (*store.DashboardStats, error) {
+	c.calls++
+	return &store.DashboardStats{TotalArticles: 42, ArticlesByStatus: map[string]int{"pending": 2}}, nil
+}
+
+func TestDashboardStatsHandlerCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	db := &countingStatsGetter{}
+	get := func(cache *responseCache) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		dashboardStatsHandler(db, nil, cache).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/stats", nil))
+		return rec
+	}
+
+	cache := newResponseCache(rdb, 30*time.Second)
+	first := get(cache)
+	second := get(cache)
+	assert.Equal(t, 1, db.calls)
+	assert.JSONEq(t, first.Body.String(), second.Body.String())
+	assert.Contains(t, second.Body.String(), `"total_articles":42`)
+
+	// A zero TTL disables caching entirely.
+	assert.Nil(t, newResponseCache(rdb, 0))
+	get(nil)
+	get(nil)
+	assert.Equal(t, 3, db.calls)
+}
+
+func TestWaitForDependency(t *testing.T) {
+	initial, maxBackoff := dependencyRetryInitial, dependencyRetryMax
+	dependencyRetryInitial, dependencyRetryMax = time.Millisecond, 4*time.Millisecond
+	defer func() { dependencyRetryInitial, dependencyRetryMax = initial, maxBackoff }()
+
+	calls := 0
+	err := waitForDependency(context.Background(), "postgres", time.Second, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = waitForDependency(context.Background(), "redis", 20*time.Millisecond, func(context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redis not ready")
+	assert.ErrorContains(t, err, "connection refused")
+	assert.Greater(t, calls, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = waitForDependency(ctx, "nats", time.Minute, func(context.Context) error {
+		return errors.New("no servers available")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+// openAPIDocument is the part of openapi.json the tests check.
+type openAPIDocument struct {
+	OpenAPI    string                                `json:"openapi"`
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadOpenAPIDocument(t *testing.T) openAPIDocument {
+	t.Helper()
+	router := chi.NewRouter()
+	router.Get("/api/openapi.json", openAPIHandler())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var doc openAPIDocument
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	require.True(t, strings.HasPrefix(doc.OpenAPI, "3."), "openapi version %q", doc.OpenAPI)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	for _, m := range regexp.MustCompile(`"\$ref":\s*"#/([^"]+)"`).FindAllStringSubmatch(rec.Body.String(), -1) {
+		var node any = raw
+		for _, key := range strings.Split(m[1], "/") {
+			obj, _ := node.(map[string]any)
+			node = obj[key]
+		}
+		assert.NotNil(t, node, "unresolved $ref #/%s", m[1])
+	}
+	return doc
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+
+	router := chi.NewRouter()
+	router.Route("/api", apiRoutes(apiDeps{cfg: &config.Config{}}))
+	registered := map[string]bool{}
+	require.NoError(t, chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		registered[strings.ToLower(method)+" "+route] = true
+		return nil
+	}))
+	require.NotEmpty(t, registered)
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method != "parameters" {
+				documented[method+" "+path] = true
+			}
+		}
+	}
+
+	for route := range registered {
+		assert.True(t, documented[route], "route %s is missing from openapi.json", route)
+	}
+	for route := range documented {
+		assert.True(t, registered[route], "openapi.json documents %s, which is not registered", route)
+	}
+}
+
+func TestOpenAPISchemasMatchResponseTypes(t *testing.T) {
+	doc := loadOpenAPIDocument(t)
+
+	for name, v := range map[string]any{
+		"Article":                articleResponse{},
+		"ArticleSection":         articleSectionResponse{},
+		"ArticleSource":          articleSourceResponse{},
+		"ArticleFeedback":        articleFeedbackResponse{},
+		"ArticleNote":            articleNoteResponse{},
+		"ArticleCluster":         articleClusterResponse{},
+		"CategoryCount":          store.CategoryCount{},
+		"Source":                 sourceResponse{},
+		"SourceSectionRef":       store.SourceSectionRef{},
+		"SourceStats":            sourceStatsResponse{},
+		"SourceFetchRun":         store.SourceFetchRun{},
+		"SourceValidation":       sourceValidation{},
+		"Section":                models.Section{},
+		"SectionStats":           store.SectionStats{},
+		"ScoreHistogram":         store.SectionScoreHistogram{},
+		"ScoreHistogramBucket":   store.ScoreHistogramBucket{},
+		"BriefingListItem":       briefingListItem{},
+		"Briefing":               briefingResponse{},
+		"BriefingExport":         briefingExport{},
+		"BriefingExportBriefing": briefingExportBriefing{},
+		"BriefingExportSection":  briefingExportSection{},
+		"BriefingExportArticle":  briefingExportArticle{},
+		"Feedback":               models.Feedback{},
+		"FeedbackRecord":         store.FeedbackRecord{},
+		"DashboardStats":         dashboardStatsResponse{},
+		"IngestionCounts":        store.IngestionCounts{},
+		"SectionLikes":           store.SectionLikes{},
+		"LLMUsageDay":            store.LLMUsageDay{},
+		"DeadLetter":             queue.DeadLetterEntry{},
+	} {
+		schema, ok := doc.Components.Schemas[name]
+		if !assert.True(t, ok, "schema %s is missing", name) {
+			continue
+		}
+		var properties []string
+		for prop := range schema.Properties {
+			properties = append(properties, prop)
+		}
+		assert.ElementsMatch(t, jsonFieldNames(reflect.TypeOf(v)), properties, "schema %s", name)
+	}
+}
+
+// jsonFieldNames lists the keys encoding/json writes for struct type t,
+// including those of embedded structs.
+func jsonFieldNames(t reflect.Type) []string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			names = append(names, jsonFieldNames(f.Type)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
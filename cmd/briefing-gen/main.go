@@ -2,8 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"os/signal"
@@ -12,18 +16,29 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron/v3"
 	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/briefing"
 	"github.com/zyrak/flux/internal/config"
+	"github.com/zyrak/flux/internal/dedup"
 	"github.com/zyrak/flux/internal/llm"
 	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/queue"
+	"github.com/zyrak/flux/internal/relevance"
 	"github.com/zyrak/flux/internal/store"
+	"github.com/zyrak/flux/internal/textutil"
 )
 
 const (
 	briefingModeCronjob = "cronjob"
 	briefingModeDaemon  = "daemon"
-	llmTimeout          = 120 * time.Second
+
+	briefingRunTimeout = 30 * time.Minute
+	briefingLockKey    = "flux:briefing:lock"
+	// The lock outlives the run timeout so a crashed run cannot leave it
+	// held forever, but never expires under a healthy run.
+	briefingLockTTL = briefingRunTimeout + 15*time.Minute
 )
 
 type sectionRun struct {
@@ -40,6 +55,44 @@ type sectionMeta struct {
 	Filtered int `json:"filtered"`
 }
 
+// briefingMessages holds the locally rendered strings of fallback briefings.
+type briefingMessages struct {
+	PartialTitle       string
+	NoArticles         string
+	ReportedBy         string
+	SeenIn             string
+	MultiSourceHeading string
+	UntitledStory      string
+}
+
+var briefingCatalog = map[string]briefingMessages{
+	"es": {
+		PartialTitle:       "Briefing parcial",
+		NoArticles:         "No había artículos listos para sintetizar en este ciclo.",
+		ReportedBy:         "Reportado por",
+		SeenIn:             "Visto en",
+		MultiSourceHeading: "Cobertura multi-fuente",
+		UntitledStory:      "Noticia sin título",
+	},
+	"en": {
+		PartialTitle:       "Partial Briefing",
+		NoArticles:         "No articles were ready for synthesis in this cycle.",
+		ReportedBy:         "Reported by",
+		SeenIn:             "Seen in",
+		MultiSourceHeading: "Multi-source Coverage",
+		UntitledStory:      "Untitled story",
+	},
+}
+
+// briefingMessagesFor returns the catalog entry for a language code, falling
+// back to English when the language is unset or has no translation.
+func briefingMessagesFor(language string) briefingMessages {
+	if msgs, ok := briefingCatalog[llm.NormalizeLanguage(language)]; ok {
+		return msgs
+	}
+	return briefingCatalog["en"]
+}
+
 type clusterInfo struct {
 	SeenIn       []string
 	ReportedBy   []string
@@ -53,43 +106,127 @@ func main() {
 
 	log.Info("Starting Flux briefing generator")
 
+	// Check the settings before connecting to anything so a typo fails fast.
+	if err := cfg.ValidateBriefing(); err != nil {
+		log.WithError(err).Fatal("Invalid briefing configuration")
+	}
+	mode := parseBriefingMode()
+	var schedule cron.Schedule
+	if mode == briefingModeDaemon {
+		var err error
+		if schedule, err = config.ValidateCronSchedule(cfg.BriefingSchedule); err != nil {
+			log.WithError(err).Fatal("Invalid BRIEFING_SCHEDULE")
+		}
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
 	}
 	defer db.Close()
 
-	analyzer, err := llm.NewAnalyzer(cfg.LLMProvider, cfg.LLMEndpoint, cfg.LLMModel, cfg.LLMAPIKey)
+	analyzer, err := llm.NewAnalyzerWithOptions(cfg.LLMProvider, cfg.LLMEndpoint, cfg.LLMModel, cfg.LLMAPIKey, cfg.BriefingLanguage, llmOptions(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize LLM analyzer")
 	}
-	log.WithField("provider", analyzer.Provider()).Info("LLM analyzer ready")
+	log.WithFields(log.Fields{
+		"provider":    analyzer.Provider(),
+		"timeout":     cfg.LLMTimeout,
+		"max_retries": cfg.LLMMaxRetries,
+	}).Info("LLM analyzer ready")
+
+	redisOpts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to parse REDIS_URL")
+	}
+	rdb := redis.NewClient(redisOpts)
+	defer func() { _ = rdb.Close() }()
+
+	// Redis only backs the run lock, so an outage degrades to unlocked runs
+	// instead of blocking briefings.
+	lock := &briefingLock{rdb: rdb, ttl: briefingLockTTL}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.WithError(err).Warn("Failed to connect to Redis, generating briefings without the run lock")
+		lock = nil
+	}
 
-	mode := parseBriefingMode()
 	if mode == briefingModeDaemon {
-		runDaemon(ctx, cfg, db, analyzer)
+		q, err := queue.New(cfg.NatsURL)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to connect to NATS")
+		}
+		defer q.Close()
+
+		go runHeartbeat(ctx, rdb)
+		runDaemon(ctx, cfg, schedule, db, analyzer, q, lock)
 		return
 	}
 
-	if err := runOnce(ctx, cfg, db, analyzer); err != nil {
+	if err := runLocked(ctx, cfg, db, analyzer, lock, nil, log.Fields{"trigger": "cronjob"}); err != nil {
 		log.WithError(err).Fatal("Briefing generation failed")
 	}
 
 	log.Info("Briefing generator finished")
 }
 
-func runDaemon(ctx context.Context, cfg *config.Config, db *store.Store, analyzer llm.Analyzer) {
-	schedule, err := cron.ParseStandard(cfg.BriefingSchedule)
+// runHeartbeat tells the API a daemon is consuming briefing requests. It
+// beats even while Redis is down so the API sees the daemon once it is back.
+func runHeartbeat(ctx context.Context, rdb redis.Cmdable) {
+	ticker := time.NewTicker(briefing.HeartbeatEvery)
+	defer ticker.Stop()
+	healthy := true
+	for {
+		err := briefing.BeatDaemon(ctx, rdb)
+		if err != nil && healthy {
+			log.WithError(err).Warn("Failed to refresh briefing daemon heartbeat; manual runs are refused until it recovers")
+		}
+		healthy = err == nil
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runDaemon(ctx context.Context, cfg *config.Config, schedule cron.Schedule, db *store.Store, analyzer llm.Analyzer, q *queue.Queue, lock *briefingLock) {
+	// Manual requests are acked right away and handed to the scheduler loop,
+	// so a long run never outlives the message ack deadline. One pending
+	// request is enough: later ones would generate the same briefing.
+	manual := make(chan queue.BriefingGenerateEvent, 1)
+	err := q.Subscribe(ctx, queue.SubjectBriefingGenerate, queue.DurableBriefingGen, func(data []byte) error {
+		var evt queue.BriefingGenerateEvent
+		if err := json.Unmarshal(data, &evt); err != nil {
+			log.WithError(err).Warn("Discarding malformed briefing request")
+			return nil
+		}
+		select {
+		case manual <- evt:
+			log.WithField("request_id", evt.RequestID).Info("Manual briefing run queued")
+		default:
+			log.WithField("request_id", evt.RequestID).Info("Manual briefing run already pending, dropping request")
+		}
+		return nil
+	})
 	if err != nil {
-		log.WithError(err).WithField("schedule", cfg.BriefingSchedule).Fatal("Invalid BRIEFING_SCHEDULE")
+		log.WithError(err).Fatal("Failed to subscribe to briefing requests")
 	}
 
 	log.WithField("schedule", cfg.BriefingSchedule).Info("Briefing daemon scheduler active")
+	last := time.Now().UTC()
 	for {
-		next := schedule.Next(time.Now().UTC())
+		// Sections are reloaded every cycle so schedule edits apply without
+		// a restart; on error the global schedule alone drives the next run.
+		sections, err := db.ListSections(ctx)
+		if err != nil {
+			log.WithError(err).Warn("Failed to load section schedules, using BRIEFING_SCHEDULE only")
+			sections = nil
+		}
+		schedules := sectionSchedules(sections, schedule)
+		next := nextBriefingRun(schedule, schedules, time.Now().UTC())
 		wait := time.Until(next)
 		log.WithFields(log.Fields{
 			"next_run_utc": next.Format(time.RFC3339),
@@ -102,20 +239,196 @@ func runDaemon(ctx context.Context, cfg *config.Config, db *store.Store, analyze
 			timer.Stop()
 			log.Info("Briefing daemon shutting down")
 			return
+		case evt := <-manual:
+			timer.Stop()
+			fields := log.Fields{"trigger": "manual", "request_id": evt.RequestID}
+			if err := runLocked(ctx, cfg, db, analyzer, lock, nil, fields); err != nil {
+				log.WithFields(fields).WithError(err).Error("Manual briefing run failed")
+			}
 		case <-timer.C:
+			due := dueSections(schedules, last, next)
+			last = next
+			fields := log.Fields{"trigger": "schedule", "sections": len(due)}
+			if err := runLocked(ctx, cfg, db, analyzer, lock, due, fields); err != nil {
+				log.WithError(err).Error("Scheduled briefing run failed")
+			}
 		}
+	}
+}
 
-		runCtx, cancel := context.WithTimeout(ctx, 30*time.Minute)
-		err := runOnce(runCtx, cfg, db, analyzer)
-		cancel()
-		if err != nil {
-			log.WithError(err).Error("Scheduled briefing run failed")
+// runLocked runs a briefing under the distributed lock. It skips the run
+// when another one is in progress, and runs anyway if Redis is unavailable.
+// A non-nil only restricts the run to those section IDs.
+func runLocked(ctx context.Context, cfg *config.Config, db *store.Store, analyzer llm.Analyzer, lock *briefingLock, only map[string]struct{}, fields log.Fields) error {
+	release, acquired, err := lock.acquire(ctx)
+	switch {
+	case err != nil:
+		log.WithFields(fields).WithError(err).Warn("Failed to acquire briefing lock, generating anyway")
+	case !acquired:
+		log.WithFields(fields).Info("Briefing generation already in progress, skipping")
+		return nil
+	default:
+		defer release()
+	}
+
+	log.WithFields(fields).Info("Starting briefing run")
+	runCtx, cancel := context.WithTimeout(ctx, briefingRunTimeout)
+	defer cancel()
+	return runOnce(runCtx, cfg, db, analyzer, only)
+}
+
+// releaseLockScript deletes the lock only if it still holds our token, so an
+// expired run never releases a lock taken over by a newer one.
+var releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// briefingLock serializes briefing generation across the scheduler, manual
+// triggers and any overlapping cronjob pods. A nil lock stands for Redis
+// being unreachable at startup.
+type briefingLock struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+// errLockUnavailable is returned by a nil briefingLock.
+var errLockUnavailable = errors.New("redis unavailable at startup")
+
+func (l *briefingLock) acquire(ctx context.Context) (release func(), acquired bool, err error) {
+	if l == nil {
+		return nil, false, errLockUnavailable
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, false, err
+	}
+	acquired, err = l.rdb.SetNX(ctx, briefingLockKey, token, l.ttl).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("acquiring briefing lock: %w", err)
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+
+	release = func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := releaseLockScript.Run(releaseCtx, l.rdb, []string{briefingLockKey}, token).Err(); err != nil {
+			log.WithError(err).Warn("Failed to release briefing lock")
 		}
 	}
+	return release, true, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating lock token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
 }
 
-func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer llm.Analyzer) error {
+// retentionStatuses are the statuses eligible for deletion; briefed
+// articles are kept as the briefing history.
+var retentionStatuses = []string{models.StatusPending, models.StatusProcessed, models.StatusArchived}
+
+// retentionCutoff returns the ingestion time before which articles expire, or
+// false when retention is disabled.
+func retentionCutoff(now time.Time, days int) (time.Time, bool) {
+	if days <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(-time.Duration(days) * 24 * time.Hour), true
+}
+
+// pruneExpiredArticles deletes never-briefed articles and dedup URL hashes
+// past the retention window. Rows referencing a deleted article go with it
+// through ON DELETE CASCADE. Failures are logged and never block briefing
+// generation.
+func pruneExpiredArticles(ctx context.Context, db *store.Store, retentionDays int, now time.Time) {
+	cutoff, ok := retentionCutoff(now, retentionDays)
+	if !ok {
+		return
+	}
+
+	removed, err := db.DeleteArticlesOlderThan(ctx, cutoff, retentionStatuses)
+	if err != nil {
+		log.WithError(err).Warn("Failed to delete expired articles")
+	}
+	fields := log.Fields{"cutoff": cutoff.Format(time.RFC3339)}
+	total := int64(0)
+	for _, status := range retentionStatuses {
+		fields["deleted_"+status] = removed[status]
+		total += removed[status]
+	}
+	if total > 0 {
+		log.WithFields(fields).Info("Deleted articles past retention window")
+	}
+
+	seen, err := db.DeleteSeenURLsOlderThan(ctx, cutoff)
+	if err != nil {
+		log.WithError(err).Warn("Failed to delete expired seen URLs")
+	}
+	if seen > 0 {
+		log.WithFields(log.Fields{"cutoff": cutoff.Format(time.RFC3339), "deleted": seen}).Info("Deleted seen URLs past retention window")
+	}
+}
+
+// loadBriefedClusters returns the clusters briefed within the dedup window so
+// a story re-posted by another source is not briefed again. Failures are
+// logged and disable the check for this run.
+func loadBriefedClusters(ctx context.Context, db *store.Store, days int, now time.Time) map[string]struct{} {
+	if days <= 0 {
+		return nil
+	}
+	since := now.Add(-time.Duration(days) * 24 * time.Hour)
+	clusters, err := db.ClusterIDsBriefedSince(ctx, since)
+	if err != nil {
+		log.WithError(err).Warn("Failed to load recently briefed clusters")
+		return nil
+	}
+	return clusters
+}
+
+// dropBriefedClusters removes candidates whose cluster already appeared in a
+// recent briefing and returns the IDs of the dropped ones.
+func dropBriefedClusters(candidates []*models.Article, briefed map[string]struct{}) ([]*models.Article, []string) {
+	if len(briefed) == 0 {
+		return candidates, nil
+	}
+	kept := make([]*models.Article, 0, len(candidates))
+	var dropped []string
+	for _, article := range candidates {
+		clusterID := metadataString(parseArticleMetadata(article.Metadata), "cluster_id")
+		if _, ok := briefed[clusterID]; ok && clusterID != "" {
+			dropped = append(dropped, article.ID)
+			continue
+		}
+		kept = append(kept, article)
+	}
+	return kept, dropped
+}
+
+// markAlreadyBriefed moves candidates of an already briefed story out of
+// pending, so they stop taking up the fetch limit of every later run.
+func markAlreadyBriefed(ctx context.Context, db *store.Store, section string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	if _, err := db.UpdateArticleStatusBatch(ctx, ids, models.StatusProcessed); err != nil {
+		log.WithField("section", section).WithError(err).Warn("Failed to mark already briefed candidates processed")
+	}
+}
+
+// runOnce generates one briefing. A non-nil only limits it to those section
+// IDs, as the daemon does for sections whose schedule fired.
+func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer llm.Analyzer, only map[string]struct{}) error {
 	start := time.Now()
+	// usage sums the provider-reported usage of every LLM call in the run.
+	var usage llm.Usage
 	maxAge := time.Duration(cfg.BriefingMaxAgeDays) * 24 * time.Hour
 
 	sections, err := db.ListSections(ctx)
@@ -125,21 +438,36 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 
 	enabledSections := make([]*models.Section, 0, len(sections))
 	sectionsByName := make(map[string]*models.Section)
+	scope := models.BriefingScopeFull
 	for _, sec := range sections {
 		if !sec.Enabled {
 			continue
 		}
+		// Sections left out of this run are also out of reach for classifier
+		// corrections, so no article is briefed under a section not shown.
+		if only != nil {
+			if _, ok := only[sec.ID]; !ok {
+				scope = models.BriefingScopeSections
+				continue
+			}
+		}
 		enabledSections = append(enabledSections, sec)
 		sectionsByName[sec.Name] = sec
 	}
 	if len(enabledSections) == 0 {
-		log.Info("No enabled sections, skipping briefing generation")
+		log.Info("No enabled sections due, skipping briefing generation")
 		return nil
 	}
 
+	dryRun := cfg.BriefingDryRun
+	llmTimeout := llmOptions(cfg).CallTimeout()
+	if dryRun {
+		log.Info("Dry run: articles, summaries and the briefing will not be saved")
+	}
+
 	// Archive stale pending articles that are too old to appear in a briefing.
 	archiveAge := time.Duration(cfg.BriefingMaxAgeDays*2) * 24 * time.Hour
-	if archiveAge > 0 {
+	if archiveAge > 0 && !dryRun {
 		archived, err := db.ArchiveStaleArticles(ctx, archiveAge)
 		if err != nil {
 			log.WithError(err).Warn("Failed to archive stale articles")
@@ -147,6 +475,10 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 			log.WithField("archived_count", archived).Info("Archived stale pending articles")
 		}
 	}
+	if !dryRun {
+		pruneExpiredArticles(ctx, db, cfg.ArticleRetentionDays, time.Now().UTC())
+	}
+	briefedClusters := loadBriefedClusters(ctx, db, cfg.BriefingDedupDays, time.Now().UTC())
 
 	sectionRuns := make(map[string]*sectionRun, len(enabledSections))
 	totalCandidates := 0
@@ -165,7 +497,21 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 			return fmt.Errorf("listing pending section articles (%s): %w", sec.Name, err)
 		}
 
-		clusteredCandidates, clusterMap := collapseClusteredCandidates(candidates, sec.MaxBriefingArticles)
+		fetched := len(candidates)
+		candidates, alreadyBriefed := dropBriefedClusters(candidates, briefedClusters)
+		if !dryRun {
+			markAlreadyBriefed(ctx, db, sec.Name, alreadyBriefed)
+		}
+		clusteredCandidates, clusterMap := collapseClusteredCandidates(candidates, 0, clampMultiSourceBonus(cfg.BriefingMultiSourceBonus))
+		ranked := clusteredCandidates
+		clusteredCandidates, trimmed := trimForClassification(ranked, classifyLimit(sec.MaxBriefingArticles, cfg.BriefingClassifyMultiplier))
+		if len(trimmed) > 0 {
+			log.WithFields(log.Fields{
+				"section":                     sec.Name,
+				"pre_trimmed":                 len(trimmed),
+				"tokens_saved_vs_section_cap": classifyTokensSaved(ranked, len(clusteredCandidates), sec),
+			}).Info("Pre-trimmed lower-ranked candidates before classification")
+		}
 		sectionRuns[sec.ID] = &sectionRun{
 			Section:    sec,
 			Threshold:  threshold,
@@ -174,12 +520,14 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 			Total:      total,
 		}
 		log.WithFields(log.Fields{
-			"section":        sec.Name,
-			"threshold":      threshold,
-			"max_age_days":   cfg.BriefingMaxAgeDays,
-			"pending_total":  total,
-			"fetched_count":  len(candidates),
-			"selected_count": len(clusteredCandidates),
+			"section":         sec.Name,
+			"threshold":       threshold,
+			"max_age_days":    cfg.BriefingMaxAgeDays,
+			"pending_total":   total,
+			"fetched_count":   fetched,
+			"kept_count":      len(candidates),
+			"already_briefed": len(alreadyBriefed),
+			"selected_count":  len(clusteredCandidates),
 		}).Info("Collected candidate articles for section")
 		totalCandidates += len(clusteredCandidates)
 	}
@@ -194,9 +542,8 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 	summarizedBySection := make(map[string][]llm.SummarizedArticle)
 	partial := false
 	pendingCount := 0
-	tokensClassify := 0
-	tokensSummarize := 0
-	tokensBriefing := 0
+	var tokensClassify, tokensSummarize, tokensBriefing tokenTally
+	budgetExceeded := false
 
 	for _, sec := range enabledSections {
 		run := sectionRuns[sec.ID]
@@ -208,9 +555,21 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 		for _, article := range run.Candidates {
 			classifyInputs = append(classifyInputs, toClassifyInput(article, run.Section))
 		}
-		tokensClassify += estimateTokens(llm.BuildClassifyPrompt(classifyInputs))
+		classifyTokens := estimateTokens(llm.BuildClassifyPrompt(classifyInputs))
+		if budgetExceeded || exceedsTokenBudget(cfg.BriefingTokenBudget, tokensClassify.spent+tokensSummarize.spent, classifyTokens) {
+			budgetExceeded = true
+			pendingCount += len(run.Candidates)
+			log.WithFields(log.Fields{
+				"section": run.Section.Name,
+				"count":   len(run.Candidates),
+				"budget":  cfg.BriefingTokenBudget,
+			}).Warn("Token budget exhausted, leaving section articles pending")
+			continue
+		}
 
-		classifications, err := classifyWithTimeout(ctx, analyzer, classifyInputs)
+		classifications, classifyUsage, err := classifyWithTimeout(ctx, llmTimeout, analyzer, classifyInputs)
+		usage = usage.Add(classifyUsage)
+		tokensClassify.add(classifyUsage, classifyTokens)
 		if err != nil {
 			partial = true
 			pendingCount += len(run.Candidates)
@@ -252,7 +611,9 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 
 			targetSection := resolveClassificationSection(classification.Section, run.Section, sectionsByName)
 			if targetSection.ID != run.Section.ID && article.RelevanceScore != nil {
-				if err := db.UpdateArticleSection(ctx, article.ID, targetSection.ID, *article.RelevanceScore); err != nil {
+				if dryRun {
+					article.SectionID = &targetSection.ID
+				} else if err := db.UpdateArticleSection(ctx, article.ID, targetSection.ID, *article.RelevanceScore); err != nil {
 					log.WithFields(log.Fields{
 						"article_id":   article.ID,
 						"from_section": run.Section.Name,
@@ -274,9 +635,16 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 			}
 
 			summarizeInput := toSummarizeInput(article, targetSection)
-			tokensSummarize += estimateTokens(llm.BuildSummarizePrompt(summarizeInput))
+			summarizeTokens := estimateTokens(llm.BuildSummarizePrompt(summarizeInput, cfg.BriefingLanguage))
+			if budgetExceeded || exceedsTokenBudget(cfg.BriefingTokenBudget, tokensClassify.spent+tokensSummarize.spent, summarizeTokens) {
+				budgetExceeded = true
+				pendingCount++
+				continue
+			}
 
-			summary, err := summarizeWithTimeout(ctx, analyzer, summarizeInput)
+			summarized, summarizeUsage, err := summarizeWithTimeout(ctx, llmTimeout, analyzer, summarizeInput)
+			usage = usage.Add(summarizeUsage)
+			tokensSummarize.add(summarizeUsage, summarizeTokens+estimateTokens(summarized.Summary))
 			if err != nil {
 				partial = true
 				pendingCount++
@@ -286,20 +654,22 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 				}).WithError(err).Warn("LLM summarization failed, leaving article pending")
 				continue
 			}
-			tokensSummarize += estimateTokens(summary)
 
-			if err := db.UpdateArticleSummary(ctx, article.ID, summary, nil); err != nil {
-				log.WithField("article_id", article.ID).WithError(err).Warn("Failed to persist article summary")
+			if !dryRun {
+				if err := db.UpdateArticleSummary(ctx, article.ID, summarized.Summary, summarized.Categories); err != nil {
+					log.WithField("article_id", article.ID).WithError(err).Warn("Failed to persist article summary")
+				}
 			}
 
 			summarizedBySection[targetSection.Name] = append(summarizedBySection[targetSection.Name], llm.SummarizedArticle{
 				ID:         article.ID,
 				Title:      article.Title,
-				Summary:    summary,
+				Summary:    summarized.Summary,
 				URL:        article.URL,
 				SourceType: article.SourceType,
 				SeenIn:     cluster.SeenIn,
 				ReportedBy: cluster.ReportedBy,
+				Categories: summarized.Categories,
 			})
 			summarizedCount++
 			briefedIDs[article.ID] = struct{}{}
@@ -314,25 +684,37 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 	}
 
 	briefingSections := buildBriefingSections(enabledSections, summarizedBySection)
+	msgs := briefingMessagesFor(cfg.BriefingLanguage)
 	var content string
 	if len(briefingSections) > 0 {
-		tokensBriefing += estimateTokens(llm.BuildBriefingPrompt(briefingSections))
-		content, err = generateBriefingWithTimeout(ctx, analyzer, briefingSections)
+		briefingTokens := estimateTokens(llm.BuildBriefingPrompt(briefingSections, cfg.BriefingLanguage))
+		briefingCtx := ctx
+		briefingTimeout := llmTimeout
+		if cfg.LLMStreamBriefing {
+			// A streamed synthesis fails on its own once chunks stop arriving,
+			// so only the run deadline caps how long it may keep writing.
+			briefingTimeout = briefingRunTimeout
+			briefingCtx = llm.WithProgress(briefingCtx, briefingProgressLogger(2000))
+		}
+		var briefingUsage llm.Usage
+		content, briefingUsage, err = generateBriefingWithTimeout(briefingCtx, briefingTimeout, analyzer, briefingSections)
+		usage = usage.Add(briefingUsage)
+		tokensBriefing.add(briefingUsage, briefingTokens+estimateTokens(content))
 		if err != nil {
 			partial = true
 			log.WithError(err).Warn("LLM briefing synthesis failed, generating local partial briefing")
-			content = buildFallbackBriefing(briefingSections)
+			content = buildFallbackBriefing(briefingSections, msgs)
 		} else {
-			tokensBriefing += estimateTokens(content)
 			log.WithField("sections_included", len(briefingSections)).Info("LLM briefing synthesized")
 		}
-		content = appendMultiSourceCoverage(content, briefingSections)
+		content = appendMultiSourceCoverage(content, briefingSections, msgs)
 	} else {
 		partial = true
-		content = buildFallbackBriefing(nil)
+		content = buildFallbackBriefing(nil, msgs)
 	}
 
-	tokensEstimated := tokensClassify + tokensSummarize + tokensBriefing
+	tokensEstimated := tokensClassify.estimated + tokensSummarize.estimated + tokensBriefing.estimated
+	tokensSpent := tokensClassify.spent + tokensSummarize.spent + tokensBriefing.spent
 
 	briefingArticleIDs := sortedIDs(briefedIDs)
 	for _, id := range briefingArticleIDs {
@@ -340,6 +722,35 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 	}
 	processedArticleIDs := sortedIDs(processedIDs)
 
+	if dryRun {
+		report := make([]dryRunSection, 0, len(enabledSections))
+		for _, sec := range enabledSections {
+			run := sectionRuns[sec.ID]
+			report = append(report, dryRunSection{
+				Name:       sec.Name,
+				Threshold:  run.Threshold,
+				Total:      run.Total,
+				Candidates: len(run.Candidates),
+				Filtered:   run.Filtered,
+				Summarized: len(summarizedBySection[sec.Name]),
+			})
+		}
+		writeDryRunReport(os.Stdout, report, len(briefingArticleIDs), len(processedArticleIDs), usage, tokensEstimated, content)
+		log.WithFields(log.Fields{
+			"dry_run":            true,
+			"included_articles":  len(briefingArticleIDs),
+			"processed_articles": len(processedArticleIDs),
+			"partial":            partial,
+			"tokens_spent":       tokensSpent,
+			"tokens_estimated":   tokensEstimated,
+			"prompt_tokens":      usage.PromptTokens,
+			"completion_tokens":  usage.CompletionTokens,
+			"llm_calls":          usage.Calls,
+			"duration_ms":        time.Since(start).Milliseconds(),
+		}).Info("Dry-run briefing generated, nothing saved")
+		return nil
+	}
+
 	for _, id := range briefingArticleIDs {
 		if err := db.UpdateArticleStatus(ctx, id, models.StatusBriefed); err != nil {
 			log.WithField("article_id", id).WithError(err).Warn("Failed to update article status to briefed")
@@ -364,18 +775,35 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 	}
 
 	metadataMap := map[string]interface{}{
+		"scope":            scope,
 		"sections":         sectionsMetadata,
 		"tokens_estimated": tokensEstimated,
+		"tokens_spent":     tokensSpent,
 		"token_breakdown": map[string]int{
-			"classify":  tokensClassify,
-			"summarize": tokensSummarize,
-			"briefing":  tokensBriefing,
+			"classify":  tokensClassify.spent,
+			"summarize": tokensSummarize.spent,
+			"briefing":  tokensBriefing.spent,
 		},
 	}
+	if cfg.BriefingTokenBudget > 0 {
+		metadataMap["token_budget"] = cfg.BriefingTokenBudget
+	}
+	if budgetExceeded {
+		metadataMap["budget_exceeded"] = true
+		metadataMap["pending_count"] = pendingCount
+		log.WithFields(log.Fields{
+			"budget":        cfg.BriefingTokenBudget,
+			"tokens_spent":  tokensClassify.spent + tokensSummarize.spent,
+			"pending_count": pendingCount,
+		}).Warn("Briefing token budget exceeded, remaining candidates left pending")
+	}
 	if partial {
 		metadataMap["partial"] = true
 		metadataMap["pending_count"] = pendingCount
 	}
+	if usage.Calls > 0 {
+		metadataMap["tokens_actual"] = usage
+	}
 	metadata, err := json.Marshal(metadataMap)
 	if err != nil {
 		return fmt.Errorf("marshalling briefing metadata: %w", err)
@@ -389,23 +817,140 @@ func runOnce(ctx context.Context, cfg *config.Config, db *store.Store, analyzer
 	if err := db.CreateBriefing(ctx, briefing); err != nil {
 		return fmt.Errorf("creating briefing: %w", err)
 	}
+	recordLLMUsage(ctx, db, analyzer.Provider(), cfg.LLMModel, briefing.ID, usage, tokensEstimated)
 
 	log.WithFields(log.Fields{
 		"briefing_id":        briefing.ID,
+		"scope":              scope,
 		"included_articles":  len(briefingArticleIDs),
 		"processed_articles": len(processedArticleIDs),
 		"partial":            partial,
 		"pending_count":      pendingCount,
 		"tokens_estimated":   tokensEstimated,
-		"tokens_classify":    tokensClassify,
-		"tokens_summarize":   tokensSummarize,
-		"tokens_briefing":    tokensBriefing,
+		"tokens_spent":       tokensSpent,
+		"tokens_classify":    tokensClassify.spent,
+		"tokens_summarize":   tokensSummarize.spent,
+		"tokens_briefing":    tokensBriefing.spent,
+		"tokens_actual":      usage.TotalTokens,
+		"llm_calls":          usage.Calls,
 		"duration_ms":        time.Since(start).Milliseconds(),
 	}).Info("Briefing generated")
 
 	return nil
 }
 
+// recordLLMUsage stores the usage of the run that generated briefingID, if it
+// made any calls. Failures are only logged.
+func recordLLMUsage(ctx context.Context, db *store.Store, provider, model, briefingID string, actual llm.Usage, estimated int) {
+	if actual.Calls == 0 {
+		return
+	}
+	run := &store.LLMUsageRun{
+		BriefingID:       &briefingID,
+		Provider:         provider,
+		Model:            model,
+		Calls:            actual.Calls,
+		PromptTokens:     actual.PromptTokens,
+		CompletionTokens: actual.CompletionTokens,
+		EstimatedTokens:  estimated,
+	}
+	if err := db.CreateLLMUsage(ctx, run); err != nil {
+		log.WithField("briefing_id", briefingID).WithError(err).Warn("Failed to record LLM usage")
+	}
+}
+
+// dryRunSection is one section's line in the dry-run report.
+type dryRunSection struct {
+	Name       string
+	Threshold  float64
+	Total      int
+	Candidates int
+	Filtered   int
+	Summarized int
+}
+
+// writeDryRunReport prints the per-section selection, the LLM usage and the
+// would-be briefing of a BRIEFING_DRY_RUN run.
+func writeDryRunReport(w io.Writer, sections []dryRunSection, included, processed int, usage llm.Usage, estimated int, content string) {
+	fmt.Fprintln(w, "===== DRY RUN: nothing was saved =====")
+	fmt.Fprintf(w, "%-20s %9s %8s %10s %8s %10s\n", "section", "threshold", "pending", "candidates", "filtered", "summarized")
+	for _, s := range sections {
+		fmt.Fprintf(w, "%-20s %9.2f %8d %10d %8d %10d\n", s.Name, s.Threshold, s.Total, s.Candidates, s.Filtered, s.Summarized)
+	}
+	fmt.Fprintf(w, "\nWould brief %d articles and mark %d processed.\n", included, processed)
+	fmt.Fprintf(w, "LLM usage: %d calls, %d prompt + %d completion tokens (%d estimated).\n\n",
+		usage.Calls, usage.PromptTokens, usage.CompletionTokens, estimated)
+	fmt.Fprintln(w, strings.TrimSpace(content))
+	fmt.Fprintln(w, "===== END DRY RUN =====")
+}
+
+// sectionSchedule is the cron schedule a section is briefed on in daemon mode.
+type sectionSchedule struct {
+	sectionID string
+	schedule  cron.Schedule
+}
+
+// sectionSchedules resolves every enabled section's schedule: its own
+// config.schedule when set and valid, otherwise the global one.
+func sectionSchedules(sections []*models.Section, global cron.Schedule) []sectionSchedule {
+	out := make([]sectionSchedule, 0, len(sections))
+	for _, sec := range sections {
+		if !sec.Enabled {
+			continue
+		}
+		schedule := global
+		if expr := sectionScheduleExpr(sec); expr != "" {
+			parsed, err := config.ValidateCronSchedule(expr)
+			if err != nil {
+				log.WithField("section", sec.Name).WithError(err).Warn("Invalid section schedule, using BRIEFING_SCHEDULE")
+			} else {
+				schedule = parsed
+			}
+		}
+		out = append(out, sectionSchedule{sectionID: sec.ID, schedule: schedule})
+	}
+	return out
+}
+
+// sectionScheduleExpr returns the section's config.schedule cron expression,
+// or "" when it follows BRIEFING_SCHEDULE.
+func sectionScheduleExpr(sec *models.Section) string {
+	if len(sec.Config) == 0 || string(sec.Config) == "null" {
+		return ""
+	}
+	var cfg struct {
+		Schedule string `json:"schedule"`
+	}
+	if err := json.Unmarshal(sec.Config, &cfg); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.Schedule)
+}
+
+// nextBriefingRun is the earliest fire time after now across the global and
+// per-section schedules.
+func nextBriefingRun(global cron.Schedule, schedules []sectionSchedule, now time.Time) time.Time {
+	next := global.Next(now)
+	for _, s := range schedules {
+		if t := s.schedule.Next(now); !t.IsZero() && t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+// dueSections returns the IDs of sections whose schedule fired in
+// (since, at], the window since the previous scheduled run.
+func dueSections(schedules []sectionSchedule, since, at time.Time) map[string]struct{} {
+	due := make(map[string]struct{})
+	for _, s := range schedules {
+		if t := s.schedule.Next(since); !t.IsZero() && !t.After(at) {
+			due[s.sectionID] = struct{}{}
+		}
+	}
+	return due
+}
+
 func parseBriefingMode() string {
 	mode := strings.ToLower(strings.TrimSpace(os.Getenv("BRIEFING_MODE")))
 	if mode == "" {
@@ -445,11 +990,23 @@ func toClassifyInput(article *models.Article, sec *models.Section) llm.ArticleIn
 		Title:      article.Title,
 		Content:    firstParagraph(article.Content, 200),
 		Section:    sec.Name,
+		RunnerUp:   runnerUpSection(article, sec),
 		SourceType: article.SourceType,
 		URL:        article.URL,
 	}
 }
 
+// runnerUpSection returns the best other section the processor weighed the
+// article against, when its source feeds several sections.
+func runnerUpSection(article *models.Article, sec *models.Section) string {
+	for _, c := range relevance.SectionCandidatesFromMetadata(article.Metadata) {
+		if c.SectionID != sec.ID && c.SectionName != "" {
+			return c.SectionName
+		}
+	}
+	return ""
+}
+
 func toSummarizeInput(article *models.Article, sec *models.Section) llm.ArticleInput {
 	content := ""
 	if article.Content != nil {
@@ -465,19 +1022,38 @@ func toSummarizeInput(article *models.Article, sec *models.Section) llm.ArticleI
 	}
 }
 
-func classifyWithTimeout(ctx context.Context, analyzer llm.Analyzer, inputs []llm.ArticleInput) ([]llm.Classification, error) {
+// briefingProgressLogger returns a progress callback that logs every time
+// another step bytes of a streamed briefing arrive.
+func briefingProgressLogger(step int) llm.ProgressFunc {
+	received, next := 0, step
+	return func(delta string) {
+		received += len(delta)
+		if received >= next {
+			log.WithField("bytes", received).Debug("Briefing synthesis in progress")
+			next = received + step
+		}
+	}
+}
+
+// llmOptions maps the LLM_TIMEOUT, LLM_MAX_RETRIES and LLM_STREAM_BRIEFING
+// settings onto the analyzer options.
+func llmOptions(cfg *config.Config) llm.Options {
+	return llm.Options{Timeout: cfg.LLMTimeout, MaxRetries: cfg.LLMMaxRetries, StreamBriefing: cfg.LLMStreamBriefing}
+}
+
+func classifyWithTimeout(ctx context.Context, llmTimeout time.Duration, analyzer llm.Analyzer, inputs []llm.ArticleInput) ([]llm.Classification, llm.Usage, error) {
 	callCtx, cancel := context.WithTimeout(ctx, llmTimeout)
 	defer cancel()
 	return analyzer.Classify(callCtx, inputs)
 }
 
-func summarizeWithTimeout(ctx context.Context, analyzer llm.Analyzer, input llm.ArticleInput) (string, error) {
+func summarizeWithTimeout(ctx context.Context, llmTimeout time.Duration, analyzer llm.Analyzer, input llm.ArticleInput) (llm.ArticleSummary, llm.Usage, error) {
 	callCtx, cancel := context.WithTimeout(ctx, llmTimeout)
 	defer cancel()
-	return analyzer.Summarize(callCtx, input)
+	return analyzer.SummarizeWithCategories(callCtx, input)
 }
 
-func generateBriefingWithTimeout(ctx context.Context, analyzer llm.Analyzer, sections []llm.BriefingSection) (string, error) {
+func generateBriefingWithTimeout(ctx context.Context, llmTimeout time.Duration, analyzer llm.Analyzer, sections []llm.BriefingSection) (string, llm.Usage, error) {
 	callCtx, cancel := context.WithTimeout(ctx, llmTimeout)
 	defer cancel()
 	return analyzer.GenerateBriefing(callCtx, sections)
@@ -527,7 +1103,66 @@ func buildBriefingSections(enabledSections []*models.Section, summarizedBySectio
 	return out
 }
 
-func collapseClusteredCandidates(candidates []*models.Article, maxArticles int) ([]*models.Article, map[string]clusterInfo) {
+// classifyLimit is how many ranked stories of a section go to the
+// classifier. It leaves headroom above max_briefing_articles so stories the
+// classifier rejects or moves to another section can be replaced.
+func classifyLimit(maxArticles int, multiplier float64) int {
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	limit := int(math.Ceil(float64(maxArticles) * multiplier))
+	if limit < 1 {
+		limit = 1
+	}
+	return limit
+}
+
+// trimForClassification keeps the first limit ranked candidates. The rest
+// stay pending and compete again in the next run.
+func trimForClassification(ranked []*models.Article, limit int) (kept, trimmed []*models.Article) {
+	if limit <= 0 || len(ranked) <= limit {
+		return ranked, nil
+	}
+	return ranked[:limit], ranked[limit:]
+}
+
+// classifyTokensSaved estimates the classify prompt tokens saved by sending
+// the first kept ranked candidates instead of max_briefing_articles of them,
+// the cap classification used before BRIEFING_CLASSIFY_MULTIPLIER. It is
+// negative when a multiplier above 1 spends more than that cap did.
+func classifyTokensSaved(ranked []*models.Article, kept int, sec *models.Section) int {
+	return classifyPromptTokens(ranked, sec.MaxBriefingArticles, sec) - classifyPromptTokens(ranked, kept, sec)
+}
+
+func classifyPromptTokens(ranked []*models.Article, n int, sec *models.Section) int {
+	if n > len(ranked) {
+		n = len(ranked)
+	}
+	inputs := make([]llm.ArticleInput, 0, n)
+	for _, article := range ranked[:n] {
+		inputs = append(inputs, toClassifyInput(article, sec))
+	}
+	return estimateTokens(llm.BuildClassifyPrompt(inputs))
+}
+
+// maxMultiSourceBonus caps the per-source bonus; relevance scores live in
+// roughly [-1, 1], so anything larger would drown them out entirely.
+const maxMultiSourceBonus = 1.0
+
+func clampMultiSourceBonus(bonus float64) float64 {
+	if bonus < 0 {
+		return 0
+	}
+	if bonus > maxMultiSourceBonus {
+		return maxMultiSourceBonus
+	}
+	return bonus
+}
+
+// collapseClusteredCandidates keeps one primary article per story cluster and
+// ranks clusters by relevance plus bonusPerSource for every source beyond the
+// first that reported the story.
+func collapseClusteredCandidates(candidates []*models.Article, maxArticles int, bonusPerSource float64) ([]*models.Article, map[string]clusterInfo) {
 	if len(candidates) == 0 {
 		return []*models.Article{}, map[string]clusterInfo{}
 	}
@@ -542,26 +1177,11 @@ func collapseClusteredCandidates(candidates []*models.Article, maxArticles int)
 		base    float64
 	}
 
-	buckets := make(map[string][]*models.Article)
-	order := make([]string, 0, len(candidates))
-
-	for _, article := range candidates {
-		clusterID := clusterIDForArticle(article)
-		if _, exists := buckets[clusterID]; !exists {
-			order = append(order, clusterID)
-		}
-		buckets[clusterID] = append(buckets[clusterID], article)
-	}
-
-	entries := make([]clusterEntry, 0, len(buckets))
-	for _, clusterID := range order {
-		members := buckets[clusterID]
-		if len(members) == 0 {
-			continue
-		}
-
-		primary := pickClusterPrimary(members)
-		seenIn, reportedBy := collectClusterCoverage(members)
+	groups := dedup.GroupByCluster(candidates)
+	entries := make([]clusterEntry, 0, len(groups))
+	for _, members := range groups {
+		primary := dedup.PickClusterPrimary(members)
+		seenIn, reportedBy := dedup.ClusterCoverage(members)
 		suppressed := make([]string, 0, len(members)-1)
 		for _, member := range members {
 			if member.ID == primary.ID {
@@ -574,7 +1194,7 @@ func collapseClusteredCandidates(candidates []*models.Article, maxArticles int)
 		sourceCount := len(seenIn)
 		bonus := 0.0
 		if sourceCount > 1 {
-			bonus = float64(sourceCount-1) * 0.1
+			bonus = float64(sourceCount-1) * bonusPerSource
 		}
 
 		base := relevanceScore(primary)
@@ -619,161 +1239,6 @@ func collapseClusteredCandidates(candidates []*models.Article, maxArticles int)
 	return selected, infoByArticle
 }
 
-func clusterIDForArticle(article *models.Article) string {
-	meta := parseArticleMetadata(article.Metadata)
-	clusterID := metadataString(meta, "cluster_id")
-	if clusterID != "" {
-		return clusterID
-	}
-	return article.ID
-}
-
-func pickClusterPrimary(members []*models.Article) *models.Article {
-	if len(members) == 0 {
-		return nil
-	}
-
-	for _, member := range members {
-		primaryID := metadataString(parseArticleMetadata(member.Metadata), "cluster_primary_id")
-		if primaryID == "" {
-			continue
-		}
-		for _, candidate := range members {
-			if candidate.ID == primaryID {
-				return candidate
-			}
-		}
-	}
-
-	best := members[0]
-	bestSignal := articleSignal(best)
-	for i := 1; i < len(members); i++ {
-		candidate := members[i]
-		candidateSignal := articleSignal(candidate)
-		if candidateSignal > bestSignal {
-			best = candidate
-			bestSignal = candidateSignal
-			continue
-		}
-		if candidateSignal < bestSignal {
-			continue
-		}
-		if candidate.IngestedAt.Before(best.IngestedAt) {
-			best = candidate
-			continue
-		}
-		if candidate.IngestedAt.Equal(best.IngestedAt) && candidate.ID < best.ID {
-			best = candidate
-		}
-	}
-
-	return best
-}
-
-func collectClusterCoverage(members []*models.Article) ([]string, []string) {
-	type coverage struct {
-		plain    string
-		detailed string
-		signal   float64
-		order    int
-	}
-
-	seen := make(map[string]coverage)
-	for i, member := range members {
-		plain, detailed, signal := sourceCoverage(member)
-		if plain == "" {
-			continue
-		}
-
-		existing, ok := seen[plain]
-		if !ok {
-			seen[plain] = coverage{
-				plain:    plain,
-				detailed: detailed,
-				signal:   signal,
-				order:    i,
-			}
-			continue
-		}
-
-		if signal > existing.signal {
-			existing.detailed = detailed
-			existing.signal = signal
-		}
-		seen[plain] = existing
-	}
-
-	items := make([]coverage, 0, len(seen))
-	for _, item := range seen {
-		items = append(items, item)
-	}
-
-	sort.Slice(items, func(i, j int) bool {
-		if items[i].signal != items[j].signal {
-			return items[i].signal > items[j].signal
-		}
-		if items[i].order != items[j].order {
-			return items[i].order < items[j].order
-		}
-		return items[i].plain < items[j].plain
-	})
-
-	seenIn := make([]string, 0, len(items))
-	reportedBy := make([]string, 0, len(items))
-	for _, item := range items {
-		seenIn = append(seenIn, item.plain)
-		reportedBy = append(reportedBy, item.detailed)
-	}
-	return seenIn, reportedBy
-}
-
-func sourceCoverage(article *models.Article) (plain string, detailed string, signal float64) {
-	meta := parseArticleMetadata(article.Metadata)
-	sourceType := strings.ToLower(strings.TrimSpace(article.SourceType))
-
-	switch sourceType {
-	case "hn":
-		score := metadataFloat(meta, "hn_score")
-		if score > 0 {
-			return "HN", fmt.Sprintf("HN (%d pts)", int(score)), score
-		}
-		return "HN", "HN", 0
-	case "reddit":
-		sub := metadataString(meta, "subreddit")
-		sub = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(sub)), "r/")
-		if sub == "" {
-			sub = "reddit"
-		}
-		score := metadataFloat(meta, "reddit_score")
-		plain = "r/" + sub
-		if score > 0 {
-			return plain, fmt.Sprintf("Reddit %s (%d pts)", plain, int(score)), score
-		}
-		return plain, "Reddit " + plain, 0
-	default:
-		name := metadataString(meta, "source_name")
-		if name == "" {
-			if sourceType == "github" {
-				name = metadataString(meta, "repo")
-			}
-		}
-		if name == "" {
-			name = article.SourceType
-		}
-		return name, name, 0
-	}
-}
-
-func articleSignal(article *models.Article) float64 {
-	meta := parseArticleMetadata(article.Metadata)
-	hn := metadataFloat(meta, "hn_score")
-	reddit := metadataFloat(meta, "reddit_score")
-	if hn > reddit {
-		return hn
-	}
-	return reddit
-}
-
 func relevanceScore(article *models.Article) float64 {
 	if article == nil || article.RelevanceScore == nil {
 		return 0
@@ -814,47 +1279,23 @@ func metadataString(meta map[string]interface{}, key string) string {
 	return strings.TrimSpace(str)
 }
 
-func metadataFloat(meta map[string]interface{}, key string) float64 {
-	if meta == nil {
-		return 0
-	}
-	value, ok := meta[key]
-	if !ok {
-		return 0
-	}
-	switch typed := value.(type) {
-	case float64:
-		return typed
-	case float32:
-		return float64(typed)
-	case int:
-		return float64(typed)
-	case int64:
-		return float64(typed)
-	case int32:
-		return float64(typed)
-	default:
-		return 0
-	}
-}
-
-func buildFallbackBriefing(sections []llm.BriefingSection) string {
+func buildFallbackBriefing(sections []llm.BriefingSection, msgs briefingMessages) string {
 	if len(sections) == 0 {
-		return "# Partial Briefing\n\nNo articles were ready for synthesis in this cycle."
+		return "# " + msgs.PartialTitle + "\n\n" + msgs.NoArticles
 	}
 
 	var sb strings.Builder
-	sb.WriteString("# Partial Briefing\n\n")
+	sb.WriteString("# " + msgs.PartialTitle + "\n\n")
 	for _, sec := range sections {
 		sb.WriteString("## " + sec.DisplayName + "\n\n")
 		for _, article := range sec.Articles {
 			sb.WriteString("- **" + article.Title + "**\n")
 			sb.WriteString("  " + article.Summary + "\n")
 			if len(article.ReportedBy) > 1 {
-				sb.WriteString("  Reported by: " + strings.Join(article.ReportedBy, ", ") + "\n")
+				sb.WriteString("  " + msgs.ReportedBy + ": " + strings.Join(article.ReportedBy, ", ") + "\n")
 			}
 			if len(article.SeenIn) > 1 {
-				sb.WriteString("  📡 Seen in: " + strings.Join(article.SeenIn, ", ") + "\n")
+				sb.WriteString("  📡 " + msgs.SeenIn + ": " + strings.Join(article.SeenIn, ", ") + "\n")
 			}
 			sb.WriteString("  " + article.URL + "\n\n")
 		}
@@ -862,7 +1303,7 @@ func buildFallbackBriefing(sections []llm.BriefingSection) string {
 	return strings.TrimSpace(sb.String())
 }
 
-func appendMultiSourceCoverage(content string, sections []llm.BriefingSection) string {
+func appendMultiSourceCoverage(content string, sections []llm.BriefingSection, msgs briefingMessages) string {
 	lines := make([]string, 0)
 	seen := make(map[string]struct{})
 
@@ -886,9 +1327,9 @@ func appendMultiSourceCoverage(content string, sections []llm.BriefingSection) s
 
 			title := strings.TrimSpace(article.Title)
 			if title == "" {
-				title = "Untitled story"
+				title = msgs.UntitledStory
 			}
-			lines = append(lines, fmt.Sprintf("- %s\n  📡 Seen in: %s", title, strings.Join(article.SeenIn, ", ")))
+			lines = append(lines, fmt.Sprintf("- %s\n  📡 %s: %s", title, msgs.SeenIn, strings.Join(article.SeenIn, ", ")))
 		}
 	}
 
@@ -898,9 +1339,9 @@ func appendMultiSourceCoverage(content string, sections []llm.BriefingSection) s
 
 	base := strings.TrimSpace(content)
 	if base == "" {
-		base = "# Partial Briefing"
+		base = "# " + msgs.PartialTitle
 	}
-	return base + "\n\n### 📡 Multi-source Coverage\n" + strings.Join(lines, "\n")
+	return base + "\n\n### 📡 " + msgs.MultiSourceHeading + "\n" + strings.Join(lines, "\n")
 }
 
 func firstParagraph(content *string, maxChars int) string {
@@ -919,10 +1360,30 @@ func firstParagraph(content *string, maxChars int) string {
 		}
 	}
 
-	if len(trimmed) > maxChars {
-		trimmed = trimmed[:maxChars]
+	return strings.TrimSpace(textutil.TruncateRunes(trimmed, maxChars))
+}
+
+// tokenTally accounts one pipeline stage. spent prefers provider-reported
+// usage and falls back to the ~4 chars/token estimate for calls that failed
+// or whose provider reported nothing; estimated always holds the estimate.
+type tokenTally struct {
+	spent     int
+	estimated int
+}
+
+func (t *tokenTally) add(usage llm.Usage, estimate int) {
+	t.estimated += estimate
+	if usage.TotalTokens > 0 {
+		t.spent += usage.TotalTokens
+		return
 	}
-	return strings.TrimSpace(trimmed)
+	t.spent += estimate
+}
+
+// exceedsTokenBudget reports whether spending next more tokens on top of spent
+// would go over budget. A budget of zero or less means unlimited.
+func exceedsTokenBudget(budget, spent, next int) bool {
+	return budget > 0 && spent+next > budget
 }
 
 func estimateTokens(text string) int {
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/llm"
+	"github.com/zyrak/flux/internal/models"
+)
+
+func TestBuildFallbackBriefingLocalized(t *testing.T) {
+	sections := []llm.BriefingSection{{
+		DisplayName: "Tech",
+		Articles: []llm.SummarizedArticle{{
+			ID:      "a1",
+			Title:   "Story",
+			Summary: "Summary.",
+			URL:     "https://example.com",
+			SeenIn:  []string{"HN", "r/golang"},
+		}},
+	}}
+
+	es := buildFallbackBriefing(sections, briefingMessagesFor("es"))
+	assert.Contains(t, es, "# Briefing parcial")
+	assert.Contains(t, es, "📡 Visto en: HN, r/golang")
+
+	en := buildFallbackBriefing(sections, briefingMessagesFor("en-US"))
+	assert.Contains(t, en, "# Partial Briefing")
+	assert.Contains(t, en, "📡 Seen in: HN, r/golang")
+
+	assert.Equal(t, briefingMessagesFor("en"), briefingMessagesFor("ja"))
+	assert.Equal(t, briefingMessagesFor("en"), briefingMessagesFor(""), "unset keeps the English fallback text")
+}
+
+func TestAppendMultiSourceCoverageLocalized(t *testing.T) {
+	sections := []llm.BriefingSection{{
+		Articles: []llm.SummarizedArticle{{ID: "a1", SeenIn: []string{"HN", "Ars"}}},
+	}}
+
+	out := appendMultiSourceCoverage("", sections, briefingMessagesFor("es"))
+	assert.Contains(t, out, "### 📡 Cobertura multi-fuente")
+	assert.Contains(t, out, "- Noticia sin título")
+}
+
+func TestExceedsTokenBudget(t *testing.T) {
+	assert.False(t, exceedsTokenBudget(0, 1_000_000, 1_000))
+	assert.False(t, exceedsTokenBudget(1000, 600, 400))
+	assert.True(t, exceedsTokenBudget(1000, 600, 401))
+}
+
+func TestTokenTallyPrefersReportedUsage(t *testing.T) {
+	var tally tokenTally
+	tally.add(llm.Usage{Calls: 1, PromptTokens: 900, CompletionTokens: 100, TotalTokens: 1000}, 400)
+	// A failed or usage-less call falls back to the estimate.
+	tally.add(llm.Usage{}, 250)
+	assert.Equal(t, 1250, tally.spent)
+	assert.Equal(t, 650, tally.estimated)
+}
+
+func TestFirstParagraphRuneSafe(t *testing.T) {
+	content := strings.Repeat("ñ", 10) + "🚀🚀\n\nsecond paragraph"
+	out := firstParagraph(&content, 11)
+	assert.True(t, utf8.ValidString(out))
+	assert.Equal(t, strings.Repeat("ñ", 10)+"🚀", out)
+}
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	_, ok := retentionCutoff(now, 0)
+	assert.False(t, ok)
+
+	cutoff, ok := retentionCutoff(now, 30)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), cutoff)
+	assert.NotContains(t, retentionStatuses, "briefed")
+}
+
+func TestBriefingLock(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	lock := &briefingLock{rdb: rdb, ttl: time.Minute}
+	ctx := context.Background()
+
+	release, acquired, err := lock.acquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	assert.Equal(t, time.Minute, mr.TTL(briefingLockKey))
+
+	_, acquired, err = lock.acquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, acquired, "second run must not take the lock")
+
+	release()
+	assert.False(t, mr.Exists(briefingLockKey))
+
+	// A run whose lock expired must not release its successor's lock.
+	staleRelease, acquired, err := lock.acquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	mr.FastForward(2 * time.Minute)
+	_, acquired, err = lock.acquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	staleRelease()
+	assert.True(t, mr.Exists(briefingLockKey))
+}
+
+func TestNilBriefingLockIsUnavailable(t *testing.T) {
+	var lock *briefingLock
+	_, acquired, err := lock.acquire(context.Background())
+	assert.ErrorIs(t, err, errLockUnavailable)
+	assert.False(t, acquired)
+}
+
+func TestDropBriefedClusters(t *testing.T) {
+	candidates := []*models.Article{
+		{ID: "a1", Metadata: json.RawMessage(`{"cluster_id":"c1"}`)},
+		{ID: "a2", Metadata: json.RawMessage(`{"cluster_id":"c2"}`)},
+		{ID: "a3"},
+	}
+
+	kept, dropped := dropBriefedClusters(candidates, map[string]struct{}{"c1": {}})
+	assert.Equal(t, []string{"a1"}, dropped)
+	require.Len(t, kept, 2)
+	assert.Equal(t, "a2", kept[0].ID)
+	assert.Equal(t, "a3", kept[1].ID)
+
+	kept, dropped = dropBriefedClusters(candidates, nil)
+	assert.Empty(t, dropped)
+	assert.Len(t, kept, 3)
+}
+
+func TestCollapseClusteredCandidatesMultiSourceBonus(t *testing.T) {
+	now := time.Now()
+	score := func(v float64) *float64 { return &v }
+	article := func(id, cluster, source string, relevance float64) *models.Article {
+		return &models.Article{
+			ID:             id,
+			SourceType:     "rss",
+			IngestedAt:     now,
+			RelevanceScore: score(relevance),
+			Metadata:       json.RawMessage(fmt.Sprintf(`{"cluster_id":%q,"source_name":%q}`, cluster, source)),
+		}
+	}
+	candidates := func() []*models.Article {
+		return []*models.Article{
+			article("solo", "c-solo", "Feed A", 0.60),
+			article("multi-1", "c-multi", "Feed B", 0.50),
+			article("multi-2", "c-multi", "Feed C", 0.45),
+		}
+	}
+
+	ranked, clusters := collapseClusteredCandidates(candidates(), 10, 0.2)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, "multi-1", ranked[0].ID, "corroborated story outranks the single-source one")
+	assert.InDelta(t, 0.2, clusters["multi-1"].Bonus, 1e-9)
+	assert.Zero(t, clusters["solo"].Bonus)
+
+	// With no bonus the ranking falls back to plain relevance.
+	ranked, clusters = collapseClusteredCandidates(candidates(), 10, 0)
+	assert.Equal(t, "solo", ranked[0].ID)
+	assert.Zero(t, clusters["multi-1"].Bonus)
+
+	assert.Equal(t, 0.0, clampMultiSourceBonus(-1))
+	assert.Equal(t, 0.1, clampMultiSourceBonus(0.1))
+	assert.Equal(t, 1.0, clampMultiSourceBonus(5))
+}
+
+func TestClassifyLimitAndTrim(t *testing.T) {
+	assert.Equal(t, 10, classifyLimit(5, 2))
+	assert.Equal(t, 8, classifyLimit(5, 1.5))
+	assert.Equal(t, 5, classifyLimit(5, 0.5), "never fewer than the section cap")
+	assert.Equal(t, 1, classifyLimit(0, 2))
+
+	ranked := []*models.Article{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	kept, trimmed := trimForClassification(ranked, 2)
+	assert.Len(t, kept, 2)
+	require.Len(t, trimmed, 1)
+	assert.Equal(t, "c", trimmed[0].ID)
+
+	kept, trimmed = trimForClassification(ranked, 5)
+	assert.Len(t, kept, 3)
+	assert.Empty(t, trimmed)
+
+	sec := &models.Section{Name: "tech", MaxBriefingArticles: 2}
+	assert.Zero(t, classifyTokensSaved(ranked, 2, sec), "the section cap itself saves nothing")
+	assert.Negative(t, classifyTokensSaved(ranked, 3, sec), "a multiplier above 1 costs extra")
+	sec.MaxBriefingArticles = 3
+	assert.Positive(t, classifyTokensSaved(ranked, 2, sec))
+}
+
+func TestSectionSchedules(t *testing.T) {
+	global, err := cron.ParseStandard("0 3 * * *")
+	require.NoError(t, err)
+	sections := []*models.Section{
+		{ID: "markets", Name: "markets", Enabled: true, Config: json.RawMessage(`{"schedule":"0 * * * *"}`)},
+		{ID: "world", Name: "world", Enabled: true},
+		{ID: "broken", Name: "broken", Enabled: true, Config: json.RawMessage(`{"schedule":"every hour"}`)},
+		{ID: "off", Name: "off", Enabled: false, Config: json.RawMessage(`{"schedule":"0 * * * *"}`)},
+	}
+	schedules := sectionSchedules(sections, global)
+	require.Len(t, schedules, 3)
+
+	// 10:00 to 11:00: only the hourly section fires.
+	since := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := since.Add(time.Hour)
+	assert.Equal(t, at, nextBriefingRun(global, schedules, since))
+	assert.Equal(t, map[string]struct{}{"markets": {}}, dueSections(schedules, since, at))
+
+	// 02:00 to 03:00: the global schedule fires too, for the sections
+	// without (or with an invalid) schedule of their own, and all three
+	// share the one run instead of getting a briefing each.
+	since = time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)
+	at = since.Add(time.Hour)
+	assert.Equal(t, map[string]struct{}{"markets": {}, "world": {}, "broken": {}}, dueSections(schedules, since, at))
+
+	// Without per-section schedules every section follows the global run.
+	plain := sectionSchedules([]*models.Section{{ID: "world", Enabled: true}}, global)
+	assert.Equal(t, time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC), nextBriefingRun(global, plain, at))
+	assert.Empty(t, dueSections(plain, at, at.Add(23*time.Hour)))
+	assert.Len(t, dueSections(plain, at, at.Add(24*time.Hour)), 1)
+}
+
+func TestWriteDryRunReport(t *testing.T) {
+	var out strings.Builder
+	writeDryRunReport(&out, []dryRunSection{
+		{Name: "tech", Threshold: 0.3, Total: 12, Candidates: 8, Filtered: 3, Summarized: 5},
+	}, 5, 9, llm.Usage{Calls: 3, PromptTokens: 1200, CompletionTokens: 300}, 1400, "# Briefing\n\nBody\n")
+
+	report := out.String()
+	assert.True(t, strings.HasPrefix(report, "===== DRY RUN: nothing was saved ====="))
+	assert.Contains(t, report, "tech")
+	assert.Contains(t, report, "0.30")
+	assert.Contains(t, report, "Would brief 5 articles and mark 9 processed.")
+	assert.Contains(t, report, "LLM usage: 3 calls, 1200 prompt + 300 completion tokens (1400 estimated).")
+	assert.Contains(t, report, "# Briefing\n\nBody\n===== END DRY RUN =====")
+}
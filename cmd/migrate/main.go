@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/config"
+	"github.com/zyrak/flux/internal/store"
+)
+
+const usage = `Usage: flux-migrate [flags] up|down|status
+
+  up      apply every pending *.up.sql migration
+  down    roll back the most recently applied migration (-steps for more)
+  status  list migrations, whether each is applied and whether an applied
+          file was edited since
+
+Flags:
+`
+
+// migrator is the slice of store.Store the migrate commands need.
+type migrator interface {
+	RunMigrations(ctx context.Context, migrationsDir string, strictChecksums bool) error
+	RollbackLastMigration(ctx context.Context, migrationsDir string) (string, error)
+	MigrationStatuses(ctx context.Context, migrationsDir string) ([]store.MigrationStatus, error)
+}
+
+func main() {
+	defaultDir := os.Getenv("MIGRATIONS_DIR")
+	if defaultDir == "" {
+		defaultDir = "migrations"
+	}
+	dir := flag.String("dir", defaultDir, "directory holding the *.up.sql and *.down.sql files")
+	steps := flag.Int("steps", 1, "number of migrations down rolls back")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.Load()
+	setupLogging(cfg.LogLevel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
+	}
+	defer db.Close()
+
+	opts := options{dir: *dir, steps: *steps, strictChecksums: cfg.MigrationsStrictChecksums}
+	if err := run(ctx, db, flag.Arg(0), opts, os.Stdout); err != nil {
+		log.WithError(err).Fatal("Migration command failed")
+	}
+}
+
+// options are the flags and settings the subcommands read.
+type options struct {
+	dir             string
+	steps           int
+	strictChecksums bool
+}
+
+// run executes one migrate subcommand against db.
+func run(ctx context.Context, db migrator, command string, opts options, out io.Writer) error {
+	switch command {
+	case "up":
+		return db.RunMigrations(ctx, opts.dir, opts.strictChecksums)
+	case "down":
+		return rollback(ctx, db, opts.dir, opts.steps)
+	case "status":
+		statuses, err := db.MigrationStatuses(ctx, opts.dir)
+		if err != nil {
+			return err
+		}
+		return printStatus(out, statuses)
+	default:
+		return fmt.Errorf("unknown command %q (want up, down or status)", command)
+	}
+}
+
+// rollback reverts up to steps migrations, newest first. Each one commits on
+// its own, so a failure stops with the earlier rollbacks kept.
+func rollback(ctx context.Context, db migrator, dir string, steps int) error {
+	if steps < 1 {
+		return fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+	for i := 0; i < steps; i++ {
+		version, err := db.RollbackLastMigration(ctx, dir)
+		if err != nil {
+			return err
+		}
+		if version == "" {
+			log.WithField("rolled_back", i).Info("No applied migrations left")
+			return nil
+		}
+	}
+	return nil
+}
+
+func printStatus(out io.Writer, statuses []store.MigrationStatus) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSTATUS\tAPPLIED AT\tDOWN")
+	for _, st := range statuses {
+		status := "pending"
+		switch {
+		case st.Applied && !st.HasUp:
+			status = "applied (file missing)"
+		case st.Modified:
+			status = "applied (file modified)"
+		case st.Applied:
+			status = "applied"
+		}
+		appliedAt := "-"
+		if st.AppliedAt != nil {
+			appliedAt = st.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		down := "no"
+		if st.HasDown {
+			down = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", st.Version, status, appliedAt, down)
+	}
+	return tw.Flush()
+}
+
+func setupLogging(level string) {
+	log.SetFormatter(&log.JSONFormatter{})
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		lvl = log.InfoLevel
+	}
+	log.SetLevel(lvl)
+}
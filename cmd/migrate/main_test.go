@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/store"
+)
+
+type fakeMigrator struct {
+	applied    []string
+	ranUp      bool
+	strict     bool
+	failOn     string
+	statuses   []store.MigrationStatus
+	rolledBack []string
+}
+
+func (f *fakeMigrator) RunMigrations(_ context.Context, _ string, strictChecksums bool) error {
+	f.ranUp, f.strict = true, strictChecksums
+	return nil
+}
+
+func (f *fakeMigrator) RollbackLastMigration(context.Context, string) (string, error) {
+	if len(f.applied) == 0 {
+		return "", nil
+	}
+	last := f.applied[len(f.applied)-1]
+	if last == f.failOn {
+		return "", errors.New("executing migration " + last + ".down.sql: boom")
+	}
+	f.applied = f.applied[:len(f.applied)-1]
+	f.rolledBack = append(f.rolledBack, last)
+	return last, nil
+}
+
+func (f *fakeMigrator) MigrationStatuses(context.Context, string) ([]store.MigrationStatus, error) {
+	return f.statuses, nil
+}
+
+func TestRunUpAndDown(t *testing.T) {
+	var out bytes.Buffer
+	db := &fakeMigrator{applied: []string{"000001_init", "000002_seed", "000003_notes"}}
+
+	require.NoError(t, run(context.Background(), db, "up", options{dir: "migrations", strictChecksums: true}, &out))
+	assert.True(t, db.ranUp)
+	assert.True(t, db.strict)
+
+	require.NoError(t, run(context.Background(), db, "down", options{dir: "migrations", steps: 1}, &out))
+	assert.Equal(t, []string{"000003_notes"}, db.rolledBack)
+
+	require.NoError(t, run(context.Background(), db, "down", options{dir: "migrations", steps: 5}, &out))
+	assert.Equal(t, []string{"000003_notes", "000002_seed", "000001_init"}, db.rolledBack)
+	assert.Empty(t, db.applied)
+
+	assert.Error(t, run(context.Background(), db, "down", options{dir: "migrations", steps: 0}, &out))
+	assert.Error(t, run(context.Background(), db, "sideways", options{dir: "migrations", steps: 1}, &out))
+}
+
+func TestRunDownStopsAtFailure(t *testing.T) {
+	db := &fakeMigrator{applied: []string{"000001_init", "000002_seed", "000003_notes"}, failOn: "000002_seed"}
+	err := run(context.Background(), db, "down", options{dir: "migrations", steps: 3}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "000002_seed.down.sql")
+	assert.Equal(t, []string{"000003_notes"}, db.rolledBack)
+	assert.Equal(t, []string{"000001_init", "000002_seed"}, db.applied)
+}
+
+func TestRunStatus(t *testing.T) {
+	at := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	db := &fakeMigrator{statuses: []store.MigrationStatus{
+		{Version: "000001_init", Applied: true, AppliedAt: &at, HasUp: true, HasDown: true},
+		{Version: "000002_gone", Applied: true, AppliedAt: &at},
+		{Version: "000003_edited", Applied: true, AppliedAt: &at, Modified: true, HasUp: true},
+		{Version: "000004_notes", HasUp: true},
+	}}
+
+	var out bytes.Buffer
+	require.NoError(t, run(context.Background(), db, "status", options{dir: "migrations", steps: 1}, &out))
+	assert.Equal(t, `VERSION        STATUS                   APPLIED AT            DOWN
+000001_init    applied                  2026-10-01T08:30:00Z  yes
+000002_gone    applied (file missing)   2026-10-01T08:30:00Z  no
+000003_edited  applied (file modified)  2026-10-01T08:30:00Z  no
+000004_notes   pending                  -                     no
+`, out.String())
+}
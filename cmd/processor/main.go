@@ -4,34 +4,46 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/signal"
 	"sort"
-	"strings"
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 	"github.com/zyrak/flux/internal/config"
 	"github.com/zyrak/flux/internal/dedup"
 	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/lang"
 	"github.com/zyrak/flux/internal/models"
 	"github.com/zyrak/flux/internal/profile"
 	"github.com/zyrak/flux/internal/queue"
 	"github.com/zyrak/flux/internal/relevance"
 	"github.com/zyrak/flux/internal/store"
+	"github.com/zyrak/flux/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-type newArticleEvent struct {
-	ArticleID string `json:"article_id"`
-}
-
 type processor struct {
 	store     *store.Store
 	embed     *embeddings.Client
 	relevance *relevance.Engine
 	semDedup  *dedup.SemanticClusterer
+	embedText embeddings.TextOptions
+	// embedTimeout bounds EmbedSingle for one article.
+	embedTimeout time.Duration
 }
 
+// Handlers get handleArticleTimeout; embedding gets embedArticleTimeout of it,
+// so a hanging embeddings service parks the article before the handler
+// context expires and the delivery counts toward PROCESSOR_MAX_DELIVER.
+const (
+	handleArticleTimeout = 2 * time.Minute
+	embedArticleTimeout  = 75 * time.Second
+)
+
 func main() {
 	cfg := config.Load()
 	setupLogging(cfg.LogLevel)
@@ -41,7 +53,19 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	shutdownTracing, err := tracing.Setup(ctx, "processor", cfg.OTLPEndpoint)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.WithError(err).Warn("Failed to flush traces")
+		}
+	}()
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
 	}
@@ -53,40 +77,100 @@ func main() {
 	}
 	defer q.Close()
 
-	embedClient := embeddings.NewClient(cfg.EmbeddingsURL)
-	relEngine, err := waitForRelevanceEngine(ctx, db, embedClient, relevance.Config{
-		DefaultThreshold: cfg.RelevanceThresholdDefault,
-		MinThreshold:     cfg.RelevanceThresholdMin,
-		MaxThreshold:     cfg.RelevanceThresholdMax,
-		ThresholdStep:    cfg.RelevanceThresholdStep,
-		SourceBoosts:     cfg.SourceBoosts,
-	})
+	embedText, err := embeddings.NewTextOptions(cfg.EmbeddingTextStrategy, cfg.EmbeddingSourceStrategies, cfg.EmbeddingTitleWeight, cfg.EmbeddingContentChars)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid embedding text configuration")
+	}
+
+	embedOpts := embeddings.Options{
+		BatchSize:         cfg.EmbeddingsBatchSize,
+		Provider:          cfg.EmbeddingsProvider,
+		APIKey:            cfg.EmbeddingsAPIKey,
+		Model:             cfg.EmbeddingsModel,
+		Dimensions:        cfg.EmbeddingsRequestDimension,
+		ExpectedDimension: cfg.EmbeddingsStoreDimension,
+	}
+	if err := embedOpts.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid embeddings configuration")
+	}
+	embedClient := embeddings.NewClientWithOptions(cfg.EmbeddingsURL, embedOpts)
+	relCfg := relevance.Config{
+		DefaultThreshold:  cfg.RelevanceThresholdDefault,
+		MinThreshold:      cfg.RelevanceThresholdMin,
+		MaxThreshold:      cfg.RelevanceThresholdMax,
+		ThresholdStep:     cfg.RelevanceThresholdStep,
+		SourceBoosts:      cfg.SourceBoosts,
+		FreshnessWeight:   cfg.RelevanceFreshnessWeight,
+		FreshnessHalfLife: cfg.RelevanceFreshnessHalfLife,
+		EmbeddingDim:      cfg.EmbeddingsStoreDimension,
+		AdjustUpperBound:  cfg.RelevanceAdjustUpperBound,
+		AdjustLowerBound:  cfg.RelevanceAdjustLowerBound,
+		AdjustMode:        cfg.RelevanceAdjustMode,
+	}
+	// Checked up front because waitForRelevanceEngine retries every error.
+	if err := relCfg.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid relevance configuration")
+	}
+	relEngine, err := waitForRelevanceEngine(ctx, db, embedClient, relCfg)
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize relevance engine")
 	}
 
+	semDedup, err := dedup.NewSemanticClustererWithThreshold(cfg.DedupSemanticThreshold)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid DEDUP_SEMANTIC_THRESHOLD")
+	}
+
 	proc := &processor{
 		store:     db,
 		embed:     embedClient,
 		relevance: relEngine,
-		semDedup:  dedup.NewSemanticClusterer(),
+		semDedup:  semDedup,
+		embedText: embedText,
+
+		embedTimeout: embedArticleTimeout,
 	}
 
-	profileRecalc := profile.NewRecalculator(db, embedClient, 0.7)
-	if cfg.ProfileRecalcTrigger == "hourly" {
-		log.WithField("every", cfg.ProfileRecalcEvery.String()).Info("Section profile recalculation enabled in hourly mode")
-		go runHourlyProfileRecalculation(ctx, profileRecalc, cfg.ProfileRecalcEvery)
+	profileRecalc := profile.NewRecalculator(db, embedClient, 0.7, cfg.ProfileHalfLife)
+	if scheduledProfileRecalc(cfg.ProfileRecalcTrigger) {
+		redisOpts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to parse REDIS_URL")
+		}
+		rdb := redis.NewClient(redisOpts)
+		defer func() { _ = rdb.Close() }()
+
+		if err := rdb.Ping(ctx).Err(); err != nil {
+			log.WithError(err).Fatal("Failed to connect to Redis")
+		}
+
+		log.WithField("every", cfg.ProfileRecalcEvery.String()).Info("Section profile recalculation enabled in scheduled mode")
+		go runScheduledProfileRecalculation(ctx, profileRecalc, rdb, cfg.ProfileRecalcEvery)
 	} else {
-		log.WithField("trigger", cfg.ProfileRecalcTrigger).Info("Section profile recalculation hourly loop disabled")
+		log.WithField("trigger", cfg.ProfileRecalcTrigger).Info("Section profile recalculation schedule disabled")
 	}
 
-	if err := q.Subscribe(ctx, queue.SubjectArticlesNew, "flux-processor", proc.handleNewArticle); err != nil {
+	if cfg.EmbeddingRetryEvery > 0 {
+		go runEmbeddingRetrySweeper(ctx, db, embedClient, q, cfg.EmbeddingRetryEvery)
+	}
+
+	subCfg := queue.SubscribeConfig{
+		Concurrency: cfg.ProcessorConcurrency,
+		MaxDeliver:  cfg.ProcessorMaxDeliver,
+		// Outlast the handler so slow articles are not redelivered mid-flight.
+		AckWait: handleArticleTimeout + 30*time.Second,
+		// Keep articles that never process for inspection via /api/admin/dead-letters.
+		DeadLetterSubject: queue.SubjectArticlesDeadLetter,
+	}
+	if err := q.SubscribeWithConfig(ctx, queue.SubjectArticlesNew, queue.DurableProcessor, subCfg, proc.handleNewArticle); err != nil {
 		log.WithError(err).Fatal("Failed to subscribe to articles.new")
 	}
 
 	log.WithFields(log.Fields{
 		"subject":        queue.SubjectArticlesNew,
 		"embeddings_url": cfg.EmbeddingsURL,
+		"concurrency":    subCfg.Concurrency,
+		"max_deliver":    subCfg.MaxDeliver,
 	}).Info("Processor subscribed and ready")
 
 	<-ctx.Done()
@@ -94,7 +178,76 @@ func main() {
 	log.Info("Processor shutting down")
 }
 
-func runHourlyProfileRecalculation(ctx context.Context, recalc *profile.Recalculator, every time.Duration) {
+// needsEmbeddingBatch bounds how many parked articles one sweep requeues.
+const needsEmbeddingBatch = 200
+
+type parkedArticleStore interface {
+	RequeueArticlesNeedingEmbedding(ctx context.Context, limit int, publish func(id string) error) ([]string, error)
+}
+
+type embeddingsPinger interface {
+	Ping(ctx context.Context) error
+}
+
+type eventPublisher interface {
+	Publish(subject string, data interface{}) error
+}
+
+func runEmbeddingRetrySweeper(ctx context.Context, st parkedArticleStore, embed embeddingsPinger, pub eventPublisher, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			requeued, err := requeueNeedsEmbedding(ctx, st, embed, pub)
+			if err != nil {
+				log.WithError(err).Warn("Failed to requeue articles needing embedding")
+			} else if requeued > 0 {
+				log.WithField("requeued", requeued).Info("Requeued articles needing embedding")
+			}
+		}
+	}
+}
+
+// requeueNeedsEmbedding republishes parked articles on articles.new once the
+// embeddings service answers again. Articles only go back to pending in the
+// transaction that claimed them, once published, so a failed publish or a
+// crash leaves them parked for the next sweep.
+func requeueNeedsEmbedding(ctx context.Context, st parkedArticleStore, embed embeddingsPinger, pub eventPublisher) (int, error) {
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	err := embed.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		log.WithError(err).Debug("Embeddings still unavailable, leaving parked articles")
+		return 0, nil
+	}
+
+	ids, err := st.RequeueArticlesNeedingEmbedding(ctx, needsEmbeddingBatch, func(id string) error {
+		// A requeued article starts a new event; the original worker trace
+		// and id ended when it was parked.
+		if err := pub.Publish(queue.SubjectArticlesNew, queue.NewArticleEvent{ArticleID: id, EventID: queue.NewEventID()}); err != nil {
+			return fmt.Errorf("publishing articles.new for %s: %w", id, err)
+		}
+		return nil
+	})
+	return len(ids), err
+}
+
+const (
+	profileRecalcScheduled = "scheduled"
+	// profileRecalcHourly is the original name of the scheduled trigger.
+	profileRecalcHourly = "hourly"
+
+	profileRecalcLockKey = "flux:profile-recalc:lock"
+)
+
+func scheduledProfileRecalc(trigger string) bool {
+	return trigger == profileRecalcScheduled || trigger == profileRecalcHourly
+}
+
+func runScheduledProfileRecalculation(ctx context.Context, recalc *profile.Recalculator, rdb *redis.Client, every time.Duration) {
 	if every <= 0 {
 		every = time.Hour
 	}
@@ -102,30 +255,60 @@ func runHourlyProfileRecalculation(ctx context.Context, recalc *profile.Recalcul
 	ticker := time.NewTicker(every)
 	defer ticker.Stop()
 
-	// Run one recalculation cycle on startup in hourly mode so profiles are not stale.
-	if err := recalc.RecalculateAllSections(ctx); err != nil {
-		log.WithError(err).Warn("Initial section profile recalculation failed")
-	} else {
-		log.Info("Initial section profile recalculation completed")
-	}
-
+	// Run one cycle on startup so profiles are not stale after a deploy.
+	runProfileRecalcCycle(ctx, recalc, rdb, every)
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
-			err := recalc.RecalculateAllSections(runCtx)
-			cancel()
-			if err != nil {
-				log.WithError(err).Warn("Hourly section profile recalculation failed")
-				continue
-			}
-			log.Info("Hourly section profile recalculation completed")
+			runProfileRecalcCycle(ctx, recalc, rdb, every)
 		}
 	}
 }
 
+// runProfileRecalcCycle recalculates every section unless another processor
+// replica already did so during this interval.
+func runProfileRecalcCycle(ctx context.Context, recalc *profile.Recalculator, rdb *redis.Client, every time.Duration) {
+	claimed, err := claimProfileRecalc(ctx, rdb, every)
+	if err != nil {
+		log.WithError(err).Warn("Failed to claim profile recalculation lock, recalculating anyway")
+	} else if !claimed {
+		log.Debug("Section profile recalculation already claimed by another instance")
+		return
+	}
+
+	start := time.Now()
+	runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	sections, err := recalc.RecalculateAllSections(runCtx)
+	cancel()
+	fields := log.Fields{
+		"sections":    sections,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		log.WithFields(fields).WithError(err).Warn("Scheduled section profile recalculation failed")
+		return
+	}
+	log.WithFields(fields).Info("Scheduled section profile recalculation completed")
+}
+
+// claimProfileRecalc takes the recalculation slot for this interval. The lock
+// is never released: it expires just before the next tick, so each interval
+// runs on exactly one replica even if their tickers are out of phase.
+func claimProfileRecalc(ctx context.Context, rdb *redis.Client, every time.Duration) (bool, error) {
+	ttl := every - every/10
+	if ttl <= 0 {
+		ttl = every
+	}
+	owner, _ := os.Hostname()
+	claimed, err := rdb.SetNX(ctx, profileRecalcLockKey, owner, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("claiming profile recalculation lock: %w", err)
+	}
+	return claimed, nil
+}
+
 func waitForRelevanceEngine(ctx context.Context, db *store.Store, embedClient *embeddings.Client, cfg relevance.Config) (*relevance.Engine, error) {
 	backoff := 2 * time.Second
 	for {
@@ -148,8 +331,25 @@ func waitForRelevanceEngine(ctx context.Context, db *store.Store, embedClient *e
 	}
 }
 
-func (p *processor) handleNewArticle(data []byte) error {
-	var evt newArticleEvent
+// embedArticle embeds text within p.embedTimeout, which ends well before the
+// handler's own deadline.
+func (p *processor) embedArticle(ctx context.Context, text string) ([]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.embedTimeout)
+	defer cancel()
+	return p.embed.EmbedSingle(ctx, text)
+}
+
+// parkOnEmbedError reports whether an embedding failure should park the
+// article as needs_embedding. Transient failures park it; rejected requests
+// and unusable responses are returned so they reach the dead-letter stream
+// instead of being requeued forever, and so is anything after the handler
+// context itself is done.
+func parkOnEmbedError(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && !embeddings.IsPermanent(err)
+}
+
+func (p *processor) handleNewArticle(data []byte) (err error) {
+	var evt queue.NewArticleEvent
 	if err := json.Unmarshal(data, &evt); err != nil {
 		return fmt.Errorf("invalid articles.new payload: %w", err)
 	}
@@ -157,35 +357,72 @@ func (p *processor) handleNewArticle(data []byte) error {
 		return fmt.Errorf("articles.new payload missing article_id")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(tracing.Extract(context.Background(), evt.TraceContext), handleArticleTimeout)
 	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "processor.handle_article",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("flux.article_id", evt.ArticleID)))
+	defer func() { tracing.End(span, err) }()
+	logger := log.WithFields(queue.ArticleLogFields(evt.EventID, evt.ArticleID))
 
 	article, err := p.store.GetArticleByID(ctx, evt.ArticleID)
 	if err != nil {
 		return fmt.Errorf("loading article %s: %w", evt.ArticleID, err)
 	}
 	if article == nil {
-		log.WithField("article_id", evt.ArticleID).Warn("Article not found, skipping")
+		logger.Warn("Article not found, skipping")
 		return nil
 	}
 
-	text := buildEmbeddingText(article)
-	articleEmbedding, err := p.embed.EmbedSingle(ctx, text)
+	p.recordLanguage(ctx, logger, article)
+
+	text := p.embedText.BuildText(article)
+	embedCtx, embedSpan := tracing.Tracer().Start(ctx, "embeddings.embed")
+	articleEmbedding, err := p.embedArticle(embedCtx, text)
+	tracing.End(embedSpan, err)
 	if err != nil {
-		return fmt.Errorf("embedding article %s: %w", article.ID, err)
+		if !parkOnEmbedError(ctx, err) {
+			return fmt.Errorf("embedding article %s: %w", article.ID, err)
+		}
+		// The deadline or the client's retries ran out. Park the article
+		// instead of redelivering so an embeddings outage does not stall the
+		// queue; requeueNeedsEmbedding picks it up once the service is back.
+		if statusErr := p.store.UpdateArticleStatus(ctx, article.ID, models.StatusNeedsEmbedding); statusErr != nil {
+			return fmt.Errorf("embedding article %s: %w", article.ID, err)
+		}
+		logger.WithError(err).Warn("Embedding failed, parked article as needs_embedding")
+		return nil
 	}
 	if err := p.store.UpdateArticleEmbedding(ctx, article.ID, articleEmbedding); err != nil {
 		return fmt.Errorf("updating embedding for article %s: %w", article.ID, err)
 	}
 
-	if err := p.applySemanticDedup(ctx, article, articleEmbedding); err != nil {
+	dedupCtx, dedupSpan := tracing.Tracer().Start(ctx, "dedup.semantic")
+	err = p.applySemanticDedup(dedupCtx, logger, article, articleEmbedding)
+	tracing.End(dedupSpan, err)
+	if err != nil {
 		return fmt.Errorf("semantic dedup for article %s: %w", article.ID, err)
 	}
 
-	result, err := p.relevance.EvaluateArticle(ctx, article, articleEmbedding)
+	relevanceCtx, relevanceSpan := tracing.Tracer().Start(ctx, "relevance.evaluate")
+	result, err := p.relevance.EvaluateArticle(relevanceCtx, article, articleEmbedding)
+	tracing.End(relevanceSpan, err)
 	if err != nil {
 		return fmt.Errorf("evaluating relevance for article %s: %w", article.ID, err)
 	}
+	span.SetAttributes(
+		attribute.String("flux.section_id", result.SectionID),
+		attribute.String("flux.status", result.Status),
+		attribute.Float64("flux.relevance_score", result.RelevanceScore),
+	)
+
+	if len(result.SectionCandidates) > 0 {
+		// Context for the briefing classifier only; losing it is not worth
+		// redelivering the article.
+		if err := p.recordSectionCandidates(ctx, article, result.SectionCandidates); err != nil {
+			logger.WithError(err).Warn("Failed to record section candidates")
+		}
+	}
 
 	if err := p.store.UpdateArticleSectionAndStatus(ctx, article.ID, result.SectionID, result.RelevanceScore, result.Status); err != nil {
 		return fmt.Errorf("updating section/score/status for article %s: %w", article.ID, err)
@@ -193,11 +430,10 @@ func (p *processor) handleNewArticle(data []byte) error {
 
 	newThreshold, changed, err := p.relevance.AdjustThreshold(ctx, result.SectionID)
 	if err != nil {
-		log.WithField("section_id", result.SectionID).WithError(err).Warn("Failed to adjust section threshold")
+		logger.WithField("section_id", result.SectionID).WithError(err).Warn("Failed to adjust section threshold")
 	}
 
 	logFields := log.Fields{
-		"article_id":      article.ID,
 		"section_id":      result.SectionID,
 		"section":         result.SectionName,
 		"relevance_score": result.RelevanceScore,
@@ -211,12 +447,49 @@ func (p *processor) handleNewArticle(data []byte) error {
 	if changed {
 		logFields["new_threshold"] = newThreshold
 	}
-	log.WithFields(logFields).Info("Article processed")
+	logger.WithFields(logFields).Info("Article processed")
 
 	return nil
 }
 
-func (p *processor) applySemanticDedup(ctx context.Context, article *models.Article, embedding []float32) error {
+// recordSectionCandidates stores the sections a multi-section source's
+// article was weighed between in metadata.section_candidates.
+func (p *processor) recordSectionCandidates(ctx context.Context, article *models.Article, candidates []relevance.SectionCandidate) error {
+	metadata, err := relevance.WithSectionCandidates(article.Metadata, candidates)
+	if err != nil {
+		return err
+	}
+	if err := p.store.UpdateArticleMetadata(ctx, article.ID, metadata); err != nil {
+		return err
+	}
+	article.Metadata = metadata
+	return nil
+}
+
+// recordLanguage stores the article's detected language in metadata.lang.
+// Detection is best effort: short or ambiguous text stays untagged and a
+// failed write is only logged, so it never holds up ingestion.
+func (p *processor) recordLanguage(ctx context.Context, logger *log.Entry, article *models.Article) {
+	text := article.Title
+	if article.Content != nil {
+		text += "\n" + *article.Content
+	}
+	code := lang.Detect(text)
+	if code == "" {
+		return
+	}
+	metadata, err := lang.WithLang(article.Metadata, code)
+	if err == nil {
+		err = p.store.UpdateArticleMetadata(ctx, article.ID, metadata)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Failed to record article language")
+		return
+	}
+	article.Metadata = metadata
+}
+
+func (p *processor) applySemanticDedup(ctx context.Context, logger *log.Entry, article *models.Article, embedding []float32) error {
 	neighbors, err := p.store.FindSimilarArticlesLast48h(ctx, embedding, article.ID, dedup.SemanticNeighborsLimit)
 	if err != nil {
 		return err
@@ -268,8 +541,7 @@ func (p *processor) applySemanticDedup(ctx context.Context, article *models.Arti
 		article.Metadata = currentMetadata
 	}
 
-	log.WithFields(log.Fields{
-		"article_id":      article.ID,
+	logger.WithFields(log.Fields{
 		"cluster_id":      result.ClusterID,
 		"primary_id":      result.PrimaryID,
 		"cluster_members": len(result.MemberIDs),
@@ -279,23 +551,6 @@ func (p *processor) applySemanticDedup(ctx context.Context, article *models.Arti
 	return nil
 }
 
-func buildEmbeddingText(article *models.Article) string {
-	content := ""
-	if article.Content != nil {
-		content = *article.Content
-	}
-	content = strings.TrimSpace(content)
-	if len(content) > 500 {
-		content = content[:500]
-	}
-
-	title := strings.TrimSpace(article.Title)
-	if content == "" {
-		return title
-	}
-	return title + "\n\n" + content
-}
-
 func setupLogging(level string) {
 	log.SetFormatter(&log.JSONFormatter{})
 	lvl, err := log.ParseLevel(level)
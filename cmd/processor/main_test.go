@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/queue"
+)
+
+func TestClaimProfileRecalc(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	ctx := context.Background()
+
+	claimed, err := claimProfileRecalc(ctx, rdb, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, 54*time.Minute, mr.TTL(profileRecalcLockKey))
+
+	claimed, err = claimProfileRecalc(ctx, rdb, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, claimed, "a second replica must skip this interval")
+
+	mr.FastForward(55 * time.Minute)
+	claimed, err = claimProfileRecalc(ctx, rdb, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestScheduledProfileRecalc(t *testing.T) {
+	assert.True(t, scheduledProfileRecalc("scheduled"))
+	assert.True(t, scheduledProfileRecalc("hourly"))
+	assert.False(t, scheduledProfileRecalc("immediate"))
+}
+
+type parkedStore struct {
+	parked []string
+	status map[string]string
+}
+
+// RequeueArticlesNeedingEmbedding mimics the store's transaction: only the
+// IDs published before a failure leave the parked list.
+func (s *parkedStore) RequeueArticlesNeedingEmbedding(_ context.Context, limit int, publish func(id string) error) ([]string, error) {
+	if len(s.parked) < limit {
+		limit = len(s.parked)
+	}
+	var published []string
+	var err error
+	for _, id := range s.parked[:limit] {
+		if err = publish(id); err != nil {
+			break
+		}
+		published = append(published, id)
+	}
+	s.parked = s.parked[len(published):]
+	for _, id := range published {
+		s.status[id] = models.StatusPending
+	}
+	return published, err
+}
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) Ping(context.Context) error { return p.err }
+
+type fakePublisher struct {
+	published []string
+	failOn    string
+}
+
+func (p *fakePublisher) Publish(_ string, data interface{}) error {
+	evt := data.(queue.NewArticleEvent)
+	if evt.ArticleID == p.failOn {
+		return errors.New("nats unavailable")
+	}
+	p.published = append(p.published, evt.ArticleID)
+	return nil
+}
+
+func TestRequeueNeedsEmbedding(t *testing.T) {
+	ctx := context.Background()
+	st := &parkedStore{parked: []string{"a1", "a2", "a3"}, status: map[string]string{}}
+	pub := &fakePublisher{}
+
+	// Nothing moves while the embeddings service is still down.
+	n, err := requeueNeedsEmbedding(ctx, st, fakePinger{err: errors.New("connection refused")}, pub)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Len(t, st.parked, 3)
+
+	pub.failOn = "a2"
+	n, err = requeueNeedsEmbedding(ctx, st, fakePinger{}, pub)
+	assert.Error(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"a1"}, pub.published)
+	assert.Equal(t, models.StatusPending, st.status["a1"])
+	assert.Equal(t, []string{"a2", "a3"}, st.parked, "a failed publish leaves the rest parked")
+
+	pub.failOn = ""
+	n, err = requeueNeedsEmbedding(ctx, st, fakePinger{}, pub)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a1", "a2", "a3"}, pub.published)
+	assert.Empty(t, st.parked)
+}
+
+func TestEmbedArticleParksWhenServiceHangs(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+	p := &processor{embed: embeddings.NewClient(srv.URL), embedTimeout: 100 * time.Millisecond}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := p.embedArticle(ctx, "text")
+	require.Error(t, err)
+	assert.NoError(t, ctx.Err(), "the embed deadline must end before the handler's")
+	assert.True(t, parkOnEmbedError(ctx, err))
+}
+
+func TestEmbedArticleRejectsWrongDimension(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(embeddings.EmbeddingResponse{Embeddings: [][]float32{{0.1, 0.2}}})
+	}))
+	t.Cleanup(srv.Close)
+	client := embeddings.NewClientWithOptions(srv.URL, embeddings.Options{ExpectedDimension: 384})
+	p := &processor{embed: client, embedTimeout: 5 * time.Second}
+
+	ctx := context.Background()
+	_, err := p.embedArticle(ctx, "text")
+	require.Error(t, err)
+	assert.False(t, parkOnEmbedError(ctx, err), "a dimension mismatch must be dead-lettered, not parked")
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/config"
+	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/store"
+)
+
+// articleStore is the slice of store.Store the backfill needs.
+type articleStore interface {
+	ListArticlesMissingEmbedding(ctx context.Context, afterID string, limit int) ([]*models.Article, error)
+	UpdateArticleEmbedding(ctx context.Context, id string, embedding []float32) error
+}
+
+type embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+type reindexStats struct {
+	Embedded int
+	Skipped  int
+	LastID   string
+}
+
+func main() {
+	pageSize := flag.Int("page", 256, "articles fetched and sent to the embeddings client per page")
+	after := flag.String("after", "", "resume after this article id (as logged by a previous run)")
+	limit := flag.Int("limit", 0, "stop after embedding this many articles (0 = no limit)")
+	flag.Parse()
+
+	cfg := config.Load()
+	setupLogging(cfg.LogLevel)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
+	if err != nil {
+		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
+	}
+	defer db.Close()
+
+	remaining, err := db.CountArticlesMissingEmbedding(ctx)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to count articles missing embeddings")
+	}
+	log.WithFields(log.Fields{
+		"missing":        remaining,
+		"page":           *pageSize,
+		"after":          *after,
+		"embeddings_url": cfg.EmbeddingsURL,
+	}).Info("Starting embedding backfill")
+
+	// Same options as the processor, so backfilled vectors are comparable
+	// with live ones.
+	embedText, err := embeddings.NewTextOptions(cfg.EmbeddingTextStrategy, cfg.EmbeddingSourceStrategies, cfg.EmbeddingTitleWeight, cfg.EmbeddingContentChars)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid embedding text configuration")
+	}
+
+	embedOpts := embeddings.Options{
+		BatchSize:         cfg.EmbeddingsBatchSize,
+		Provider:          cfg.EmbeddingsProvider,
+		APIKey:            cfg.EmbeddingsAPIKey,
+		Model:             cfg.EmbeddingsModel,
+		Dimensions:        cfg.EmbeddingsRequestDimension,
+		ExpectedDimension: cfg.EmbeddingsStoreDimension,
+	}
+	if err := embedOpts.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid embeddings configuration")
+	}
+	embedClient := embeddings.NewClientWithOptions(cfg.EmbeddingsURL, embedOpts)
+	start := time.Now()
+	stats, err := reindex(ctx, db, embedClient, embedText, *after, *pageSize, *limit)
+	fields := log.Fields{
+		"embedded":    stats.Embedded,
+		"skipped":     stats.Skipped,
+		"last_id":     stats.LastID,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		log.WithFields(fields).WithError(err).Fatal("Embedding backfill stopped; rerun to resume")
+	}
+	log.WithFields(fields).Info("Embedding backfill finished")
+}
+
+// reindex embeds every article missing an embedding, page by page in id
+// order. Finished articles drop out of the query, so a rerun resumes where a
+// failed or cancelled one stopped; afterID skips ahead explicitly.
+func reindex(ctx context.Context, st articleStore, embed embedder, embedText embeddings.TextOptions, afterID string, pageSize, limit int) (reindexStats, error) {
+	if pageSize <= 0 {
+		pageSize = 256
+	}
+	stats := reindexStats{LastID: afterID}
+
+	for limit <= 0 || stats.Embedded < limit {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		fetch := pageSize
+		if limit > 0 && limit-stats.Embedded < fetch {
+			fetch = limit - stats.Embedded
+		}
+		articles, err := st.ListArticlesMissingEmbedding(ctx, stats.LastID, fetch)
+		if err != nil {
+			return stats, err
+		}
+		if len(articles) == 0 {
+			return stats, nil
+		}
+
+		pending := make([]*models.Article, 0, len(articles))
+		texts := make([]string, 0, len(articles))
+		for _, article := range articles {
+			text := embedText.BuildText(article)
+			if text == "" {
+				stats.Skipped++
+				continue
+			}
+			pending = append(pending, article)
+			texts = append(texts, text)
+		}
+
+		vectors, err := embed.Embed(ctx, texts)
+		if err != nil {
+			return stats, fmt.Errorf("embedding page after %q: %w", stats.LastID, err)
+		}
+		if len(vectors) != len(pending) {
+			return stats, fmt.Errorf("embeddings count mismatch: requested=%d got=%d", len(pending), len(vectors))
+		}
+
+		for i, article := range pending {
+			if err := st.UpdateArticleEmbedding(ctx, article.ID, vectors[i]); err != nil {
+				return stats, fmt.Errorf("updating embedding for %s: %w", article.ID, err)
+			}
+			stats.Embedded++
+		}
+		stats.LastID = articles[len(articles)-1].ID
+
+		log.WithFields(log.Fields{
+			"embedded": stats.Embedded,
+			"skipped":  stats.Skipped,
+			"last_id":  stats.LastID,
+		}).Info("Embedding backfill progress")
+	}
+	return stats, nil
+}
+
+func setupLogging(level string) {
+	log.SetFormatter(&log.JSONFormatter{})
+	lvl, err := log.ParseLevel(level)
+	if err != nil {
+		lvl = log.InfoLevel
+	}
+	log.SetLevel(lvl)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/models"
+)
+
+type memoryStore struct {
+	articles   []*models.Article
+	embeddings map[string][]float32
+	failAfter  int
+}
+
+func newMemoryStore(n int) *memoryStore {
+	st := &memoryStore{embeddings: make(map[string][]float32)}
+	for i := 0; i < n; i++ {
+		st.articles = append(st.articles, &models.Article{ID: fmt.Sprintf("a%04d", i), Title: fmt.Sprintf("Story %d", i)})
+	}
+	return st
+}
+
+func (m *memoryStore) ListArticlesMissingEmbedding(_ context.Context, afterID string, limit int) ([]*models.Article, error) {
+	var out []*models.Article
+	for _, a := range m.articles {
+		if _, done := m.embeddings[a.ID]; done || a.ID <= afterID {
+			continue
+		}
+		out = append(out, a)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryStore) UpdateArticleEmbedding(_ context.Context, id string, embedding []float32) error {
+	if m.failAfter > 0 && len(m.embeddings) >= m.failAfter {
+		return fmt.Errorf("database unavailable")
+	}
+	m.embeddings[id] = embedding
+	return nil
+}
+
+func newEmbeddingsServer(t *testing.T) (*embeddings.Client, func() []int) {
+	t.Helper()
+	var mu sync.Mutex
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embeddings.EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		sizes = append(sizes, len(req.Texts))
+		mu.Unlock()
+
+		resp := embeddings.EmbeddingResponse{}
+		for range req.Texts {
+			resp.Embeddings = append(resp.Embeddings, []float32{1, 0, 0})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return embeddings.NewClient(srv.URL), func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), sizes...)
+	}
+}
+
+func TestReindexBatchesThroughClient(t *testing.T) {
+	st := newMemoryStore(300)
+	empty := ""
+	st.articles[5].Title = "  "
+	st.articles[5].Content = &empty
+	client, sizes := newEmbeddingsServer(t)
+
+	stats, err := reindex(context.Background(), st, client, embeddings.TextOptions{}, "", 256, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 299, stats.Embedded)
+	assert.Equal(t, 1, stats.Skipped)
+	assert.Equal(t, "a0299", stats.LastID)
+	assert.Len(t, st.embeddings, 299)
+
+	// The 255-text first page is split by the client into chunks of 32; the
+	// 44-text tail page fits in a single request.
+	got := sizes()
+	sort.Ints(got)
+	assert.Contains(t, got, 32)
+	assert.Equal(t, 44, got[len(got)-1])
+	total := 0
+	for _, n := range got {
+		total += n
+	}
+	assert.Equal(t, 299, total)
+}
+
+func TestReindexResumesAfterFailure(t *testing.T) {
+	st := newMemoryStore(50)
+	st.failAfter = 20
+	client, _ := newEmbeddingsServer(t)
+
+	stats, err := reindex(context.Background(), st, client, embeddings.TextOptions{}, "", 10, 0)
+	require.Error(t, err)
+	assert.Equal(t, 20, stats.Embedded)
+	assert.Equal(t, "a0019", stats.LastID)
+
+	st.failAfter = 0
+	stats, err = reindex(context.Background(), st, client, embeddings.TextOptions{}, "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 30, stats.Embedded)
+	assert.Len(t, st.embeddings, 50)
+}
+
+func TestReindexHonoursLimitAndCancellation(t *testing.T) {
+	st := newMemoryStore(50)
+	client, _ := newEmbeddingsServer(t)
+
+	stats, err := reindex(context.Background(), st, client, embeddings.TextOptions{}, "", 8, 12)
+	require.NoError(t, err)
+	assert.Equal(t, 12, stats.Embedded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = reindex(ctx, st, client, embeddings.TextOptions{}, "", 8, 0)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReindexAppliesSourceStrategies(t *testing.T) {
+	content, summary := "Full release notes.\n\nMore detail.", "Adds streaming."
+	st := &memoryStore{embeddings: make(map[string][]float32), articles: []*models.Article{
+		{ID: "a1", SourceType: "github", Title: "v2.0", Content: &content, Summary: &summary},
+		{ID: "a2", SourceType: "rss", Title: "Post", Content: &content, Summary: &summary},
+	}}
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embeddings.EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		texts = append(texts, req.Texts...)
+		resp := embeddings.EmbeddingResponse{}
+		for range req.Texts {
+			resp.Embeddings = append(resp.Embeddings, []float32{1, 0, 0})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	opts, err := embeddings.NewTextOptions("title_content", map[string]string{"github": "title_summary"}, 1, 0)
+	require.NoError(t, err)
+	_, err = reindex(context.Background(), st, embeddings.NewClient(srv.URL), opts, "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v2.0\n\nAdds streaming.", "Post\n\nFull release notes.\n\nMore detail."}, texts)
+}
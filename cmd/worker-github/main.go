@@ -22,6 +22,7 @@ import (
 	"github.com/zyrak/flux/internal/queue"
 	"github.com/zyrak/flux/internal/ratelimit"
 	"github.com/zyrak/flux/internal/store"
+	"github.com/zyrak/flux/internal/tracing"
 )
 
 const (
@@ -33,16 +34,61 @@ const (
 	requestTimeout = 30 * time.Second
 	runInterval    = time.Hour
 	releaseLimit   = 5
-)
+	tagLimit       = 10
+	commitLimit    = 20
 
-type newArticleEvent struct {
-	ArticleID string `json:"article_id"`
-}
+	githubModeReleases = "releases"
+	githubModeTags     = "tags"
+	githubModeCommits  = "commits"
+
+	defaultCommitSinceHours = 48
+)
 
 type githubSourceConfig struct {
 	Repo  string `json:"repo"`
 	Owner string `json:"owner,omitempty"`
 	Name  string `json:"name,omitempty"`
+	// Mode selects what is ingested: releases (default), tags or commits.
+	Mode string `json:"mode,omitempty"`
+	// SinceHours bounds the commits window; ignored by the other modes.
+	SinceHours int `json:"since_hours,omitempty"`
+}
+
+// githubItem is the mode-independent shape every fetched entry is mapped to
+// before it becomes an article.
+type githubItem struct {
+	SourceID    string
+	Title       string
+	URL         string
+	Content     string
+	Author      string
+	PublishedAt *time.Time
+	Metadata    map[string]interface{}
+}
+
+type githubTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type githubCommit struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Date string `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
 }
 
 type githubRelease struct {
@@ -64,6 +110,8 @@ type githubWorker struct {
 	queue      *queue.Queue
 	httpClient *http.Client
 	token      string
+	// Runs are skipped while more articles than this await the processor.
+	backpressureMax int
 }
 
 type githubRunStats struct {
@@ -83,13 +131,20 @@ type sourceRunStats struct {
 func main() {
 	cfg := config.Load()
 	setupLogging(cfg.LogLevel)
+	dedup.SetTrackingParams(dedup.NewTrackingParams(cfg.DedupTrackingParams, cfg.DedupTrackingDefaults))
 
 	log.Info("Starting Flux GitHub releases worker")
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	shutdownTracing, err := tracing.Setup(ctx, "worker-github", cfg.OTLPEndpoint)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer tracing.Flush(shutdownTracing)
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
 	}
@@ -118,8 +173,11 @@ func main() {
 	}
 
 	limiter, err := ratelimit.New(rdb, ratelimit.Config{
-		Limits:    limits,
-		UserAgent: cfg.UserAgent,
+		Limits:        limits,
+		MaxConcurrent: cfg.RateLimitConcurrency,
+		Jitter:        cfg.RateLimitJitter,
+		UserAgent:     cfg.UserAgent,
+		Proxy:         cfg.OutboundProxy,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize rate limiter")
@@ -135,6 +193,8 @@ func main() {
 		queue:      q,
 		httpClient: ratelimit.NewHTTPClient(limiter, requestTimeout),
 		token:      token,
+
+		backpressureMax: cfg.IngestBackpressureMax,
 	}
 
 	mode := parseWorkerMode()
@@ -173,6 +233,12 @@ func main() {
 
 func (w *githubWorker) runOnce(ctx context.Context) (githubRunStats, error) {
 	stats := githubRunStats{}
+	ctx, span := tracing.Tracer().Start(ctx, "worker-github.run")
+	defer span.End()
+
+	if w.queue.IngestBackpressure(sourceTypeGitHub, w.backpressureMax) {
+		return stats, nil
+	}
 
 	sources, err := w.store.ListSourcesByTypeWithSectionIDs(ctx, sourceTypeGitHub, true)
 	if err != nil {
@@ -185,6 +251,9 @@ func (w *githubWorker) runOnce(ctx context.Context) (githubRunStats, error) {
 		stats.ReleasesSeen += sourceStats.ReleasesSeen
 		stats.NewArticles += sourceStats.NewArticles
 		stats.SkippedSeen += sourceStats.SkippedSeen
+		if logErr := w.store.RecordSourceFetch(ctx, src.Source.ID, sourceStats.ReleasesSeen, sourceStats.NewArticles, err); logErr != nil {
+			log.WithField("source_id", src.Source.ID).WithError(logErr).Warn("Failed to record source fetch")
+		}
 		if err != nil {
 			stats.SourceErrors++
 			log.WithFields(log.Fields{
@@ -208,10 +277,10 @@ func (w *githubWorker) processSource(ctx context.Context, src *store.SourceWithS
 		return stats, err
 	}
 
-	releases, err := w.fetchReleases(ctx, cfg.Repo)
+	items, err := w.fetchItems(ctx, cfg)
 	if err != nil {
 		_ = w.store.UpdateSourceFetchStatus(ctx, src.Source.ID, err)
-		return stats, fmt.Errorf("fetching releases for %s: %w", cfg.Repo, err)
+		return stats, fmt.Errorf("fetching %s for %s: %w", cfg.Mode, cfg.Repo, err)
 	}
 
 	var sectionID *string
@@ -219,55 +288,31 @@ func (w *githubWorker) processSource(ctx context.Context, src *store.SourceWithS
 		sectionID = &src.SectionIDs[0]
 	}
 
-	for _, rel := range releases {
-		if rel.Draft {
-			continue
-		}
-		tag := strings.TrimSpace(rel.TagName)
-		if tag == "" {
-			continue
-		}
-
+	for _, item := range items {
 		stats.ReleasesSeen++
 
-		sourceID := fmt.Sprintf("%s:%s", cfg.Repo, tag)
-		title := strings.TrimSpace(rel.Name)
-		if title == "" {
-			title = fmt.Sprintf("%s %s", cfg.Repo, tag)
-		}
-
-		releaseURL := strings.TrimSpace(rel.HTMLURL)
-		if releaseURL == "" {
-			releaseURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", cfg.Repo, tag)
-		}
-		releaseURL = dedup.NormalizeURL(releaseURL)
-
-		content := strings.TrimSpace(rel.Body)
 		var contentPtr *string
-		if content != "" {
+		if item.Content != "" {
+			content := item.Content
 			contentPtr = &content
 		}
 
 		var author *string
-		if rel.Author != nil {
-			login := strings.TrimSpace(rel.Author.Login)
-			if login != "" {
-				author = &login
-			}
-		}
-
-		publishedAt := parseReleaseTime(rel.PublishedAt)
-		if publishedAt == nil {
-			publishedAt = parseReleaseTime(rel.CreatedAt)
+		if item.Author != "" {
+			login := item.Author
+			author = &login
 		}
 
-		metadata, err := json.Marshal(map[string]interface{}{
+		meta := map[string]interface{}{
 			"repo":        cfg.Repo,
-			"tag":         tag,
-			"prerelease":  rel.Prerelease,
+			"mode":        cfg.Mode,
 			"source_name": cfg.Repo,
 			"source_ref":  src.Source.ID,
-		})
+		}
+		for k, v := range item.Metadata {
+			meta[k] = v
+		}
+		metadata, err := json.Marshal(meta)
 		if err != nil {
 			log.WithError(err).Warn("Failed to marshal GitHub metadata")
 			metadata = []byte("{}")
@@ -275,13 +320,13 @@ func (w *githubWorker) processSource(ctx context.Context, src *store.SourceWithS
 
 		article := &models.Article{
 			SourceType:  sourceTypeGitHub,
-			SourceID:    sourceID,
+			SourceID:    item.SourceID,
 			SectionID:   sectionID,
-			URL:         releaseURL,
-			Title:       title,
+			URL:         item.URL,
+			Title:       item.Title,
 			Content:     contentPtr,
 			Author:      author,
-			PublishedAt: publishedAt,
+			PublishedAt: item.PublishedAt,
 			Status:      models.StatusPending,
 			Metadata:    metadata,
 		}
@@ -294,13 +339,12 @@ func (w *githubWorker) processSource(ctx context.Context, src *store.SourceWithS
 			log.WithFields(log.Fields{
 				"source_id": src.Source.ID,
 				"repo":      cfg.Repo,
-				"tag":       tag,
-			}).WithError(err).Error("Failed to insert GitHub release article")
+				"item":      item.SourceID,
+			}).WithError(err).Error("Failed to insert GitHub article")
 			continue
 		}
 
-		if err := w.queue.Publish(queue.SubjectArticlesNew, newArticleEvent{ArticleID: article.ID}); err != nil {
-			log.WithField("article_id", article.ID).WithError(err).Error("Failed to publish articles.new")
+		if err := w.queue.PublishNewArticle(ctx, "worker-github", article); err != nil {
 			continue
 		}
 
@@ -318,6 +362,7 @@ func (w *githubWorker) processSource(ctx context.Context, src *store.SourceWithS
 		"source_id":     src.Source.ID,
 		"source":        src.Source.Name,
 		"repo":          cfg.Repo,
+		"mode":          cfg.Mode,
 		"releases_seen": stats.ReleasesSeen,
 		"new_articles":  stats.NewArticles,
 		"section_links": len(src.SectionIDs),
@@ -342,14 +387,188 @@ func parseGitHubSourceConfig(raw json.RawMessage) (*githubSourceConfig, error) {
 		return nil, errors.New("github source config requires repo in owner/repo format")
 	}
 	cfg.Repo = parts[0] + "/" + parts[1]
+
+	cfg.Mode = strings.ToLower(strings.TrimSpace(cfg.Mode))
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = githubModeReleases
+	case githubModeReleases, githubModeTags, githubModeCommits:
+	default:
+		return nil, fmt.Errorf("github source config has unknown mode %q (want releases, tags or commits)", cfg.Mode)
+	}
+
+	if cfg.SinceHours < 0 {
+		return nil, errors.New("github source config since_hours must be positive")
+	}
+	if cfg.SinceHours == 0 {
+		cfg.SinceHours = defaultCommitSinceHours
+	}
 	return cfg, nil
 }
 
+func (w *githubWorker) fetchItems(ctx context.Context, cfg *githubSourceConfig) ([]githubItem, error) {
+	switch cfg.Mode {
+	case githubModeTags:
+		var tags []githubTag
+		url := fmt.Sprintf("%s/repos/%s/tags?per_page=%d", githubAPIBase, cfg.Repo, tagLimit)
+		if err := w.getJSON(ctx, url, &tags); err != nil {
+			return nil, err
+		}
+		return tagItems(cfg.Repo, tags), nil
+	case githubModeCommits:
+		var commits []githubCommit
+		since := time.Now().UTC().Add(-time.Duration(cfg.SinceHours) * time.Hour)
+		url := fmt.Sprintf("%s/repos/%s/commits?per_page=%d&since=%s", githubAPIBase, cfg.Repo, commitLimit, since.Format(time.RFC3339))
+		if err := w.getJSON(ctx, url, &commits); err != nil {
+			return nil, err
+		}
+		return commitItems(cfg.Repo, commits), nil
+	default:
+		releases, err := w.fetchReleases(ctx, cfg.Repo)
+		if err != nil {
+			return nil, err
+		}
+		return releaseItems(cfg.Repo, releases), nil
+	}
+}
+
+func releaseItems(repo string, releases []githubRelease) []githubItem {
+	items := make([]githubItem, 0, len(releases))
+	for _, rel := range releases {
+		if rel.Draft {
+			continue
+		}
+		tag := strings.TrimSpace(rel.TagName)
+		if tag == "" {
+			continue
+		}
+
+		title := strings.TrimSpace(rel.Name)
+		if title == "" {
+			title = fmt.Sprintf("%s %s", repo, tag)
+		}
+
+		releaseURL := strings.TrimSpace(rel.HTMLURL)
+		if releaseURL == "" {
+			releaseURL = fmt.Sprintf("https://github.com/%s/releases/tag/%s", repo, tag)
+		}
+
+		author := ""
+		if rel.Author != nil {
+			author = strings.TrimSpace(rel.Author.Login)
+		}
+
+		publishedAt := parseReleaseTime(rel.PublishedAt)
+		if publishedAt == nil {
+			publishedAt = parseReleaseTime(rel.CreatedAt)
+		}
+
+		items = append(items, githubItem{
+			SourceID:    fmt.Sprintf("%s:%s", repo, tag),
+			Title:       title,
+			URL:         dedup.NormalizeURL(releaseURL),
+			Content:     strings.TrimSpace(rel.Body),
+			Author:      author,
+			PublishedAt: publishedAt,
+			Metadata: map[string]interface{}{
+				"tag":        tag,
+				"prerelease": rel.Prerelease,
+			},
+		})
+	}
+	return items
+}
+
+// tagItems maps lightweight tags. The tags endpoint carries no date, so
+// published_at is left empty and the article falls back to ingestion time.
+func tagItems(repo string, tags []githubTag) []githubItem {
+	items := make([]githubItem, 0, len(tags))
+	for _, tag := range tags {
+		name := strings.TrimSpace(tag.Name)
+		if name == "" {
+			continue
+		}
+		items = append(items, githubItem{
+			SourceID: fmt.Sprintf("%s:tag:%s", repo, name),
+			Title:    fmt.Sprintf("%s %s", repo, name),
+			URL:      dedup.NormalizeURL(fmt.Sprintf("https://github.com/%s/tree/%s", repo, name)),
+			Metadata: map[string]interface{}{
+				"tag":        name,
+				"commit_sha": tag.Commit.SHA,
+			},
+		})
+	}
+	return items
+}
+
+func commitItems(repo string, commits []githubCommit) []githubItem {
+	items := make([]githubItem, 0, len(commits))
+	for _, c := range commits {
+		sha := strings.TrimSpace(c.SHA)
+		if sha == "" {
+			continue
+		}
+
+		message := strings.TrimSpace(c.Commit.Message)
+		subject, _, _ := strings.Cut(message, "\n")
+		subject = strings.TrimSpace(subject)
+		if subject == "" {
+			subject = shortSHA(sha)
+		}
+
+		commitURL := strings.TrimSpace(c.HTMLURL)
+		if commitURL == "" {
+			commitURL = fmt.Sprintf("https://github.com/%s/commit/%s", repo, sha)
+		}
+
+		author := ""
+		if c.Author != nil {
+			author = strings.TrimSpace(c.Author.Login)
+		}
+		if author == "" {
+			author = strings.TrimSpace(c.Commit.Author.Name)
+		}
+
+		publishedAt := parseReleaseTime(c.Commit.Author.Date)
+		if publishedAt == nil {
+			publishedAt = parseReleaseTime(c.Commit.Committer.Date)
+		}
+
+		items = append(items, githubItem{
+			SourceID:    fmt.Sprintf("%s:commit:%s", repo, sha),
+			Title:       fmt.Sprintf("%s: %s", repo, subject),
+			URL:         dedup.NormalizeURL(commitURL),
+			Content:     message,
+			Author:      author,
+			PublishedAt: publishedAt,
+			Metadata: map[string]interface{}{
+				"commit_sha": sha,
+			},
+		})
+	}
+	return items
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
 func (w *githubWorker) fetchReleases(ctx context.Context, repo string) ([]githubRelease, error) {
+	var releases []githubRelease
 	url := fmt.Sprintf("%s/repos/%s/releases?per_page=%d", githubAPIBase, repo, releaseLimit)
+	if err := w.getJSON(ctx, url, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+func (w *githubWorker) getJSON(ctx context.Context, url string, out interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	req.Header.Set("Authorization", "Bearer "+w.token)
 	req.Header.Set("Accept", "application/vnd.github+json")
@@ -357,20 +576,19 @@ func (w *githubWorker) fetchReleases(ctx context.Context, repo string) ([]github
 
 	resp, err := w.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
-		return nil, fmt.Errorf("github api status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		return fmt.Errorf("github api status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
 	}
 
-	var releases []githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
-		return nil, fmt.Errorf("decoding releases response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding github response: %w", err)
 	}
-	return releases, nil
+	return nil
 }
 
 func parseReleaseTime(raw string) *time.Time {
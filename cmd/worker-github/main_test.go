@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGitHubSourceConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantRepo  string
+		wantMode  string
+		wantSince int
+		wantErr   bool
+	}{
+		{"defaults to releases", `{"repo":"golang/go"}`, "golang/go", githubModeReleases, defaultCommitSinceHours, false},
+		{"owner and name", `{"owner":"golang","name":"go","mode":"Tags"}`, "golang/go", githubModeTags, defaultCommitSinceHours, false},
+		{"commits with window", `{"repo":"/golang/go/","mode":"commits","since_hours":12}`, "golang/go", githubModeCommits, 12, false},
+		{"unknown mode", `{"repo":"golang/go","mode":"issues"}`, "", "", 0, true},
+		{"negative window", `{"repo":"golang/go","mode":"commits","since_hours":-1}`, "", "", 0, true},
+		{"missing repo", `{"mode":"tags"}`, "", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseGitHubSourceConfig(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRepo, cfg.Repo)
+			assert.Equal(t, tt.wantMode, cfg.Mode)
+			assert.Equal(t, tt.wantSince, cfg.SinceHours)
+		})
+	}
+}
+
+func TestTagItems(t *testing.T) {
+	var tags []githubTag
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"name":"v1.2.0","commit":{"sha":"abc123"}},
+		{"name":"  ","commit":{"sha":"def456"}}
+	]`), &tags))
+
+	items := tagItems("golang/go", tags)
+	require.Len(t, items, 1)
+	assert.Equal(t, "golang/go:tag:v1.2.0", items[0].SourceID)
+	assert.Equal(t, "golang/go v1.2.0", items[0].Title)
+	assert.Contains(t, items[0].URL, "github.com/golang/go/tree/v1.2.0")
+	assert.Equal(t, "abc123", items[0].Metadata["commit_sha"])
+	assert.Nil(t, items[0].PublishedAt)
+}
+
+func TestCommitItems(t *testing.T) {
+	var commits []githubCommit
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{
+			"sha":"0123456789abcdef",
+			"html_url":"https://github.com/golang/go/commit/0123456789abcdef",
+			"commit":{"message":"runtime: fix stack growth\n\nLonger explanation.","author":{"name":"Gopher","date":"2026-01-02T03:04:05Z"}},
+			"author":null
+		},
+		{"sha":"","commit":{"message":"ignored"}}
+	]`), &commits))
+
+	items := commitItems("golang/go", commits)
+	require.Len(t, items, 1)
+	item := items[0]
+	assert.Equal(t, "golang/go:commit:0123456789abcdef", item.SourceID)
+	assert.Equal(t, "golang/go: runtime: fix stack growth", item.Title)
+	assert.Equal(t, "Gopher", item.Author)
+	assert.Contains(t, item.Content, "Longer explanation.")
+	require.NotNil(t, item.PublishedAt)
+	assert.Equal(t, 2026, item.PublishedAt.Year())
+}
+
+func TestReleaseItemsSkipsDrafts(t *testing.T) {
+	items := releaseItems("golang/go", []githubRelease{
+		{TagName: "v1.0.0", Draft: true},
+		{TagName: "v1.1.0", PublishedAt: "2026-01-02T03:04:05Z"},
+	})
+	require.Len(t, items, 1)
+	assert.Equal(t, "golang/go:v1.1.0", items[0].SourceID)
+	assert.Equal(t, "golang/go v1.1.0", items[0].Title)
+}
@@ -7,25 +7,27 @@ import (
 	"fmt"
 	"html"
 	"net/http"
-	nurl "net/url"
 	"os"
 	"os/signal"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"github.com/go-shiori/go-readability"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 	"github.com/zyrak/flux/internal/config"
 	"github.com/zyrak/flux/internal/dedup"
+	"github.com/zyrak/flux/internal/extract"
 	"github.com/zyrak/flux/internal/models"
 	"github.com/zyrak/flux/internal/queue"
 	"github.com/zyrak/flux/internal/ratelimit"
 	"github.com/zyrak/flux/internal/store"
+	"github.com/zyrak/flux/internal/tracing"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -36,14 +38,16 @@ const (
 	runInterval       = 15 * time.Minute
 	requestTimeout    = 30 * time.Second
 	defaultMinScore   = 10
+	// defaultFetchConcurrency is how many item requests run at once; the
+	// rate limiter still spaces them out.
+	defaultFetchConcurrency = 8
+	// defaultMaxStories caps the unseen ids fetched per run; the merged
+	// top/best/new lists hold up to ~1500.
+	defaultMaxStories = 500
 )
 
 var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
 
-type newArticleEvent struct {
-	ArticleID string `json:"article_id"`
-}
-
 type hnItem struct {
 	ID          int64  `json:"id"`
 	Type        string `json:"type"`
@@ -56,13 +60,32 @@ type hnItem struct {
 	Descendants int    `json:"descendants"`
 }
 
+type hnSourceConfig struct {
+	MinScore     int
+	FetchContent bool
+	TitleDedup   bool
+	// Extraction overrides CONTENT_EXTRACTION_CHAIN; nil when unset.
+	Extraction extract.Chain
+}
+
 type hnWorker struct {
-	store      *store.Store
-	queue      *queue.Queue
-	checker    *dedup.Checker
-	httpClient *http.Client
-	minScore   int
-	sourceID   string
+	store        *store.Store
+	queue        *queue.Queue
+	checker      *dedup.Checker
+	httpClient   *http.Client
+	baseURL      string
+	minScore     int
+	sourceID     string
+	fetchContent bool
+	titleDedup   bool
+	pages        *extract.PageFetcher
+	extraction   extract.Chain
+	// Runs are skipped while more articles than this await the processor.
+	backpressureMax int
+	// fetchConcurrency bounds the item requests in flight.
+	fetchConcurrency int
+	// maxStories caps the item fetches per run; 0 means no cap.
+	maxStories int
 }
 
 type hnRunStats struct {
@@ -72,18 +95,27 @@ type hnRunStats struct {
 	SkippedLowScore  int
 	SkippedSeen      int
 	Errors           int
+	// Backpressure is set when the cycle was skipped without fetching.
+	Backpressure bool
 }
 
 func main() {
 	cfg := config.Load()
 	setupLogging(cfg.LogLevel)
+	dedup.SetTrackingParams(dedup.NewTrackingParams(cfg.DedupTrackingParams, cfg.DedupTrackingDefaults))
 
 	log.Info("Starting Flux Hacker News worker")
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	shutdownTracing, err := tracing.Setup(ctx, "worker-hn", cfg.OTLPEndpoint)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer tracing.Flush(shutdownTracing)
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
 	}
@@ -112,14 +144,17 @@ func main() {
 	}
 
 	limiter, err := ratelimit.New(rdb, ratelimit.Config{
-		Limits:    limits,
-		UserAgent: cfg.UserAgent,
+		Limits:        limits,
+		MaxConcurrent: cfg.RateLimitConcurrency,
+		Jitter:        cfg.RateLimitJitter,
+		UserAgent:     cfg.UserAgent,
+		Proxy:         cfg.OutboundProxy,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize rate limiter")
 	}
 
-	sourceID, err := resolveHNSourceID(ctx, db)
+	sourceID, sourceCfg, err := resolveHNSourceID(ctx, db, parseMinScore())
 	if err != nil {
 		log.WithError(err).Fatal("Failed to resolve HN source from database")
 	}
@@ -128,13 +163,32 @@ func main() {
 		return
 	}
 
+	extraction := sourceCfg.Extraction
+	if len(extraction) == 0 {
+		extraction, err = extract.NewChain(cfg.ContentExtractionChain)
+		if err != nil {
+			log.WithError(err).Fatal("Invalid CONTENT_EXTRACTION_CHAIN")
+		}
+	}
+
+	httpClient := ratelimit.NewHTTPClient(limiter, requestTimeout)
 	worker := &hnWorker{
-		store:      db,
-		queue:      q,
-		checker:    dedup.NewChecker(rdb),
-		httpClient: ratelimit.NewHTTPClient(limiter, requestTimeout),
-		minScore:   parseMinScore(),
-		sourceID:   sourceID,
+		store:        db,
+		queue:        q,
+		checker:      dedup.NewCheckerWithStore(rdb, db),
+		httpClient:   httpClient,
+		baseURL:      hnBaseURL,
+		minScore:     sourceCfg.MinScore,
+		sourceID:     sourceID,
+		fetchContent: sourceCfg.FetchContent,
+		titleDedup:   sourceCfg.TitleDedup,
+
+		pages:      &extract.PageFetcher{Client: httpClient, AllowedTypes: cfg.ContentAllowedTypes, MaxBytes: int64(cfg.ContentMaxBytes)},
+		extraction: extraction,
+
+		backpressureMax:  cfg.IngestBackpressureMax,
+		fetchConcurrency: parseFetchConcurrency(),
+		maxStories:       parseMaxStories(),
 	}
 
 	mode := parseWorkerMode()
@@ -144,6 +198,13 @@ func main() {
 		if err != nil {
 			log.WithError(err).Error("HN worker run failed")
 		}
+		// A skipped cycle fetched nothing, so it stays out of the fetch log
+		// instead of pushing real runs out of it.
+		if !stats.Backpressure {
+			if logErr := db.RecordSourceFetch(ctx, sourceID, stats.StoriesProcessed, stats.NewArticles, err); logErr != nil {
+				log.WithField("source_id", sourceID).WithError(logErr).Warn("Failed to record source fetch")
+			}
+		}
 
 		log.WithFields(log.Fields{
 			"mode":              mode,
@@ -153,6 +214,7 @@ func main() {
 			"skipped_low_score": stats.SkippedLowScore,
 			"skipped_seen":      stats.SkippedSeen,
 			"errors":            stats.Errors,
+			"backpressure":      stats.Backpressure,
 			"elapsed_ms":        time.Since(runStart).Milliseconds(),
 		}).Info("HN worker run completed")
 
@@ -174,11 +236,18 @@ func main() {
 
 func (w *hnWorker) runOnce(ctx context.Context) (hnRunStats, error) {
 	stats := hnRunStats{}
+	ctx, span := tracing.Tracer().Start(ctx, "worker-hn.run")
+	defer span.End()
+
+	if w.queue.IngestBackpressure(sourceTypeHN, w.backpressureMax) {
+		stats.Backpressure = true
+		return stats, nil
+	}
 
 	endpoints := []string{
-		hnBaseURL + "/topstories.json",
-		hnBaseURL + "/beststories.json",
-		hnBaseURL + "/newstories.json",
+		w.baseURL + "/topstories.json",
+		w.baseURL + "/beststories.json",
+		w.baseURL + "/newstories.json",
 	}
 
 	seenIDs := make(map[int64]struct{})
@@ -200,18 +269,30 @@ func (w *hnWorker) runOnce(ctx context.Context) (hnRunStats, error) {
 		}
 	}
 
-	for _, storyID := range storyIDs {
-		itemURL := fmt.Sprintf("%s/item/%d.json", hnBaseURL, storyID)
-		item := &hnItem{}
-		if err := w.fetchJSON(ctx, itemURL, item); err != nil {
-			stats.Errors++
-			log.WithFields(log.Fields{
-				"story_id": storyID,
-				"url":      itemURL,
-			}).WithError(err).Error("Failed to fetch HN item")
-			continue
-		}
-		if item.ID == 0 || item.Type != "story" {
+	sourceIDs := make([]string, len(storyIDs))
+	for i, id := range storyIDs {
+		sourceIDs[i] = strconv.FormatInt(id, 10)
+	}
+	stored, err := w.store.ExistingArticleSourceIDs(ctx, sourceTypeHN, sourceIDs)
+	if err != nil {
+		log.WithError(err).Warn("Stored story check failed, fetching anyway")
+	}
+	storyIDs, skipped := windowStoryIDs(storyIDs, w.maxStories, func(id int64) bool {
+		_, exists := stored[strconv.FormatInt(id, 10)]
+		return exists
+	})
+	stats.SkippedSeen += skipped
+
+	// A backoff abort still returns the items fetched before it; they are
+	// stored now instead of being refetched next run.
+	items, failed, fetchErr := w.fetchItems(ctx, storyIDs)
+	stats.Errors += failed
+	if fetchErr != nil {
+		log.WithError(fetchErr).Warn("HN item fetch aborted, storing the stories fetched so far")
+	}
+
+	for _, item := range items {
+		if item == nil || item.ID == 0 || item.Type != "story" {
 			continue
 		}
 
@@ -242,19 +323,28 @@ func (w *hnWorker) runOnce(ctx context.Context) (hnRunStats, error) {
 			continue
 		}
 
-		content := ""
-		if strings.TrimSpace(item.URL) != "" {
-			content, err = w.fetchReadableContent(ctx, articleURL)
+		titleKey := dedup.TitleKey(item.Title)
+		if w.titleDedup && titleKey != "" {
+			dup, err := w.store.ExistsRecentSimilarTitle(ctx, titleKey, time.Now().UTC().Add(-dedup.TitleDedupWindow))
+			if err != nil {
+				log.WithField("story_id", item.ID).WithError(err).Warn("Title dedup check failed")
+			} else if dup {
+				stats.SkippedSeen++
+				continue
+			}
+		}
+
+		var page []byte
+		if w.fetchContent && strings.TrimSpace(item.URL) != "" && w.extraction.NeedsPage() {
+			page, err = w.pages.Fetch(ctx, articleURL)
 			if err != nil {
 				log.WithFields(log.Fields{
 					"story_id": item.ID,
 					"url":      articleURL,
-				}).WithError(err).Warn("Failed to fetch readable content, using HN text fallback")
-				content = cleanText(item.Text)
+				}).WithError(err).Log(extract.FetchLogLevel(err), "Failed to fetch article page, using HN text fallback")
 			}
-		} else {
-			content = cleanText(item.Text)
 		}
+		content, method := w.extraction.Run(page, articleURL, cleanText(item.Text))
 
 		var contentPtr *string
 		if content != "" {
@@ -275,14 +365,21 @@ func (w *hnWorker) runOnce(ctx context.Context) (hnRunStats, error) {
 			title = fmt.Sprintf("HN story %d", item.ID)
 		}
 
-		metadata, err := json.Marshal(map[string]interface{}{
+		metadataMap := map[string]interface{}{
 			"hn_score":    item.Score,
 			"hn_comments": item.Descendants,
 			"hn_id":       item.ID,
 			"hn_type":     item.Type,
 			"source_name": "Hacker News",
 			"source_ref":  w.sourceID,
-		})
+		}
+		if titleKey != "" {
+			metadataMap["title_key"] = titleKey
+		}
+		if method != "" {
+			metadataMap["extraction_method"] = method
+		}
+		metadata, err := json.Marshal(metadataMap)
 		if err != nil {
 			stats.Errors++
 			log.WithError(err).WithField("story_id", item.ID).Error("Failed to marshal HN metadata")
@@ -314,15 +411,18 @@ func (w *hnWorker) runOnce(ctx context.Context) (hnRunStats, error) {
 			continue
 		}
 
-		if err := w.queue.Publish(queue.SubjectArticlesNew, newArticleEvent{ArticleID: article.ID}); err != nil {
+		if err := w.queue.PublishNewArticle(ctx, "worker-hn", article); err != nil {
 			stats.Errors++
-			log.WithField("article_id", article.ID).WithError(err).Error("Failed to publish articles.new")
 			continue
 		}
 
 		stats.NewArticles++
 	}
 
+	if fetchErr != nil {
+		_ = w.store.UpdateSourceFetchStatus(ctx, w.sourceID, fetchErr)
+		return stats, fmt.Errorf("fetching HN items: %w", fetchErr)
+	}
 	if err := w.store.UpdateSourceFetchStatus(ctx, w.sourceID, nil); err != nil {
 		log.WithField("source_id", w.sourceID).WithError(err).Warn("Failed to update HN source fetch status")
 	}
@@ -330,33 +430,63 @@ func (w *hnWorker) runOnce(ctx context.Context) (hnRunStats, error) {
 	return stats, nil
 }
 
-func (w *hnWorker) fetchReadableContent(ctx context.Context, url string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return "", err
-	}
-
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
-
-	parsedURL, err := nurl.Parse(url)
-	if err != nil {
-		return "", err
+// windowStoryIDs drops the ids stored reports as already ingested and keeps
+// at most maxStories of the rest, in list order (0 keeps them all). It
+// returns the ids to fetch and how many were skipped as stored.
+func windowStoryIDs(ids []int64, maxStories int, stored func(id int64) bool) ([]int64, int) {
+	window := make([]int64, 0, len(ids))
+	skipped := 0
+	for _, id := range ids {
+		if maxStories > 0 && len(window) >= maxStories {
+			break
+		}
+		if stored(id) {
+			skipped++
+			continue
+		}
+		window = append(window, id)
 	}
+	return window, skipped
+}
 
-	article, err := readability.FromReader(resp.Body, parsedURL)
-	if err != nil {
-		return "", err
+// fetchItems fetches the given story items with up to w.fetchConcurrency
+// requests in flight. Items come back in the order of ids, nil where the
+// fetch failed; failed counts those. A rate-limit backoff aborts the batch,
+// since every remaining item would fail the same way until the next run; the
+// items fetched before it are returned along with the error.
+func (w *hnWorker) fetchItems(ctx context.Context, ids []int64) ([]*hnItem, int, error) {
+	items := make([]*hnItem, len(ids))
+	var failed atomic.Int64
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(w.fetchConcurrency, 1))
+	for i, id := range ids {
+		if gctx.Err() != nil {
+			break // stop queueing once the batch is aborted
+		}
+		g.Go(func() error {
+			itemURL := fmt.Sprintf("%s/item/%d.json", w.baseURL, id)
+			item := &hnItem{}
+			if err := w.fetchJSON(gctx, itemURL, item); err != nil {
+				if errors.Is(err, ratelimit.ErrInBackoff) {
+					return err
+				}
+				if gctx.Err() != nil {
+					return nil // batch aborted or shutting down
+				}
+				failed.Add(1)
+				log.WithFields(log.Fields{
+					"story_id": id,
+					"url":      itemURL,
+				}).WithError(err).Error("Failed to fetch HN item")
+				return nil
+			}
+			items[i] = item
+			return nil
+		})
 	}
-
-	return cleanText(article.TextContent), nil
+	err := g.Wait()
+	return items, int(failed.Load()), err
 }
 
 func (w *hnWorker) fetchJSON(ctx context.Context, url string, out interface{}) error {
@@ -381,18 +511,60 @@ func (w *hnWorker) fetchJSON(ctx context.Context, url string, out interface{}) e
 	return nil
 }
 
-func resolveHNSourceID(ctx context.Context, db *store.Store) (string, error) {
+func resolveHNSourceID(ctx context.Context, db *store.Store, fallbackMinScore int) (string, *hnSourceConfig, error) {
 	sources, err := db.ListSourcesByTypeWithSectionIDs(ctx, sourceTypeHN, true)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	if len(sources) == 0 {
-		return "", nil
+		return "", nil, nil
 	}
 	if len(sources) > 1 {
 		log.WithField("count", len(sources)).Warn("Multiple enabled HN sources found; using the first one")
 	}
-	return sources[0].Source.ID, nil
+
+	src := sources[0].Source
+	cfg, err := parseHNSourceConfig(src.Config, fallbackMinScore)
+	if err != nil {
+		_ = db.UpdateSourceFetchStatus(ctx, src.ID, err)
+		return "", nil, fmt.Errorf("source %s: %w", src.ID, err)
+	}
+	return src.ID, cfg, nil
+}
+
+// parseHNSourceConfig reads the optional min_score from the source config,
+// falling back to the HN_MIN_SCORE/default value when it is absent or negative.
+// fetch_content defaults to true; extraction overrides the worker's chain.
+func parseHNSourceConfig(raw json.RawMessage, fallbackMinScore int) (*hnSourceConfig, error) {
+	cfg := &hnSourceConfig{MinScore: fallbackMinScore, FetchContent: true}
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		return cfg, nil
+	}
+
+	var parsed struct {
+		MinScore     *int     `json:"min_score"`
+		FetchContent *bool    `json:"fetch_content"`
+		TitleDedup   bool     `json:"title_dedup"`
+		Extraction   []string `json:"extraction"`
+	}
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing source config: %w", err)
+	}
+	if len(parsed.Extraction) > 0 {
+		chain, err := extract.NewChain(parsed.Extraction)
+		if err != nil {
+			return nil, fmt.Errorf("parsing source config: %w", err)
+		}
+		cfg.Extraction = chain
+	}
+	if parsed.MinScore != nil && *parsed.MinScore >= 0 {
+		cfg.MinScore = *parsed.MinScore
+	}
+	if parsed.FetchContent != nil {
+		cfg.FetchContent = *parsed.FetchContent
+	}
+	cfg.TitleDedup = parsed.TitleDedup
+	return cfg, nil
 }
 
 func copyRateLimits(in map[string]string) map[string]string {
@@ -422,6 +594,32 @@ func parseMinScore() int {
 	return score
 }
 
+func parseFetchConcurrency() int {
+	raw := strings.TrimSpace(os.Getenv("HN_FETCH_CONCURRENCY"))
+	if raw == "" {
+		return defaultFetchConcurrency
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		log.WithField("HN_FETCH_CONCURRENCY", raw).Warn("Invalid HN_FETCH_CONCURRENCY, using default")
+		return defaultFetchConcurrency
+	}
+	return n
+}
+
+func parseMaxStories() int {
+	raw := strings.TrimSpace(os.Getenv("HN_MAX_STORIES"))
+	if raw == "" {
+		return defaultMaxStories
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		log.WithField("HN_MAX_STORIES", raw).Warn("Invalid HN_MAX_STORIES, using default")
+		return defaultMaxStories
+	}
+	return n
+}
+
 func parseWorkerMode() string {
 	mode := strings.ToLower(strings.TrimSpace(os.Getenv("WORKER_MODE")))
 	if mode == "" {
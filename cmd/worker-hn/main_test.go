@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/extract"
+	"github.com/zyrak/flux/internal/ratelimit"
+)
+
+func TestParseHNSourceConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		fallback int
+		want     int
+	}{
+		{name: "empty config uses fallback", raw: "", fallback: 15, want: 15},
+		{name: "missing field uses fallback", raw: `{"api_base":"https://hacker-news.firebaseio.com/v0"}`, fallback: 10, want: 10},
+		{name: "explicit min score", raw: `{"min_score":50}`, fallback: 10, want: 50},
+		{name: "zero is honoured", raw: `{"min_score":0}`, fallback: 10, want: 0},
+		{name: "negative uses fallback", raw: `{"min_score":-3}`, fallback: 10, want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseHNSourceConfig(json.RawMessage(tt.raw), tt.fallback)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.MinScore)
+		})
+	}
+}
+
+func TestParseHNSourceConfigInvalidJSON(t *testing.T) {
+	_, err := parseHNSourceConfig(json.RawMessage(`{"min_score":"high"}`), 10)
+	assert.Error(t, err)
+}
+
+func TestParseHNSourceConfigFetchContent(t *testing.T) {
+	cfg, err := parseHNSourceConfig(json.RawMessage(`{}`), 10)
+	require.NoError(t, err)
+	assert.True(t, cfg.FetchContent)
+
+	cfg, err = parseHNSourceConfig(json.RawMessage(`{"fetch_content":false}`), 10)
+	require.NoError(t, err)
+	assert.False(t, cfg.FetchContent)
+}
+
+func TestParseHNSourceConfigExtraction(t *testing.T) {
+	cfg, err := parseHNSourceConfig(json.RawMessage(`{}`), 10)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.Extraction)
+
+	cfg, err = parseHNSourceConfig(json.RawMessage(`{"extraction":["meta","feed"]}`), 10)
+	require.NoError(t, err)
+	assert.Equal(t, extract.Chain{extract.MethodMeta, extract.MethodFeed}, cfg.Extraction)
+
+	_, err = parseHNSourceConfig(json.RawMessage(`{"extraction":["ocr"]}`), 10)
+	assert.Error(t, err)
+}
+
+func TestParseHNSourceConfigTitleDedup(t *testing.T) {
+	cfg, err := parseHNSourceConfig(json.RawMessage(`{"min_score":50}`), 10)
+	require.NoError(t, err)
+	assert.False(t, cfg.TitleDedup)
+
+	cfg, err = parseHNSourceConfig(json.RawMessage(`{"title_dedup":true}`), 10)
+	require.NoError(t, err)
+	assert.True(t, cfg.TitleDedup)
+}
+
+func TestFetchItemsConcurrently(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		var id int64
+		if _, err := fmt.Sscanf(r.URL.Path, "/item/%d.json", &id); err != nil || id == 13 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(hnItem{ID: id, Type: "story", Title: fmt.Sprintf("story %d", id)})
+	}))
+	defer srv.Close()
+
+	w := &hnWorker{httpClient: srv.Client(), baseURL: srv.URL, fetchConcurrency: 4}
+	ids := make([]int64, 20)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+
+	items, failed, err := w.fetchItems(context.Background(), ids)
+	require.NoError(t, err)
+	require.Len(t, items, len(ids))
+	assert.Equal(t, 1, failed)
+	for i, item := range items {
+		if ids[i] == 13 {
+			assert.Nil(t, item)
+			continue
+		}
+		require.NotNil(t, item)
+		assert.Equal(t, ids[i], item.ID)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(4))
+	assert.Greater(t, peak.Load(), int32(1))
+}
+
+type backoffTransport struct{ calls atomic.Int32 }
+
+func (b *backoffTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if b.calls.Add(1) > 2 {
+		return nil, ratelimit.ErrInBackoff
+	}
+	id := strings.TrimSuffix(path.Base(r.URL.Path), ".json")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"id":` + id + `,"type":"story"}`)),
+		Request:    r,
+	}, nil
+}
+
+func TestFetchItemsAbortsOnBackoff(t *testing.T) {
+	transport := &backoffTransport{}
+	w := &hnWorker{httpClient: &http.Client{Transport: transport}, baseURL: "http://hn.test", fetchConcurrency: 1}
+
+	items, failed, err := w.fetchItems(context.Background(), []int64{1, 2, 3, 4, 5, 6})
+	require.ErrorIs(t, err, ratelimit.ErrInBackoff)
+	assert.Zero(t, failed)
+	assert.Less(t, transport.calls.Load(), int32(6))
+	// Stories fetched before the backoff are kept for this run.
+	require.Len(t, items, 6)
+	require.NotNil(t, items[0])
+	require.NotNil(t, items[1])
+	assert.Equal(t, []int64{1, 2}, []int64{items[0].ID, items[1].ID})
+	assert.Nil(t, items[2])
+}
+
+func TestWindowStoryIDs(t *testing.T) {
+	stored := map[int64]bool{2: true, 4: true}
+	isStored := func(id int64) bool { return stored[id] }
+
+	window, skipped := windowStoryIDs([]int64{1, 2, 3, 4, 5, 6}, 3, isStored)
+	assert.Equal(t, []int64{1, 3, 5}, window)
+	assert.Equal(t, 2, skipped)
+
+	window, skipped = windowStoryIDs([]int64{1, 2, 3, 4, 5, 6}, 0, isStored)
+	assert.Equal(t, []int64{1, 3, 5, 6}, window)
+	assert.Equal(t, 2, skipped)
+}
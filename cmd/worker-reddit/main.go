@@ -5,28 +5,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"html"
 	"io"
 	"net/http"
 	nurl "net/url"
 	"os"
 	"os/signal"
-	"regexp"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/go-shiori/go-readability"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 	"github.com/zyrak/flux/internal/config"
 	"github.com/zyrak/flux/internal/dedup"
+	"github.com/zyrak/flux/internal/extract"
 	"github.com/zyrak/flux/internal/models"
 	"github.com/zyrak/flux/internal/queue"
 	"github.com/zyrak/flux/internal/ratelimit"
 	"github.com/zyrak/flux/internal/store"
+	"github.com/zyrak/flux/internal/tracing"
 )
 
 const (
@@ -41,20 +40,39 @@ const (
 	runInterval     = 30 * time.Minute
 	defaultMinScore = 20
 	defaultSort     = "hot"
+	defaultWindow   = "day"
 	defaultLimit    = 50
 )
 
-var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
-
-type newArticleEvent struct {
-	ArticleID string `json:"article_id"`
-}
-
 type redditSourceConfig struct {
 	Subreddit string `json:"subreddit"`
 	MinScore  int    `json:"min_score"`
 	Sort      string `json:"sort,omitempty"`
 	Limit     int    `json:"limit,omitempty"`
+	// TimeWindow is sent as t= for sort=top (hour, day or week).
+	TimeWindow string `json:"time_window,omitempty"`
+	// FetchContent disables the readability fetch for link posts when false.
+	// Defaults to true.
+	FetchContent *bool `json:"fetch_content,omitempty"`
+	// TitleDedup skips posts whose normalized title matches an article from
+	// the last day. Off by default.
+	TitleDedup bool `json:"title_dedup,omitempty"`
+	// Extraction overrides CONTENT_EXTRACTION_CHAIN for link posts from this
+	// subreddit.
+	Extraction []string `json:"extraction,omitempty"`
+}
+
+func (c *redditSourceConfig) shouldFetchContent() bool {
+	return c.FetchContent == nil || *c.FetchContent
+}
+
+// extractionChain returns the source's own chain, or fallback when it has
+// none.
+func (c *redditSourceConfig) extractionChain(fallback extract.Chain) extract.Chain {
+	if len(c.Extraction) == 0 {
+		return fallback
+	}
+	return extract.Chain(c.Extraction)
 }
 
 type redditListingResponse struct {
@@ -93,6 +111,11 @@ type redditWorker struct {
 	checker    *dedup.Checker
 	httpClient *http.Client
 	oauth      *redditOAuthClient
+	pages      *extract.PageFetcher
+	// extraction is the default content extraction chain.
+	extraction extract.Chain
+	// Runs are skipped while more articles than this await the processor.
+	backpressureMax int
 }
 
 type redditOAuthClient struct {
@@ -126,13 +149,20 @@ type sourceRunStats struct {
 func main() {
 	cfg := config.Load()
 	setupLogging(cfg.LogLevel)
+	dedup.SetTrackingParams(dedup.NewTrackingParams(cfg.DedupTrackingParams, cfg.DedupTrackingDefaults))
 
 	log.Info("Starting Flux Reddit worker")
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	shutdownTracing, err := tracing.Setup(ctx, "worker-reddit", cfg.OTLPEndpoint)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer tracing.Flush(shutdownTracing)
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
 	}
@@ -164,8 +194,11 @@ func main() {
 	}
 
 	limiter, err := ratelimit.New(rdb, ratelimit.Config{
-		Limits:    limits,
-		UserAgent: cfg.UserAgent,
+		Limits:        limits,
+		MaxConcurrent: cfg.RateLimitConcurrency,
+		Jitter:        cfg.RateLimitJitter,
+		UserAgent:     cfg.UserAgent,
+		Proxy:         cfg.OutboundProxy,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize rate limiter")
@@ -177,12 +210,22 @@ func main() {
 		log.WithError(err).Fatal("Failed to initialize Reddit OAuth credentials")
 	}
 
+	extraction, err := extract.NewChain(cfg.ContentExtractionChain)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid CONTENT_EXTRACTION_CHAIN")
+	}
+
 	worker := &redditWorker{
 		store:      db,
 		queue:      q,
-		checker:    dedup.NewChecker(rdb),
+		checker:    dedup.NewCheckerWithStore(rdb, db),
 		httpClient: httpClient,
 		oauth:      oauth,
+
+		pages:      &extract.PageFetcher{Client: httpClient, AllowedTypes: cfg.ContentAllowedTypes, MaxBytes: int64(cfg.ContentMaxBytes)},
+		extraction: extraction,
+
+		backpressureMax: cfg.IngestBackpressureMax,
 	}
 
 	mode := parseWorkerMode()
@@ -319,6 +362,12 @@ func (c *redditOAuthClient) refreshToken(ctx context.Context) (string, time.Time
 
 func (w *redditWorker) runOnce(ctx context.Context) (redditRunStats, error) {
 	stats := redditRunStats{}
+	ctx, span := tracing.Tracer().Start(ctx, "worker-reddit.run")
+	defer span.End()
+
+	if w.queue.IngestBackpressure(sourceTypeReddit, w.backpressureMax) {
+		return stats, nil
+	}
 
 	sources, err := w.store.ListSourcesByTypeWithSectionIDs(ctx, sourceTypeReddit, true)
 	if err != nil {
@@ -332,6 +381,9 @@ func (w *redditWorker) runOnce(ctx context.Context) (redditRunStats, error) {
 		stats.NewArticles += sourceStats.NewArticles
 		stats.SkippedLowScore += sourceStats.SkippedLowScore
 		stats.SkippedSeen += sourceStats.SkippedSeen
+		if logErr := w.store.RecordSourceFetch(ctx, src.Source.ID, sourceStats.PostsSeen, sourceStats.NewArticles, err); logErr != nil {
+			log.WithField("source_id", src.Source.ID).WithError(logErr).Warn("Failed to record source fetch")
+		}
 
 		if err != nil {
 			stats.SourceErrors++
@@ -404,21 +456,34 @@ func (w *redditWorker) processSubredditSource(ctx context.Context, src *store.So
 			}
 		}
 
-		content := ""
-		if post.IsSelf {
-			content = strings.TrimSpace(post.SelfText)
-		} else {
-			content, err = w.fetchReadableContent(ctx, articleURL)
+		titleKey := dedup.TitleKey(post.Title)
+		if cfg.TitleDedup && titleKey != "" {
+			dup, err := w.store.ExistsRecentSimilarTitle(ctx, titleKey, time.Now().UTC().Add(-dedup.TitleDedupWindow))
+			if err != nil {
+				log.WithFields(log.Fields{
+					"source_id":   src.Source.ID,
+					"reddit_post": post.ID,
+				}).WithError(err).Warn("Title dedup check failed")
+			} else if dup {
+				stats.SkippedSeen++
+				continue
+			}
+		}
+
+		chain := cfg.extractionChain(w.extraction)
+		var page []byte
+		if !post.IsSelf && cfg.shouldFetchContent() && chain.NeedsPage() {
+			page, err = w.pages.Fetch(ctx, articleURL)
 			if err != nil {
 				log.WithFields(log.Fields{
 					"source_id":   src.Source.ID,
 					"subreddit":   cfg.Subreddit,
 					"reddit_post": post.ID,
 					"url":         articleURL,
-				}).WithError(err).Warn("Failed to fetch readable content, falling back to selftext")
-				content = strings.TrimSpace(post.SelfText)
+				}).WithError(err).Log(extract.FetchLogLevel(err), "Failed to fetch article page, falling back to selftext")
 			}
 		}
+		content, method := chain.Run(page, articleURL, post.SelfText)
 
 		var contentPtr *string
 		if content != "" {
@@ -442,7 +507,7 @@ func (w *redditWorker) processSubredditSource(ctx context.Context, src *store.So
 			publishedAt = &ts
 		}
 
-		metadata, err := json.Marshal(map[string]interface{}{
+		metadataMap := map[string]interface{}{
 			"reddit_score":    post.Score,
 			"reddit_comments": post.NumComments,
 			"subreddit":       cfg.Subreddit,
@@ -451,7 +516,14 @@ func (w *redditWorker) processSubredditSource(ctx context.Context, src *store.So
 			"source_name":     fmt.Sprintf("r/%s", cfg.Subreddit),
 			"source_ref":      src.Source.ID,
 			"permalink":       permalink,
-		})
+		}
+		if titleKey != "" {
+			metadataMap["title_key"] = titleKey
+		}
+		if method != "" {
+			metadataMap["extraction_method"] = method
+		}
+		metadata, err := json.Marshal(metadataMap)
 		if err != nil {
 			log.WithError(err).Warn("Failed to marshal Reddit metadata")
 			metadata = []byte("{}")
@@ -483,8 +555,7 @@ func (w *redditWorker) processSubredditSource(ctx context.Context, src *store.So
 			continue
 		}
 
-		if err := w.queue.Publish(queue.SubjectArticlesNew, newArticleEvent{ArticleID: article.ID}); err != nil {
-			log.WithField("article_id", article.ID).WithError(err).Error("Failed to publish articles.new")
+		if err := w.queue.PublishNewArticle(ctx, "worker-reddit", article); err != nil {
 			continue
 		}
 
@@ -534,7 +605,7 @@ func (w *redditWorker) fetchSubredditPosts(ctx context.Context, cfg *redditSourc
 }
 
 func (w *redditWorker) fetchSubredditPostsWithToken(ctx context.Context, cfg *redditSourceConfig, token string) ([]redditPost, int, error) {
-	url := fmt.Sprintf("%s/r/%s/%s.json?limit=%d", redditAPIBase, cfg.Subreddit, cfg.Sort, cfg.Limit)
+	url := subredditListingURL(cfg)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, 0, err
@@ -567,33 +638,13 @@ func (w *redditWorker) fetchSubredditPostsWithToken(ctx context.Context, cfg *re
 	return posts, resp.StatusCode, nil
 }
 
-func (w *redditWorker) fetchReadableContent(ctx context.Context, url string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return "", err
-	}
-
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
-
-	parsedURL, err := nurl.Parse(url)
-	if err != nil {
-		return "", err
-	}
-
-	article, err := readability.FromReader(resp.Body, parsedURL)
-	if err != nil {
-		return "", err
+func subredditListingURL(cfg *redditSourceConfig) string {
+	url := fmt.Sprintf("%s/r/%s/%s.json?limit=%d", redditAPIBase, cfg.Subreddit, cfg.Sort, cfg.Limit)
+	if cfg.Sort == "top" {
+		// Without t= Reddit ranks top posts over all time.
+		url += "&t=" + cfg.TimeWindow
 	}
-
-	return cleanText(article.TextContent), nil
+	return url
 }
 
 func parseRedditSourceConfig(raw json.RawMessage) (*redditSourceConfig, error) {
@@ -615,10 +666,19 @@ func parseRedditSourceConfig(raw json.RawMessage) (*redditSourceConfig, error) {
 	}
 
 	cfg.Sort = normalizeRedditSort(cfg.Sort)
+	cfg.TimeWindow = normalizeRedditTimeWindow(cfg.TimeWindow)
 	if cfg.Limit <= 0 || cfg.Limit > 100 {
 		cfg.Limit = defaultLimit
 	}
 
+	if len(cfg.Extraction) > 0 {
+		chain, err := extract.NewChain(cfg.Extraction)
+		if err != nil {
+			return nil, fmt.Errorf("parsing source config: %w", err)
+		}
+		cfg.Extraction = chain
+	}
+
 	return cfg, nil
 }
 
@@ -639,6 +699,16 @@ func normalizeRedditSort(raw string) string {
 	}
 }
 
+func normalizeRedditTimeWindow(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	switch raw {
+	case "hour", "day", "week":
+		return raw
+	default:
+		return defaultWindow
+	}
+}
+
 func normalizePermalink(permalink string) string {
 	permalink = strings.TrimSpace(permalink)
 	if permalink == "" {
@@ -658,12 +728,6 @@ func copyRateLimits(in map[string]string) map[string]string {
 	return out
 }
 
-func cleanText(raw string) string {
-	raw = htmlTagPattern.ReplaceAllString(raw, " ")
-	raw = html.UnescapeString(raw)
-	return strings.TrimSpace(strings.Join(strings.Fields(raw), " "))
-}
-
 func parseWorkerMode() string {
 	mode := strings.ToLower(strings.TrimSpace(os.Getenv("WORKER_MODE")))
 	if mode == "" {
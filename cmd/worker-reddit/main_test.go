@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/extract"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestParseRedditSourceConfigTimeWindow(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`{"subreddit":"golang","sort":"top"}`, "day"},
+		{`{"subreddit":"golang","sort":"top","time_window":"Week"}`, "week"},
+		{`{"subreddit":"golang","sort":"top","time_window":"hour"}`, "hour"},
+		{`{"subreddit":"golang","sort":"top","time_window":"all"}`, "day"},
+		{`{"subreddit":"golang","sort":"top","time_window":"fortnight"}`, "day"},
+	}
+
+	for _, tt := range tests {
+		cfg, err := parseRedditSourceConfig(json.RawMessage(tt.raw))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, cfg.TimeWindow, tt.raw)
+	}
+}
+
+func TestFetchSubredditPostsURL(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"hot ignores window", `{"subreddit":"r/golang","time_window":"week"}`, redditAPIBase + "/r/golang/hot.json?limit=50"},
+		{"top uses window", `{"subreddit":"golang","sort":"top","time_window":"week","limit":10}`, redditAPIBase + "/r/golang/top.json?limit=10&t=week"},
+		{"top defaults to day", `{"subreddit":"golang","sort":"top"}`, redditAPIBase + "/r/golang/top.json?limit=50&t=day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseRedditSourceConfig(json.RawMessage(tt.raw))
+			require.NoError(t, err)
+
+			var gotURL, gotAuth string
+			w := &redditWorker{httpClient: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				gotURL = req.URL.String()
+				gotAuth = req.Header.Get("Authorization")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     make(http.Header),
+					Body:       io.NopCloser(strings.NewReader(`{"data":{"children":[{"data":{"id":"abc","title":"Hello"}}]}}`)),
+				}, nil
+			})}}
+
+			posts, status, err := w.fetchSubredditPostsWithToken(context.Background(), cfg, "tok")
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, status)
+			assert.Equal(t, tt.want, gotURL)
+			assert.Equal(t, "Bearer tok", gotAuth)
+			require.Len(t, posts, 1)
+			assert.Equal(t, "abc", posts[0].ID)
+		})
+	}
+}
+
+func TestRedditShouldFetchContent(t *testing.T) {
+	cfg, err := parseRedditSourceConfig(json.RawMessage(`{"subreddit":"golang"}`))
+	require.NoError(t, err)
+	assert.True(t, cfg.shouldFetchContent())
+
+	cfg, err = parseRedditSourceConfig(json.RawMessage(`{"subreddit":"golang","fetch_content":false}`))
+	require.NoError(t, err)
+	assert.False(t, cfg.shouldFetchContent())
+}
+
+func TestRedditExtractionChain(t *testing.T) {
+	cfg, err := parseRedditSourceConfig(json.RawMessage(`{"subreddit":"golang"}`))
+	require.NoError(t, err)
+	assert.Equal(t, extract.DefaultChain, cfg.extractionChain(extract.DefaultChain))
+
+	cfg, err = parseRedditSourceConfig(json.RawMessage(`{"subreddit":"golang","extraction":["feed"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, extract.Chain{extract.MethodFeed}, cfg.extractionChain(extract.DefaultChain))
+
+	_, err = parseRedditSourceConfig(json.RawMessage(`{"subreddit":"golang","extraction":["feed",""]}`))
+	assert.Error(t, err)
+}
@@ -6,26 +6,28 @@ import (
 	"errors"
 	"fmt"
 	"html"
-	"net/http"
-	nurl "net/url"
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
-	"github.com/go-shiori/go-readability"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mmcdole/gofeed"
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 	"github.com/zyrak/flux/internal/config"
 	"github.com/zyrak/flux/internal/dedup"
+	"github.com/zyrak/flux/internal/extract"
+	"github.com/zyrak/flux/internal/feeds"
 	"github.com/zyrak/flux/internal/models"
 	"github.com/zyrak/flux/internal/queue"
 	"github.com/zyrak/flux/internal/ratelimit"
 	"github.com/zyrak/flux/internal/store"
+	"github.com/zyrak/flux/internal/tracing"
 )
 
 const (
@@ -34,48 +36,114 @@ const (
 	sourceTypeRSS     = "rss"
 	runInterval       = 30 * time.Minute
 	requestTimeout    = 30 * time.Second
+	maxFeedBytes      = 10 << 20
+	defaultMaxItems   = 50
 )
 
 var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
 
 type rssSourceConfig struct {
-	URL    string `json:"url"`
-	Format string `json:"format,omitempty"`
+	URL string `json:"url"`
+	// FetchContent disables the readability fetch when false, keeping the
+	// feed-provided text. Defaults to true.
+	FetchContent *bool `json:"fetch_content,omitempty"`
+	// TitleDedup skips items whose normalized title matches an article from
+	// the last day. Off by default.
+	TitleDedup bool `json:"title_dedup,omitempty"`
+	// MaxItems caps how many of the newest items are processed per run, so a
+	// feed with hundreds of entries does not trigger as many content fetches.
+	// Defaults to 50.
+	MaxItems int `json:"max_items,omitempty"`
+	// Extraction overrides CONTENT_EXTRACTION_CHAIN for this feed, e.g.
+	// ["readability","feed","meta"] for a site whose pages are SPAs.
+	Extraction []string `json:"extraction,omitempty"`
 }
 
-type newArticleEvent struct {
-	ArticleID string `json:"article_id"`
+func (c *rssSourceConfig) shouldFetchContent() bool {
+	return c.FetchContent == nil || *c.FetchContent
+}
+
+// extractionChain returns the feed's own chain, or fallback when it has none.
+func (c *rssSourceConfig) extractionChain(fallback extract.Chain) extract.Chain {
+	if len(c.Extraction) == 0 {
+		return fallback
+	}
+	return extract.Chain(c.Extraction)
+}
+
+func (c *rssSourceConfig) maxItems() int {
+	if c.MaxItems <= 0 {
+		return defaultMaxItems
+	}
+	return c.MaxItems
 }
 
 type rssWorker struct {
-	store      *store.Store
-	queue      *queue.Queue
-	checker    *dedup.Checker
-	httpClient *http.Client
+	store   *store.Store
+	queue   *queue.Queue
+	checker *dedup.Checker
+	feeds   *feeds.Fetcher
+	pages   *extract.PageFetcher
+	// extraction is the default content extraction chain.
+	extraction extract.Chain
+	// Articles with less text than this are tagged thin_content.
+	thinContentChars int
+	// Runs are skipped while more articles than this await the processor.
+	backpressureMax int
 }
 
 type rssRunStats struct {
 	FeedsProcessed int
 	ItemsSeen      int
 	NewArticles    int
+	ThinContent    int
 	FeedErrors     int
 }
 
 type feedStats struct {
 	ItemsSeen   int
 	NewArticles int
+	ThinContent int
+}
+
+// Content quality labels stored in article metadata.
+const (
+	contentQualityFull  = "full"
+	contentQualityThin  = "thin"
+	contentQualityEmpty = "empty"
+)
+
+// contentQuality grades extracted text by length so thin, summary-only
+// items can be audited or deprioritized downstream.
+func contentQuality(content string, thinChars int) string {
+	n := utf8.RuneCountInString(strings.TrimSpace(content))
+	switch {
+	case n == 0:
+		return contentQualityEmpty
+	case n < thinChars:
+		return contentQualityThin
+	default:
+		return contentQualityFull
+	}
 }
 
 func main() {
 	cfg := config.Load()
 	setupLogging(cfg.LogLevel)
+	dedup.SetTrackingParams(dedup.NewTrackingParams(cfg.DedupTrackingParams, cfg.DedupTrackingDefaults))
 
 	log.Info("Starting Flux RSS worker")
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	db, err := store.New(ctx, cfg.DatabaseURL)
+	shutdownTracing, err := tracing.Setup(ctx, "worker-rss", cfg.OTLPEndpoint)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to set up tracing")
+	}
+	defer tracing.Flush(shutdownTracing)
+
+	db, err := store.New(ctx, cfg.DatabaseURL, store.OptionsFromConfig(cfg))
 	if err != nil {
 		log.WithError(err).Fatal("Failed to connect to PostgreSQL")
 	}
@@ -99,18 +167,33 @@ func main() {
 	}
 
 	limiter, err := ratelimit.New(rdb, ratelimit.Config{
-		Limits:    cfg.RateLimits,
-		UserAgent: cfg.UserAgent,
+		Limits:        cfg.RateLimits,
+		MaxConcurrent: cfg.RateLimitConcurrency,
+		Jitter:        cfg.RateLimitJitter,
+		UserAgent:     cfg.UserAgent,
+		Proxy:         cfg.OutboundProxy,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("Failed to initialize rate limiter")
 	}
 
+	extraction, err := extract.NewChain(cfg.ContentExtractionChain)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid CONTENT_EXTRACTION_CHAIN")
+	}
+
+	httpClient := ratelimit.NewHTTPClient(limiter, requestTimeout)
 	worker := &rssWorker{
-		store:      db,
-		queue:      q,
-		checker:    dedup.NewChecker(rdb),
-		httpClient: ratelimit.NewHTTPClient(limiter, requestTimeout),
+		store:   db,
+		queue:   q,
+		checker: dedup.NewCheckerWithStore(rdb, db),
+		feeds:   &feeds.Fetcher{Client: httpClient, MaxBytes: maxFeedBytes},
+
+		pages:            &extract.PageFetcher{Client: httpClient, AllowedTypes: cfg.ContentAllowedTypes, MaxBytes: int64(cfg.ContentMaxBytes)},
+		extraction:       extraction,
+		thinContentChars: cfg.ThinContentChars,
+
+		backpressureMax: cfg.IngestBackpressureMax,
 	}
 
 	mode := parseWorkerMode()
@@ -126,6 +209,7 @@ func main() {
 			"feeds_processed": stats.FeedsProcessed,
 			"items_seen":      stats.ItemsSeen,
 			"new_articles":    stats.NewArticles,
+			"thin_content":    stats.ThinContent,
 			"feed_errors":     stats.FeedErrors,
 			"elapsed_ms":      time.Since(runStart).Milliseconds(),
 		}).Info("RSS worker run completed")
@@ -148,6 +232,12 @@ func main() {
 
 func (w *rssWorker) runOnce(ctx context.Context) (rssRunStats, error) {
 	stats := rssRunStats{}
+	ctx, span := tracing.Tracer().Start(ctx, "worker-rss.run")
+	defer span.End()
+
+	if w.queue.IngestBackpressure(sourceTypeRSS, w.backpressureMax) {
+		return stats, nil
+	}
 
 	sources, err := w.store.ListSourcesByTypeWithSectionIDs(ctx, sourceTypeRSS, true)
 	if err != nil {
@@ -159,6 +249,10 @@ func (w *rssWorker) runOnce(ctx context.Context) (rssRunStats, error) {
 		stats.FeedsProcessed++
 		stats.ItemsSeen += feedStats.ItemsSeen
 		stats.NewArticles += feedStats.NewArticles
+		stats.ThinContent += feedStats.ThinContent
+		if logErr := w.store.RecordSourceFetch(ctx, source.Source.ID, feedStats.ItemsSeen, feedStats.NewArticles, err); logErr != nil {
+			log.WithField("source_id", source.Source.ID).WithError(logErr).Warn("Failed to record source fetch")
+		}
 		if err != nil {
 			stats.FeedErrors++
 			log.WithFields(log.Fields{
@@ -187,28 +281,82 @@ func (w *rssWorker) processFeed(ctx context.Context, src *store.SourceWithSectio
 		_ = w.store.UpdateSourceFetchStatus(ctx, src.Source.ID, parseErr)
 		return stats, parseErr
 	}
+	chain := cfg.extractionChain(w.extraction)
 
-	parser := gofeed.NewParser()
-	parser.Client = w.httpClient
+	var validators *feeds.Validators
+	cache, err := w.store.GetSourceHTTPCache(ctx, src.Source.ID)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"source_id": src.Source.ID,
+			"source":    src.Source.Name,
+		}).WithError(err).Warn("Failed to load feed HTTP cache, fetching unconditionally")
+	} else if cache != nil {
+		validators = &feeds.Validators{ETag: cache.ETag, LastModified: cache.LastModified}
+	}
 
-	feed, err := parser.ParseURL(feedURL)
+	sourceURL := feedURL
+	res, err := w.feeds.Fetch(ctx, sourceURL, validators)
+	if res.URL != sourceURL {
+		log.WithFields(log.Fields{
+			"source_id": src.Source.ID,
+			"source":    src.Source.Name,
+			"page_url":  sourceURL,
+			"feed_url":  res.URL,
+		}).Warn("Source URL is a web page; using the feed it advertises (update the source to skip the extra fetch)")
+		feedURL = res.URL
+	}
 	if err != nil {
 		_ = w.store.UpdateSourceFetchStatus(ctx, src.Source.ID, err)
 		return stats, fmt.Errorf("parsing feed %s: %w", feedURL, err)
 	}
+	feed := res.Feed
+	if feed == nil {
+		if err := w.store.UpdateSourceFetchStatus(ctx, src.Source.ID, nil); err != nil {
+			log.WithFields(log.Fields{
+				"source_id": src.Source.ID,
+				"source":    src.Source.Name,
+			}).WithError(err).Warn("Failed to update source fetch status")
+		}
+		log.WithFields(log.Fields{
+			"source_id": src.Source.ID,
+			"source":    src.Source.Name,
+			"feed_url":  feedURL,
+		}).Debug("RSS feed not modified since last fetch")
+		return stats, nil
+	}
+	// The next run requests the source URL, so a discovered feed's
+	// validators are not kept: replayed against the page they could earn a
+	// 304 that hides new items.
+	newCache := store.SourceHTTPCache{ETag: res.Validators.ETag, LastModified: res.Validators.LastModified}
+	if res.URL != sourceURL {
+		newCache = store.SourceHTTPCache{}
+	}
+	if err := w.store.UpdateSourceHTTPCache(ctx, src.Source.ID, newCache); err != nil {
+		log.WithFields(log.Fields{
+			"source_id": src.Source.ID,
+			"source":    src.Source.Name,
+		}).WithError(err).Warn("Failed to store feed HTTP cache")
+	}
 
 	var sectionID *string
 	if len(src.SectionIDs) == 1 {
 		sectionID = &src.SectionIDs[0]
 	}
 
-	for _, item := range feed.Items {
+	items := newestItems(feed.Items, cfg.maxItems())
+	if skipped := len(feed.Items) - len(items); skipped > 0 {
+		log.WithFields(log.Fields{
+			"source_id": src.Source.ID,
+			"source":    src.Source.Name,
+			"max_items": cfg.maxItems(),
+			"skipped":   skipped,
+		}).Debug("Capped RSS feed to its newest items")
+	}
+
+	for _, item := range items {
 		stats.ItemsSeen++
 
-		rawURL := strings.TrimSpace(item.Link)
-		if rawURL == "" {
-			rawURL = strings.TrimSpace(item.GUID)
-		}
+		rawURL := itemLink(item)
 		if rawURL == "" {
 			continue
 		}
@@ -229,17 +377,53 @@ func (w *rssWorker) processFeed(ctx context.Context, src *store.SourceWithSectio
 			continue
 		}
 
-		content, contentErr := w.fetchArticleContent(ctx, normalizedURL)
-		if contentErr != nil {
-			log.WithFields(log.Fields{
-				"source_id": src.Source.ID,
-				"source":    src.Source.Name,
-				"url":       normalizedURL,
-			}).WithError(contentErr).Warn("Failed to fetch readable content, using feed fallback")
+		titleKey := dedup.TitleKey(item.Title)
+		if cfg.TitleDedup && titleKey != "" {
+			dup, err := w.store.ExistsRecentSimilarTitle(ctx, titleKey, time.Now().UTC().Add(-dedup.TitleDedupWindow))
+			if err != nil {
+				log.WithFields(log.Fields{
+					"source_id": src.Source.ID,
+					"url":       normalizedURL,
+				}).WithError(err).Warn("Title dedup check failed")
+			} else if dup {
+				log.WithFields(log.Fields{
+					"source_id": src.Source.ID,
+					"url":       normalizedURL,
+					"title_key": titleKey,
+				}).Debug("Skipping RSS item with duplicate title")
+				continue
+			}
+		}
+
+		feedText := cleanText(strings.TrimSpace(item.Content))
+		if feedText == "" {
+			feedText = cleanText(strings.TrimSpace(item.Description))
+		}
 
-			content = cleanText(strings.TrimSpace(item.Content))
-			if content == "" {
-				content = cleanText(strings.TrimSpace(item.Description))
+		var page []byte
+		var pageErr error
+		if cfg.shouldFetchContent() && chain.NeedsPage() {
+			page, pageErr = w.pages.Fetch(ctx, normalizedURL)
+			if pageErr != nil {
+				log.WithFields(log.Fields{
+					"source_id": src.Source.ID,
+					"source":    src.Source.Name,
+					"url":       normalizedURL,
+				}).WithError(pageErr).Log(extract.FetchLogLevel(pageErr), "Failed to fetch article page, using feed fallback")
+			}
+		}
+		content, method := chain.Run(page, normalizedURL, feedText)
+
+		// Summary-only feeds: normalization can drop query params some sites
+		// need, so give the original link one more try before settling.
+		quality := contentQuality(content, w.thinContentChars)
+		var skipErr *extract.SkipError
+		if quality != contentQualityFull && pageErr != nil && !errors.As(pageErr, &skipErr) && rawURL != normalizedURL {
+			if retryPage, err := w.pages.Fetch(ctx, rawURL); err == nil {
+				if retried, retriedMethod := chain.Run(retryPage, rawURL, feedText); len(retried) > len(content) {
+					content, method = retried, retriedMethod
+					quality = contentQuality(content, w.thinContentChars)
+				}
 			}
 		}
 
@@ -263,6 +447,16 @@ func (w *rssWorker) processFeed(ctx context.Context, src *store.SourceWithSectio
 		if guid := strings.TrimSpace(item.GUID); guid != "" {
 			metadataMap["guid"] = guid
 		}
+		if titleKey != "" {
+			metadataMap["title_key"] = titleKey
+		}
+		metadataMap["content_quality"] = quality
+		if method != "" {
+			metadataMap["extraction_method"] = method
+		}
+		if quality != contentQualityFull {
+			metadataMap["thin_content"] = true
+		}
 
 		metadata, err := json.Marshal(metadataMap)
 		if err != nil {
@@ -295,12 +489,14 @@ func (w *rssWorker) processFeed(ctx context.Context, src *store.SourceWithSectio
 			continue
 		}
 
-		if err := w.queue.Publish(queue.SubjectArticlesNew, newArticleEvent{ArticleID: article.ID}); err != nil {
-			log.WithField("article_id", article.ID).WithError(err).Error("Failed to publish articles.new")
+		if err := w.queue.PublishNewArticle(ctx, "worker-rss", article); err != nil {
 			continue
 		}
 
 		stats.NewArticles++
+		if quality != contentQualityFull {
+			stats.ThinContent++
+		}
 	}
 
 	if err := w.store.UpdateSourceFetchStatus(ctx, src.Source.ID, nil); err != nil {
@@ -316,39 +512,29 @@ func (w *rssWorker) processFeed(ctx context.Context, src *store.SourceWithSectio
 		"feed_url":      feedURL,
 		"items_seen":    stats.ItemsSeen,
 		"new_articles":  stats.NewArticles,
+		"thin_content":  stats.ThinContent,
 		"section_links": len(src.SectionIDs),
 	}).Info("RSS feed processed")
 
 	return stats, nil
 }
 
-func (w *rssWorker) fetchArticleContent(ctx context.Context, url string) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return "", err
+// newestItems returns at most limit items, newest first by published (or
+// updated) date. Undated items rank after dated ones in feed order.
+func newestItems(items []*gofeed.Item, limit int) []*gofeed.Item {
+	if limit <= 0 || len(items) <= limit {
+		return items
 	}
-
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
-	}
-
-	parsedURL, err := nurl.Parse(url)
-	if err != nil {
-		return "", err
-	}
-
-	article, err := readability.FromReader(resp.Body, parsedURL)
-	if err != nil {
-		return "", err
-	}
-
-	return cleanText(article.TextContent), nil
+	sorted := make([]*gofeed.Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := extractPublishedAt(sorted[i]), extractPublishedAt(sorted[j])
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.After(*b)
+	})
+	return sorted[:limit]
 }
 
 func cleanText(raw string) string {
@@ -362,6 +548,13 @@ func parseRSSSourceConfig(raw json.RawMessage) (*rssSourceConfig, error) {
 	if err := json.Unmarshal(raw, cfg); err != nil {
 		return nil, fmt.Errorf("parsing source config: %w", err)
 	}
+	if len(cfg.Extraction) > 0 {
+		chain, err := extract.NewChain(cfg.Extraction)
+		if err != nil {
+			return nil, fmt.Errorf("parsing source config: %w", err)
+		}
+		cfg.Extraction = chain
+	}
 	return cfg, nil
 }
 
@@ -376,6 +569,20 @@ func normalizeFeedURL(raw string) string {
 	return "https://" + raw
 }
 
+// itemLink returns the item's link, falling back to its other links (a JSON
+// Feed external_url) and then its GUID.
+func itemLink(item *gofeed.Item) string {
+	if link := strings.TrimSpace(item.Link); link != "" {
+		return link
+	}
+	for _, link := range item.Links {
+		if link = strings.TrimSpace(link); link != "" {
+			return link
+		}
+	}
+	return strings.TrimSpace(item.GUID)
+}
+
 func extractAuthor(item *gofeed.Item) *string {
 	if item.Author != nil {
 		name := strings.TrimSpace(item.Author.Name)
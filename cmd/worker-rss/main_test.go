@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/extract"
+	"github.com/zyrak/flux/internal/feeds"
+)
+
+func TestFetchFeedJSONFeed(t *testing.T) {
+	body, err := os.ReadFile("testdata/feed.json")
+	require.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	fetcher := &feeds.Fetcher{Client: srv.Client()}
+	res, err := fetcher.Fetch(context.Background(), srv.URL+"/feed.json", nil)
+	require.NoError(t, err)
+	feed := res.Feed
+	assert.Equal(t, "json", feed.FeedType)
+	assert.Equal(t, "Example JSON Feed", feed.Title)
+	require.Len(t, feed.Items, 2)
+
+	first := feed.Items[0]
+	assert.Equal(t, "https://example.org/posts/2", itemLink(first))
+	assert.Equal(t, "Second post", first.Title)
+	assert.Equal(t, "<p>Hello <b>world</b>.</p>", first.Content)
+	assert.Equal(t, "A short greeting.", first.Description)
+	require.NotNil(t, extractPublishedAt(first))
+	assert.True(t, time.Date(2026, 10, 14, 6, 30, 0, 0, time.UTC).Equal(*extractPublishedAt(first)))
+	assert.Equal(t, "Ana", *extractAuthor(first))
+
+	second := feed.Items[1]
+	assert.Equal(t, "https://news.example.com/story", itemLink(second), "external_url stands in for a missing url")
+	assert.Equal(t, "Plain text body.", second.Content)
+	assert.Nil(t, second.PublishedParsed)
+	require.NotNil(t, extractPublishedAt(second))
+	assert.Equal(t, "Luis", *extractAuthor(second))
+}
+
+func TestNewestItems(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	var items []*gofeed.Item
+	for i := 0; i < 60; i++ {
+		published := base.Add(time.Duration(i) * time.Hour)
+		items = append(items, &gofeed.Item{Title: fmt.Sprintf("item %d", i), PublishedParsed: &published})
+	}
+	items = append(items, &gofeed.Item{Title: "undated"})
+
+	cfg := rssSourceConfig{}
+	got := newestItems(items, cfg.maxItems())
+	require.Len(t, got, defaultMaxItems)
+	assert.Equal(t, "item 59", got[0].Title)
+	assert.Equal(t, "item 10", got[len(got)-1].Title)
+	assert.Equal(t, "item 0", items[0].Title, "input order is preserved")
+
+	cfg.MaxItems = 3
+	got = newestItems(items, cfg.maxItems())
+	assert.Equal(t, []string{"item 59", "item 58", "item 57"}, []string{got[0].Title, got[1].Title, got[2].Title})
+
+	assert.Len(t, newestItems(items[:5], 50), 5)
+}
+
+func TestRSSShouldFetchContent(t *testing.T) {
+	cfg, err := parseRSSSourceConfig(json.RawMessage(`{"url":"https://example.com/feed"}`))
+	require.NoError(t, err)
+	assert.True(t, cfg.shouldFetchContent())
+
+	cfg, err = parseRSSSourceConfig(json.RawMessage(`{"url":"https://example.com/feed","fetch_content":false}`))
+	require.NoError(t, err)
+	assert.False(t, cfg.shouldFetchContent())
+}
+
+func TestRSSExtractionChain(t *testing.T) {
+	cfg, err := parseRSSSourceConfig(json.RawMessage(`{"url":"https://example.com/feed"}`))
+	require.NoError(t, err)
+	assert.Equal(t, extract.DefaultChain, cfg.extractionChain(extract.DefaultChain))
+
+	cfg, err = parseRSSSourceConfig(json.RawMessage(`{"url":"https://example.com/feed","extraction":["Readability","feed","meta"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, extract.Chain{extract.MethodReadability, extract.MethodFeed, extract.MethodMeta}, cfg.extractionChain(extract.DefaultChain))
+
+	_, err = parseRSSSourceConfig(json.RawMessage(`{"url":"https://example.com/feed","extraction":["browser"]}`))
+	assert.Error(t, err)
+}
+
+func TestContentQuality(t *testing.T) {
+	assert.Equal(t, contentQualityEmpty, contentQuality("   ", 10))
+	assert.Equal(t, contentQualityThin, contentQuality("short summary", 20))
+	assert.Equal(t, contentQualityFull, contentQuality("short summary", 10))
+	// Counted in runes, not bytes.
+	assert.Equal(t, contentQualityThin, contentQuality("ñññññ", 6))
+}
@@ -0,0 +1,45 @@
+// Package briefing holds the Redis-backed state briefing-gen shares with the
+// API.
+package briefing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// A daemon-mode briefing-gen refreshes HeartbeatKey every HeartbeatEvery
+// while it consumes briefing.generate. The key expires HeartbeatTTL after the
+// last beat, so it is gone shortly after the daemon stops, while its durable
+// NATS consumer is not.
+const (
+	HeartbeatKey   = "flux:briefing:daemon"
+	HeartbeatEvery = 20 * time.Second
+	HeartbeatTTL   = 3 * HeartbeatEvery
+)
+
+// BeatDaemon refreshes the briefing daemon heartbeat.
+func BeatDaemon(ctx context.Context, rdb redis.Cmdable) error {
+	owner, _ := os.Hostname()
+	if err := rdb.Set(ctx, HeartbeatKey, owner, HeartbeatTTL).Err(); err != nil {
+		return fmt.Errorf("refreshing briefing daemon heartbeat: %w", err)
+	}
+	return nil
+}
+
+// DaemonAlive reports whether a daemon-mode briefing-gen has beaten
+// within HeartbeatTTL.
+func DaemonAlive(ctx context.Context, rdb redis.Cmdable) (bool, error) {
+	err := rdb.Get(ctx, HeartbeatKey).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading briefing daemon heartbeat: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,33 @@
+package briefing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDaemonHeartbeat(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	ctx := context.Background()
+
+	alive, err := DaemonAlive(ctx, rdb)
+	require.NoError(t, err)
+	assert.False(t, alive)
+
+	require.NoError(t, BeatDaemon(ctx, rdb))
+	alive, err = DaemonAlive(ctx, rdb)
+	require.NoError(t, err)
+	assert.True(t, alive)
+
+	mr.FastForward(HeartbeatTTL + time.Second)
+	alive, err = DaemonAlive(ctx, rdb)
+	require.NoError(t, err)
+	assert.False(t, alive, "the heartbeat lapses once the daemon stops beating")
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICacheTTL(t *testing.T) {
+	t.Setenv("API_CACHE_TTL", "0")
+	assert.Zero(t, Load().APICacheTTL, "0 disables the cache")
+
+	t.Setenv("API_CACHE_TTL", "-5s")
+	assert.Equal(t, 30*time.Second, Load().APICacheTTL)
+
+	t.Setenv("API_CACHE_TTL", "2m")
+	assert.Equal(t, 2*time.Minute, Load().APICacheTTL)
+}
+
+func TestProfileHalfLife(t *testing.T) {
+	t.Setenv("PROFILE_HALF_LIFE", "0")
+	assert.Zero(t, Load().ProfileHalfLife, "0 weighs all feedback equally")
+
+	t.Setenv("PROFILE_HALF_LIFE", "-1h")
+	assert.Equal(t, 30*24*time.Hour, Load().ProfileHalfLife)
+}
+
+func TestEmbeddingRetryEvery(t *testing.T) {
+	t.Setenv("PROCESSOR_EMBEDDING_RETRY_EVERY", "0")
+	assert.Zero(t, Load().EmbeddingRetryEvery, "0 disables the needs_embedding sweep")
+
+	t.Setenv("PROCESSOR_EMBEDDING_RETRY_EVERY", "-1m")
+	assert.Equal(t, 10*time.Minute, Load().EmbeddingRetryEvery)
+}
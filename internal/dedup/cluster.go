@@ -0,0 +1,209 @@
+package dedup
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zyrak/flux/internal/models"
+)
+
+// ClusterKey returns the semantic cluster an article belongs to, or its own
+// ID when it was never clustered, so every article groups under some key.
+func ClusterKey(article *models.Article) string {
+	if clusterID := stringFromMap(decodeMetadata(article.Metadata), "cluster_id"); clusterID != "" {
+		return clusterID
+	}
+	return article.ID
+}
+
+// GroupByCluster splits articles by ClusterKey, keeping clusters in the
+// order their first member appears and members in input order.
+func GroupByCluster(articles []*models.Article) [][]*models.Article {
+	index := make(map[string]int)
+	var groups [][]*models.Article
+	for _, article := range articles {
+		key := ClusterKey(article)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], article)
+	}
+	return groups
+}
+
+// PickClusterPrimary returns the article that represents a cluster: the one
+// the semantic clusterer recorded as primary when it is among members,
+// otherwise the member with the strongest HN/Reddit score, breaking ties by
+// earliest ingestion and then by ID.
+func PickClusterPrimary(members []*models.Article) *models.Article {
+	if len(members) == 0 {
+		return nil
+	}
+
+	for _, member := range members {
+		primaryID := stringFromMap(decodeMetadata(member.Metadata), "cluster_primary_id")
+		if primaryID == "" {
+			continue
+		}
+		for _, candidate := range members {
+			if candidate.ID == primaryID {
+				return candidate
+			}
+		}
+	}
+
+	ranked := make([]primaryCandidate, 0, len(members))
+	for _, member := range members {
+		ranked = append(ranked, primaryCandidate{
+			id:         member.ID,
+			ingestedAt: member.IngestedAt,
+			signal:     metadataSignal(member.Metadata),
+		})
+	}
+	return members[strongestCandidate(ranked)]
+}
+
+// primaryCandidate is what strongestCandidate ranks a cluster member by.
+type primaryCandidate struct {
+	id         string
+	ingestedAt time.Time
+	signal     float64
+}
+
+// strongestCandidate returns the index of the candidate with the highest
+// signal, breaking ties by earliest ingestion and then by lowest ID. Both the
+// semantic clusterer and PickClusterPrimary choose primaries with it, so the
+// briefing and the API agree with the primary recorded at ingestion.
+func strongestCandidate(candidates []primaryCandidate) int {
+	best := 0
+	for i := 1; i < len(candidates); i++ {
+		candidate, current := candidates[i], candidates[best]
+		switch {
+		case candidate.signal != current.signal:
+			if candidate.signal > current.signal {
+				best = i
+			}
+		case !candidate.ingestedAt.Equal(current.ingestedAt):
+			if candidate.ingestedAt.Before(current.ingestedAt) {
+				best = i
+			}
+		case candidate.id < current.id:
+			best = i
+		}
+	}
+	return best
+}
+
+// metadataSignal is an article's community score: the larger of its HN and
+// Reddit scores, or 0 for sources without one.
+func metadataSignal(raw json.RawMessage) float64 {
+	meta := decodeMetadata(raw)
+	hn := floatFromMap(meta, "hn_score")
+	reddit := floatFromMap(meta, "reddit_score")
+	if hn > reddit {
+		return hn
+	}
+	return reddit
+}
+
+// ClusterCoverage lists the distinct sources that reported a cluster,
+// strongest signal first. seenIn holds plain labels such as "HN" or
+// "r/golang"; reportedBy holds the same sources with their scores, e.g.
+// "HN (142 pts)".
+func ClusterCoverage(members []*models.Article) (seenIn []string, reportedBy []string) {
+	type coverage struct {
+		plain    string
+		detailed string
+		signal   float64
+		order    int
+	}
+
+	seen := make(map[string]coverage)
+	for i, member := range members {
+		plain, detailed, signal := sourceCoverage(member)
+		if plain == "" {
+			continue
+		}
+
+		existing, ok := seen[plain]
+		if !ok {
+			seen[plain] = coverage{
+				plain:    plain,
+				detailed: detailed,
+				signal:   signal,
+				order:    i,
+			}
+			continue
+		}
+
+		if signal > existing.signal {
+			existing.detailed = detailed
+			existing.signal = signal
+		}
+		seen[plain] = existing
+	}
+
+	items := make([]coverage, 0, len(seen))
+	for _, item := range seen {
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].signal != items[j].signal {
+			return items[i].signal > items[j].signal
+		}
+		if items[i].order != items[j].order {
+			return items[i].order < items[j].order
+		}
+		return items[i].plain < items[j].plain
+	})
+
+	seenIn = make([]string, 0, len(items))
+	reportedBy = make([]string, 0, len(items))
+	for _, item := range items {
+		seenIn = append(seenIn, item.plain)
+		reportedBy = append(reportedBy, item.detailed)
+	}
+	return seenIn, reportedBy
+}
+
+func sourceCoverage(article *models.Article) (plain string, detailed string, signal float64) {
+	meta := decodeMetadata(article.Metadata)
+	sourceType := strings.ToLower(strings.TrimSpace(article.SourceType))
+
+	switch sourceType {
+	case "hn":
+		score := floatFromMap(meta, "hn_score")
+		if score > 0 {
+			return "HN", fmt.Sprintf("HN (%d pts)", int(score)), score
+		}
+		return "HN", "HN", 0
+	case "reddit":
+		sub := stringFromMap(meta, "subreddit")
+		sub = strings.TrimPrefix(strings.ToLower(sub), "r/")
+		if sub == "" {
+			sub = "reddit"
+		}
+		score := floatFromMap(meta, "reddit_score")
+		plain = "r/" + sub
+		if score > 0 {
+			return plain, fmt.Sprintf("Reddit %s (%d pts)", plain, int(score)), score
+		}
+		return plain, "Reddit " + plain, 0
+	default:
+		name := stringFromMap(meta, "source_name")
+		if name == "" && sourceType == "github" {
+			name = stringFromMap(meta, "repo")
+		}
+		if name == "" {
+			name = article.SourceType
+		}
+		return name, name, 0
+	}
+}
@@ -0,0 +1,154 @@
+package dedup
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/models"
+)
+
+func clusterArticle(t *testing.T, id, sourceType string, ingestedAt time.Time, meta map[string]interface{}) *models.Article {
+	t.Helper()
+	raw, err := json.Marshal(meta)
+	require.NoError(t, err)
+	return &models.Article{ID: id, SourceType: sourceType, IngestedAt: ingestedAt, Metadata: raw}
+}
+
+func TestClusterKey(t *testing.T) {
+	now := time.Now().UTC()
+	assert.Equal(t, "c1", ClusterKey(clusterArticle(t, "a1", "hn", now, map[string]interface{}{"cluster_id": "c1"})))
+	assert.Equal(t, "a2", ClusterKey(clusterArticle(t, "a2", "hn", now, map[string]interface{}{})))
+}
+
+func TestPickClusterPrimary(t *testing.T) {
+	now := time.Now().UTC()
+	rss := clusterArticle(t, "a1", "rss", now.Add(-time.Hour), map[string]interface{}{"source_name": "LWN"})
+	hn := clusterArticle(t, "a2", "hn", now, map[string]interface{}{"hn_score": 142})
+	reddit := clusterArticle(t, "a3", "reddit", now, map[string]interface{}{"reddit_score": 89, "subreddit": "golang"})
+
+	assert.Equal(t, "a2", PickClusterPrimary([]*models.Article{rss, hn, reddit}).ID)
+
+	// The clusterer's recorded primary wins over signal.
+	recorded := clusterArticle(t, "a1", "rss", now, map[string]interface{}{"cluster_primary_id": "a1"})
+	assert.Equal(t, "a1", PickClusterPrimary([]*models.Article{hn, recorded}).ID)
+
+	assert.Nil(t, PickClusterPrimary(nil))
+}
+
+func TestClusterCoverage(t *testing.T) {
+	now := time.Now().UTC()
+	members := []*models.Article{
+		clusterArticle(t, "a1", "rss", now, map[string]interface{}{"source_name": "LWN"}),
+		clusterArticle(t, "a2", "reddit", now, map[string]interface{}{"reddit_score": 89, "subreddit": "r/Golang"}),
+		clusterArticle(t, "a3", "hn", now, map[string]interface{}{"hn_score": 142}),
+		clusterArticle(t, "a4", "hn", now, map[string]interface{}{"hn_score": 12}),
+	}
+
+	seenIn, reportedBy := ClusterCoverage(members)
+	assert.Equal(t, []string{"HN", "r/golang", "LWN"}, seenIn)
+	assert.Equal(t, []string{"HN (142 pts)", "Reddit r/golang (89 pts)", "LWN"}, reportedBy)
+}
+
+func TestStrongestCandidateTieBreaks(t *testing.T) {
+	now := time.Now().UTC()
+	tests := []struct {
+		name       string
+		candidates []primaryCandidate
+		want       int
+	}{
+		{
+			name:       "highest signal wins",
+			candidates: []primaryCandidate{{id: "a", ingestedAt: now, signal: 10}, {id: "b", ingestedAt: now, signal: 50}},
+			want:       1,
+		},
+		{
+			name:       "signal beats earlier ingestion",
+			candidates: []primaryCandidate{{id: "a", ingestedAt: now.Add(-time.Hour), signal: 10}, {id: "b", ingestedAt: now, signal: 11}},
+			want:       1,
+		},
+		{
+			name:       "equal signal prefers earliest ingestion",
+			candidates: []primaryCandidate{{id: "a", ingestedAt: now, signal: 10}, {id: "b", ingestedAt: now.Add(-time.Minute), signal: 10}},
+			want:       1,
+		},
+		{
+			name:       "equal signal and ingestion prefers lowest id",
+			candidates: []primaryCandidate{{id: "b", ingestedAt: now}, {id: "c", ingestedAt: now}, {id: "a", ingestedAt: now}},
+			want:       2,
+		},
+		{
+			name:       "first of identical candidates",
+			candidates: []primaryCandidate{{id: "a", ingestedAt: now}, {id: "a", ingestedAt: now}},
+			want:       0,
+		},
+		{
+			name:       "single candidate",
+			candidates: []primaryCandidate{{id: "a"}},
+			want:       0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, strongestCandidate(tc.candidates))
+		})
+	}
+}
+
+func TestPickClusterPrimaryAgreesWithClusterer(t *testing.T) {
+	now := time.Now().UTC()
+	metas := []map[string]interface{}{
+		{"hn_score": 40},
+		{"reddit_score": 40, "subreddit": "golang"},
+		{"source_name": "LWN"},
+		{"hn_score": 12},
+	}
+	ids := []string{"d", "c", "b", "a"}
+	ingested := []time.Time{now, now.Add(-time.Minute), now.Add(-time.Hour), now}
+
+	var (
+		articles []*models.Article
+		semantic []SemanticArticle
+	)
+	for i, meta := range metas {
+		a := clusterArticle(t, ids[i], "hn", ingested[i], meta)
+		articles = append(articles, a)
+		semantic = append(semantic, SemanticArticle{ID: a.ID, IngestedAt: a.IngestedAt, Metadata: a.Metadata})
+	}
+
+	// hn_score 40 and reddit_score 40 tie; the reddit post was ingested first.
+	assert.Equal(t, "c", PickClusterPrimary(articles).ID)
+	assert.Equal(t, "c", pickPrimaryArticle(semantic).ID)
+}
+
+func TestGroupByCluster(t *testing.T) {
+	now := time.Now().UTC()
+	a1 := clusterArticle(t, "a1", "hn", now, map[string]interface{}{"cluster_id": "c1"})
+	a2 := clusterArticle(t, "a2", "rss", now, map[string]interface{}{})
+	a3 := clusterArticle(t, "a3", "reddit", now, map[string]interface{}{"cluster_id": "c1"})
+	a4 := clusterArticle(t, "a4", "rss", now, map[string]interface{}{"cluster_id": "c2"})
+
+	groups := GroupByCluster([]*models.Article{a1, a2, a3, a4})
+	require.Len(t, groups, 3)
+	assert.Equal(t, []*models.Article{a1, a3}, groups[0])
+	assert.Equal(t, []*models.Article{a2}, groups[1])
+	assert.Equal(t, []*models.Article{a4}, groups[2])
+	assert.Empty(t, GroupByCluster(nil))
+}
+
+func TestClusterCoverageKeepsStrongestPerSource(t *testing.T) {
+	now := time.Now().UTC()
+	members := []*models.Article{
+		clusterArticle(t, "a1", "reddit", now, map[string]interface{}{"subreddit": "programming"}),
+		clusterArticle(t, "a2", "rss", now, map[string]interface{}{"source_name": "LWN"}),
+		clusterArticle(t, "a3", "reddit", now, map[string]interface{}{"subreddit": "programming", "reddit_score": 5}),
+		clusterArticle(t, "a4", "github", now, map[string]interface{}{"repo": "golang/go"}),
+	}
+
+	seenIn, reportedBy := ClusterCoverage(members)
+	assert.Equal(t, []string{"r/programming", "LWN", "golang/go"}, seenIn)
+	assert.Equal(t, []string{"Reddit r/programming (5 pts)", "LWN", "golang/go"}, reportedBy)
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -19,19 +20,93 @@ const (
 	keyPrefix = "flux:dedup:"
 )
 
-// Tracking parameters to strip from URLs before hashing.
-var trackingParams = map[string]bool{
-	"utm_source": true, "utm_medium": true, "utm_campaign": true,
-	"utm_term": true, "utm_content": true, "utm_id": true,
-	"fbclid": true, "gclid": true, "dclid": true,
-	"mc_cid": true, "mc_eid": true,
-	"ref": true, "source": true,
-	"_ga": true, "_gl": true,
+// defaultTrackingParams are stripped from URLs before hashing unless the
+// defaults are disabled. Entries ending in "*" match by prefix.
+var defaultTrackingParams = []string{
+	"utm_*",
+	"fbclid", "gclid", "dclid", "msclkid", "yclid", "igshid",
+	"mc_cid", "mc_eid",
+	"ref", "source",
+	"_ga", "_gl",
 }
 
-// Checker provides URL deduplication using Redis.
+// TrackingParams is the set of query parameters NormalizeURL removes.
+type TrackingParams struct {
+	exact    map[string]bool
+	prefixes []string
+}
+
+// NewTrackingParams builds a parameter set from extra entries, merged with the
+// defaults when includeDefaults is true. Matching is case-insensitive and an
+// entry ending in "*" (e.g. "utm_*") matches every parameter with that prefix.
+func NewTrackingParams(extra []string, includeDefaults bool) *TrackingParams {
+	t := &TrackingParams{exact: make(map[string]bool)}
+	entries := extra
+	if includeDefaults {
+		entries = append(append([]string{}, defaultTrackingParams...), extra...)
+	}
+	for _, e := range entries {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if e == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(e, "*"); ok {
+			if prefix != "" {
+				t.prefixes = append(t.prefixes, prefix)
+			}
+			continue
+		}
+		t.exact[e] = true
+	}
+	return t
+}
+
+// Matches reports whether key is a tracking parameter.
+func (t *TrackingParams) Matches(key string) bool {
+	key = strings.ToLower(key)
+	if t.exact[key] {
+		return true
+	}
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	trackingMu     sync.RWMutex
+	trackingParams = NewTrackingParams(nil, true)
+)
+
+// SetTrackingParams replaces the process-wide set used by NormalizeURL and
+// HashURL. Call it once at startup, before any URL is hashed, so every binary
+// produces the same hashes.
+func SetTrackingParams(t *TrackingParams) {
+	trackingMu.Lock()
+	defer trackingMu.Unlock()
+	trackingParams = t
+}
+
+func currentTrackingParams() *TrackingParams {
+	trackingMu.RLock()
+	defer trackingMu.RUnlock()
+	return trackingParams
+}
+
+// SeenStore is a durable record of seen URL hashes that survives Redis
+// flushes and TTL expiry.
+type SeenStore interface {
+	// MarkURLSeen records hash and reports whether it was not recorded before.
+	MarkURLSeen(ctx context.Context, urlHash string) (bool, error)
+}
+
+// Checker provides URL deduplication using Redis, optionally backed by a
+// durable SeenStore.
 type Checker struct {
-	rdb *redis.Client
+	rdb  *redis.Client
+	seen SeenStore
 }
 
 // NewChecker creates a new dedup checker.
@@ -39,24 +114,56 @@ func NewChecker(rdb *redis.Client) *Checker {
 	return &Checker{rdb: rdb}
 }
 
+// NewCheckerWithStore creates a dedup checker that falls back to seen on a
+// Redis miss, so entries outlive the Redis TTL.
+func NewCheckerWithStore(rdb *redis.Client, seen SeenStore) *Checker {
+	return &Checker{rdb: rdb, seen: seen}
+}
+
 // IsNew returns true if this URL has not been seen before.
 func (c *Checker) IsNew(ctx context.Context, rawURL string) (bool, error) {
 	hash := HashURL(rawURL)
 	key := keyPrefix + hash
 
-	// SETNX: set only if not exists, with TTL
-	set, err := c.rdb.SetNX(ctx, key, "1", dedupTTL).Result()
+	if c.seen == nil {
+		// SETNX: set only if not exists, with TTL
+		set, err := c.rdb.SetNX(ctx, key, "1", dedupTTL).Result()
+		if err != nil {
+			return false, err
+		}
+		return set, nil // true = was new (key was set), false = already existed
+	}
+
+	n, err := c.rdb.Exists(ctx, key).Result()
 	if err != nil {
 		return false, err
 	}
-	return set, nil // true = was new (key was set), false = already existed
+	if n > 0 {
+		return false, nil
+	}
+
+	// Redis miss: the key may have expired or been flushed, so the durable
+	// store has the final say. It is written first so a failed insert never
+	// leaves the URL marked seen in Redis alone.
+	isNew, err := c.seen.MarkURLSeen(ctx, hash)
+	if err != nil {
+		return false, err
+	}
+	if err := c.rdb.Set(ctx, key, "1", dedupTTL).Err(); err != nil {
+		return false, err
+	}
+	return isNew, nil
 }
 
 // MarkSeen marks a URL as seen without checking.
 func (c *Checker) MarkSeen(ctx context.Context, rawURL string) error {
 	hash := HashURL(rawURL)
-	key := keyPrefix + hash
-	return c.rdb.Set(ctx, key, "1", dedupTTL).Err()
+	if c.seen != nil {
+		if _, err := c.seen.MarkURLSeen(ctx, hash); err != nil {
+			return err
+		}
+	}
+	return c.rdb.Set(ctx, keyPrefix+hash, "1", dedupTTL).Err()
 }
 
 // HashURL normalizes a URL and returns its SHA-256 hash.
@@ -69,6 +176,11 @@ func HashURL(rawURL string) string {
 // NormalizeURL removes tracking parameters, normalizes www, lowercases scheme/host,
 // removes trailing slashes, and sorts query params for consistent hashing.
 func NormalizeURL(rawURL string) string {
+	return currentTrackingParams().NormalizeURL(rawURL)
+}
+
+// NormalizeURL is the package-level NormalizeURL using this parameter set.
+func (t *TrackingParams) NormalizeURL(rawURL string) string {
 	rawURL = strings.TrimSpace(rawURL)
 
 	u, err := url.Parse(rawURL)
@@ -96,7 +208,7 @@ func NormalizeURL(rawURL string) string {
 		params := u.Query()
 		cleaned := url.Values{}
 		for k, v := range params {
-			if !trackingParams[strings.ToLower(k)] {
+			if !t.Matches(k) {
 				cleaned[k] = v
 			}
 		}
@@ -1,9 +1,14 @@
 package dedup
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNormalizeURL(t *testing.T) {
@@ -88,3 +93,116 @@ func TestHashURL(t *testing.T) {
 	hash5 := HashURL("https://www.example.com/article")
 	assert.Equal(t, hash1, hash5)
 }
+
+type memorySeenStore struct {
+	hashes map[string]bool
+	calls  int
+	err    error
+}
+
+func (m *memorySeenStore) MarkURLSeen(_ context.Context, urlHash string) (bool, error) {
+	m.calls++
+	if m.err != nil {
+		return false, m.err
+	}
+	if m.hashes[urlHash] {
+		return false, nil
+	}
+	m.hashes[urlHash] = true
+	return true, nil
+}
+
+func TestCheckerFallsBackToSeenStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	seen := &memorySeenStore{hashes: map[string]bool{}}
+	c := NewCheckerWithStore(rdb, seen)
+	ctx := context.Background()
+	url := "https://example.com/article?utm_source=x"
+
+	isNew, err := c.IsNew(ctx, url)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+	assert.True(t, seen.hashes[HashURL(url)])
+
+	// Redis hit: the durable store is not consulted.
+	isNew, err = c.IsNew(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.Equal(t, 1, seen.calls)
+
+	// Redis flushed: Postgres still remembers the URL and Redis is repopulated.
+	mr.FlushAll()
+	isNew, err = c.IsNew(ctx, url)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+	assert.True(t, mr.Exists(keyPrefix+HashURL(url)))
+}
+
+func TestCheckerLeavesRedisUnsetWhenStoreFails(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	seen := &memorySeenStore{hashes: map[string]bool{}, err: errors.New("postgres down")}
+	c := NewCheckerWithStore(rdb, seen)
+	url := "https://example.com/article"
+
+	_, err := c.IsNew(context.Background(), url)
+	require.Error(t, err)
+	assert.False(t, mr.Exists(keyPrefix+HashURL(url)), "a URL missing from Postgres must not be marked seen in Redis")
+
+	require.Error(t, c.MarkSeen(context.Background(), url))
+	assert.False(t, mr.Exists(keyPrefix+HashURL(url)))
+}
+
+func TestCheckerWithoutStore(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	c := NewChecker(rdb)
+	ctx := context.Background()
+
+	isNew, err := c.IsNew(ctx, "https://example.com/a")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+
+	mr.FlushAll()
+	isNew, err = c.IsNew(ctx, "https://example.com/a")
+	require.NoError(t, err)
+	assert.True(t, isNew)
+}
+
+func TestTrackingParamsCustom(t *testing.T) {
+	params := NewTrackingParams([]string{"igshid", "si", "ref_*"}, true)
+
+	assert.Equal(t,
+		"https://example.com/watch?v=abc",
+		params.NormalizeURL("https://example.com/watch?v=abc&si=XYZ&ref_src=tw&utm_campaign=x&UTM_Foo=y"),
+	)
+	assert.True(t, params.Matches("fbclid"))
+	assert.True(t, params.Matches("Ref_Anything"))
+	assert.False(t, params.Matches("v"))
+}
+
+func TestTrackingParamsWithoutDefaults(t *testing.T) {
+	params := NewTrackingParams([]string{"si"}, false)
+
+	assert.Equal(t,
+		"https://example.com/a?utm_source=x",
+		params.NormalizeURL("https://example.com/a?utm_source=x&si=1"),
+	)
+	assert.False(t, params.Matches("fbclid"))
+}
+
+func TestSetTrackingParams(t *testing.T) {
+	t.Cleanup(func() { SetTrackingParams(NewTrackingParams(nil, true)) })
+
+	before := HashURL("https://example.com/a?feature=share")
+	SetTrackingParams(NewTrackingParams([]string{"feature"}, true))
+	assert.Equal(t, "https://example.com/a", NormalizeURL("https://example.com/a?feature=share"))
+	assert.NotEqual(t, before, HashURL("https://example.com/a?feature=share"))
+}
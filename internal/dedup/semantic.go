@@ -45,6 +45,16 @@ func NewSemanticClusterer() *SemanticClusterer {
 	return &SemanticClusterer{threshold: SemanticSimilarityThreshold}
 }
 
+// NewSemanticClustererWithThreshold creates a clusterer that treats articles
+// as duplicates when their cosine similarity exceeds threshold, which must be
+// strictly between 0 and 1.
+func NewSemanticClustererWithThreshold(threshold float64) (*SemanticClusterer, error) {
+	if !(threshold > 0 && threshold < 1) {
+		return nil, fmt.Errorf("semantic dedup threshold must be between 0 and 1, got %v", threshold)
+	}
+	return &SemanticClusterer{threshold: threshold}, nil
+}
+
 // Cluster builds metadata updates for articles in a semantic cluster.
 // Returns (result, true, nil) when clustering was applied.
 func (c *SemanticClusterer) Cluster(current SemanticArticle, neighbors []SemanticArticle) (*SemanticClusterResult, bool, error) {
@@ -155,42 +165,15 @@ func pickPrimaryArticle(candidates []SemanticArticle) SemanticArticle {
 		return SemanticArticle{}
 	}
 
-	best := candidates[0]
-	bestSignal := signalScore(best)
-
-	for i := 1; i < len(candidates); i++ {
-		candidate := candidates[i]
-		candidateSignal := signalScore(candidate)
-
-		if candidateSignal > bestSignal {
-			best = candidate
-			bestSignal = candidateSignal
-			continue
-		}
-		if candidateSignal < bestSignal {
-			continue
-		}
-
-		if candidate.IngestedAt.Before(best.IngestedAt) {
-			best = candidate
-			continue
-		}
-		if candidate.IngestedAt.Equal(best.IngestedAt) && candidate.ID < best.ID {
-			best = candidate
-		}
-	}
-
-	return best
-}
-
-func signalScore(article SemanticArticle) float64 {
-	meta := decodeMetadata(article.Metadata)
-	hn := floatFromMap(meta, "hn_score")
-	reddit := floatFromMap(meta, "reddit_score")
-	if hn > reddit {
-		return hn
+	ranked := make([]primaryCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, primaryCandidate{
+			id:         candidate.ID,
+			ingestedAt: candidate.IngestedAt,
+			signal:     metadataSignal(candidate.Metadata),
+		})
 	}
-	return reddit
+	return candidates[strongestCandidate(ranked)]
 }
 
 func decodeMetadata(raw json.RawMessage) map[string]interface{} {
@@ -89,3 +89,21 @@ func TestSemanticClusterer_ReusesExistingClusterID(t *testing.T) {
 	require.NotNil(t, result)
 	assert.Equal(t, existing, result.ClusterID)
 }
+
+func TestNewSemanticClustererWithThreshold(t *testing.T) {
+	for _, invalid := range []float64{0, 1, -0.2, 1.5} {
+		_, err := NewSemanticClustererWithThreshold(invalid)
+		assert.Error(t, err, "threshold %v", invalid)
+	}
+
+	clusterer, err := NewSemanticClustererWithThreshold(0.75)
+	require.NoError(t, err)
+
+	result, clustered, err := clusterer.Cluster(
+		SemanticArticle{ID: "a1", IngestedAt: time.Now()},
+		[]SemanticArticle{{ID: "a2", Similarity: 0.80, IngestedAt: time.Now()}},
+	)
+	require.NoError(t, err)
+	assert.True(t, clustered)
+	assert.Equal(t, []string{"a1", "a2"}, result.MemberIDs)
+}
@@ -0,0 +1,47 @@
+package dedup
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// TitleDedupWindow is how far back an identical title key counts as the same story.
+const TitleDedupWindow = 24 * time.Hour
+
+// minTitleKeyWords is the fewest significant words a title needs before it
+// is used for near-duplicate matching; shorter titles collide too easily.
+const minTitleKeyWords = 4
+
+// titleStopwords are dropped from title keys (English and Spanish).
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true,
+	"to": true, "in": true, "on": true, "for": true, "with": true, "by": true,
+	"at": true, "from": true, "is": true, "are": true, "was": true, "be": true,
+	"as": true, "it": true, "its": true, "this": true, "that": true,
+	"el": true, "la": true, "los": true, "las": true, "un": true, "una": true,
+	"de": true, "del": true, "y": true, "o": true, "en": true, "con": true,
+	"por": true, "para": true, "que": true, "se": true, "al": true, "es": true,
+}
+
+// TitleKey reduces a title to a comparison key: lowercased, punctuation
+// stripped, stopwords removed and whitespace collapsed. Word order is kept so
+// only near-identical titles match. Returns "" when the title has too few
+// significant words to compare safely.
+func TitleKey(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	kept := make([]string, 0, len(words))
+	for _, w := range words {
+		if titleStopwords[w] {
+			continue
+		}
+		kept = append(kept, w)
+	}
+	if len(kept) < minTitleKeyWords {
+		return ""
+	}
+	return strings.Join(kept, " ")
+}
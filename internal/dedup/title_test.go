@@ -0,0 +1,37 @@
+package dedup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitleKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"punctuation and case", "OpenSSL 3.5: Patches a Critical Bug!", "openssl 3 5 patches critical bug"},
+		{"stopwords dropped", "The Rise of the Rust Kernel Drivers", "rise rust kernel drivers"},
+		{"spanish stopwords", "La nueva versión de Go mejora el rendimiento", "nueva versión go mejora rendimiento"},
+		{"too short", "Rust is out today", ""},
+		{"empty", "   ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TitleKey(tt.input))
+		})
+	}
+}
+
+func TestTitleKeyMatchesVariants(t *testing.T) {
+	canonical := TitleKey("Chrome zero-day exploited in the wild, patch now")
+	amp := TitleKey("Chrome Zero-Day Exploited in the Wild — Patch Now")
+	assert.NotEmpty(t, canonical)
+	assert.Equal(t, canonical, amp)
+
+	// Reordered words are a different story as far as the key is concerned.
+	assert.NotEqual(t, canonical, TitleKey("Patch now: Chrome zero-day exploited in the wild"))
+}
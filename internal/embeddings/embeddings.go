@@ -10,14 +10,94 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/zyrak/flux/internal/retry"
+)
+
+// Default batching used by NewClient.
+const (
+	DefaultBatchSize      = 32
+	DefaultBatchThreshold = 100
 )
 
-// Client communicates with the local embeddings service (all-MiniLM-L6-v2).
+// Embedding backends.
+const (
+	// ProviderNative is the bundled embeddings-svc (all-MiniLM-L6-v2),
+	// called at POST {endpoint}/embed.
+	ProviderNative = "native"
+	// ProviderOpenAI is any OpenAI-compatible server (OpenAI, Ollama, LM
+	// Studio), called at POST {endpoint}/embeddings with endpoint ending in
+	// /v1.
+	ProviderOpenAI = "openai"
+)
+
+// Client communicates with the local embeddings service (all-MiniLM-L6-v2)
+// or an OpenAI-compatible embeddings API.
 type Client struct {
 	httpClient *http.Client
 	endpoint   string
 	maxRetries int
+	// Inputs larger than batchThreshold are sent in requests of batchSize.
+	batchSize      int
+	batchThreshold int
+
+	provider   string
+	apiKey     string
+	model      string
+	dimensions int
+	// expectedDimension rejects responses of another size; 0 skips the check.
+	expectedDimension int
+}
+
+// Options tunes request batching and selects the backend. Zero values fall
+// back to the defaults: the native service without a dimension check.
+type Options struct {
+	BatchSize      int
+	BatchThreshold int
+
+	// Provider is ProviderNative (default) or ProviderOpenAI.
+	Provider string
+	// APIKey is sent as a bearer token to OpenAI-compatible servers.
+	APIKey string
+	// Model names the OpenAI-compatible embedding model; required there.
+	Model string
+	// Dimensions, when set, is sent as "dimensions" to OpenAI-compatible
+	// servers, for models that can shorten their output
+	// (text-embedding-3-*).
+	Dimensions int
+	// ExpectedDimension fails requests whose vectors have another size,
+	// usually the store's EMBEDDINGS_STORE_DIMENSION.
+	ExpectedDimension int
+}
+
+// Validate reports options NewClientWithOptions cannot work with.
+func (o Options) Validate() error {
+	switch o.provider() {
+	case ProviderNative:
+	case ProviderOpenAI:
+		if strings.TrimSpace(o.Model) == "" {
+			return fmt.Errorf("embeddings provider %q needs a model", ProviderOpenAI)
+		}
+	default:
+		return fmt.Errorf("unknown embeddings provider %q (want %s or %s)", o.Provider, ProviderNative, ProviderOpenAI)
+	}
+	if o.Dimensions < 0 {
+		return fmt.Errorf("embeddings dimensions must not be negative, got %d", o.Dimensions)
+	}
+	if o.Dimensions > 0 && o.ExpectedDimension > 0 && o.Dimensions != o.ExpectedDimension {
+		return fmt.Errorf("requested %d embedding dimensions but %d are stored; changing the dimension needs a schema change and a reindex", o.Dimensions, o.ExpectedDimension)
+	}
+	return nil
+}
+
+func (o Options) provider() string {
+	if p := strings.ToLower(strings.TrimSpace(o.Provider)); p != "" {
+		return p
+	}
+	return ProviderNative
 }
 
 // EmbeddingRequest is the request body for the embeddings service.
@@ -30,18 +110,57 @@ type EmbeddingResponse struct {
 	Embeddings [][]float32 `json:"embeddings"`
 }
 
+// openAIEmbeddingRequest is the body of an OpenAI-compatible
+// POST /v1/embeddings.
+type openAIEmbeddingRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+// openAIEmbeddingResponse is an OpenAI-compatible embeddings response; data
+// is matched back to the inputs by index.
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
 // NewClient creates a new embeddings client.
 func NewClient(endpoint string) *Client {
+	return NewClientWithOptions(endpoint, Options{})
+}
+
+// NewClientWithOptions creates an embeddings client with custom batching.
+func NewClientWithOptions(endpoint string, opts Options) *Client {
 	if endpoint == "" {
 		endpoint = os.Getenv("EMBEDDINGS_URL")
 	}
 	if endpoint == "" {
-		endpoint = "http://embeddings-svc:8000"
+		if opts.provider() == ProviderOpenAI {
+			endpoint = "https://api.openai.com/v1"
+		} else {
+			endpoint = "http://embeddings-svc:8000"
+		}
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.BatchThreshold <= 0 {
+		opts.BatchThreshold = DefaultBatchThreshold
 	}
 	return &Client{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		endpoint:   endpoint,
-		maxRetries: 6,
+		httpClient:        &http.Client{Timeout: 30 * time.Second},
+		endpoint:          strings.TrimRight(endpoint, "/"),
+		maxRetries:        6,
+		batchSize:         opts.BatchSize,
+		batchThreshold:    opts.BatchThreshold,
+		provider:          opts.provider(),
+		apiKey:            opts.APIKey,
+		model:             strings.TrimSpace(opts.Model),
+		dimensions:        opts.Dimensions,
+		expectedDimension: opts.ExpectedDimension,
 	}
 }
 
@@ -52,8 +171,8 @@ func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error)
 	}
 
 	// Large payloads are split into smaller requests to reduce memory spikes.
-	if len(texts) > 100 {
-		return c.embedInBatches(ctx, texts, 32)
+	if len(texts) > c.batchThreshold {
+		return c.embedInBatches(ctx, texts, c.batchSize)
 	}
 
 	return c.embedRequestWithRetry(ctx, texts)
@@ -76,19 +195,28 @@ func (c *Client) embedInBatches(ctx context.Context, texts []string, batchSize i
 }
 
 func (c *Client) embedRequestWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
-	body, err := json.Marshal(EmbeddingRequest{Texts: texts})
+	path := "/embed"
+	var payload interface{} = EmbeddingRequest{Texts: texts}
+	if c.provider == ProviderOpenAI {
+		path = "/embeddings"
+		payload = openAIEmbeddingRequest{Model: c.model, Input: texts, Dimensions: c.dimensions}
+	}
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
 	var lastErr error
-	backoff := 500 * time.Millisecond
+	backoff := retry.Backoff{Initial: 500 * time.Millisecond, Max: 8 * time.Second}
 	for attempt := 1; attempt <= c.maxRetries; attempt++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/embed", bytes.NewReader(body))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(body))
 		if err != nil {
 			return nil, fmt.Errorf("creating request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
+		if c.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+		}
 
 		resp, err := c.httpClient.Do(req)
 		if err != nil {
@@ -99,18 +227,15 @@ func (c *Client) embedRequestWithRetry(ctx context.Context, texts []string) ([][
 			if readErr != nil {
 				lastErr = fmt.Errorf("reading response: %w", readErr)
 			} else if resp.StatusCode == http.StatusOK {
-				var embResp EmbeddingResponse
-				if err := json.Unmarshal(respBody, &embResp); err != nil {
-					return nil, fmt.Errorf("unmarshalling response: %w", err)
-				}
-				if len(embResp.Embeddings) != len(texts) {
-					return nil, fmt.Errorf("embeddings count mismatch: requested=%d got=%d", len(texts), len(embResp.Embeddings))
+				vectors, err := c.decodeResponse(respBody, len(texts))
+				if err != nil {
+					return nil, &permanentError{err: err}
 				}
-				return embResp.Embeddings, nil
+				return vectors, nil
 			} else {
 				lastErr = fmt.Errorf("embeddings service returned %d: %s", resp.StatusCode, string(respBody))
-				if !isRetryableStatus(resp.StatusCode) {
-					return nil, lastErr
+				if !retry.RetryableStatus(resp.StatusCode) {
+					return nil, &permanentError{err: lastErr}
 				}
 			}
 		}
@@ -118,13 +243,9 @@ func (c *Client) embedRequestWithRetry(ctx context.Context, texts []string) ([][
 		if attempt == c.maxRetries {
 			break
 		}
-		if err := sleepWithContext(ctx, backoff); err != nil {
+		if err := retry.Sleep(ctx, backoff.Next()); err != nil {
 			return nil, err
 		}
-		backoff *= 2
-		if backoff > 8*time.Second {
-			backoff = 8 * time.Second
-		}
 	}
 
 	if lastErr == nil {
@@ -133,6 +254,54 @@ func (c *Client) embedRequestWithRetry(ctx context.Context, texts []string) ([][
 	return nil, fmt.Errorf("embeddings request failed after retries: %w", lastErr)
 }
 
+// permanentError marks a failure that retrying the same request cannot fix.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// IsPermanent reports whether err is a request the service rejected (a 4xx
+// other than 429) or a response the client cannot use, such as vectors of
+// the wrong dimension. Connection errors, timeouts and 5xx are transient.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// decodeResponse reads a successful response body holding want vectors.
+func (c *Client) decodeResponse(body []byte, want int) ([][]float32, error) {
+	var vectors [][]float32
+	if c.provider == ProviderOpenAI {
+		var embResp openAIEmbeddingResponse
+		if err := json.Unmarshal(body, &embResp); err != nil {
+			return nil, fmt.Errorf("unmarshalling response: %w", err)
+		}
+		sort.SliceStable(embResp.Data, func(i, j int) bool { return embResp.Data[i].Index < embResp.Data[j].Index })
+		vectors = make([][]float32, 0, len(embResp.Data))
+		for _, d := range embResp.Data {
+			vectors = append(vectors, d.Embedding)
+		}
+	} else {
+		var embResp EmbeddingResponse
+		if err := json.Unmarshal(body, &embResp); err != nil {
+			return nil, fmt.Errorf("unmarshalling response: %w", err)
+		}
+		vectors = embResp.Embeddings
+	}
+
+	if len(vectors) != want {
+		return nil, fmt.Errorf("embeddings count mismatch: requested=%d got=%d", want, len(vectors))
+	}
+	if c.expectedDimension > 0 {
+		for _, v := range vectors {
+			if len(v) != c.expectedDimension {
+				return nil, fmt.Errorf("embedding has %d dimensions, expected %d; a different model needs a matching dimension or a schema change and a reindex", len(v), c.expectedDimension)
+			}
+		}
+	}
+	return vectors, nil
+}
+
 // EmbedSingle generates an embedding for a single text.
 func (c *Client) EmbedSingle(ctx context.Context, text string) ([]float32, error) {
 	results, err := c.Embed(ctx, []string{text})
@@ -165,22 +334,34 @@ func CosineSimilarity(a, b []float32) float64 {
 	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
-func isRetryableStatus(status int) bool {
-	switch status {
-	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
-		return true
-	default:
-		return false
+// Ping embeds a single short text without retries, for health checks.
+func (c *Client) Ping(ctx context.Context) error {
+	once := *c
+	once.maxRetries = 1
+	_, err := once.embedRequestWithRetry(ctx, []string{"ping"})
+	return err
+}
+
+// VectorNorm returns the Euclidean norm of v.
+func VectorNorm(v []float32) float64 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
 	}
+	return math.Sqrt(sum)
 }
 
-func sleepWithContext(ctx context.Context, d time.Duration) error {
-	timer := time.NewTimer(d)
-	defer timer.Stop()
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	case <-timer.C:
-		return nil
+// CosineSimilarityPrenorm is CosineSimilarity with both norms supplied by the
+// caller (see VectorNorm), for hot loops that compare one vector against many
+// fixed ones.
+func CosineSimilarityPrenorm(a []float32, normA float64, b []float32, normB float64) float64 {
+	if len(a) != len(b) || len(a) == 0 || normA == 0 || normB == 0 {
+		return 0
+	}
+
+	var dotProduct float64
+	for i := range a {
+		dotProduct += float64(a[i]) * float64(b[i])
 	}
+	return dotProduct / (normA * normB)
 }
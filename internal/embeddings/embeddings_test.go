@@ -0,0 +1,208 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRecordingServer(t *testing.T) (*httptest.Server, func() []int) {
+	t.Helper()
+	var mu sync.Mutex
+	var sizes []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		sizes = append(sizes, len(req.Texts))
+		mu.Unlock()
+
+		resp := EmbeddingResponse{}
+		for range req.Texts {
+			resp.Embeddings = append(resp.Embeddings, []float32{0.5})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), sizes...)
+	}
+}
+
+func texts(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = "text"
+	}
+	return out
+}
+
+func TestEmbedSplitsAboveThreshold(t *testing.T) {
+	srv, sizes := newRecordingServer(t)
+	client := NewClientWithOptions(srv.URL, Options{BatchSize: 4, BatchThreshold: 10})
+
+	embs, err := client.Embed(context.Background(), texts(10))
+	require.NoError(t, err)
+	assert.Len(t, embs, 10)
+	assert.Equal(t, []int{10}, sizes(), "inputs at the threshold go out in one request")
+
+	embs, err = client.Embed(context.Background(), texts(11))
+	require.NoError(t, err)
+	assert.Len(t, embs, 11)
+	assert.Equal(t, []int{10, 4, 4, 3}, sizes())
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	srv, sizes := newRecordingServer(t)
+	client := NewClient(srv.URL)
+	assert.Equal(t, DefaultBatchSize, client.batchSize)
+	assert.Equal(t, DefaultBatchThreshold, client.batchThreshold)
+
+	_, err := client.Embed(context.Background(), texts(101))
+	require.NoError(t, err)
+	assert.Equal(t, []int{32, 32, 32, 5}, sizes())
+}
+
+func TestOpenAIProvider(t *testing.T) {
+	var got openAIEmbeddingRequest
+	var path, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, auth = r.URL.Path, r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		// Out of order on purpose: vectors are matched to inputs by index.
+		_, _ = w.Write([]byte(`{"object":"list","data":[
+			{"object":"embedding","index":1,"embedding":[0,1]},
+			{"object":"embedding","index":0,"embedding":[1,0]}
+		],"model":"all-minilm"}`))
+	}))
+	defer srv.Close()
+
+	client := NewClientWithOptions(srv.URL+"/v1/", Options{
+		Provider:          ProviderOpenAI,
+		APIKey:            "sk-test",
+		Model:             "all-minilm",
+		Dimensions:        2,
+		ExpectedDimension: 2,
+	})
+	embs, err := client.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1, 0}, {0, 1}}, embs)
+	assert.Equal(t, "/v1/embeddings", path)
+	assert.Equal(t, "Bearer sk-test", auth)
+	assert.Equal(t, openAIEmbeddingRequest{Model: "all-minilm", Input: []string{"first", "second"}, Dimensions: 2}, got)
+}
+
+func TestEmbedRejectsUnexpectedDimension(t *testing.T) {
+	srv, _ := newRecordingServer(t)
+	client := NewClientWithOptions(srv.URL, Options{ExpectedDimension: 384})
+	_, err := client.Embed(context.Background(), texts(2))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 dimensions, expected 384")
+	assert.True(t, IsPermanent(err))
+}
+
+func TestIsPermanent(t *testing.T) {
+	status := http.StatusBadRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	client := NewClient(srv.URL)
+	client.maxRetries = 1
+
+	_, err := client.Embed(context.Background(), texts(1))
+	require.Error(t, err)
+	assert.True(t, IsPermanent(err), "a 4xx will not succeed on retry")
+
+	status = http.StatusServiceUnavailable
+	_, err = client.Embed(context.Background(), texts(1))
+	require.Error(t, err)
+	assert.False(t, IsPermanent(err))
+}
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, Options{}.Validate())
+	assert.NoError(t, Options{Provider: "OpenAI", Model: "text-embedding-3-small", Dimensions: 384, ExpectedDimension: 384}.Validate())
+
+	for name, opts := range map[string]Options{
+		"unknown provider":     {Provider: "cohere"},
+		"openai without model": {Provider: ProviderOpenAI},
+		"negative dimensions":  {Provider: ProviderOpenAI, Model: "m", Dimensions: -1},
+		"dimension mismatch":   {Provider: ProviderOpenAI, Model: "m", Dimensions: 1536, ExpectedDimension: 384},
+	} {
+		assert.Error(t, opts.Validate(), name)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	// cos(a, b) = 32 / (sqrt(14) * sqrt(77)) ≈ 0.974632
+	assert.InDelta(t, 0.9746318461970762, CosineSimilarity([]float32{1, 2, 3}, []float32{4, 5, 6}), 1e-9)
+	assert.InDelta(t, 1.0, CosineSimilarity([]float32{3, 4}, []float32{6, 8}), 1e-12)
+	assert.InDelta(t, -1.0, CosineSimilarity([]float32{1, 0}, []float32{-1, 0}), 1e-12)
+	assert.Zero(t, CosineSimilarity([]float32{1, 0}, []float32{0, 1}))
+
+	// Degenerate inputs score zero rather than NaN.
+	assert.Zero(t, CosineSimilarity(nil, nil))
+	assert.Zero(t, CosineSimilarity([]float32{1, 2}, []float32{1}))
+	assert.Zero(t, CosineSimilarity([]float32{0, 0}, []float32{1, 1}))
+}
+
+func TestCosineSimilarityPrenormMatches(t *testing.T) {
+	a := []float32{1, 2, 3}
+	b := []float32{4, 5, 6}
+	assert.InDelta(t, CosineSimilarity(a, b), CosineSimilarityPrenorm(a, VectorNorm(a), b, VectorNorm(b)), 1e-12)
+	assert.Zero(t, CosineSimilarityPrenorm(a, 0, b, VectorNorm(b)))
+	assert.Zero(t, CosineSimilarityPrenorm(a, 1, []float32{1}, 1))
+}
+
+// benchmarkSections mirrors assignSection: one 384-dim article embedding
+// (all-MiniLM-L6-v2) scored against many section seeds.
+func benchmarkSections(n int) ([]float32, [][]float32) {
+	rng := rand.New(rand.NewSource(1))
+	vec := func() []float32 {
+		v := make([]float32, 384)
+		for i := range v {
+			v[i] = rng.Float32()*2 - 1
+		}
+		return v
+	}
+	seeds := make([][]float32, n)
+	for i := range seeds {
+		seeds[i] = vec()
+	}
+	return vec(), seeds
+}
+
+func BenchmarkAssignCosine(b *testing.B) {
+	article, seeds := benchmarkSections(50)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, seed := range seeds {
+			_ = CosineSimilarity(article, seed)
+		}
+	}
+}
+
+func BenchmarkAssignCosinePrenorm(b *testing.B) {
+	article, seeds := benchmarkSections(50)
+	norms := make([]float64, len(seeds))
+	for i, seed := range seeds {
+		norms[i] = VectorNorm(seed)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		articleNorm := VectorNorm(article)
+		for j, seed := range seeds {
+			_ = CosineSimilarityPrenorm(article, articleNorm, seed, norms[j])
+		}
+	}
+}
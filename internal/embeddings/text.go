@@ -0,0 +1,133 @@
+package embeddings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/textutil"
+)
+
+// TextStrategy selects which article fields make up the embedded text.
+type TextStrategy string
+
+// Embedding text strategies.
+const (
+	// TextTitleOnly embeds the title alone.
+	TextTitleOnly TextStrategy = "title_only"
+	// TextTitleContent embeds the title plus the start of the content.
+	TextTitleContent TextStrategy = "title_content"
+	// TextTitleSummary embeds the title plus the article summary, falling
+	// back to the first paragraph of the content when there is none yet.
+	TextTitleSummary TextStrategy = "title_summary"
+)
+
+// DefaultContentChars is how much content TextTitleContent keeps by default.
+const DefaultContentChars = 500
+
+// ParseTextStrategy returns the strategy named by s, or false when s names
+// none.
+func ParseTextStrategy(s string) (TextStrategy, bool) {
+	switch st := TextStrategy(strings.ToLower(strings.TrimSpace(s))); st {
+	case TextTitleOnly, TextTitleContent, TextTitleSummary:
+		return st, true
+	default:
+		return "", false
+	}
+}
+
+// TextOptions configures how article text is composed before embedding.
+// The processor and the reindex backfill must build text with the same
+// options so their vectors stay comparable.
+type TextOptions struct {
+	// Strategy applies to source types without an entry in SourceStrategies;
+	// empty means TextTitleContent.
+	Strategy         TextStrategy
+	SourceStrategies map[string]TextStrategy
+	// TitleWeight repeats the title this many times to pull the vector
+	// towards it; values below 1 count as 1.
+	TitleWeight int
+	// ContentChars caps the content or summary in runes; 0 means
+	// DefaultContentChars.
+	ContentChars int
+}
+
+// NewTextOptions builds TextOptions from configuration strings, rejecting
+// unknown strategy names so a typo fails at startup rather than silently
+// changing every vector.
+func NewTextOptions(strategy string, sourceStrategies map[string]string, titleWeight, contentChars int) (TextOptions, error) {
+	opts := TextOptions{TitleWeight: titleWeight, ContentChars: contentChars}
+	if strings.TrimSpace(strategy) != "" {
+		st, ok := ParseTextStrategy(strategy)
+		if !ok {
+			return TextOptions{}, fmt.Errorf("unknown embedding text strategy %q", strategy)
+		}
+		opts.Strategy = st
+	}
+	for sourceType, name := range sourceStrategies {
+		st, ok := ParseTextStrategy(name)
+		if !ok {
+			return TextOptions{}, fmt.Errorf("unknown embedding text strategy %q for source type %s", name, sourceType)
+		}
+		if opts.SourceStrategies == nil {
+			opts.SourceStrategies = make(map[string]TextStrategy)
+		}
+		opts.SourceStrategies[strings.ToLower(sourceType)] = st
+	}
+	return opts, nil
+}
+
+// StrategyFor returns the strategy used for articles of sourceType.
+func (o TextOptions) StrategyFor(sourceType string) TextStrategy {
+	if st, ok := o.SourceStrategies[strings.ToLower(sourceType)]; ok {
+		return st
+	}
+	if o.Strategy == "" {
+		return TextTitleContent
+	}
+	return o.Strategy
+}
+
+// BuildText returns the text to embed for article, or "" when it has no
+// usable title or body.
+func (o TextOptions) BuildText(article *models.Article) string {
+	title := strings.TrimSpace(article.Title)
+	if title != "" && o.TitleWeight > 1 {
+		title = strings.TrimSpace(strings.Repeat(title+"\n", o.TitleWeight))
+	}
+
+	var body string
+	switch o.StrategyFor(article.SourceType) {
+	case TextTitleOnly:
+	case TextTitleSummary:
+		body = derefTrimmed(article.Summary)
+		if body == "" {
+			body, _, _ = strings.Cut(derefTrimmed(article.Content), "\n\n")
+			body = strings.TrimSpace(body)
+		}
+	default:
+		body = derefTrimmed(article.Content)
+	}
+
+	maxChars := o.ContentChars
+	if maxChars <= 0 {
+		maxChars = DefaultContentChars
+	}
+	body = textutil.TruncateRunes(body, maxChars)
+
+	switch {
+	case body == "":
+		return title
+	case title == "":
+		return body
+	default:
+		return title + "\n\n" + body
+	}
+}
+
+func derefTrimmed(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(*s)
+}
@@ -0,0 +1,75 @@
+package embeddings
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/models"
+)
+
+func TestBuildTextRuneSafe(t *testing.T) {
+	content := strings.Repeat("é", 300) + strings.Repeat("🔥", 300)
+	article := &models.Article{Title: "Título", Content: &content}
+
+	text := TextOptions{}.BuildText(article)
+	assert.True(t, utf8.ValidString(text))
+	body := strings.TrimPrefix(text, "Título\n\n")
+	assert.Equal(t, 500, utf8.RuneCountInString(body))
+	assert.True(t, strings.HasSuffix(body, "🔥"))
+}
+
+func TestBuildTextStrategies(t *testing.T) {
+	content := "First paragraph of the changelog.\n\nSecond paragraph with details."
+	summary := "  LLM summary.  "
+	article := &models.Article{SourceType: "github", Title: " v1.2.0 ", Content: &content, Summary: &summary}
+
+	tests := []struct {
+		name string
+		opts TextOptions
+		want string
+	}{
+		{"default is title_content", TextOptions{}, "v1.2.0\n\n" + content},
+		{"title_only", TextOptions{Strategy: TextTitleOnly}, "v1.2.0"},
+		{"title_summary", TextOptions{Strategy: TextTitleSummary}, "v1.2.0\n\nLLM summary."},
+		{"content chars", TextOptions{ContentChars: 5}, "v1.2.0\n\nFirst"},
+		{"title weight", TextOptions{Strategy: TextTitleOnly, TitleWeight: 3}, "v1.2.0\nv1.2.0\nv1.2.0"},
+		{
+			"source override",
+			TextOptions{Strategy: TextTitleContent, SourceStrategies: map[string]TextStrategy{"github": TextTitleOnly}},
+			"v1.2.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.opts.BuildText(article))
+		})
+	}
+
+	noSummary := &models.Article{Title: "v1.2.0", Content: &content}
+	assert.Equal(t, "v1.2.0\n\nFirst paragraph of the changelog.", TextOptions{Strategy: TextTitleSummary}.BuildText(noSummary))
+
+	untitled := &models.Article{Title: "  ", Content: &content}
+	assert.Equal(t, content, TextOptions{}.BuildText(untitled))
+	assert.Equal(t, "", TextOptions{Strategy: TextTitleOnly}.BuildText(untitled))
+}
+
+func TestNewTextOptions(t *testing.T) {
+	opts, err := NewTextOptions("Title_Summary", map[string]string{"GitHub": "title_only"}, 2, 300)
+	require.NoError(t, err)
+	assert.Equal(t, TextTitleSummary, opts.StrategyFor("rss"))
+	assert.Equal(t, TextTitleOnly, opts.StrategyFor("github"))
+	assert.Equal(t, 2, opts.TitleWeight)
+	assert.Equal(t, 300, opts.ContentChars)
+
+	opts, err = NewTextOptions("", nil, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, TextTitleContent, opts.StrategyFor("hn"))
+
+	_, err = NewTextOptions("title_body", nil, 1, 500)
+	assert.Error(t, err)
+	_, err = NewTextOptions("title_content", map[string]string{"reddit": "everything"}, 1, 500)
+	assert.Error(t, err)
+}
@@ -0,0 +1,168 @@
+// Package extract turns a fetched article page into plain text through an
+// ordered chain of strategies, so sources whose pages defeat readability
+// (SPAs, paywalls) still end up with some text.
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-shiori/go-readability"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Extraction methods, recorded in article metadata as extraction_method.
+const (
+	// MethodReadability runs go-readability over the fetched page.
+	MethodReadability = "readability"
+	// MethodFeed uses the text the source itself provided: the feed item's
+	// content or description, the HN post text or the Reddit selftext.
+	MethodFeed = "feed"
+	// MethodMeta reads the page's Open Graph, Twitter or meta description.
+	MethodMeta = "meta"
+)
+
+// DefaultChain is the chain workers used before it became configurable.
+var DefaultChain = Chain{MethodReadability, MethodFeed}
+
+// Chain is an ordered list of extraction methods; the first one that yields
+// text wins.
+type Chain []string
+
+// ParseChain reads a comma-separated chain such as "readability,feed,meta".
+// Blank input returns DefaultChain.
+func ParseChain(raw string) (Chain, error) {
+	var methods []string
+	for _, m := range strings.Split(raw, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			methods = append(methods, m)
+		}
+	}
+	return NewChain(methods)
+}
+
+// NewChain validates methods, lowercasing them and dropping repeats. An
+// empty list returns DefaultChain.
+func NewChain(methods []string) (Chain, error) {
+	if len(methods) == 0 {
+		return DefaultChain, nil
+	}
+	chain := make(Chain, 0, len(methods))
+	seen := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		m = strings.ToLower(strings.TrimSpace(m))
+		switch m {
+		case MethodReadability, MethodFeed, MethodMeta:
+		default:
+			return nil, fmt.Errorf("unknown extraction method %q (want %s, %s or %s)", m, MethodReadability, MethodFeed, MethodMeta)
+		}
+		if !seen[m] {
+			seen[m] = true
+			chain = append(chain, m)
+		}
+	}
+	return chain, nil
+}
+
+// NeedsPage reports whether any method in the chain reads the article page,
+// so workers can skip the fetch for feed-only chains.
+func (c Chain) NeedsPage() bool {
+	for _, m := range c {
+		if m != MethodFeed {
+			return true
+		}
+	}
+	return false
+}
+
+// Run walks the chain and returns the first non-empty text with the method
+// that produced it; both are empty when every method comes up empty. page is
+// the fetched HTML, nil when it was not fetched or the fetch failed, and
+// pageURL resolves its relative links. feedText is the source-provided text.
+func (c Chain) Run(page []byte, pageURL string, feedText string) (text string, method string) {
+	for _, m := range c {
+		switch m {
+		case MethodReadability:
+			if page != nil {
+				text = Readability(page, pageURL)
+			}
+		case MethodFeed:
+			text = strings.TrimSpace(feedText)
+		case MethodMeta:
+			if page != nil {
+				text = MetaDescription(page)
+			}
+		}
+		if text != "" {
+			return text, m
+		}
+	}
+	return "", ""
+}
+
+// Readability returns the main text of an HTML page with whitespace
+// collapsed, or "" when go-readability cannot find any.
+func Readability(page []byte, pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	article, err := readability.FromReader(bytes.NewReader(page), u)
+	if err != nil {
+		return ""
+	}
+	return collapseSpace(article.TextContent)
+}
+
+// metaPriority ranks the description tags MetaDescription reads.
+var metaPriority = []string{"og:description", "twitter:description", "description"}
+
+// MetaDescription returns the page summary publishers put in <meta> tags,
+// preferring og:description, then twitter:description, then description.
+func MetaDescription(page []byte) string {
+	found := make(map[string]string, len(metaPriority))
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		tok := z.Token()
+		if tok.DataAtom == atom.Body {
+			break
+		}
+		if tok.DataAtom != atom.Meta {
+			continue
+		}
+		var key, content string
+		for _, attr := range tok.Attr {
+			switch strings.ToLower(attr.Key) {
+			case "property", "name":
+				if key == "" {
+					key = strings.ToLower(strings.TrimSpace(attr.Val))
+				}
+			case "content":
+				content = collapseSpace(attr.Val)
+			}
+		}
+		if _, ok := found[key]; !ok && content != "" {
+			found[key] = content
+		}
+	}
+	for _, key := range metaPriority {
+		if text := found[key]; text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
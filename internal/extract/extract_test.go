@@ -0,0 +1,102 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<!doctype html>
+<html>
+<head>
+  <title>Release notes</title>
+  <meta name="description" content="Plain description.">
+  <meta property="og:description" content="Open   Graph &amp; friends.">
+</head>
+<body>
+  <nav><a href="/">Home</a></nav>
+  <article>
+    <h1>Release notes</h1>
+    <p>` + "The new release ships a rewritten scheduler that cuts tail latency in half. " + `</p>
+    <p>` + "It also drops support for the legacy configuration format after two years of warnings. " + `</p>
+    <p>` + "Upgrading requires regenerating the state files with the bundled migration tool. " + `</p>
+  </article>
+</body>
+</html>`
+
+// spaPage is what a client-rendered app serves before its scripts run:
+// metadata in the head and an empty mount point.
+const spaPage = `<!doctype html>
+<html>
+<head>
+  <title>App</title>
+  <meta name="twitter:description" content="Twitter summary of the story.">
+  <meta name="description" content="Plain description.">
+</head>
+<body><div id="root"></div><script src="/app.js"></script></body>
+</html>`
+
+func TestParseChain(t *testing.T) {
+	chain, err := ParseChain("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultChain, chain)
+
+	chain, err = ParseChain(" Readability, feed ,meta,feed")
+	require.NoError(t, err)
+	assert.Equal(t, Chain{MethodReadability, MethodFeed, MethodMeta}, chain)
+	assert.True(t, chain.NeedsPage())
+
+	chain, err = NewChain([]string{"feed"})
+	require.NoError(t, err)
+	assert.False(t, chain.NeedsPage())
+
+	_, err = ParseChain("readability,headless")
+	assert.Error(t, err)
+}
+
+func TestRunUsesReadabilityFirst(t *testing.T) {
+	text, method := DefaultChain.Run([]byte(articlePage), "https://example.com/notes", "Feed summary.")
+	assert.Equal(t, MethodReadability, method)
+	assert.Contains(t, text, "rewritten scheduler")
+	assert.NotContains(t, text, "\n")
+}
+
+func TestRunFallsBackToFeedText(t *testing.T) {
+	chain := Chain{MethodReadability, MethodFeed, MethodMeta}
+
+	text, method := chain.Run([]byte(spaPage), "https://example.com/app", "  Feed summary.  ")
+	assert.Equal(t, MethodFeed, method)
+	assert.Equal(t, "Feed summary.", text)
+
+	// A failed fetch leaves no page at all.
+	text, method = chain.Run(nil, "https://example.com/app", "Feed summary.")
+	assert.Equal(t, MethodFeed, method)
+	assert.Equal(t, "Feed summary.", text)
+}
+
+func TestRunFallsBackToMetaDescription(t *testing.T) {
+	chain := Chain{MethodReadability, MethodFeed, MethodMeta}
+	text, method := chain.Run([]byte(spaPage), "https://example.com/app", "")
+	assert.Equal(t, MethodMeta, method)
+	assert.Equal(t, "Twitter summary of the story.", text)
+
+	// Without meta in the chain an empty SPA yields nothing.
+	text, method = DefaultChain.Run([]byte(spaPage), "https://example.com/app", "")
+	assert.Empty(t, text)
+	assert.Empty(t, method)
+	text, method = chain.Run(nil, "https://example.com/app", "")
+	assert.Empty(t, text)
+	assert.Empty(t, method)
+}
+
+func TestMetaDescriptionPriority(t *testing.T) {
+	assert.Equal(t, "Open Graph & friends.", MetaDescription([]byte(articlePage)))
+	assert.Equal(t, "Twitter summary of the story.", MetaDescription([]byte(spaPage)))
+	assert.Equal(t, "Plain.", MetaDescription([]byte(`<meta name="Description" content=" Plain. ">`)))
+	// Tags in the body are ignored.
+	body := `<html><head></head><body>` + strings.Repeat("<p>x</p>", 3) + `<meta name="description" content="late"></body></html>`
+	assert.Empty(t, MetaDescription([]byte(body)))
+	assert.Empty(t, MetaDescription([]byte("not html at all")))
+}
@@ -0,0 +1,104 @@
+package extract
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// PageFetcher downloads article pages for the extraction chain, skipping
+// responses that are not worth parsing.
+type PageFetcher struct {
+	Client *http.Client
+	// AllowedTypes are the accepted media types; empty accepts any. A
+	// response without a Content-Type is always let through.
+	AllowedTypes []string
+	// MaxBytes caps the body read; 0 means no cap.
+	MaxBytes int64
+}
+
+// SkipError reports a response that is not worth handing to the extraction
+// chain (non-HTML or oversized); callers fall back to the source's own text.
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return "skipping readable content: " + e.Reason
+}
+
+// Fetch downloads pageURL, returning a SkipError when its type or size is
+// outside the fetcher's limits.
+func (f *PageFetcher) Fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := f.readableBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(body)
+}
+
+// readableBody checks the response Content-Type and caps the body at
+// MaxBytes before it is parsed.
+func (f *PageFetcher) readableBody(resp *http.Response) (io.Reader, error) {
+	if ct := strings.TrimSpace(resp.Header.Get("Content-Type")); ct != "" && len(f.AllowedTypes) > 0 {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || !containsFold(f.AllowedTypes, mediaType) {
+			return nil, &SkipError{Reason: fmt.Sprintf("content type %q", ct)}
+		}
+	}
+	if f.MaxBytes <= 0 {
+		return resp.Body, nil
+	}
+	if resp.ContentLength > f.MaxBytes {
+		return nil, &SkipError{Reason: fmt.Sprintf("content length %d exceeds %d bytes", resp.ContentLength, f.MaxBytes)}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > f.MaxBytes {
+		return nil, &SkipError{Reason: fmt.Sprintf("body exceeds %d bytes", f.MaxBytes)}
+	}
+	return bytes.NewReader(body), nil
+}
+
+// FetchLogLevel keeps expected skips out of the warning log.
+func FetchLogLevel(err error) log.Level {
+	var skipErr *SkipError
+	if errors.As(err, &skipErr) {
+		return log.DebugLevel
+	}
+	return log.WarnLevel
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), target) {
+			return true
+		}
+	}
+	return false
+}
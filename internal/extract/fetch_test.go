@@ -0,0 +1,55 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageFetcherGuards(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG"))
+		case "/huge":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(strings.Repeat("a", 10000)))
+		case "/missing":
+			http.NotFound(w, r)
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(articlePage))
+		}
+	}))
+	defer srv.Close()
+
+	f := &PageFetcher{Client: srv.Client(), AllowedTypes: []string{"text/html"}, MaxBytes: 8192}
+	ctx := context.Background()
+
+	body, err := f.Fetch(ctx, srv.URL+"/article")
+	require.NoError(t, err)
+	assert.Equal(t, articlePage, string(body))
+
+	var skipErr *SkipError
+	_, err = f.Fetch(ctx, srv.URL+"/image")
+	require.True(t, errors.As(err, &skipErr), "got %v", err)
+	assert.Contains(t, skipErr.Reason, "image/png")
+	assert.Equal(t, log.DebugLevel, FetchLogLevel(err))
+
+	_, err = f.Fetch(ctx, srv.URL+"/huge")
+	require.True(t, errors.As(err, &skipErr), "got %v", err)
+	assert.Contains(t, skipErr.Reason, "exceeds 8192")
+
+	_, err = f.Fetch(ctx, srv.URL+"/missing")
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &skipErr))
+	assert.Equal(t, log.WarnLevel, FetchLogLevel(err))
+}
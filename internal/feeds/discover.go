@@ -0,0 +1,100 @@
+// Package feeds fetches syndication feeds and finds the ones a web page
+// advertises, so a homepage URL pasted as a source resolves to its real feed.
+package feeds
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// feedTypes are the <link type> values recognised as feeds, in the order
+// they are preferred when a page advertises several.
+var feedTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/feed+json",
+}
+
+// DiscoverLinks returns the absolute URLs of the feeds advertised by an HTML
+// page through <link rel="alternate"> tags, RSS first, then Atom, then JSON
+// Feed, each group in document order. pageURL resolves relative hrefs.
+func DiscoverLinks(body io.Reader, pageURL string) ([]string, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := html.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string][]string, len(feedTypes))
+	seen := make(map[string]struct{})
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "link" {
+			if feedType, href, ok := alternateFeed(n); ok {
+				if ref, err := url.Parse(href); err == nil {
+					abs := base.ResolveReference(ref).String()
+					if _, dup := seen[abs]; !dup {
+						seen[abs] = struct{}{}
+						byType[feedType] = append(byType[feedType], abs)
+					}
+				}
+			}
+		}
+		// Feeds are only advertised in <head>; skip the body.
+		if n.Type == html.ElementNode && n.Data == "body" {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var links []string
+	for _, t := range feedTypes {
+		links = append(links, byType[t]...)
+	}
+	return links, nil
+}
+
+// alternateFeed reports whether a <link> node advertises a feed.
+func alternateFeed(n *html.Node) (feedType, href string, ok bool) {
+	var rel string
+	for _, attr := range n.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "rel":
+			rel = strings.ToLower(attr.Val)
+		case "type":
+			feedType = strings.ToLower(strings.TrimSpace(attr.Val))
+		case "href":
+			href = strings.TrimSpace(attr.Val)
+		}
+	}
+	if href == "" || !hasToken(rel, "alternate") {
+		return "", "", false
+	}
+	if i := strings.IndexByte(feedType, ';'); i >= 0 {
+		feedType = strings.TrimSpace(feedType[:i])
+	}
+	for _, t := range feedTypes {
+		if feedType == t {
+			return feedType, href, true
+		}
+	}
+	return "", "", false
+}
+
+func hasToken(list, token string) bool {
+	for _, f := range strings.Fields(list) {
+		if f == token {
+			return true
+		}
+	}
+	return false
+}
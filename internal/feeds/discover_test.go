@@ -0,0 +1,44 @@
+package feeds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const homepage = `<!doctype html>
+<html>
+<head>
+  <title>Example blog</title>
+  <link rel="stylesheet" href="/style.css">
+  <link rel="alternate" type="application/feed+json" href="/feed.json" title="JSON Feed">
+  <link rel="alternate" type="application/atom+xml" href="https://cdn.example.com/atom.xml">
+  <link rel="alternate" hreflang="es" href="/es/">
+  <link rel="ALTERNATE" type="application/rss+xml; charset=utf-8" href="feed.xml">
+  <link rel="alternate" type="application/rss+xml" href="/blog/feed.xml">
+</head>
+<body>
+  <link rel="alternate" type="application/rss+xml" href="/in-body.xml">
+</body>
+</html>`
+
+func TestDiscoverLinks(t *testing.T) {
+	links, err := DiscoverLinks(strings.NewReader(homepage), "https://example.com/blog/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://example.com/blog/feed.xml",
+		"https://cdn.example.com/atom.xml",
+		"https://example.com/feed.json",
+	}, links)
+}
+
+func TestDiscoverLinksWithoutFeeds(t *testing.T) {
+	links, err := DiscoverLinks(strings.NewReader(`<html><head><title>x</title></head></html>`), "https://example.com/")
+	require.NoError(t, err)
+	assert.Empty(t, links)
+
+	_, err = DiscoverLinks(strings.NewReader(homepage), "://bad")
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package feeds
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// DefaultMaxBytes caps a feed body when Fetcher.MaxBytes is unset.
+const DefaultMaxBytes = 10 << 20
+
+// Validators are the conditional GET validators of a previous response.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// Fetcher downloads and parses RSS, Atom and JSON Feed documents, falling
+// back to the feed a web page advertises.
+type Fetcher struct {
+	Client *http.Client
+	// UserAgent is sent when set; leave it empty for clients that add their own.
+	UserAgent string
+	// MaxBytes caps the body read; 0 means DefaultMaxBytes.
+	MaxBytes int64
+}
+
+// Result is the outcome of a Fetch.
+type Result struct {
+	// Feed is nil when the server answered 304 Not Modified.
+	Feed *gofeed.Feed
+	// URL is where the feed was read: the requested URL, or the first feed
+	// the requested page advertises.
+	URL string
+	// Validators come from the response for URL, so they only apply to a
+	// later request for that same URL.
+	Validators Validators
+}
+
+// NotAFeedError reports a URL that served a web page advertising feeds
+// instead of a feed.
+type NotAFeedError struct {
+	Err        error
+	Alternates []string
+}
+
+func (e *NotAFeedError) Error() string {
+	return fmt.Sprintf("not a feed (page advertises %s): %v", e.Alternates[0], e.Err)
+}
+
+func (e *NotAFeedError) Unwrap() error { return e.Err }
+
+// StatusError reports a feed request answered with a status other than 2xx
+// or 304.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+}
+
+// Fetch downloads and parses feedURL, sending prev as conditional GET
+// validators. When feedURL is a web page advertising feeds, the first one is
+// fetched instead, unconditionally, since prev belongs to feedURL. Result.URL
+// is set even when an error is returned.
+func (f *Fetcher) Fetch(ctx context.Context, feedURL string, prev *Validators) (Result, error) {
+	feed, validators, err := f.fetch(ctx, feedURL, prev)
+	var notFeed *NotAFeedError
+	if !errors.As(err, &notFeed) {
+		return Result{Feed: feed, URL: feedURL, Validators: validators}, err
+	}
+
+	feedURL = notFeed.Alternates[0]
+	feed, validators, err = f.fetch(ctx, feedURL, nil)
+	return Result{Feed: feed, URL: feedURL, Validators: validators}, err
+}
+
+// fetch requests feedURL once. A body that does not parse as a feed is
+// returned as a NotAFeedError when it is a page advertising feeds.
+func (f *Fetcher) fetch(ctx context.Context, feedURL string, prev *Validators) (*gofeed.Feed, Validators, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, Validators{}, fmt.Errorf("building request: %w", err)
+	}
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+	if prev != nil {
+		if prev.ETag != "" {
+			req.Header.Set("If-None-Match", prev.ETag)
+		}
+		if prev.LastModified != "" {
+			req.Header.Set("If-Modified-Since", prev.LastModified)
+		}
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, Validators{}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, Validators{}, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	maxBytes := f.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, Validators{}, fmt.Errorf("reading %s: %w", feedURL, err)
+	}
+	// A cut body would fail to parse and be blamed on the URL not being a feed.
+	if int64(len(body)) > maxBytes {
+		return nil, Validators{}, fmt.Errorf("feed exceeds %d bytes", maxBytes)
+	}
+
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		alternates, discoverErr := DiscoverLinks(bytes.NewReader(body), resp.Request.URL.String())
+		if discoverErr != nil || len(alternates) == 0 {
+			return nil, Validators{}, fmt.Errorf("%w; the page advertises no feed links either", err)
+		}
+		return nil, Validators{}, &NotAFeedError{Err: err, Alternates: alternates}
+	}
+
+	return feed, Validators{
+		ETag:         strings.TrimSpace(resp.Header.Get("ETag")),
+		LastModified: strings.TrimSpace(resp.Header.Get("Last-Modified")),
+	}, nil
+}
@@ -0,0 +1,105 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testFeed = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Test</title>
+<item><title>Hello</title><link>https://example.com/hello</link></item>
+</channel></rss>`
+
+func TestFetchConditionalGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte(testFeed))
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{Client: srv.Client()}
+
+	res, err := f.Fetch(context.Background(), srv.URL, nil)
+	require.NoError(t, err)
+	require.NotNil(t, res.Feed)
+	assert.Len(t, res.Feed.Items, 1)
+	assert.Equal(t, srv.URL, res.URL)
+	assert.Equal(t, Validators{ETag: `"v1"`, LastModified: "Mon, 02 Jan 2006 15:04:05 GMT"}, res.Validators)
+
+	res, err = f.Fetch(context.Background(), srv.URL, &Validators{ETag: `"v1"`})
+	require.NoError(t, err)
+	assert.Nil(t, res.Feed)
+}
+
+func TestFetchDiscoversAdvertisedFeed(t *testing.T) {
+	var feedConditional bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html><head>
+<link rel="alternate" type="application/rss+xml" href="/feed.xml" title="RSS">
+</head><body>Welcome</body></html>`))
+		case "/feed.xml":
+			if r.Header.Get("If-None-Match") != "" {
+				feedConditional = true
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"feed"`)
+			_, _ = w.Write([]byte(testFeed))
+		case "/about":
+			_, _ = w.Write([]byte(`<html><head><title>About</title></head></html>`))
+		}
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{Client: srv.Client()}
+
+	_, _, err := f.fetch(context.Background(), srv.URL+"/", nil)
+	var notFeed *NotAFeedError
+	require.ErrorAs(t, err, &notFeed)
+	assert.Equal(t, []string{srv.URL + "/feed.xml"}, notFeed.Alternates)
+
+	// Validators stored for the page must not be replayed against the feed.
+	res, err := f.Fetch(context.Background(), srv.URL+"/", &Validators{ETag: `"feed"`})
+	require.NoError(t, err)
+	require.NotNil(t, res.Feed)
+	assert.False(t, feedConditional)
+	assert.Len(t, res.Feed.Items, 1)
+	assert.Equal(t, srv.URL+"/feed.xml", res.URL)
+	assert.Equal(t, `"feed"`, res.Validators.ETag)
+
+	res, err = f.Fetch(context.Background(), srv.URL+"/about", nil)
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &notFeed))
+	assert.Equal(t, srv.URL+"/about", res.URL)
+}
+
+func TestFetchRejectsOversizedFeed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(testFeed))
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{Client: srv.Client(), MaxBytes: int64(len(testFeed)) - 1}
+	_, err := f.Fetch(context.Background(), srv.URL, nil)
+	assert.EqualError(t, err, fmt.Sprintf("feed exceeds %d bytes", len(testFeed)-1))
+
+	f.MaxBytes = int64(len(testFeed))
+	res, err := f.Fetch(context.Background(), srv.URL, nil)
+	require.NoError(t, err)
+	assert.Len(t, res.Feed.Items, 1)
+}
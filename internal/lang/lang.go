@@ -0,0 +1,65 @@
+// Package lang guesses the language an article is written in, so lists can
+// be filtered by language when sources mix English, Spanish and others.
+package lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/abadojack/whatlanggo"
+)
+
+// MetadataKey is the article metadata key holding the detected language.
+const MetadataKey = "lang"
+
+const (
+	// minDetectRunes is the shortest text Detect guesses at; trigram
+	// detection on a headline alone is mostly noise.
+	minDetectRunes = 40
+	// maxDetectBytes caps how much of a long article is examined.
+	maxDetectBytes = 4096
+)
+
+// Detect returns the ISO 639-1 code (ISO 639-3 for languages without one) of
+// the language text is written in, or "" when the text is too short or the
+// detector is not confident.
+func Detect(text string) string {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) < minDetectRunes {
+		return ""
+	}
+	if len(text) > maxDetectBytes {
+		cut := maxDetectBytes
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+
+	info := whatlanggo.Detect(text)
+	if info.Lang < 0 || !info.IsReliable() {
+		return ""
+	}
+	if code := info.Lang.Iso6391(); code != "" {
+		return code
+	}
+	return info.Lang.Iso6393()
+}
+
+// WithLang returns metadata with lang set to code, keeping every other key.
+func WithLang(metadata json.RawMessage, code string) (json.RawMessage, error) {
+	m := map[string]interface{}{}
+	if len(metadata) > 0 && string(metadata) != "null" {
+		if err := json.Unmarshal(metadata, &m); err != nil {
+			return nil, fmt.Errorf("decoding article metadata: %w", err)
+		}
+	}
+	m[MetadataKey] = code
+	out, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("encoding article metadata: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,67 @@
+package lang
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english",
+			text: "Kubernetes 1.32 ships with improved scheduling for batch workloads and a new API for dynamic resource allocation.",
+			want: "en",
+		},
+		{
+			name: "spanish",
+			text: "El Banco Central Europeo mantiene los tipos de interés sin cambios y advierte de que la inflación seguirá alta durante los próximos meses.",
+			want: "es",
+		},
+		{
+			name: "french",
+			text: "Le gouvernement a présenté mercredi un projet de loi visant à renforcer la sécurité des réseaux informatiques des hôpitaux.",
+			want: "fr",
+		},
+		{
+			name: "german",
+			text: "Die Bundesregierung hat am Mittwoch einen Gesetzentwurf vorgelegt, der die Sicherheit der Computernetze in Krankenhäusern verbessern soll.",
+			want: "de",
+		},
+		{name: "too short", text: "Go 1.26 released", want: ""},
+		{name: "empty", text: "   ", want: ""},
+		{name: "no letters", text: strings.Repeat("1234 5678 ", 10), want: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Detect(tc.text))
+		})
+	}
+}
+
+func TestDetectLongTextIsCapped(t *testing.T) {
+	long := strings.Repeat("Este es un artículo muy largo sobre la economía española y sus perspectivas. ", 200)
+	require.Greater(t, len(long), maxDetectBytes)
+	assert.Equal(t, "es", Detect(long))
+}
+
+func TestWithLang(t *testing.T) {
+	out, err := WithLang(json.RawMessage(`{"source_name":"LWN"}`), "en")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"source_name":"LWN","lang":"en"}`, string(out))
+
+	out, err = WithLang(nil, "es")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"lang":"es"}`, string(out))
+
+	_, err = WithLang(json.RawMessage(`not json`), "es")
+	assert.Error(t, err)
+}
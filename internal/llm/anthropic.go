@@ -1,11 +1,9 @@
 package llm
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
@@ -19,6 +17,8 @@ type AnthropicAnalyzer struct {
 	endpoint   string
 	model      string
 	apiKey     string
+	language   string
+	retry      retryPolicy
 }
 
 // Anthropic-specific request/response types.
@@ -59,17 +59,27 @@ func NewAnthropicAnalyzer(endpoint, model, apiKey string) *AnthropicAnalyzer {
 	if model == "" {
 		model = "claude-sonnet-4-20250514"
 	}
+	opts := DefaultOptions()
 	return &AnthropicAnalyzer{
-		httpClient: &http.Client{Timeout: 120 * time.Second},
+		httpClient: &http.Client{Timeout: opts.Timeout},
 		endpoint:   endpoint,
 		model:      model,
 		apiKey:     apiKey,
+		language:   DefaultLanguage,
+		retry:      newRetryPolicy(opts),
 	}
 }
 
+// configure applies opts to the analyzer's timeout and retries.
+func (a *AnthropicAnalyzer) configure(opts Options) {
+	opts = opts.normalized()
+	a.httpClient = &http.Client{Timeout: opts.Timeout}
+	a.retry = newRetryPolicy(opts)
+}
+
 func (a *AnthropicAnalyzer) Provider() string { return "anthropic" }
 
-func (a *AnthropicAnalyzer) complete(ctx context.Context, system, userMessage string, maxTokens int, temperature float64) (string, error) {
+func (a *AnthropicAnalyzer) complete(ctx context.Context, system, userMessage string, maxTokens int, temperature float64) (string, Usage, error) {
 	req := anthropicRequest{
 		Model:     a.model,
 		MaxTokens: maxTokens,
@@ -82,48 +92,41 @@ func (a *AnthropicAnalyzer) complete(ctx context.Context, system, userMessage st
 
 	body, err := json.Marshal(req)
 	if err != nil {
-		return "", fmt.Errorf("marshalling request: %w", err)
+		return "", Usage{}, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	url := a.endpoint + "/v1/messages"
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+	headers := map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": "2023-06-01",
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", a.apiKey)
-	httpReq.Header.Set("anthropic-version", "2023-06-01")
-
 	start := time.Now()
-	resp, err := a.httpClient.Do(httpReq)
+	status, respBody, err := postWithRetry(ctx, a.httpClient, a.endpoint+"/v1/messages", headers, body, a.retry)
 	if err != nil {
-		return "", fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
+		return "", Usage{}, err
 	}
 
 	duration := time.Since(start)
 
-	if resp.StatusCode != http.StatusOK {
+	if status != http.StatusOK {
 		log.WithFields(log.Fields{
-			"status":   resp.StatusCode,
+			"status":   status,
 			"body":     string(respBody[:min(len(respBody), 500)]),
 			"duration": duration,
 		}).Error("Anthropic API error")
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody[:min(len(respBody), 200)]))
+		return "", Usage{}, fmt.Errorf("API returned status %d: %s", status, string(respBody[:min(len(respBody), 200)]))
 	}
 
 	var anthropicResp anthropicResponse
 	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
-		return "", fmt.Errorf("unmarshalling response: %w", err)
+		return "", Usage{}, fmt.Errorf("unmarshalling response: %w", err)
 	}
 
+	// Anthropic reports input and output tokens, which map to prompt and
+	// completion.
+	usage := newUsage(0, 0, 0)
 	if anthropicResp.Usage != nil {
+		usage = newUsage(anthropicResp.Usage.InputTokens, anthropicResp.Usage.OutputTokens, 0)
 		log.WithFields(log.Fields{
 			"input_tokens":  anthropicResp.Usage.InputTokens,
 			"output_tokens": anthropicResp.Usage.OutputTokens,
@@ -132,7 +135,7 @@ func (a *AnthropicAnalyzer) complete(ctx context.Context, system, userMessage st
 	}
 
 	if len(anthropicResp.Content) == 0 {
-		return "", fmt.Errorf("empty response: no content blocks returned")
+		return "", usage, fmt.Errorf("empty response: no content blocks returned")
 	}
 
 	// Concatenate all text blocks
@@ -142,36 +145,42 @@ func (a *AnthropicAnalyzer) complete(ctx context.Context, system, userMessage st
 			result += block.Text
 		}
 	}
-	return result, nil
+	return result, usage, nil
 }
 
-func (a *AnthropicAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]Classification, error) {
+func (a *AnthropicAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]Classification, Usage, error) {
 	prompt := BuildClassifyPrompt(articles)
 
-	content, err := a.complete(ctx, systemPrompt, prompt, 2000, 0.1)
+	content, usage, err := a.complete(ctx, systemPrompt, prompt, 2000, 0.1)
 	if err != nil {
-		return nil, fmt.Errorf("anthropic classify: %w", err)
+		return nil, usage, fmt.Errorf("anthropic classify: %w", err)
 	}
 
-	return parseClassifications(content)
+	classifications, err := parseClassifications(content)
+	return classifications, usage, err
+}
+
+func (a *AnthropicAnalyzer) Summarize(ctx context.Context, article ArticleInput) (string, Usage, error) {
+	s, usage, err := a.SummarizeWithCategories(ctx, article)
+	return s.Summary, usage, err
 }
 
-func (a *AnthropicAnalyzer) Summarize(ctx context.Context, article ArticleInput) (string, error) {
-	prompt := BuildSummarizePrompt(article)
+func (a *AnthropicAnalyzer) SummarizeWithCategories(ctx context.Context, article ArticleInput) (ArticleSummary, Usage, error) {
+	prompt := BuildSummarizePrompt(article, a.language)
 
-	content, err := a.complete(ctx, systemPrompt, prompt, 500, 0.3)
+	content, usage, err := a.complete(ctx, systemPrompt, prompt, 500, 0.3)
 	if err != nil {
-		return "", fmt.Errorf("anthropic summarize: %w", err)
+		return ArticleSummary{}, usage, fmt.Errorf("anthropic summarize: %w", err)
 	}
-	return content, nil
+	return parseSummary(content), usage, nil
 }
 
-func (a *AnthropicAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, error) {
-	prompt := BuildBriefingPrompt(sections)
+func (a *AnthropicAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, Usage, error) {
+	prompt := BuildBriefingPrompt(sections, a.language)
 
-	content, err := a.complete(ctx, systemPrompt, prompt, 4000, 0.5)
+	content, usage, err := a.complete(ctx, systemPrompt, prompt, 4000, 0.5)
 	if err != nil {
-		return "", fmt.Errorf("anthropic briefing: %w", err)
+		return "", usage, fmt.Errorf("anthropic briefing: %w", err)
 	}
-	return content, nil
+	return content, usage, nil
 }
@@ -1,11 +1,9 @@
 package llm
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
@@ -15,20 +13,36 @@ import (
 // baseClient provides shared HTTP and parsing logic for LLM implementations.
 type baseClient struct {
 	httpClient *http.Client
-	endpoint   string
-	model      string
-	apiKey     string
+	// streamClient serves streamed completions; see newStreamClient.
+	streamClient *http.Client
+	endpoint     string
+	model        string
+	apiKey       string
+	language     string
+	retry        retryPolicy
 }
 
 func newBaseClient(endpoint, model, apiKey string) baseClient {
+	opts := DefaultOptions()
 	return baseClient{
-		httpClient: &http.Client{Timeout: 120 * time.Second},
-		endpoint:   endpoint,
-		model:      model,
-		apiKey:     apiKey,
+		httpClient:   &http.Client{Timeout: opts.Timeout},
+		streamClient: newStreamClient(opts.Timeout),
+		endpoint:     endpoint,
+		model:        model,
+		apiKey:       apiKey,
+		language:     DefaultLanguage,
+		retry:        newRetryPolicy(opts),
 	}
 }
 
+// configure applies opts to the client's timeout and retries.
+func (c *baseClient) configure(opts Options) {
+	opts = opts.normalized()
+	c.httpClient = &http.Client{Timeout: opts.Timeout}
+	c.streamClient = newStreamClient(opts.Timeout)
+	c.retry = newRetryPolicy(opts)
+}
+
 // chatCompletion sends an OpenAI-compatible chat completion request.
 func (c *baseClient) chatCompletion(ctx context.Context, path string, headers map[string]string, req ChatRequest) (*ChatResponse, error) {
 	body, err := json.Marshal(req)
@@ -36,38 +50,21 @@ func (c *baseClient) chatCompletion(ctx context.Context, path string, headers ma
 		return nil, fmt.Errorf("marshalling request: %w", err)
 	}
 
-	url := c.endpoint + path
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	for k, v := range headers {
-		httpReq.Header.Set(k, v)
-	}
-
 	start := time.Now()
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	status, respBody, err := postWithRetry(ctx, c.httpClient, c.endpoint+path, headers, body, c.retry)
 	if err != nil {
-		return nil, fmt.Errorf("reading response body: %w", err)
+		return nil, err
 	}
 
 	duration := time.Since(start)
 
-	if resp.StatusCode != http.StatusOK {
+	if status != http.StatusOK {
 		log.WithFields(log.Fields{
-			"status":   resp.StatusCode,
+			"status":   status,
 			"body":     string(respBody[:min(len(respBody), 500)]),
 			"duration": duration,
 		}).Error("LLM API error")
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody[:min(len(respBody), 200)]))
+		return nil, fmt.Errorf("API returned status %d: %s", status, string(respBody[:min(len(respBody), 200)]))
 	}
 
 	var chatResp ChatResponse
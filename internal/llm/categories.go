@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxCategories caps how many tags are kept from one summary.
+const maxCategories = 3
+
+// parseSummary reads the {"summary", "categories"} object the summarize
+// prompt asks for. Models that answer in plain text instead still yield a
+// usable summary, just without categories. A reply that is JSON but cannot
+// be used, such as one cut off at the token limit, yields an empty summary
+// rather than storing the raw JSON as the summary.
+func parseSummary(raw string) ArticleSummary {
+	text := strings.TrimSpace(raw)
+	unfenced := strings.TrimSpace(stripCodeFences(text))
+
+	var parsed ArticleSummary
+	err := json.Unmarshal([]byte(unfenced), &parsed)
+	if err == nil {
+		if summary := strings.TrimSpace(parsed.Summary); summary != "" {
+			return ArticleSummary{Summary: summary, Categories: normalizeCategories(parsed.Categories)}
+		}
+	}
+	if !strings.HasPrefix(unfenced, "{") {
+		return ArticleSummary{Summary: text}
+	}
+
+	fields := log.Fields{"reply_chars": len(text)}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	log.WithFields(fields).Warn("LLM summary reply is unusable JSON, storing an empty summary")
+	return ArticleSummary{}
+}
+
+// normalizeCategories lowercases and deduplicates tags, dropping blanks and
+// keeping at most maxCategories, so the same topic is filterable however the
+// model spelled it.
+func normalizeCategories(tags []string) []string {
+	var out []string
+	seen := map[string]struct{}{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#")))
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+		if len(out) == maxCategories {
+			break
+		}
+	}
+	return out
+}
@@ -15,31 +15,53 @@ const (
 
 // NewAnalyzer creates the appropriate Analyzer implementation based on the provider string.
 // Configuration is read from the provided parameters, typically sourced from env vars.
-func NewAnalyzer(provider, endpoint, model, apiKey string) (Analyzer, error) {
+// language selects the response language for summaries and briefings (e.g. "es", "en").
+func NewAnalyzer(provider, endpoint, model, apiKey, language string) (Analyzer, error) {
+	return NewAnalyzerWithOptions(provider, endpoint, model, apiKey, language, DefaultOptions())
+}
+
+// NewAnalyzerWithOptions is NewAnalyzer with a custom request timeout, retry
+// count and streaming choice.
+func NewAnalyzerWithOptions(provider, endpoint, model, apiKey, language string, opts Options) (Analyzer, error) {
+	language = NormalizeLanguage(language)
+
 	switch provider {
 	case ProviderGLM:
 		log.WithFields(log.Fields{
 			"provider": provider,
 			"endpoint": endpoint,
 			"model":    model,
+			"language": language,
 		}).Info("Initializing GLM analyzer")
-		return NewGLMAnalyzer(endpoint, model, apiKey), nil
+		a := NewGLMAnalyzer(endpoint, model, apiKey)
+		a.base.language = language
+		a.base.configure(opts)
+		return a, nil
 
 	case ProviderOpenAICompat:
 		log.WithFields(log.Fields{
 			"provider": provider,
 			"endpoint": endpoint,
 			"model":    model,
+			"language": language,
 		}).Info("Initializing OpenAI-compatible analyzer")
-		return NewOpenAICompatAnalyzer(endpoint, model, apiKey), nil
+		a := NewOpenAICompatAnalyzer(endpoint, model, apiKey)
+		a.base.language = language
+		a.base.configure(opts)
+		a.streamBriefing = opts.StreamBriefing
+		return a, nil
 
 	case ProviderAnthropic:
 		log.WithFields(log.Fields{
 			"provider": provider,
 			"endpoint": endpoint,
 			"model":    model,
+			"language": language,
 		}).Info("Initializing Anthropic analyzer")
-		return NewAnthropicAnalyzer(endpoint, model, apiKey), nil
+		a := NewAnthropicAnalyzer(endpoint, model, apiKey)
+		a.language = language
+		a.configure(opts)
+		return a, nil
 
 	default:
 		return nil, fmt.Errorf("unknown LLM provider %q: must be one of: %s, %s, %s",
@@ -28,7 +28,7 @@ func NewGLMAnalyzer(endpoint, model, apiKey string) *GLMAnalyzer {
 
 func (g *GLMAnalyzer) Provider() string { return "glm" }
 
-func (g *GLMAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]Classification, error) {
+func (g *GLMAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]Classification, Usage, error) {
 	prompt := BuildClassifyPrompt(articles)
 
 	req := ChatRequest{
@@ -46,19 +46,26 @@ func (g *GLMAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]
 
 	resp, err := g.base.chatCompletion(ctx, "/chat/completions", headers, req)
 	if err != nil {
-		return nil, fmt.Errorf("glm classify: %w", err)
+		return nil, Usage{}, fmt.Errorf("glm classify: %w", err)
 	}
+	usage := responseUsage(resp)
 
 	content, err := extractContent(resp)
 	if err != nil {
-		return nil, fmt.Errorf("glm classify extract: %w", err)
+		return nil, usage, fmt.Errorf("glm classify extract: %w", err)
 	}
 
-	return parseClassifications(content)
+	classifications, err := parseClassifications(content)
+	return classifications, usage, err
 }
 
-func (g *GLMAnalyzer) Summarize(ctx context.Context, article ArticleInput) (string, error) {
-	prompt := BuildSummarizePrompt(article)
+func (g *GLMAnalyzer) Summarize(ctx context.Context, article ArticleInput) (string, Usage, error) {
+	s, usage, err := g.SummarizeWithCategories(ctx, article)
+	return s.Summary, usage, err
+}
+
+func (g *GLMAnalyzer) SummarizeWithCategories(ctx context.Context, article ArticleInput) (ArticleSummary, Usage, error) {
+	prompt := BuildSummarizePrompt(article, g.base.language)
 
 	req := ChatRequest{
 		Model: g.base.model,
@@ -76,14 +83,19 @@ func (g *GLMAnalyzer) Summarize(ctx context.Context, article ArticleInput) (stri
 
 	resp, err := g.base.chatCompletion(ctx, "/chat/completions", headers, req)
 	if err != nil {
-		return "", fmt.Errorf("glm summarize: %w", err)
+		return ArticleSummary{}, Usage{}, fmt.Errorf("glm summarize: %w", err)
 	}
+	usage := responseUsage(resp)
 
-	return extractContent(resp)
+	content, err := extractContent(resp)
+	if err != nil {
+		return ArticleSummary{}, usage, fmt.Errorf("glm summarize extract: %w", err)
+	}
+	return parseSummary(content), usage, nil
 }
 
-func (g *GLMAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, error) {
-	prompt := BuildBriefingPrompt(sections)
+func (g *GLMAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, Usage, error) {
+	prompt := BuildBriefingPrompt(sections, g.base.language)
 
 	req := ChatRequest{
 		Model: g.base.model,
@@ -101,8 +113,9 @@ func (g *GLMAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingS
 
 	resp, err := g.base.chatCompletion(ctx, "/chat/completions", headers, req)
 	if err != nil {
-		return "", fmt.Errorf("glm briefing: %w", err)
+		return "", Usage{}, fmt.Errorf("glm briefing: %w", err)
 	}
 
-	return extractContent(resp)
+	content, err := extractContent(resp)
+	return content, responseUsage(resp), err
 }
@@ -0,0 +1,45 @@
+package llm
+
+import "strings"
+
+// DefaultLanguage is the unset language: prompts carry no language
+// instruction, so the model answers as it did before BRIEFING_LANGUAGE.
+const DefaultLanguage = ""
+
+// languageInstructions tells the model which language to answer in, written in
+// that language so the hint is not lost on smaller models.
+var languageInstructions = map[string]string{
+	"es": "Responde siempre en español, aunque el artículo esté en otro idioma. Usa la terminología técnica habitual en español (vulnerabilidad, parche, despliegue) y conserva sin traducir nombres propios e identificadores como CVEs.",
+	"en": "Always respond in English, even if the article is written in another language. Keep proper names and identifiers such as CVEs untranslated.",
+	"fr": "Réponds toujours en français, même si l'article est rédigé dans une autre langue. Conserve les noms propres et les identifiants comme les CVE sans les traduire.",
+	"de": "Antworte immer auf Deutsch, auch wenn der Artikel in einer anderen Sprache verfasst ist. Eigennamen und Kennungen wie CVEs nicht übersetzen.",
+	"pt": "Responda sempre em português, mesmo que o artigo esteja em outro idioma. Mantenha nomes próprios e identificadores como CVEs sem tradução.",
+	"it": "Rispondi sempre in italiano, anche se l'articolo è scritto in un'altra lingua. Mantieni invariati nomi propri e identificatori come i CVE.",
+}
+
+// NormalizeLanguage lowercases a language code and strips any region suffix
+// ("es-ES" → "es"). Empty input yields DefaultLanguage.
+func NormalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	if lang == "" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// languageInstruction returns the response-language instruction for a prompt,
+// or "" for DefaultLanguage. Unknown codes fall back to a generic English
+// instruction naming the code.
+func languageInstruction(lang string) string {
+	lang = NormalizeLanguage(lang)
+	if lang == DefaultLanguage {
+		return ""
+	}
+	if instruction, ok := languageInstructions[lang]; ok {
+		return instruction
+	}
+	return "Always respond in the language with ISO 639-1 code \"" + lang + "\"."
+}
@@ -5,7 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -94,7 +98,7 @@ func TestNewAnalyzer(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.provider, func(t *testing.T) {
-			a, err := NewAnalyzer(tt.provider, "http://localhost", "model", "key")
+			a, err := NewAnalyzer(tt.provider, "http://localhost", "model", "key", "")
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -112,7 +116,7 @@ func TestGLMClassify(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewGLMAnalyzer(srv.URL, "glm-4.7", "test-key")
-	results, err := analyzer.Classify(context.Background(), testArticles)
+	results, _, err := analyzer.Classify(context.Background(), testArticles)
 	require.NoError(t, err)
 	require.Len(t, results, 2)
 	assert.Equal(t, "art-1", results[0].ArticleID)
@@ -126,7 +130,7 @@ func TestGLMSummarize(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewGLMAnalyzer(srv.URL, "glm-4.7", "test-key")
-	result, err := analyzer.Summarize(context.Background(), testArticles[0])
+	result, _, err := analyzer.Summarize(context.Background(), testArticles[0])
 	require.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
@@ -147,7 +151,7 @@ func TestGLMGenerateBriefing(t *testing.T) {
 			},
 		},
 	}
-	result, err := analyzer.GenerateBriefing(context.Background(), sections)
+	result, _, err := analyzer.GenerateBriefing(context.Background(), sections)
 	require.NoError(t, err)
 	assert.Contains(t, result, "Cybersecurity")
 }
@@ -159,7 +163,7 @@ func TestOpenAICompatClassify(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewOpenAICompatAnalyzer(srv.URL, "gpt-4o-mini", "test-key")
-	results, err := analyzer.Classify(context.Background(), testArticles)
+	results, _, err := analyzer.Classify(context.Background(), testArticles)
 	require.NoError(t, err)
 	require.Len(t, results, 2)
 	assert.True(t, results[1].Relevant)
@@ -172,7 +176,7 @@ func TestOpenAICompatSummarize(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewOpenAICompatAnalyzer(srv.URL, "gpt-4o-mini", "")
-	result, err := analyzer.Summarize(context.Background(), testArticles[1])
+	result, _, err := analyzer.Summarize(context.Background(), testArticles[1])
 	require.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
@@ -184,7 +188,7 @@ func TestAnthropicClassify(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewAnthropicAnalyzer(srv.URL, "claude-sonnet-4-20250514", "test-key")
-	results, err := analyzer.Classify(context.Background(), testArticles)
+	results, _, err := analyzer.Classify(context.Background(), testArticles)
 	require.NoError(t, err)
 	require.Len(t, results, 2)
 	assert.False(t, results[0].Clickbait)
@@ -196,7 +200,7 @@ func TestAnthropicSummarize(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewAnthropicAnalyzer(srv.URL, "claude-sonnet-4-20250514", "test-key")
-	result, err := analyzer.Summarize(context.Background(), testArticles[0])
+	result, _, err := analyzer.Summarize(context.Background(), testArticles[0])
 	require.NoError(t, err)
 	assert.Equal(t, expected, result)
 }
@@ -224,7 +228,7 @@ func TestAnthropicHeaders(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewAnthropicAnalyzer(srv.URL, "claude-sonnet-4-20250514", "test-api-key")
-	_, err := analyzer.Summarize(context.Background(), testArticles[0])
+	_, _, err := analyzer.Summarize(context.Background(), testArticles[0])
 	require.NoError(t, err)
 }
 
@@ -236,14 +240,74 @@ func TestBuildClassifyPrompt(t *testing.T) {
 	assert.Contains(t, prompt, "art-2")
 	assert.Contains(t, prompt, "cybersecurity")
 	assert.Contains(t, prompt, "JSON array")
+	assert.NotContains(t, prompt, "runner-up")
+
+	withRunnerUp := append([]ArticleInput(nil), testArticles...)
+	withRunnerUp[0].RunnerUp = "economy"
+	assert.Contains(t, BuildClassifyPrompt(withRunnerUp), "cybersecurity (runner-up: economy)")
 }
 
 func TestBuildSummarizePrompt(t *testing.T) {
-	prompt := BuildSummarizePrompt(testArticles[0])
+	prompt := BuildSummarizePrompt(testArticles[0], "es")
 	assert.Contains(t, prompt, "Critical CVE")
 	assert.Contains(t, prompt, "vulnerabilidad")
 }
 
+func TestSummarizeWithCategories(t *testing.T) {
+	reply := "```json\n{\"summary\": \"Kubernetes RBAC flaw, patched in 1.30.2.\", \"categories\": [\"Kubernetes\", \"#CVE\", \"kubernetes\", \" \", \"rbac\", \"security\"]}\n```"
+	srv := newMockOpenAIServer(t, openAIHandler(reply))
+	defer srv.Close()
+
+	out, _, err := NewOpenAICompatAnalyzer(srv.URL, "m", "k").SummarizeWithCategories(context.Background(), testArticles[0])
+	require.NoError(t, err)
+	assert.Equal(t, "Kubernetes RBAC flaw, patched in 1.30.2.", out.Summary)
+	assert.Equal(t, []string{"kubernetes", "cve", "rbac"}, out.Categories)
+}
+
+func TestParseSummaryPlainTextFallback(t *testing.T) {
+	assert.Equal(t, ArticleSummary{Summary: "A plain summary."}, parseSummary("  A plain summary.\n"))
+}
+
+func TestParseSummaryUnusableJSON(t *testing.T) {
+	assert.Equal(t, ArticleSummary{}, parseSummary(`{"summary": ""}`))
+	assert.Equal(t, ArticleSummary{}, parseSummary(`{"summary": "Kubernetes RBAC flaw, patched in`))
+	assert.Equal(t, ArticleSummary{}, parseSummary("```json\n{\"summary\": \"cut off\n```"))
+}
+
+func TestBuildSummarizePromptLanguage(t *testing.T) {
+	prompt := BuildSummarizePrompt(testArticles[0], "en")
+	assert.Contains(t, prompt, "Always respond in English")
+	assert.NotContains(t, prompt, "Responde siempre en español")
+
+	prompt = BuildSummarizePrompt(testArticles[0], "es-ES")
+	assert.Contains(t, prompt, "Responde siempre en español")
+
+	prompt = BuildSummarizePrompt(testArticles[0], "nl")
+	assert.Contains(t, prompt, `ISO 639-1 code "nl"`)
+
+	// Unset keeps the prompt free of any language instruction.
+	prompt = BuildSummarizePrompt(testArticles[0], "")
+	assert.NotContains(t, prompt, "respond in")
+	assert.NotContains(t, prompt, "Responde")
+	assert.Contains(t, prompt, "trend.\nAlso tag")
+}
+
+func TestBuildBriefingPromptLanguage(t *testing.T) {
+	sections := []BriefingSection{{
+		Name:        "tech",
+		DisplayName: "Tech",
+		MaxArticles: 3,
+		Articles:    []SummarizedArticle{{ID: "art-1", Title: "Go 1.30 released", URL: "https://go.dev", Summary: "New release."}},
+	}}
+
+	prompt := BuildBriefingPrompt(sections, "es")
+	assert.Contains(t, prompt, "Go 1.30 released")
+	assert.Contains(t, prompt, "Responde siempre en español")
+
+	prompt = BuildBriefingPrompt(sections, "")
+	assert.Contains(t, prompt, "no filler.\n\n## Tech")
+}
+
 func TestStripCodeFences(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -269,11 +333,193 @@ func TestAPIErrorHandling(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewGLMAnalyzer(srv.URL, "glm-4.7", "test-key")
-	_, err := analyzer.Classify(context.Background(), testArticles)
+	analyzer.base.retry.backoff = time.Millisecond
+	_, _, err := analyzer.Classify(context.Background(), testArticles)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "429")
 }
 
+func TestRetryTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/v1/messages") {
+			anthropicHandler("ok")(w, r)
+			return
+		}
+		openAIHandler("ok")(w, r)
+	}))
+	defer srv.Close()
+
+	compat := NewOpenAICompatAnalyzer(srv.URL, "m", "k")
+	compat.base.retry.backoff = time.Millisecond
+	out, _, err := compat.Summarize(context.Background(), testArticles[0])
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+	assert.Equal(t, int32(2), calls.Load())
+
+	calls.Store(0)
+	anthropic := NewAnthropicAnalyzer(srv.URL, "m", "k")
+	anthropic.retry.backoff = time.Millisecond
+	out, _, err = anthropic.Summarize(context.Background(), testArticles[0])
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	a, err := NewAnalyzerWithOptions(ProviderOpenAICompat, srv.URL, "m", "k", "", Options{MaxRetries: 3})
+	require.NoError(t, err)
+	compat := a.(*OpenAICompatAnalyzer)
+	compat.base.retry.backoff = time.Millisecond
+	_, _, err = compat.Summarize(context.Background(), testArticles[0])
+	assert.ErrorContains(t, err, "status 500")
+	assert.Equal(t, int32(4), calls.Load())
+
+	calls.Store(0)
+	a, err = NewAnalyzerWithOptions(ProviderAnthropic, srv.URL, "m", "k", "", Options{MaxRetries: 0})
+	require.NoError(t, err)
+	_, _, err = a.Summarize(context.Background(), testArticles[0])
+	assert.ErrorContains(t, err, "status 500")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	_, _, err := NewGLMAnalyzer(srv.URL, "m", "k").Summarize(context.Background(), testArticles[0])
+	assert.ErrorContains(t, err, "status 400")
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestOpenAICompatStreamedBriefing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.True(t, req.Stream)
+		require.NotNil(t, req.StreamOptions)
+		assert.True(t, req.StreamOptions.IncludeUsage)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{
+			`{"choices":[{"delta":{"role":"assistant","content":""}}]}`,
+			`{"choices":[{"delta":{"content":"# Brief"}}]}`,
+			`{"choices":[{"delta":{"content":"ing\n\nAll quiet."}}]}`,
+			`{"choices":[],"usage":{"prompt_tokens":40,"completion_tokens":7,"total_tokens":47}}`,
+			`[DONE]`,
+		} {
+			_, _ = w.Write([]byte(": keep-alive\n\ndata: " + event + "\n\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	a, err := NewAnalyzerWithOptions(ProviderOpenAICompat, srv.URL, "m", "k", "", Options{StreamBriefing: true})
+	require.NoError(t, err)
+
+	var deltas []string
+	ctx := WithProgress(context.Background(), func(delta string) { deltas = append(deltas, delta) })
+	out, usage, err := a.GenerateBriefing(ctx, []BriefingSection{{Name: "tech", DisplayName: "Tech"}})
+	require.NoError(t, err)
+	assert.Equal(t, "# Briefing\n\nAll quiet.", out)
+	assert.Equal(t, []string{"# Brief", "ing\n\nAll quiet."}, deltas)
+	assert.Equal(t, Usage{Calls: 1, PromptTokens: 40, CompletionTokens: 7, TotalTokens: 47}, usage)
+}
+
+func TestOpenAICompatStreamIdleTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"# Brief"}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	a, err := NewAnalyzerWithOptions(ProviderOpenAICompat, srv.URL, "m", "k", "", Options{Timeout: 50 * time.Millisecond, StreamBriefing: true})
+	require.NoError(t, err)
+	_, _, err = a.GenerateBriefing(context.Background(), []BriefingSection{{Name: "tech"}})
+	assert.ErrorContains(t, err, "no data for 50ms after 7 chars")
+}
+
+func TestOpenAICompatStreamTruncatedIsRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"# Brief"}}]}` + "\n\n"))
+		if calls.Add(1) == 1 {
+			return // connection closes cleanly before the generation ends
+		}
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"ing"},"finish_reason":"stop"}]}` + "\n\n"))
+	}))
+	defer srv.Close()
+
+	a, err := NewAnalyzerWithOptions(ProviderOpenAICompat, srv.URL, "m", "k", "", Options{MaxRetries: 1, StreamBriefing: true})
+	require.NoError(t, err)
+	compat := a.(*OpenAICompatAnalyzer)
+	compat.base.retry.backoff = time.Millisecond
+	out, _, err := compat.GenerateBriefing(context.Background(), []BriefingSection{{Name: "tech"}})
+	require.NoError(t, err)
+	assert.Equal(t, "# Briefing", out)
+	assert.Equal(t, int32(2), calls.Load())
+
+	calls.Store(0)
+	compat.base.retry.maxRetries = 0
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"# Brief"}}]}` + "\n\n"))
+	})
+	_, _, err = compat.GenerateBriefing(context.Background(), []BriefingSection{{Name: "tech"}})
+	assert.ErrorIs(t, err, ErrStreamTruncated)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestOpenAICompatStreamSharesRetryBudget(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A flaky proxy alternating 503s and truncated streams.
+		if calls.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"# Brief"}}]}` + "\n\n"))
+	}))
+	defer srv.Close()
+
+	a, err := NewAnalyzerWithOptions(ProviderOpenAICompat, srv.URL, "m", "k", "", Options{MaxRetries: 2, StreamBriefing: true})
+	require.NoError(t, err)
+	compat := a.(*OpenAICompatAnalyzer)
+	compat.base.retry.backoff = time.Millisecond
+	_, _, err = compat.GenerateBriefing(context.Background(), []BriefingSection{{Name: "tech"}})
+	assert.ErrorContains(t, err, "status 503")
+	assert.Equal(t, int32(3), calls.Load(), "5xx and truncation retries share LLM_MAX_RETRIES")
+}
+
+func TestOptionsCallTimeout(t *testing.T) {
+	assert.Equal(t, DefaultTimeout, Options{MaxRetries: -1}.CallTimeout())
+	assert.Equal(t, 3*10*time.Second+2*maxRetryWait, Options{Timeout: 10 * time.Second, MaxRetries: 2}.CallTimeout())
+}
+
 func TestEmptyResponseHandling(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := ChatResponse{Choices: []ChatChoice{}}
@@ -282,7 +528,100 @@ func TestEmptyResponseHandling(t *testing.T) {
 	defer srv.Close()
 
 	analyzer := NewOpenAICompatAnalyzer(srv.URL, "model", "key")
-	_, err := analyzer.Summarize(context.Background(), testArticles[0])
+	_, _, err := analyzer.Summarize(context.Background(), testArticles[0])
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "empty response")
 }
+
+func TestTruncateContentRuneSafe(t *testing.T) {
+	content := strings.Repeat("á", 5) + strings.Repeat("😀", 5)
+	out := truncateContent(content, 7)
+	assert.True(t, utf8.ValidString(out))
+	assert.Equal(t, strings.Repeat("á", 5)+"😀😀\n[...truncated]", out)
+	assert.Equal(t, content, truncateContent(content, 10))
+
+	prompt := BuildClassifyPrompt([]ArticleInput{{ID: "x", Title: "t", Content: strings.Repeat("€", 250)}})
+	assert.True(t, utf8.ValidString(prompt))
+}
+
+func TestPing(t *testing.T) {
+	status := http.StatusOK
+	var gotPath, gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	compat := NewOpenAICompatAnalyzer(srv.URL, "m", "secret")
+	require.NoError(t, compat.Ping(ctx))
+	assert.Equal(t, "/models", gotPath)
+	assert.Equal(t, "Bearer secret", gotAuth)
+
+	status = http.StatusNotFound
+	assert.NoError(t, compat.Ping(ctx), "servers without a models endpoint are still reachable")
+
+	status = http.StatusUnauthorized
+	assert.ErrorContains(t, compat.Ping(ctx), "API key rejected")
+
+	status = http.StatusBadGateway
+	assert.ErrorContains(t, NewAnthropicAnalyzer(srv.URL, "m", "k").Ping(ctx), "status 502")
+	assert.Equal(t, "/v1/models", gotPath)
+}
+
+func TestUsageAdd(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Resumen."}}],"usage":{"prompt_tokens":120,"completion_tokens":30,"total_tokens":150}}`))
+	}))
+	defer srv.Close()
+
+	analyzer := NewOpenAICompatAnalyzer(srv.URL, "m", "k")
+	var total Usage
+	for i := 0; i < 2; i++ {
+		_, usage, err := analyzer.Summarize(context.Background(), ArticleInput{ID: "a1", Title: "T", Content: "Body"})
+		require.NoError(t, err)
+		total = total.Add(usage)
+	}
+	assert.Equal(t, Usage{Calls: 2, PromptTokens: 240, CompletionTokens: 60, TotalTokens: 300}, total)
+
+	// A server that omits usage still counts the call.
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"Resumen."}}]}`))
+	})
+	_, usage, err := analyzer.Summarize(context.Background(), ArticleInput{ID: "a1", Title: "T"})
+	require.NoError(t, err)
+	assert.Equal(t, Usage{Calls: 1}, usage)
+}
+
+func TestUsagePropagatesPerProvider(t *testing.T) {
+	openAI := newMockOpenAIServer(t, openAIHandler(testClassificationResponse))
+	defer openAI.Close()
+	anthropic := newMockOpenAIServer(t, anthropicHandler(testClassificationResponse))
+	defer anthropic.Close()
+
+	analyzers := []Analyzer{
+		NewGLMAnalyzer(openAI.URL, "glm-4.7", "k"),
+		NewOpenAICompatAnalyzer(openAI.URL, "gpt-4o-mini", "k"),
+		NewAnthropicAnalyzer(anthropic.URL, "claude-sonnet-4-20250514", "k"),
+	}
+	// Anthropic input/output tokens map onto prompt/completion, and its
+	// total is their sum.
+	want := Usage{Calls: 1, PromptTokens: 100, CompletionTokens: 50, TotalTokens: 150}
+	for _, analyzer := range analyzers {
+		t.Run(analyzer.Provider(), func(t *testing.T) {
+			_, usage, err := analyzer.Classify(context.Background(), testArticles)
+			require.NoError(t, err)
+			assert.Equal(t, want, usage)
+
+			_, usage, err = analyzer.Summarize(context.Background(), testArticles[0])
+			require.NoError(t, err)
+			assert.Equal(t, want, usage)
+
+			_, usage, err = analyzer.GenerateBriefing(context.Background(), []BriefingSection{{Name: "tech"}})
+			require.NoError(t, err)
+			assert.Equal(t, want, usage)
+		})
+	}
+}
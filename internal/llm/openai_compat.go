@@ -9,6 +9,8 @@ import (
 // Works with: OpenAI, Ollama, vLLM, LiteLLM, Together, Groq, etc.
 type OpenAICompatAnalyzer struct {
 	base baseClient
+	// streamBriefing streams GenerateBriefing; see Options.StreamBriefing.
+	streamBriefing bool
 }
 
 // NewOpenAICompatAnalyzer creates an OpenAI-compatible analyzer.
@@ -26,7 +28,7 @@ func NewOpenAICompatAnalyzer(endpoint, model, apiKey string) *OpenAICompatAnalyz
 
 func (o *OpenAICompatAnalyzer) Provider() string { return "openai_compat" }
 
-func (o *OpenAICompatAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]Classification, error) {
+func (o *OpenAICompatAnalyzer) Classify(ctx context.Context, articles []ArticleInput) ([]Classification, Usage, error) {
 	prompt := BuildClassifyPrompt(articles)
 
 	req := ChatRequest{
@@ -45,19 +47,26 @@ func (o *OpenAICompatAnalyzer) Classify(ctx context.Context, articles []ArticleI
 
 	resp, err := o.base.chatCompletion(ctx, "/chat/completions", headers, req)
 	if err != nil {
-		return nil, fmt.Errorf("openai classify: %w", err)
+		return nil, Usage{}, fmt.Errorf("openai classify: %w", err)
 	}
+	usage := responseUsage(resp)
 
 	content, err := extractContent(resp)
 	if err != nil {
-		return nil, fmt.Errorf("openai classify extract: %w", err)
+		return nil, usage, fmt.Errorf("openai classify extract: %w", err)
 	}
 
-	return parseClassifications(content)
+	classifications, err := parseClassifications(content)
+	return classifications, usage, err
 }
 
-func (o *OpenAICompatAnalyzer) Summarize(ctx context.Context, article ArticleInput) (string, error) {
-	prompt := BuildSummarizePrompt(article)
+func (o *OpenAICompatAnalyzer) Summarize(ctx context.Context, article ArticleInput) (string, Usage, error) {
+	s, usage, err := o.SummarizeWithCategories(ctx, article)
+	return s.Summary, usage, err
+}
+
+func (o *OpenAICompatAnalyzer) SummarizeWithCategories(ctx context.Context, article ArticleInput) (ArticleSummary, Usage, error) {
+	prompt := BuildSummarizePrompt(article, o.base.language)
 
 	req := ChatRequest{
 		Model: o.base.model,
@@ -76,14 +85,19 @@ func (o *OpenAICompatAnalyzer) Summarize(ctx context.Context, article ArticleInp
 
 	resp, err := o.base.chatCompletion(ctx, "/chat/completions", headers, req)
 	if err != nil {
-		return "", fmt.Errorf("openai summarize: %w", err)
+		return ArticleSummary{}, Usage{}, fmt.Errorf("openai summarize: %w", err)
 	}
+	usage := responseUsage(resp)
 
-	return extractContent(resp)
+	content, err := extractContent(resp)
+	if err != nil {
+		return ArticleSummary{}, usage, fmt.Errorf("openai summarize extract: %w", err)
+	}
+	return parseSummary(content), usage, nil
 }
 
-func (o *OpenAICompatAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, error) {
-	prompt := BuildBriefingPrompt(sections)
+func (o *OpenAICompatAnalyzer) GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, Usage, error) {
+	prompt := BuildBriefingPrompt(sections, o.base.language)
 
 	req := ChatRequest{
 		Model: o.base.model,
@@ -100,10 +114,15 @@ func (o *OpenAICompatAnalyzer) GenerateBriefing(ctx context.Context, sections []
 		headers["Authorization"] = "Bearer " + o.base.apiKey
 	}
 
-	resp, err := o.base.chatCompletion(ctx, "/chat/completions", headers, req)
+	complete := o.base.chatCompletion
+	if o.streamBriefing {
+		complete = o.base.chatCompletionStream
+	}
+	resp, err := complete(ctx, "/chat/completions", headers, req)
 	if err != nil {
-		return "", fmt.Errorf("openai briefing: %w", err)
+		return "", Usage{}, fmt.Errorf("openai briefing: %w", err)
 	}
 
-	return extractContent(resp)
+	content, err := extractContent(resp)
+	return content, responseUsage(resp), err
 }
@@ -0,0 +1,60 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Ping checks that the GLM endpoint is reachable and accepts the API key.
+func (g *GLMAnalyzer) Ping(ctx context.Context) error {
+	return pingEndpoint(ctx, g.base.httpClient, g.base.endpoint+"/models", map[string]string{
+		"Authorization": "Bearer " + g.base.apiKey,
+	})
+}
+
+// Ping checks that the OpenAI-compatible endpoint is reachable and accepts
+// the API key.
+func (o *OpenAICompatAnalyzer) Ping(ctx context.Context) error {
+	return pingEndpoint(ctx, o.base.httpClient, o.base.endpoint+"/models", map[string]string{
+		"Authorization": "Bearer " + o.base.apiKey,
+	})
+}
+
+// Ping checks that the Anthropic API is reachable and accepts the API key.
+func (a *AnthropicAnalyzer) Ping(ctx context.Context) error {
+	return pingEndpoint(ctx, a.httpClient, a.endpoint+"/v1/models", map[string]string{
+		"x-api-key":         a.apiKey,
+		"anthropic-version": "2023-06-01",
+	})
+}
+
+// pingEndpoint issues a token-free GET against a model listing endpoint.
+// Servers without that endpoint (404/405) still count as reachable; auth
+// failures and server errors do not.
+func pingEndpoint(ctx context.Context, client *http.Client, url string, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating ping request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing ping request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode < 300, resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusMethodNotAllowed:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("API key rejected (status %d)", resp.StatusCode)
+	default:
+		return fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+}
@@ -3,6 +3,8 @@ package llm
 import (
 	"fmt"
 	"strings"
+
+	"github.com/zyrak/flux/internal/textutil"
 )
 
 // Prompt templates for the LLM pipeline.
@@ -23,12 +25,13 @@ Articles:
 `)
 
 	for i, a := range articles {
-		content := a.Content
-		if len(content) > 200 {
-			content = content[:200]
+		content := textutil.TruncateRunes(a.Content, 200)
+		section := a.Section
+		if a.RunnerUp != "" {
+			section += " (runner-up: " + a.RunnerUp + ")"
 		}
 		sb.WriteString(fmt.Sprintf("%d. [ID: %s] %s - %s - %s\n",
-			i+1, a.ID, a.Title, a.Section, content))
+			i+1, a.ID, a.Title, section, content))
 	}
 
 	sb.WriteString(`
@@ -37,22 +40,35 @@ Respond ONLY with a JSON array.`)
 	return sb.String()
 }
 
-// BuildSummarizePrompt creates the single-article summarization prompt.
-func BuildSummarizePrompt(article ArticleInput) string {
+// BuildSummarizePrompt creates the single-article summarization prompt,
+// instructing the model to answer in the given language.
+func BuildSummarizePrompt(article ArticleInput, language string) string {
 	return fmt.Sprintf(`Summarize this article in 2-3 sentences. If it's a vulnerability, include severity
 and whether a patch exists. If it's code/tool, explain what it does and why it matters.
 If there are concrete data points (benchmarks, figures), include them.
-If it's financial news, include key figures and trend.
+If it's financial news, include key figures and trend.%s
+Also tag the article with 1-3 short lowercase English topic tags (e.g. "kubernetes", "cve").
+Respond ONLY with a JSON object: {"summary": "...", "categories": ["...", "..."]}
 
 Title: %s
 Source: %s
 Section: %s
 
-%s`, article.Title, article.SourceType, article.Section, truncateContent(article.Content, 4000))
+%s`, summarizeLanguageLine(language), article.Title, article.SourceType, article.Section, truncateContent(article.Content, 4000))
+}
+
+// summarizeLanguageLine is the language instruction as its own prompt line,
+// or nothing when no language is configured.
+func summarizeLanguageLine(language string) string {
+	if instruction := languageInstruction(language); instruction != "" {
+		return "\n" + instruction
+	}
+	return ""
 }
 
-// BuildBriefingPrompt creates the final briefing synthesis prompt.
-func BuildBriefingPrompt(sections []BriefingSection) string {
+// BuildBriefingPrompt creates the final briefing synthesis prompt,
+// instructing the model to answer in the given language.
+func BuildBriefingPrompt(sections []BriefingSection, language string) string {
 	var sb strings.Builder
 	sb.WriteString(`Generate a morning briefing organized into the following sections.
 For each section, highlight the most important article first.
@@ -60,8 +76,11 @@ If there are related articles across sections, connect them explicitly.
 If an article has multiple sources, explicitly keep a line with this format:
 "📡 Seen in: HN, r/netsec, ...".
 Format: Markdown. Tone: direct, technical, no filler.
-
 `)
+	if instruction := languageInstruction(language); instruction != "" {
+		sb.WriteString(instruction + "\n")
+	}
+	sb.WriteString("\n")
 
 	for _, sec := range sections {
 		sb.WriteString(fmt.Sprintf("## %s (max %d articles)\n", sec.DisplayName, sec.MaxArticles))
@@ -82,8 +101,9 @@ Format: Markdown. Tone: direct, technical, no filler.
 }
 
 func truncateContent(content string, maxChars int) string {
-	if len(content) <= maxChars {
+	truncated := textutil.TruncateRunes(content, maxChars)
+	if len(truncated) == len(content) {
 		return content
 	}
-	return content[:maxChars] + "\n[...truncated]"
+	return truncated + "\n[...truncated]"
 }
@@ -0,0 +1,184 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/retry"
+)
+
+// Defaults used by NewAnalyzer and the provider constructors.
+const (
+	DefaultTimeout    = 120 * time.Second
+	DefaultMaxRetries = 2
+)
+
+const (
+	initialRetryBackoff = time.Second
+	// maxRetryWait caps both the exponential backoff and a server's
+	// Retry-After, so a misbehaving proxy cannot park a run for an hour.
+	maxRetryWait = 30 * time.Second
+)
+
+// Options tunes the HTTP behaviour of an analyzer.
+type Options struct {
+	// Timeout bounds a single HTTP attempt; zero means DefaultTimeout.
+	Timeout time.Duration
+	// MaxRetries is how many times a 429, 5xx or transport failure is
+	// retried after the first attempt; negative values count as 0.
+	MaxRetries int
+	// StreamBriefing makes providers that support it stream the briefing
+	// synthesis, so Timeout bounds the gap between chunks rather than the
+	// whole generation.
+	StreamBriefing bool
+}
+
+// DefaultOptions returns the options used when none are given.
+func DefaultOptions() Options {
+	return Options{Timeout: DefaultTimeout, MaxRetries: DefaultMaxRetries}
+}
+
+// CallTimeout is the longest a single analyzer call can take with these
+// options: every attempt timing out and every retry waiting the maximum.
+// Callers that wrap calls in their own deadline should allow at least this.
+func (o Options) CallTimeout() time.Duration {
+	o = o.normalized()
+	return time.Duration(o.MaxRetries+1)*o.Timeout + time.Duration(o.MaxRetries)*maxRetryWait
+}
+
+func (o Options) normalized() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	return o
+}
+
+// retryPolicy is the per-analyzer state used by postWithRetry.
+type retryPolicy struct {
+	maxRetries int
+	// backoff is the first wait between attempts; it doubles on each retry.
+	backoff time.Duration
+}
+
+func newRetryPolicy(opts Options) retryPolicy {
+	return retryPolicy{maxRetries: opts.normalized().MaxRetries, backoff: initialRetryBackoff}
+}
+
+// postWithRetry POSTs body to url and returns the status and body of the
+// last attempt. 429 and 5xx responses and transport errors are retried with
+// exponential backoff, honouring Retry-After when the server sends one; any
+// other status is returned to the caller as is.
+func postWithRetry(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte, policy retryPolicy) (int, []byte, error) {
+	resp, err := sendWithRetry(ctx, client, url, headers, body, policy)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("reading response body: %w", err)
+	}
+	return resp.StatusCode, respBody, nil
+}
+
+// sendWithRetry is postWithRetry for callers that consume the body
+// themselves. It returns the last response with its body unread; the caller
+// must close it.
+func sendWithRetry(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte, policy retryPolicy) (*http.Response, error) {
+	var resp *http.Response
+	err := retryTransient(ctx, policy, func() (time.Duration, bool, error) {
+		if resp != nil {
+			discardBody(resp)
+		}
+		var err error
+		resp, err = send(ctx, client, url, headers, body)
+		if err != nil {
+			return 0, true, err
+		}
+		if !retry.RetryableStatus(resp.StatusCode) {
+			return 0, false, nil
+		}
+		return retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), true, &statusError{code: resp.StatusCode}
+	})
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		// Out of retries: the caller reports the status itself.
+		return resp, nil
+	}
+	if err != nil && resp != nil {
+		discardBody(resp)
+		return nil, err
+	}
+	return resp, err
+}
+
+// retryTransient calls attempt until it succeeds, fails for good, or the
+// policy's retries are spent, waiting with exponential backoff in between.
+// attempt reports whether its failure is worth retrying and any wait the
+// server asked for (Retry-After), which replaces the backoff. Every kind of
+// transient failure draws on the same budget, so a call makes at most
+// maxRetries+1 requests.
+func retryTransient(ctx context.Context, policy retryPolicy, attempt func() (retryAfter time.Duration, transient bool, err error)) error {
+	backoff := retry.Backoff{Initial: policy.backoff, Max: maxRetryWait}
+	for n := 0; ; n++ {
+		retryAfter, transient, err := attempt()
+		if err == nil || !transient || n >= policy.maxRetries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := backoff.Next()
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		wait = min(wait, maxRetryWait)
+		log.WithFields(log.Fields{"attempt": n + 1, "error": err.Error(), "wait": wait}).Warn("LLM request failed, retrying")
+
+		if err := retry.Sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// send makes a single POST of body to url.
+func send(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	return resp, nil
+}
+
+// statusError is a retryable status that sendWithRetry hands back as the
+// response once retries run out.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status %d", e.code)
+}
+
+// discardBody drains a little of a response that will not be read, so the
+// connection can be reused, and closes it.
+func discardBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
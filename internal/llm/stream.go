@@ -0,0 +1,166 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/retry"
+)
+
+// ProgressFunc receives each piece of text a streamed generation produces,
+// in order, as it arrives.
+type ProgressFunc func(delta string)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose streamed analyzer calls report their
+// output to fn as it arrives. Calls that do not stream ignore it; a stream
+// retried after truncation reports its text again from the start.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, delta string) {
+	if fn, _ := ctx.Value(progressKey{}).(ProgressFunc); fn != nil && delta != "" {
+		fn(delta)
+	}
+}
+
+var errStreamIdle = errors.New("stream idle")
+
+// ErrStreamTruncated reports a stream that ended before the server marked
+// the generation complete, e.g. a proxy closing the connection early. The
+// partial text is discarded rather than returned as the answer.
+var ErrStreamTruncated = errors.New("stream ended before the generation completed")
+
+// newStreamClient returns a client without an overall deadline, which would
+// cut long generations short; timeout instead bounds the wait for response
+// headers, and chatCompletionStream applies it between chunks.
+func newStreamClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = timeout
+	return &http.Client{Transport: transport}
+}
+
+// chatCompletionStream sends req as an OpenAI-compatible streaming chat
+// completion and accumulates the server-sent chunks into a single response.
+// The request fails when no chunk arrives for the client's timeout. A stream
+// cut short (ErrStreamTruncated) is retried like a 5xx, from the start, and
+// both draw on the same LLM_MAX_RETRIES budget.
+func (c *baseClient) chatCompletionStream(ctx context.Context, path string, headers map[string]string, req ChatRequest) (*ChatResponse, error) {
+	req.Stream = true
+	req.StreamOptions = &ChatStreamOptions{IncludeUsage: true}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling request: %w", err)
+	}
+
+	var out *ChatResponse
+	err = retryTransient(ctx, c.retry, func() (time.Duration, bool, error) {
+		var (
+			retryAfter time.Duration
+			transient  bool
+			err        error
+		)
+		out, retryAfter, transient, err = c.streamOnce(ctx, path, headers, body)
+		return retryAfter, transient, err
+	})
+	return out, err
+}
+
+// streamOnce makes a single streamed request and reports, on failure,
+// whether it is worth retrying and any Retry-After the server sent.
+func (c *baseClient) streamOnce(ctx context.Context, path string, headers map[string]string, body []byte) (*ChatResponse, time.Duration, bool, error) {
+	streamCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	start := time.Now()
+	resp, err := send(streamCtx, c.streamClient, c.endpoint+path, headers, body)
+	if err != nil {
+		return nil, 0, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		log.WithFields(log.Fields{
+			"status":   resp.StatusCode,
+			"body":     string(respBody),
+			"duration": time.Since(start),
+		}).Error("LLM API error")
+		retryAfter := retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return nil, retryAfter, retry.RetryableStatus(resp.StatusCode), fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody[:min(len(respBody), 200)]))
+	}
+
+	idle := time.AfterFunc(c.httpClient.Timeout, func() { cancel(errStreamIdle) })
+	defer idle.Stop()
+
+	var (
+		content strings.Builder
+		usage   *ChatUsage
+		// finished is set by [DONE] or a finish_reason; without either the
+		// connection closed mid-generation.
+		finished bool
+	)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		idle.Reset(c.httpClient.Timeout)
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // blank separators, comments and event names
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			finished = true
+			break
+		}
+
+		var chunk ChatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, 0, false, fmt.Errorf("unmarshalling stream chunk: %w (raw: %.200s)", err, data)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		for _, choice := range chunk.Choices {
+			content.WriteString(choice.Delta.Content)
+			reportProgress(ctx, choice.Delta.Content)
+			if choice.FinishReason != "" {
+				finished = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(context.Cause(streamCtx), errStreamIdle) {
+			return nil, 0, false, fmt.Errorf("reading stream: no data for %s after %d chars", c.httpClient.Timeout, content.Len())
+		}
+		return nil, 0, false, fmt.Errorf("reading stream: %w", err)
+	}
+	if !finished {
+		return nil, 0, true, fmt.Errorf("reading stream: %w after %d chars", ErrStreamTruncated, content.Len())
+	}
+
+	duration := time.Since(start)
+	if usage != nil {
+		log.WithFields(log.Fields{
+			"prompt_tokens":     usage.PromptTokens,
+			"completion_tokens": usage.CompletionTokens,
+			"total_tokens":      usage.TotalTokens,
+			"duration":          duration,
+		}).Debug("LLM API usage")
+	}
+
+	return &ChatResponse{
+		Choices: []ChatChoice{{Message: ChatMessage{Role: "assistant", Content: content.String()}}},
+		Usage:   usage,
+	}, 0, false, nil
+}
@@ -6,28 +6,43 @@ import (
 
 // Analyzer defines the interface for LLM-powered article analysis.
 // All implementations (GLM, OpenAI-compatible, Anthropic) must satisfy this.
+//
+// Each method returns the provider-reported token usage of its call. Usage
+// is also returned alongside a parse error, since the tokens were spent.
 type Analyzer interface {
 	// Classify takes a batch of articles and returns classifications for each.
 	// Used in Phase 2 of the pipeline to filter irrelevant/clickbait content.
-	Classify(ctx context.Context, articles []ArticleInput) ([]Classification, error)
+	Classify(ctx context.Context, articles []ArticleInput) ([]Classification, Usage, error)
 
 	// Summarize generates a concise summary of a single article.
-	Summarize(ctx context.Context, article ArticleInput) (string, error)
+	Summarize(ctx context.Context, article ArticleInput) (string, Usage, error)
+
+	// SummarizeWithCategories is Summarize plus 1-3 topic tags for the
+	// article. Categories are empty when the model ignores the requested
+	// JSON format.
+	SummarizeWithCategories(ctx context.Context, article ArticleInput) (ArticleSummary, Usage, error)
 
 	// GenerateBriefing synthesizes multiple summarized articles into a structured briefing.
-	GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, error)
+	GenerateBriefing(ctx context.Context, sections []BriefingSection) (string, Usage, error)
 
 	// Provider returns the name of the LLM provider (for logging/metrics).
 	Provider() string
 }
 
+// Pinger is implemented by analyzers that can cheaply check their endpoint
+// without spending tokens.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
 // ArticleInput is the minimal article data sent to the LLM.
 type ArticleInput struct {
 	ID         string `json:"id"`
 	Title      string `json:"title"`
-	Content    string `json:"content"`     // Full text or truncated
-	Section    string `json:"section"`     // Pre-assigned section name
-	SourceType string `json:"source_type"` // rss, hn, reddit
+	Content    string `json:"content"`             // Full text or truncated
+	Section    string `json:"section"`             // Pre-assigned section name
+	RunnerUp   string `json:"runner_up,omitempty"` // Next best section of a multi-section source
+	SourceType string `json:"source_type"`         // rss, hn, reddit
 	URL        string `json:"url"`
 }
 
@@ -40,6 +55,12 @@ type Classification struct {
 	Reason    string `json:"reason"`
 }
 
+// ArticleSummary is the summarizer's answer for one article.
+type ArticleSummary struct {
+	Summary    string   `json:"summary"`
+	Categories []string `json:"categories"`
+}
+
 // SummarizedArticle is an article with its LLM-generated summary, ready for briefing.
 type SummarizedArticle struct {
 	ID         string   `json:"id"`
@@ -72,6 +93,14 @@ type ChatRequest struct {
 	Messages    []ChatMessage `json:"messages"`
 	Temperature float64       `json:"temperature,omitempty"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Stream      bool          `json:"stream,omitempty"`
+	// StreamOptions asks streaming servers to report usage in the last chunk.
+	StreamOptions *ChatStreamOptions `json:"stream_options,omitempty"`
+}
+
+// ChatStreamOptions configures a streamed chat completion.
+type ChatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // ChatResponse is a generic chat completion response.
@@ -85,6 +114,19 @@ type ChatChoice struct {
 	Message ChatMessage `json:"message"`
 }
 
+// ChatStreamChunk is one server-sent event of a streamed chat completion.
+type ChatStreamChunk struct {
+	Choices []ChatStreamChoice `json:"choices"`
+	Usage   *ChatUsage         `json:"usage,omitempty"`
+}
+
+// ChatStreamChoice carries the text added by one streamed chunk. The last
+// chunk of a completed choice sets FinishReason.
+type ChatStreamChoice struct {
+	Delta        ChatMessage `json:"delta"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+}
+
 // ChatUsage tracks token consumption.
 type ChatUsage struct {
 	PromptTokens     int `json:"prompt_tokens"`
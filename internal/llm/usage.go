@@ -0,0 +1,39 @@
+package llm
+
+// Usage is the token consumption reported by the provider. Analyzer methods
+// return it for the calls they made; a provider that omits usage still
+// counts as a call with zero tokens.
+type Usage struct {
+	Calls            int `json:"calls"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Add returns the sum of u and other.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		Calls:            u.Calls + other.Calls,
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+	}
+}
+
+// newUsage is one successful call's usage. Total falls back to prompt plus
+// completion for providers that do not report it.
+func newUsage(promptTokens, completionTokens, totalTokens int) Usage {
+	if totalTokens == 0 {
+		totalTokens = promptTokens + completionTokens
+	}
+	return Usage{Calls: 1, PromptTokens: promptTokens, CompletionTokens: completionTokens, TotalTokens: totalTokens}
+}
+
+// responseUsage is the usage of a chat completion, zero tokens when the
+// server left it out.
+func responseUsage(resp *ChatResponse) Usage {
+	if resp.Usage == nil {
+		return newUsage(0, 0, 0)
+	}
+	return newUsage(resp.Usage.PromptTokens, resp.Usage.CompletionTokens, resp.Usage.TotalTokens)
+}
@@ -44,6 +44,17 @@ const (
 	StatusProcessed = "processed"
 	StatusBriefed   = "briefed"
 	StatusArchived  = "archived"
+	// StatusNeedsEmbedding marks articles the processor could not embed;
+	// they are requeued once the embeddings service is back.
+	StatusNeedsEmbedding = "needs_embedding"
+)
+
+// BriefingScope constants, stored as metadata.scope. A daemon run for
+// sections on their own schedule briefs only those sections; briefings from
+// before scopes were recorded count as full.
+const (
+	BriefingScopeFull     = "full"
+	BriefingScopeSections = "sections"
 )
 
 // Briefing represents a generated daily briefing.
@@ -3,10 +3,12 @@ package profile
 import (
 	"context"
 	"fmt"
-	"strings"
+	"math"
+	"time"
 
 	"github.com/zyrak/flux/internal/embeddings"
 	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/relevance"
 	"github.com/zyrak/flux/internal/store"
 )
 
@@ -15,17 +17,24 @@ type Recalculator struct {
 	store        *store.Store
 	embedClient  *embeddings.Client
 	recentWeight float32
+	// halfLife is the feedback age at which a vote counts half as much as a
+	// fresh one; zero weighs all feedback equally.
+	halfLife time.Duration
 }
 
 // NewRecalculator creates a new section profile recalculator.
-func NewRecalculator(st *store.Store, embedClient *embeddings.Client, recentWeight float32) *Recalculator {
+func NewRecalculator(st *store.Store, embedClient *embeddings.Client, recentWeight float32, halfLife time.Duration) *Recalculator {
 	if recentWeight <= 0 || recentWeight >= 1 {
 		recentWeight = 0.7
 	}
+	if halfLife < 0 {
+		halfLife = 0
+	}
 	return &Recalculator{
 		store:        st,
 		embedClient:  embedClient,
 		recentWeight: recentWeight,
+		halfLife:     halfLife,
 	}
 }
 
@@ -47,25 +56,28 @@ func (r *Recalculator) RecalculateSection(ctx context.Context, sectionID string)
 		profile = &models.SectionProfile{SectionID: sectionID}
 	}
 
-	likeVectors, err := r.store.ListSectionEmbeddingsByFeedbackAction(ctx, sectionID, models.ActionLike)
+	likeVectors, err := r.store.ListSectionFeedbackEmbeddings(ctx, sectionID, models.ActionLike)
 	if err != nil {
 		return fmt.Errorf("listing like embeddings for section %s: %w", sectionID, err)
 	}
-	dislikeVectors, err := r.store.ListSectionEmbeddingsByFeedbackAction(ctx, sectionID, models.ActionDislike)
+	dislikeVectors, err := r.store.ListSectionFeedbackEmbeddings(ctx, sectionID, models.ActionDislike)
 	if err != nil {
 		return fmt.Errorf("listing dislike embeddings for section %s: %w", sectionID, err)
 	}
 
+	// Seeds only prime a profile that feedback is about to build; without
+	// likes the engine scores against the seed groups directly.
 	var seedEmbedding []float32
-	if len(likeVectors) == 0 || len(profile.PositiveEmbedding) == 0 {
+	if len(likeVectors) > 0 && len(profile.PositiveEmbedding) == 0 {
 		seedEmbedding, err = r.embedSeedKeywords(ctx, sec)
 		if err != nil {
 			return fmt.Errorf("embedding seed keywords for section %s: %w", sectionID, err)
 		}
 	}
 
-	positive := r.recalculatePositive(profile.PositiveEmbedding, seedEmbedding, likeVectors)
-	negative := r.recalculateNegative(profile.NegativeEmbedding, dislikeVectors)
+	now := time.Now()
+	positive := r.recalculatePositive(profile.PositiveEmbedding, seedEmbedding, likeVectors, now)
+	negative := r.recalculateNegative(profile.NegativeEmbedding, dislikeVectors, now)
 
 	likes, dislikes, err := r.store.CountFeedbackBySection(ctx, sectionID)
 	if err != nil {
@@ -86,29 +98,33 @@ func (r *Recalculator) RecalculateSection(ctx context.Context, sectionID string)
 	return nil
 }
 
-// RecalculateAllSections refreshes section profiles for every configured section.
-func (r *Recalculator) RecalculateAllSections(ctx context.Context) error {
+// RecalculateAllSections refreshes section profiles for every configured
+// section and returns how many were recalculated.
+func (r *Recalculator) RecalculateAllSections(ctx context.Context) (int, error) {
 	sections, err := r.store.ListSections(ctx)
 	if err != nil {
-		return fmt.Errorf("listing sections for profile recalculation: %w", err)
+		return 0, fmt.Errorf("listing sections for profile recalculation: %w", err)
 	}
+	done := 0
 	for _, sec := range sections {
 		if err := r.RecalculateSection(ctx, sec.ID); err != nil {
-			return err
+			return done, err
 		}
+		done++
 	}
-	return nil
+	return done, nil
 }
 
-func (r *Recalculator) recalculatePositive(existing, seed []float32, likeVectors [][]float32) []float32 {
+// recalculatePositive blends recent likes into the existing profile, or into
+// seed when there is none yet. Without likes there is no learned profile, so
+// it returns nil and the engine keeps using the best matching seed group, the
+// same rule it applies before any recalculation.
+func (r *Recalculator) recalculatePositive(existing, seed []float32, likeVectors []store.FeedbackEmbedding, now time.Time) []float32 {
 	if len(likeVectors) == 0 {
-		if len(seed) > 0 {
-			return seed
-		}
-		return existing
+		return nil
 	}
 
-	recent := averageVector(likeVectors)
+	recent := decayedAverage(likeVectors, r.halfLife, now)
 	history := existing
 	if len(history) == 0 {
 		history = seed
@@ -116,22 +132,23 @@ func (r *Recalculator) recalculatePositive(existing, seed []float32, likeVectors
 	return blendVectors(recent, history, r.recentWeight)
 }
 
-func (r *Recalculator) recalculateNegative(existing []float32, dislikeVectors [][]float32) []float32 {
+func (r *Recalculator) recalculateNegative(existing []float32, dislikeVectors []store.FeedbackEmbedding, now time.Time) []float32 {
 	if len(dislikeVectors) == 0 {
 		return existing
 	}
 
-	recent := averageVector(dislikeVectors)
+	recent := decayedAverage(dislikeVectors, r.halfLife, now)
 	return blendVectors(recent, existing, r.recentWeight)
 }
 
+// embedSeedKeywords embeds every seed keyword group of the section and
+// returns the weight-averaged group centroid, the prior the first likes are
+// blended into, so one large group does not drown out the others.
 func (r *Recalculator) embedSeedKeywords(ctx context.Context, section *models.Section) ([]float32, error) {
-	keywords := make([]string, 0, len(section.SeedKeywords))
-	for _, kw := range section.SeedKeywords {
-		kw = strings.TrimSpace(kw)
-		if kw != "" {
-			keywords = append(keywords, kw)
-		}
+	groups := relevance.SeedGroups(section)
+	var keywords []string
+	for _, g := range groups {
+		keywords = append(keywords, g.Keywords...)
 	}
 	if len(keywords) == 0 {
 		return nil, nil
@@ -141,10 +158,19 @@ func (r *Recalculator) embedSeedKeywords(ctx context.Context, section *models.Se
 	if err != nil {
 		return nil, err
 	}
-	if len(embs) == 0 {
-		return nil, nil
+	if len(embs) != len(keywords) {
+		return nil, fmt.Errorf("seed embeddings count mismatch: expected=%d got=%d", len(keywords), len(embs))
+	}
+
+	centroids := make([][]float32, 0, len(groups))
+	weights := make([]float64, 0, len(groups))
+	offset := 0
+	for _, g := range groups {
+		centroids = append(centroids, averageVector(embs[offset:offset+len(g.Keywords)]))
+		weights = append(weights, g.Weight)
+		offset += len(g.Keywords)
 	}
-	return averageVector(embs), nil
+	return weightedAverage(centroids, weights), nil
 }
 
 func averageVector(vectors [][]float32) []float32 {
@@ -176,6 +202,75 @@ func averageVector(vectors [][]float32) []float32 {
 	return out
 }
 
+// decayedAverage averages feedback embeddings weighting each by
+// 0.5^(age/halfLife), so older votes fade without being discarded.
+func decayedAverage(vectors []store.FeedbackEmbedding, halfLife time.Duration, now time.Time) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	dim := len(vectors[0].Embedding)
+	if dim == 0 {
+		return nil
+	}
+
+	out := make([]float64, dim)
+	total := 0.0
+	for _, v := range vectors {
+		if len(v.Embedding) != dim {
+			continue
+		}
+		weight := 1.0
+		if halfLife > 0 {
+			age := now.Sub(v.CreatedAt)
+			if age < 0 {
+				age = 0
+			}
+			weight = math.Exp2(-age.Hours() / halfLife.Hours())
+		}
+		for i := 0; i < dim; i++ {
+			out[i] += float64(v.Embedding[i]) * weight
+		}
+		total += weight
+	}
+	if total == 0 {
+		return nil
+	}
+
+	avg := make([]float32, dim)
+	for i := range out {
+		avg[i] = float32(out[i] / total)
+	}
+	return avg
+}
+
+func weightedAverage(vectors [][]float32, weights []float64) []float32 {
+	if len(vectors) == 0 || len(vectors[0]) == 0 {
+		return nil
+	}
+	dim := len(vectors[0])
+
+	out := make([]float64, dim)
+	total := 0.0
+	for i, v := range vectors {
+		if len(v) != dim {
+			continue
+		}
+		for j := 0; j < dim; j++ {
+			out[j] += float64(v[j]) * weights[i]
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		return nil
+	}
+
+	avg := make([]float32, dim)
+	for i := range out {
+		avg[i] = float32(out[i] / total)
+	}
+	return avg
+}
+
 func blendVectors(recent, historical []float32, recentWeight float32) []float32 {
 	if len(recent) == 0 {
 		return historical
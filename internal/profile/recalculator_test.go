@@ -0,0 +1,43 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/store"
+)
+
+func TestDecayedAverage(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	vectors := []store.FeedbackEmbedding{
+		{Embedding: []float32{1, 0}, CreatedAt: now},
+		{Embedding: []float32{0, 1}, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+	}
+
+	// One half-life old: the stale like counts half as much as the fresh one.
+	avg := decayedAverage(vectors, 10*24*time.Hour, now)
+	require.Len(t, avg, 2)
+	assert.InDelta(t, 2.0/3.0, avg[0], 1e-6)
+	assert.InDelta(t, 1.0/3.0, avg[1], 1e-6)
+
+	// No half-life keeps the plain mean.
+	avg = decayedAverage(vectors, 0, now)
+	assert.InDelta(t, 0.5, avg[0], 1e-6)
+	assert.InDelta(t, 0.5, avg[1], 1e-6)
+
+	// Mismatched dimensions are skipped.
+	avg = decayedAverage(append(vectors, store.FeedbackEmbedding{Embedding: []float32{1}, CreatedAt: now}), 0, now)
+	assert.InDelta(t, 0.5, avg[0], 1e-6)
+
+	assert.Nil(t, decayedAverage(nil, time.Hour, now))
+}
+
+func TestWeightedAverage(t *testing.T) {
+	avg := weightedAverage([][]float32{{1, 0}, {0, 1}}, []float64{3, 1})
+	require.Len(t, avg, 2)
+	assert.InDelta(t, 0.75, avg[0], 1e-6)
+	assert.InDelta(t, 0.25, avg[1], 1e-6)
+	assert.Nil(t, weightedAverage(nil, nil))
+}
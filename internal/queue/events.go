@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/models"
+	"github.com/zyrak/flux/internal/tracing"
+)
+
+// NewArticleEvent is the articles.new payload announcing a stored article,
+// shared by the workers that publish it and the processor that consumes it.
+type NewArticleEvent struct {
+	ArticleID string `json:"article_id"`
+	// EventID correlates this article's worker and processor log lines.
+	EventID string `json:"event_id,omitempty"`
+	// TraceContext carries the article's trace to the processor, which
+	// continues it while processing.
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+}
+
+// BriefingGenerateEvent is the briefing.generate payload asking a daemon-mode
+// briefing-gen to run immediately, shared by the API that publishes it and
+// the daemon that consumes it.
+type BriefingGenerateEvent struct {
+	RequestID   string    `json:"request_id"`
+	RequestedBy string    `json:"requested_by,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// NewEventID returns a random correlation id for one article's trip through
+// the pipeline. Workers stamp it on articles.new so worker and processor log
+// lines for the same article can be grepped together.
+func NewEventID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("evt-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ArticleLogFields returns the log fields identifying an article event.
+// eventID is left out when empty, as on events published before correlation
+// ids existed.
+func ArticleLogFields(eventID, articleID string) log.Fields {
+	fields := log.Fields{"article_id": articleID}
+	if eventID != "" {
+		fields["event_id"] = eventID
+	}
+	return fields
+}
+
+// PublishNewArticle announces a stored article to the processor under a
+// fresh event id, starting the article's trace as "<worker>.publish_article"
+// and carrying its context in the event. Failures are logged here so they
+// carry the event id too.
+func (q *Queue) PublishNewArticle(ctx context.Context, worker string, article *models.Article) error {
+	eventID := NewEventID()
+	logger := log.WithFields(ArticleLogFields(eventID, article.ID)).WithField("source_type", article.SourceType)
+
+	ctx, span := tracing.StartArticle(ctx, worker+".publish_article", article.SourceType, article.ID)
+	err := q.Publish(SubjectArticlesNew, NewArticleEvent{
+		ArticleID:    article.ID,
+		EventID:      eventID,
+		TraceContext: tracing.Inject(ctx),
+	})
+	tracing.End(span, err)
+	if err != nil {
+		logger.WithError(err).Error("Failed to publish articles.new")
+		return err
+	}
+	logger.WithField("url", article.URL).Info("Article queued")
+	return nil
+}
@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,15 +15,26 @@ import (
 const (
 	SubjectArticlesNew       = "articles.new"
 	SubjectArticlesProcessed = "articles.processed"
-	SubjectBriefingGenerate  = "briefing.generate"
+	// SubjectArticlesDeadLetter receives articles the processor gave up on.
+	SubjectArticlesDeadLetter = "articles.dead"
+	SubjectBriefingGenerate   = "briefing.generate"
 )
 
 // Stream names.
 const (
-	StreamArticles = "ARTICLES"
-	StreamBriefing = "BRIEFING"
+	StreamArticles   = "ARTICLES"
+	StreamBriefing   = "BRIEFING"
+	StreamDeadLetter = "DEADLETTER"
 )
 
+// DurableProcessor is the durable consumer the processor drains
+// SubjectArticlesNew with.
+const DurableProcessor = "flux-processor"
+
+// DurableBriefingGen is the durable consumer a daemon-mode briefing-gen
+// drains SubjectBriefingGenerate with.
+const DurableBriefingGen = "briefing-gen"
+
 // Queue wraps a NATS JetStream connection.
 type Queue struct {
 	conn *nats.Conn
@@ -32,6 +44,21 @@ type Queue struct {
 // MessageHandler is a callback for processing received messages.
 type MessageHandler func(data []byte) error
 
+// NewWithConn sets up JetStream streams on an existing connection. The caller
+// keeps ownership of conn and must not call Close on the returned Queue.
+func NewWithConn(conn *nats.Conn) (*Queue, error) {
+	js, err := conn.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("getting JetStream context: %w", err)
+	}
+
+	q := &Queue{conn: conn, js: js}
+	if err := q.ensureStreams(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
 // New connects to NATS and sets up JetStream streams.
 func New(natsURL string) (*Queue, error) {
 	conn, err := nats.Connect(natsURL,
@@ -65,12 +92,14 @@ func New(natsURL string) (*Queue, error) {
 	return q, nil
 }
 
-// ensureStreams creates the required streams if they don't exist.
+// ensureStreams creates the required streams if they don't exist, and updates
+// existing ones whose subjects changed.
 func (q *Queue) ensureStreams() error {
 	streams := []nats.StreamConfig{
 		{
+			// Explicit subjects so articles.dead can live in its own stream.
 			Name:      StreamArticles,
-			Subjects:  []string{"articles.>"},
+			Subjects:  []string{SubjectArticlesNew, SubjectArticlesProcessed},
 			Retention: nats.WorkQueuePolicy,
 			MaxAge:    72 * time.Hour,
 			Storage:   nats.FileStorage,
@@ -82,19 +111,98 @@ func (q *Queue) ensureStreams() error {
 			MaxAge:    24 * time.Hour,
 			Storage:   nats.FileStorage,
 		},
+		{
+			Name:      StreamDeadLetter,
+			Subjects:  []string{SubjectArticlesDeadLetter},
+			Retention: nats.LimitsPolicy,
+			MaxAge:    14 * 24 * time.Hour,
+			Storage:   nats.FileStorage,
+		},
 	}
 
 	for _, cfg := range streams {
-		if _, err := q.js.StreamInfo(cfg.Name); err != nil {
+		info, err := q.js.StreamInfo(cfg.Name)
+		if err != nil {
 			if _, err := q.js.AddStream(&cfg); err != nil {
 				return fmt.Errorf("creating stream %s: %w", cfg.Name, err)
 			}
 			log.WithField("stream", cfg.Name).Info("Created NATS stream")
+			continue
+		}
+		if !sameSubjects(info.Config.Subjects, cfg.Subjects) {
+			updated := info.Config
+			updated.Subjects = cfg.Subjects
+			if _, err := q.js.UpdateStream(&updated); err != nil {
+				return fmt.Errorf("updating stream %s subjects: %w", cfg.Name, err)
+			}
+			log.WithFields(log.Fields{
+				"stream":   cfg.Name,
+				"subjects": cfg.Subjects,
+			}).Info("Updated NATS stream subjects")
 		}
 	}
 	return nil
 }
 
+// ArticlesPending returns how many articles.new messages the processor has
+// not been delivered yet, from its consumer's NumPending. Until the processor
+// first subscribes there is no consumer, so every articles.new message still
+// held by the work-queue stream counts as pending.
+func (q *Queue) ArticlesPending() (uint64, error) {
+	info, err := q.js.ConsumerInfo(StreamArticles, DurableProcessor)
+	if err == nil {
+		return info.NumPending, nil
+	}
+	if !errors.Is(err, nats.ErrConsumerNotFound) {
+		return 0, fmt.Errorf("getting consumer %s info: %w", DurableProcessor, err)
+	}
+
+	stream, err := q.js.StreamInfo(StreamArticles, &nats.StreamInfoRequest{SubjectsFilter: SubjectArticlesNew})
+	if err != nil {
+		return 0, fmt.Errorf("getting stream %s info: %w", StreamArticles, err)
+	}
+	return stream.State.Subjects[SubjectArticlesNew], nil
+}
+
+// IngestBackpressure reports whether a worker should skip its ingestion
+// cycle because more than max articles are waiting for the processor. max <=
+// 0 disables the check. A backlog that cannot be read never throttles.
+func (q *Queue) IngestBackpressure(worker string, max int) bool {
+	return ingestBackpressure(q.ArticlesPending, worker, max)
+}
+
+func ingestBackpressure(pending func() (uint64, error), worker string, max int) bool {
+	if max <= 0 {
+		return false
+	}
+	n, err := pending()
+	if err != nil {
+		log.WithField("worker", worker).WithError(err).Warn("Failed to read articles backlog, ingesting anyway")
+		return false
+	}
+	if n <= uint64(max) {
+		return false
+	}
+	log.WithFields(log.Fields{
+		"worker":  worker,
+		"pending": n,
+		"max":     max,
+	}).Warn("Backpressure engaged: processor backlog above INGEST_BACKPRESSURE_MAX, skipping ingestion cycle")
+	return true
+}
+
+func sameSubjects(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // Publish serializes data as JSON and publishes to the given subject.
 func (q *Queue) Publish(subject string, data interface{}) error {
 	payload, err := json.Marshal(data)
@@ -109,24 +217,78 @@ func (q *Queue) Publish(subject string, data interface{}) error {
 	return nil
 }
 
+// SubscribeConfig tunes how a pull subscription is drained.
+type SubscribeConfig struct {
+	// Concurrency is the number of goroutines handling messages. Defaults to 1.
+	Concurrency int
+	// AckWait is how long a delivered message may go unacknowledged before
+	// JetStream redelivers it. Set it above the handler's timeout; zero keeps
+	// the consumer's setting (30s for a new one).
+	AckWait time.Duration
+	// MaxDeliver is how many times a failing message is attempted before it is
+	// terminated. Zero means redeliver forever.
+	MaxDeliver int
+	// DeadLetterSubject receives a DeadLetter for every terminated message.
+	// Leave empty to only log terminated messages.
+	DeadLetterSubject string
+}
+
+// DeadLetter describes a message that exhausted its delivery attempts.
+type DeadLetter struct {
+	Subject    string          `json:"subject"`
+	Payload    json.RawMessage `json:"payload"`
+	Error      string          `json:"error"`
+	Deliveries uint64          `json:"deliveries"`
+	FailedAt   time.Time       `json:"failed_at"`
+}
+
 // Subscribe creates a durable pull subscription and processes messages with the handler.
 func (q *Queue) Subscribe(ctx context.Context, subject, durable string, handler MessageHandler) error {
-	sub, err := q.js.PullSubscribe(subject, durable)
+	return q.SubscribeWithConfig(ctx, subject, durable, SubscribeConfig{}, handler)
+}
+
+// SubscribeWithConfig creates a durable pull subscription drained by
+// cfg.Concurrency workers. Messages are only fetched for idle workers, so
+// none wait unacknowledged behind a busy handler. Failed messages are NAKed
+// for redelivery until cfg.MaxDeliver attempts, then terminated and sent to
+// cfg.DeadLetterSubject.
+func (q *Queue) SubscribeWithConfig(ctx context.Context, subject, durable string, cfg SubscribeConfig, handler MessageHandler) error {
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	var opts []nats.SubOpt
+	if cfg.AckWait > 0 {
+		if err := q.ensureAckWait(subject, durable, cfg.AckWait); err != nil {
+			return err
+		}
+		opts = append(opts, nats.AckWait(cfg.AckWait))
+	}
+
+	sub, err := q.js.PullSubscribe(subject, durable, opts...)
 	if err != nil {
 		return fmt.Errorf("subscribing to %s: %w", subject, err)
 	}
 
+	// One slot per worker; a slot is held from fetch until the handler
+	// returns.
+	slots := make(chan struct{}, cfg.Concurrency)
 	go func() {
 		for {
 			select {
 			case <-ctx.Done():
 				log.WithField("subject", subject).Info("Subscription shutting down")
 				return
-			default:
+			case slots <- struct{}{}:
+			}
+			idle := 1
+			for idle < cfg.Concurrency && trySend(slots) {
+				idle++
 			}
 
-			msgs, err := sub.Fetch(10, nats.MaxWait(5*time.Second))
+			msgs, err := sub.Fetch(idle, nats.MaxWait(5*time.Second))
 			if err != nil {
+				release(slots, idle)
 				if err == nats.ErrTimeout {
 					continue
 				}
@@ -135,17 +297,12 @@ func (q *Queue) Subscribe(ctx context.Context, subject, durable string, handler
 				continue
 			}
 
+			release(slots, idle-len(msgs))
 			for _, msg := range msgs {
-				if err := handler(msg.Data); err != nil {
-					log.WithError(err).WithField("subject", subject).Error("Error processing message")
-					if err := msg.Nak(); err != nil {
-						log.WithError(err).Warn("Failed to NAK message")
-					}
-					continue
-				}
-				if err := msg.Ack(); err != nil {
-					log.WithError(err).Warn("Failed to ACK message")
-				}
+				go func(msg *nats.Msg) {
+					defer release(slots, 1)
+					q.handleMessage(subject, cfg, handler, msg)
+				}(msg)
 			}
 		}
 	}()
@@ -153,6 +310,145 @@ func (q *Queue) Subscribe(ctx context.Context, subject, durable string, handler
 	return nil
 }
 
+// ensureAckWait sets ackWait on an existing durable consumer, which
+// PullSubscribe would otherwise reject as a configuration mismatch.
+func (q *Queue) ensureAckWait(subject, durable string, ackWait time.Duration) error {
+	stream, err := q.js.StreamNameBySubject(subject)
+	if err != nil {
+		return fmt.Errorf("finding stream for %s: %w", subject, err)
+	}
+	info, err := q.js.ConsumerInfo(stream, durable)
+	if errors.Is(err, nats.ErrConsumerNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting consumer %s info: %w", durable, err)
+	}
+	if info.Config.AckWait == ackWait {
+		return nil
+	}
+	updated := info.Config
+	updated.AckWait = ackWait
+	if _, err := q.js.UpdateConsumer(stream, &updated); err != nil {
+		return fmt.Errorf("updating consumer %s ack wait: %w", durable, err)
+	}
+	log.WithFields(log.Fields{
+		"consumer": durable,
+		"ack_wait": ackWait,
+	}).Info("Updated NATS consumer ack wait")
+	return nil
+}
+
+func trySend(slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func release(slots chan struct{}, n int) {
+	for i := 0; i < n; i++ {
+		<-slots
+	}
+}
+
+func (q *Queue) handleMessage(subject string, cfg SubscribeConfig, handler MessageHandler, msg *nats.Msg) {
+	handlerErr := handler(msg.Data)
+	if handlerErr == nil {
+		if err := msg.Ack(); err != nil {
+			log.WithError(err).Warn("Failed to ACK message")
+		}
+		return
+	}
+
+	var deliveries uint64
+	if meta, err := msg.Metadata(); err == nil {
+		deliveries = meta.NumDelivered
+	}
+
+	if cfg.MaxDeliver <= 0 || deliveries < uint64(cfg.MaxDeliver) {
+		log.WithError(handlerErr).WithFields(log.Fields{
+			"subject":    subject,
+			"deliveries": deliveries,
+		}).Error("Error processing message")
+		if err := msg.Nak(); err != nil {
+			log.WithError(err).Warn("Failed to NAK message")
+		}
+		return
+	}
+
+	log.WithError(handlerErr).WithFields(log.Fields{
+		"subject":     subject,
+		"deliveries":  deliveries,
+		"dead_letter": cfg.DeadLetterSubject,
+	}).Error("Message exceeded max deliveries, terminating")
+
+	if cfg.DeadLetterSubject != "" {
+		payload := json.RawMessage(msg.Data)
+		if !json.Valid(msg.Data) {
+			payload, _ = json.Marshal(string(msg.Data))
+		}
+		dl := DeadLetter{
+			Subject:    subject,
+			Payload:    payload,
+			Error:      handlerErr.Error(),
+			Deliveries: deliveries,
+			FailedAt:   time.Now().UTC(),
+		}
+		if err := q.Publish(cfg.DeadLetterSubject, dl); err != nil {
+			// Leave the message for redelivery rather than lose it.
+			log.WithError(err).WithField("subject", subject).Error("Failed to publish dead letter")
+			if err := msg.Nak(); err != nil {
+				log.WithError(err).Warn("Failed to NAK message")
+			}
+			return
+		}
+	}
+
+	if err := msg.Term(); err != nil {
+		log.WithError(err).Warn("Failed to terminate message")
+	}
+}
+
+// DeadLetterEntry is a stored dead letter with its stream sequence.
+type DeadLetterEntry struct {
+	Sequence uint64 `json:"sequence"`
+	DeadLetter
+}
+
+// ListDeadLetters returns up to limit dead letters, newest first.
+func (q *Queue) ListDeadLetters(limit int) ([]DeadLetterEntry, error) {
+	info, err := q.js.StreamInfo(StreamDeadLetter)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s stream info: %w", StreamDeadLetter, err)
+	}
+
+	out := make([]DeadLetterEntry, 0)
+	if info.State.Msgs == 0 {
+		return out, nil
+	}
+
+	for seq := info.State.LastSeq; seq >= info.State.FirstSeq && seq > 0 && len(out) < limit; seq-- {
+		raw, err := q.js.GetMsg(StreamDeadLetter, seq)
+		if err != nil {
+			if errors.Is(err, nats.ErrMsgNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("reading dead letter %d: %w", seq, err)
+		}
+
+		entry := DeadLetterEntry{Sequence: seq}
+		if err := json.Unmarshal(raw.Data, &entry.DeadLetter); err != nil {
+			log.WithError(err).WithField("sequence", seq).Warn("Skipping malformed dead letter")
+			continue
+		}
+		out = append(out, entry)
+	}
+	return out, nil
+}
+
 // Close gracefully closes the NATS connection.
 func (q *Queue) Close() {
 	if err := q.conn.Drain(); err != nil {
@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestBackpressure(t *testing.T) {
+	calls := 0
+	pending := func(n uint64, err error) func() (uint64, error) {
+		return func() (uint64, error) {
+			calls++
+			return n, err
+		}
+	}
+
+	assert.False(t, ingestBackpressure(pending(5000, nil), "rss", 0), "disabled")
+	assert.Zero(t, calls, "disabled check does not query JetStream")
+
+	assert.False(t, ingestBackpressure(pending(2000, nil), "rss", 2000))
+	assert.True(t, ingestBackpressure(pending(2001, nil), "rss", 2000))
+	assert.False(t, ingestBackpressure(pending(0, errors.New("nats: timeout")), "rss", 10))
+}
+
+func TestArticleLogFields(t *testing.T) {
+	id := NewEventID()
+	assert.Len(t, id, 16)
+	assert.NotEqual(t, id, NewEventID())
+
+	assert.Equal(t, log.Fields{"article_id": "a1", "event_id": id}, ArticleLogFields(id, "a1"))
+	assert.Equal(t, log.Fields{"article_id": "a1"}, ArticleLogFields("", "a1"))
+}
+
+func TestNewArticleEventWireFormat(t *testing.T) {
+	data, err := json.Marshal(NewArticleEvent{ArticleID: "a1"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"article_id":"a1"}`, string(data))
+
+	var evt NewArticleEvent
+	require.NoError(t, json.Unmarshal([]byte(`{"article_id":"a1","event_id":"e1","trace_context":{"traceparent":"00-x"}}`), &evt))
+	assert.Equal(t, NewArticleEvent{ArticleID: "a1", EventID: "e1", TraceContext: map[string]string{"traceparent": "00-x"}}, evt)
+}
+
+func TestWorkerSlots(t *testing.T) {
+	slots := make(chan struct{}, 2)
+	assert.True(t, trySend(slots))
+	assert.True(t, trySend(slots))
+	assert.False(t, trySend(slots), "no idle worker left to fetch for")
+
+	release(slots, 1)
+	assert.Len(t, slots, 1)
+	assert.True(t, trySend(slots))
+	release(slots, 2)
+	assert.Empty(t, slots)
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(t *testing.T, cfg Config) (*Limiter, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	l, err := New(rdb, cfg)
+	require.NoError(t, err)
+	return l, mr
+}
+
+func TestConcurrencySlots(t *testing.T) {
+	l, mr := newTestLimiter(t, Config{MaxConcurrent: map[string]int{"example.com": 2}})
+	ctx := context.Background()
+
+	first, err := l.acquireSlot(ctx, "example.com")
+	require.NoError(t, err)
+	second, err := l.acquireSlot(ctx, "example.com")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{first, second}, mustMembers(t, mr, "flux:concurrency_slots:example.com"))
+	assert.Greater(t, mr.TTL("flux:concurrency_slots:example.com"), time.Duration(0))
+
+	// Third caller blocks until its context expires.
+	blocked, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+	defer cancel()
+	_, err = l.acquireSlot(blocked, "example.com")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, mustMembers(t, mr, "flux:concurrency_slots:example.com"), 2)
+
+	l.Release(ctx, "example.com", first)
+	third, err := l.acquireSlot(ctx, "example.com")
+	require.NoError(t, err)
+
+	l.Release(ctx, "example.com", second)
+	l.Release(ctx, "example.com", third)
+	assert.False(t, mr.Exists("flux:concurrency_slots:example.com"))
+
+	// Releasing twice frees nobody else's slot.
+	fourth, err := l.acquireSlot(ctx, "example.com")
+	require.NoError(t, err)
+	l.Release(ctx, "example.com", third)
+	assert.Equal(t, []string{fourth}, mustMembers(t, mr, "flux:concurrency_slots:example.com"))
+}
+
+func TestConcurrencyReclaimsLeakedSlot(t *testing.T) {
+	l, mr := newTestLimiter(t, Config{MaxConcurrent: map[string]int{"example.com": 1}})
+	var offset atomic.Int64
+	l.now = func() time.Time { return time.Now().Add(time.Duration(offset.Load())) }
+	ctx := context.Background()
+
+	// The holder crashes without releasing.
+	_, err := l.acquireSlot(ctx, "example.com")
+	require.NoError(t, err)
+
+	acquired := make(chan error, 1)
+	go func() {
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_, err := l.acquireSlot(waitCtx, "example.com")
+		acquired <- err
+	}()
+
+	// A few failed polls must not keep the leaked slot alive.
+	time.Sleep(3 * concurrencyPollInterval)
+	select {
+	case err := <-acquired:
+		t.Fatalf("acquired a slot while the leaked one was live: %v", err)
+	default:
+	}
+
+	offset.Store(int64(concurrencySlotTTL + time.Second))
+	require.NoError(t, <-acquired)
+	assert.Len(t, mustMembers(t, mr, "flux:concurrency_slots:example.com"), 1)
+}
+
+func TestConcurrencyUnlimitedByDefault(t *testing.T) {
+	l, mr := newTestLimiter(t, Config{})
+	ctx := context.Background()
+
+	for i := 0; i < 50; i++ {
+		holder, err := l.acquireSlot(ctx, "example.com")
+		require.NoError(t, err)
+		assert.Empty(t, holder)
+	}
+	l.Release(ctx, "example.com", "")
+	assert.False(t, mr.Exists("flux:concurrency_slots:example.com"))
+}
+
+func mustMembers(t *testing.T, mr *miniredis.Miniredis, key string) []string {
+	t.Helper()
+	members, err := mr.ZMembers(key)
+	require.NoError(t, err)
+	return members
+}
+
+func TestWaitSkipsZeroJitter(t *testing.T) {
+	l, _ := newTestLimiter(t, Config{
+		Limits: map[string]string{"api.example.com": "100/sec"},
+		Jitter: map[string]string{"api.example.com": "0"},
+	})
+
+	start := time.Now()
+	_, err := l.Wait(context.Background(), "api.example.com")
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
@@ -2,20 +2,30 @@ package ratelimit
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/retry"
 )
 
-// NewHTTPClient builds an HTTP client that enforces the shared Redis-backed limiter.
+// NewHTTPClient builds an HTTP client that enforces the shared Redis-backed
+// limiter and routes through the limiter's configured proxy, if any.
 func NewHTTPClient(limiter *Limiter, timeout time.Duration) *http.Client {
+	base := http.DefaultTransport
+	if limiter.proxy != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(limiter.proxy)
+		base = transport
+	}
 	return &http.Client{
 		Timeout: timeout,
 		Transport: &rateLimitedTransport{
-			base:    http.DefaultTransport,
+			base:    base,
 			limiter: limiter,
 		},
 	}
@@ -37,7 +47,8 @@ func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, err
 	}
 
 	waitStart := time.Now()
-	if err := t.limiter.Wait(req.Context(), domain); err != nil {
+	holder, err := t.limiter.Wait(req.Context(), domain)
+	if err != nil {
 		return nil, err
 	}
 
@@ -57,11 +68,16 @@ func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, err
 
 	resp, err := t.base.RoundTrip(clonedReq)
 	if err != nil {
+		t.limiter.Release(req.Context(), domain, holder)
 		return nil, err
 	}
+	// Hold the concurrency slot until the caller is done reading the body.
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: func() {
+		t.limiter.Release(req.Context(), domain, holder)
+	}}
 
 	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden {
-		t.limiter.RecordError(req.Context(), domain, resp.StatusCode, parseRetryAfter(resp.Header.Get("Retry-After")))
+		t.limiter.RecordError(req.Context(), domain, resp.StatusCode, retryAfterFromResponse(resp, time.Now()))
 	} else if resp.StatusCode < 500 {
 		t.limiter.ResetBackoff(req.Context(), domain)
 	}
@@ -69,23 +85,46 @@ func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, err
 	return resp, nil
 }
 
-func parseRetryAfter(value string) time.Duration {
-	value = strings.TrimSpace(value)
-	if value == "" {
-		return 0
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+// retryAfterFromResponse picks the backoff hinted by a throttled response.
+// Retry-After wins; otherwise an exhausted X-RateLimit-Remaining with an
+// X-RateLimit-Reset (GitHub sends a Unix timestamp, Reddit seconds until
+// reset) is used. Zero means no hint, so the limiter falls back to
+// exponential backoff.
+func retryAfterFromResponse(resp *http.Response, now time.Time) time.Duration {
+	if d := retry.ParseRetryAfter(resp.Header.Get("Retry-After"), now); d > 0 {
+		return d
 	}
 
-	seconds, err := strconv.Atoi(value)
-	if err == nil && seconds > 0 {
-		return time.Duration(seconds) * time.Second
+	remaining := strings.TrimSpace(resp.Header.Get("X-RateLimit-Remaining"))
+	if remaining == "" {
+		return 0
+	}
+	if left, err := strconv.ParseFloat(remaining, 64); err != nil || left >= 1 {
+		return 0
 	}
 
-	when, err := http.ParseTime(value)
-	if err != nil {
+	reset, err := strconv.ParseFloat(strings.TrimSpace(resp.Header.Get("X-RateLimit-Reset")), 64)
+	if err != nil || reset <= 0 {
 		return 0
 	}
-	if d := time.Until(when); d > 0 {
-		return d
+	// Anything past 2001-09-09 is an epoch timestamp rather than a delta.
+	if reset > 1e9 {
+		if d := time.Unix(int64(reset), 0).Sub(now); d > 0 {
+			return d
+		}
+		return 0
 	}
-	return 0
+	return time.Duration(reset * float64(time.Second))
 }
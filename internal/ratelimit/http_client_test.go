@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfterFromResponse(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	resp := func(headers map[string]string) *http.Response {
+		h := http.Header{}
+		for k, v := range headers {
+			h.Set(k, v)
+		}
+		return &http.Response{Header: h}
+	}
+
+	assert.Equal(t, 30*time.Second, retryAfterFromResponse(resp(map[string]string{
+		"Retry-After":           "30",
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     "600",
+	}), now))
+
+	// GitHub: epoch reset timestamp.
+	assert.Equal(t, 90*time.Second, retryAfterFromResponse(resp(map[string]string{
+		"X-RateLimit-Remaining": "0",
+		"X-RateLimit-Reset":     strconv.FormatInt(now.Add(90*time.Second).Unix(), 10),
+	}), now))
+
+	// Reddit: seconds until reset, remaining reported as a float.
+	assert.Equal(t, 42*time.Second, retryAfterFromResponse(resp(map[string]string{
+		"X-RateLimit-Remaining": "0.0",
+		"X-RateLimit-Reset":     "42",
+	}), now))
+
+	// Quota left: a 403 is not a rate limit hint.
+	assert.Zero(t, retryAfterFromResponse(resp(map[string]string{
+		"X-RateLimit-Remaining": "12",
+		"X-RateLimit-Reset":     "42",
+	}), now))
+
+	assert.Zero(t, retryAfterFromResponse(resp(nil), now))
+}
+
+func TestNewHTTPClientUsesConfiguredProxy(t *testing.T) {
+	limiter, err := New(nil, Config{Proxy: "http://proxy.local:3128"})
+	require.NoError(t, err)
+
+	client := NewHTTPClient(limiter, time.Second)
+	transport, ok := client.Transport.(*rateLimitedTransport).base.(*http.Transport)
+	require.True(t, ok)
+	require.NotNil(t, transport.Proxy)
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/feed.xml", nil)
+	require.NoError(t, err)
+	proxyURL, err := transport.Proxy(req)
+	require.NoError(t, err)
+	require.NotNil(t, proxyURL)
+	assert.Equal(t, "http://proxy.local:3128", proxyURL.String())
+
+	// Without an explicit proxy the default transport keeps honouring the
+	// HTTP_PROXY/HTTPS_PROXY environment.
+	limiter, err = New(nil, Config{})
+	require.NoError(t, err)
+	assert.Same(t, http.DefaultTransport, NewHTTPClient(limiter, time.Second).Transport.(*rateLimitedTransport).base)
+
+	for _, invalid := range []string{"proxy.local:3128", "http://", "://bad"} {
+		_, err := New(nil, Config{Proxy: invalid})
+		assert.Error(t, err, invalid)
+	}
+}
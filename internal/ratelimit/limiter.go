@@ -2,9 +2,13 @@ package ratelimit
 
 import (
 	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -13,14 +17,47 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// ErrInBackoff is matched (via errors.Is) by errors returned while a domain
+// is backing off after a 429/403.
+var ErrInBackoff = errors.New("domain in backoff")
+
+// BackoffError reports that requests to Domain are suspended for Remaining.
+type BackoffError struct {
+	Domain    string
+	Remaining time.Duration
+}
+
+func (e *BackoffError) Error() string {
+	return fmt.Sprintf("domain %s is in backoff for %v", e.Domain, e.Remaining.Round(time.Second))
+}
+
+// Is lets errors.Is(err, ErrInBackoff) match any BackoffError.
+func (e *BackoffError) Is(target error) bool {
+	return target == ErrInBackoff
+}
+
 // Limiter provides centralized rate limiting backed by Redis.
 // All outgoing HTTP requests must pass through this limiter.
 type Limiter struct {
-	rdb       *redis.Client
-	limits    map[string]rateSpec
-	userAgent string
+	rdb           *redis.Client
+	limits        map[string]rateSpec
+	maxConcurrent map[string]int
+	jitter        map[string]jitterSpec
+	userAgent     string
+	proxy         *url.URL
+	// now stamps concurrency slots; tests replace it to expire them.
+	now func() time.Time
 }
 
+// jitterSpec is the random pause range applied after a token is granted.
+type jitterSpec struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// defaultJitter spaces out content fetches when no jitter is configured.
+var defaultJitter = jitterSpec{Min: time.Second, Max: 3 * time.Second}
+
 // rateSpec defines a rate limit: maxRequests per period.
 type rateSpec struct {
 	MaxRequests int
@@ -30,10 +67,44 @@ type rateSpec struct {
 // Config holds rate limiter configuration.
 type Config struct {
 	// Limits maps domain -> "requests/period" (e.g. "60/min", "5000/hour")
-	Limits    map[string]string
+	Limits map[string]string
+	// MaxConcurrent maps domain -> max in-flight requests across all workers.
+	// Domains without an entry (and no "default") are unlimited.
+	MaxConcurrent map[string]int
+	// Jitter maps domain -> pause range after each granted request, e.g.
+	// "1s-3s", "500ms" or "0" to disable. Falls back to "default", then 1s-3s.
+	Jitter    map[string]string
 	UserAgent string
+	// Proxy routes every outbound request through this URL. Empty falls back
+	// to HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment.
+	Proxy string
 }
 
+// concurrencySlotTTL bounds how long a slot survives if its holder crashes
+// before releasing it.
+const concurrencySlotTTL = 5 * time.Minute
+
+// concurrencyPollInterval is how often Wait retries a full semaphore.
+const concurrencyPollInterval = 200 * time.Millisecond
+
+// Acquires a slot for holder ARGV[4] if fewer than ARGV[1] holders remain
+// after evicting those acquired more than ARGV[3] ms before now (ARGV[2]).
+// Each holder is a ZSET member scored by its acquire time, so a crashed
+// holder's slot expires on its own even while others keep polling. Returns 1
+// on success. The key differs from the old flux:concurrency: counter so a
+// leftover string value cannot fail the script with WRONGTYPE.
+var acquireSlotScript = redis.NewScript(`
+local now = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - ttl)
+if redis.call('ZCARD', KEYS[1]) >= tonumber(ARGV[1]) then
+    return 0
+end
+redis.call('ZADD', KEYS[1], now, ARGV[4])
+redis.call('PEXPIRE', KEYS[1], ttl)
+return 1
+`)
+
 // Lua script for atomic token bucket check-and-decrement.
 // Returns 1 if allowed, 0 if rate limited, along with time-to-wait in ms.
 var tokenBucketScript = redis.NewScript(`
@@ -86,17 +157,124 @@ func New(rdb *redis.Client, cfg Config) (*Limiter, error) {
 		userAgent = "Flux/1.0 (+https://github.com/zyrak/flux)"
 	}
 
-	return &Limiter{rdb: rdb, limits: limits, userAgent: userAgent}, nil
+	maxConcurrent := make(map[string]int, len(cfg.MaxConcurrent))
+	for domain, n := range cfg.MaxConcurrent {
+		if n > 0 {
+			maxConcurrent[domain] = n
+		}
+	}
+
+	jitter := make(map[string]jitterSpec, len(cfg.Jitter))
+	for domain, spec := range cfg.Jitter {
+		js, err := parseJitterSpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("parsing jitter for %q: %w", domain, err)
+		}
+		jitter[domain] = js
+	}
+
+	proxy, err := ParseProxy(cfg.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{
+		rdb:           rdb,
+		limits:        limits,
+		maxConcurrent: maxConcurrent,
+		jitter:        jitter,
+		userAgent:     userAgent,
+		proxy:         proxy,
+		now:           time.Now,
+	}, nil
+}
+
+// ParseProxy parses FLUX_OUTBOUND_PROXY. An empty value means no proxy and
+// returns nil.
+func ParseProxy(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid outbound proxy %q", raw)
+	}
+	return u, nil
 }
 
 // Wait blocks until a request to the given domain is allowed, or ctx expires.
-// It also applies jitter between requests to the same domain.
-func (l *Limiter) Wait(ctx context.Context, domain string) error {
+// It also applies jitter between requests to the same domain. When the domain
+// has a concurrency limit, a successful Wait returns the holder of a slot
+// that must be freed with Release once the request completes; otherwise the
+// holder is empty.
+func (l *Limiter) Wait(ctx context.Context, domain string) (string, error) {
 	// Check if domain is in backoff
 	if err := l.checkBackoff(ctx, domain); err != nil {
-		return err
+		return "", err
+	}
+
+	holder, err := l.acquireSlot(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	if err := l.waitForToken(ctx, domain); err != nil {
+		l.Release(ctx, domain, holder)
+		return "", err
+	}
+	return holder, nil
+}
+
+// Release frees the concurrency slot Wait returned for holder. It is a no-op
+// for an empty holder, as returned for domains without a concurrency limit.
+func (l *Limiter) Release(ctx context.Context, domain, holder string) {
+	if holder == "" {
+		return
+	}
+	// Use a fresh context so a cancelled request still frees its slot.
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+	defer cancel()
+	if err := l.rdb.ZRem(releaseCtx, "flux:concurrency_slots:"+domain, holder).Err(); err != nil {
+		log.WithError(err).WithField("domain", domain).Warn("Failed to release concurrency slot")
+	}
+}
+
+func (l *Limiter) acquireSlot(ctx context.Context, domain string) (string, error) {
+	limit, ok := l.getMaxConcurrent(domain)
+	if !ok {
+		return "", nil
 	}
 
+	key := "flux:concurrency_slots:" + domain
+	holder := newSlotHolder()
+	for {
+		acquired, err := acquireSlotScript.Run(ctx, l.rdb, []string{key},
+			limit, l.now().UnixMilli(), concurrencySlotTTL.Milliseconds(), holder).Int()
+		if err != nil {
+			return "", fmt.Errorf("executing concurrency script: %w", err)
+		}
+		if acquired == 1 {
+			return holder, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(concurrencyPollInterval):
+		}
+	}
+}
+
+// newSlotHolder returns a random ID naming one concurrency slot holder.
+func newSlotHolder() string {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return fmt.Sprintf("slot-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+func (l *Limiter) waitForToken(ctx context.Context, domain string) error {
 	spec := l.getSpec(domain)
 	key := "flux:ratelimit:" + domain
 
@@ -117,8 +295,11 @@ func (l *Limiter) Wait(ctx context.Context, domain string) error {
 		}
 
 		if result[0] == 1 {
-			// Allowed — apply jitter (1-3s) for content fetching domains
-			jitter := time.Duration(1000+rand.Intn(2000)) * time.Millisecond
+			// Allowed — apply the domain's jitter (1-3s by default for content fetches)
+			jitter := l.getJitter(domain).duration()
+			if jitter <= 0 {
+				return nil
+			}
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -215,11 +396,64 @@ func (l *Limiter) checkBackoff(ctx context.Context, domain string) error {
 		return nil // Redis error — proceed anyway
 	}
 	if ttl > 0 {
-		return fmt.Errorf("domain %s is in backoff for %v", domain, ttl)
+		return &BackoffError{Domain: domain, Remaining: ttl}
 	}
 	return nil
 }
 
+// getMaxConcurrent returns the concurrency limit for a domain, falling back to "default".
+func (l *Limiter) getMaxConcurrent(domain string) (int, bool) {
+	if n, ok := l.maxConcurrent[domain]; ok {
+		return n, true
+	}
+	n, ok := l.maxConcurrent["default"]
+	return n, ok
+}
+
+// getJitter returns the jitter range for a domain, falling back to "default".
+func (l *Limiter) getJitter(domain string) jitterSpec {
+	if spec, ok := l.jitter[domain]; ok {
+		return spec
+	}
+	if spec, ok := l.jitter["default"]; ok {
+		return spec
+	}
+	return defaultJitter
+}
+
+// duration picks a random pause within the range.
+func (j jitterSpec) duration() time.Duration {
+	if j.Max <= j.Min {
+		return j.Min
+	}
+	return j.Min + time.Duration(rand.Int63n(int64(j.Max-j.Min)+1))
+}
+
+// parseJitterSpec parses "0", "500ms" or "1s-3s" into a jitterSpec.
+func parseJitterSpec(s string) (jitterSpec, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return jitterSpec{}, nil
+	}
+
+	minRaw, maxRaw, isRange := strings.Cut(s, "-")
+	minD, err := time.ParseDuration(strings.TrimSpace(minRaw))
+	if err != nil {
+		return jitterSpec{}, fmt.Errorf("invalid jitter %q: %w", s, err)
+	}
+	maxD := minD
+	if isRange {
+		maxD, err = time.ParseDuration(strings.TrimSpace(maxRaw))
+		if err != nil {
+			return jitterSpec{}, fmt.Errorf("invalid jitter %q: %w", s, err)
+		}
+	}
+	if minD < 0 || maxD < minD {
+		return jitterSpec{}, fmt.Errorf("invalid jitter %q: expected 0 <= min <= max", s)
+	}
+	return jitterSpec{Min: minD, Max: maxD}, nil
+}
+
 // getSpec returns the rate spec for a domain, falling back to "default".
 func (l *Limiter) getSpec(domain string) rateSpec {
 	if spec, ok := l.limits[domain]; ok {
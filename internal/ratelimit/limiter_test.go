@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -77,13 +78,75 @@ func TestUserAgent(t *testing.T) {
 //
 //     // First 5 requests should be fast
 //     for i := 0; i < 5; i++ {
-//         err := limiter.Wait(ctx, "test.com")
+//         _, err := limiter.Wait(ctx, "test.com")
 //         require.NoError(t, err)
 //     }
 //
 //     // 6th request should be delayed
 //     start := time.Now()
-//     err = limiter.Wait(ctx, "test.com")
+//     _, err = limiter.Wait(ctx, "test.com")
 //     require.NoError(t, err)
 //     assert.True(t, time.Since(start) > 100*time.Millisecond)
 // }
+
+func TestBackoffError(t *testing.T) {
+	var err error = &BackoffError{Domain: "oauth.reddit.com", Remaining: 90*time.Second + 400*time.Millisecond}
+	wrapped := fmt.Errorf("fetching r/golang: %w", err)
+
+	assert.ErrorIs(t, wrapped, ErrInBackoff)
+	assert.Equal(t, "domain oauth.reddit.com is in backoff for 1m30s", err.Error())
+
+	var backoff *BackoffError
+	require.ErrorAs(t, wrapped, &backoff)
+	assert.Equal(t, "oauth.reddit.com", backoff.Domain)
+}
+
+func TestParseJitterSpec(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    jitterSpec
+		wantErr bool
+	}{
+		{"0", jitterSpec{}, false},
+		{"", jitterSpec{}, false},
+		{"500ms", jitterSpec{Min: 500 * time.Millisecond, Max: 500 * time.Millisecond}, false},
+		{"1s-3s", jitterSpec{Min: time.Second, Max: 3 * time.Second}, false},
+		{" 200ms - 1s ", jitterSpec{Min: 200 * time.Millisecond, Max: time.Second}, false},
+		{"3s-1s", jitterSpec{}, true},
+		{"fast", jitterSpec{}, true},
+		{"1s-", jitterSpec{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseJitterSpec(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	spec := jitterSpec{Min: 100 * time.Millisecond, Max: 300 * time.Millisecond}
+	for i := 0; i < 1000; i++ {
+		d := spec.duration()
+		assert.GreaterOrEqual(t, d, spec.Min)
+		assert.LessOrEqual(t, d, spec.Max)
+	}
+	assert.Zero(t, jitterSpec{}.duration())
+}
+
+func TestGetJitter(t *testing.T) {
+	l := &Limiter{jitter: map[string]jitterSpec{
+		"hacker-news.firebaseio.com": {},
+	}}
+	assert.Equal(t, jitterSpec{}, l.getJitter("hacker-news.firebaseio.com"))
+	assert.Equal(t, defaultJitter, l.getJitter("example.com"))
+
+	l.jitter["default"] = jitterSpec{Min: time.Second, Max: time.Second}
+	assert.Equal(t, time.Second, l.getJitter("example.com").Min)
+}
@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RequestLimiter throttles inbound requests per client key using the same
+// Redis token bucket as the outbound limiter. Unlike Limiter.Wait it never
+// blocks: callers reject the request when Take reports no token.
+type RequestLimiter struct {
+	rdb  *redis.Client
+	spec rateSpec
+}
+
+// NewRequestLimiter builds a limiter from a "requests/period" spec such as
+// "100/min". An empty spec or "0" returns nil, meaning no limit.
+func NewRequestLimiter(rdb *redis.Client, spec string) (*RequestLimiter, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == "0" {
+		return nil, nil
+	}
+	parsed, err := parseRateSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.MaxRequests <= 0 {
+		return nil, nil
+	}
+	return &RequestLimiter{rdb: rdb, spec: parsed}, nil
+}
+
+// Take consumes a token for key. When the bucket is empty it returns false
+// and how long until the next token is available.
+func (l *RequestLimiter) Take(ctx context.Context, key string) (bool, time.Duration, error) {
+	refillRate := float64(l.spec.MaxRequests) / l.spec.Period.Seconds()
+	now := float64(time.Now().UnixMilli()) / 1000.0
+
+	result, err := tokenBucketScript.Run(ctx, l.rdb, []string{"flux:apilimit:" + key},
+		l.spec.MaxRequests, refillRate, now).Int64Slice()
+	if err != nil {
+		return false, 0, fmt.Errorf("executing rate limit script: %w", err)
+	}
+	if result[0] == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequestLimiterDisabled(t *testing.T) {
+	for _, spec := range []string{"", "0", " ", "0/min"} {
+		l, err := NewRequestLimiter(nil, spec)
+		require.NoError(t, err, spec)
+		assert.Nil(t, l, spec)
+	}
+
+	_, err := NewRequestLimiter(nil, "100/fortnight")
+	assert.Error(t, err)
+}
+
+func TestRequestLimiterTake(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+
+	l, err := NewRequestLimiter(rdb, "2/min")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		ok, _, err := l.Take(ctx, "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+
+	ok, retryAfter, err := l.Take(ctx, "ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Greater(t, retryAfter, time.Duration(0))
+	assert.LessOrEqual(t, retryAfter, 30*time.Second)
+
+	// Other clients have their own bucket.
+	ok, _, err = l.Take(ctx, "ip:10.0.0.2")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
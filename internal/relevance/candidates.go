@@ -0,0 +1,50 @@
+package relevance
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// sectionCandidatesKey is the article metadata key holding the sections a
+// multi-section source's article was scored against.
+const sectionCandidatesKey = "section_candidates"
+
+// SectionCandidate is one section an article was scored against when its
+// source is linked to several sections.
+type SectionCandidate struct {
+	SectionID   string  `json:"section_id"`
+	SectionName string  `json:"section"`
+	Score       float64 `json:"score"`
+}
+
+// WithSectionCandidates returns metadata with section_candidates set to
+// candidates, keeping every other key.
+func WithSectionCandidates(metadata json.RawMessage, candidates []SectionCandidate) (json.RawMessage, error) {
+	m := map[string]interface{}{}
+	if len(metadata) > 0 && string(metadata) != "null" {
+		if err := json.Unmarshal(metadata, &m); err != nil {
+			return nil, fmt.Errorf("decoding article metadata: %w", err)
+		}
+	}
+	m[sectionCandidatesKey] = candidates
+	out, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("encoding article metadata: %w", err)
+	}
+	return out, nil
+}
+
+// SectionCandidatesFromMetadata returns the candidates recorded by
+// WithSectionCandidates, best first, or nil when there are none.
+func SectionCandidatesFromMetadata(metadata json.RawMessage) []SectionCandidate {
+	if len(metadata) == 0 || string(metadata) == "null" {
+		return nil
+	}
+	var m struct {
+		Candidates []SectionCandidate `json:"section_candidates"`
+	}
+	if err := json.Unmarshal(metadata, &m); err != nil {
+		return nil
+	}
+	return m.Candidates
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/zyrak/flux/internal/embeddings"
 	"github.com/zyrak/flux/internal/models"
@@ -15,6 +17,7 @@ import (
 
 const (
 	sectionThresholdConfigKey = "relevance_threshold"
+	sectionThresholdLockedKey = "threshold_locked"
 )
 
 // Config controls relevance scoring and threshold behavior.
@@ -24,6 +27,120 @@ type Config struct {
 	MaxThreshold     float64
 	ThresholdStep    float64
 	SourceBoosts     map[string]float64
+	// FreshnessWeight is the boost a just-published article gets; it decays
+	// exponentially, halving every FreshnessHalfLife. Zero disables the term.
+	FreshnessWeight   float64
+	FreshnessHalfLife time.Duration
+	// AdjustThreshold raises the threshold a step when more than
+	// AdjustUpperBound pending articles pass it and lowers it when fewer than
+	// AdjustLowerBound do; counts in between leave it alone.
+	AdjustUpperBound int
+	AdjustLowerBound int
+	// AdjustMode picks how far each adjustment moves: AdjustModeStep (the
+	// default) or AdjustModeProportional.
+	AdjustMode string
+	// EmbeddingDim is the vector length seed, profile and article embeddings
+	// must have. A mismatch would otherwise score as a silent 0 similarity.
+	// Zero disables the check.
+	EmbeddingDim int
+}
+
+// Threshold adjustment modes.
+const (
+	// AdjustModeStep moves the threshold by ThresholdStep whenever the
+	// pending count leaves the band.
+	AdjustModeStep = "step"
+	// AdjustModeProportional scales the move by how far the pending count is
+	// from the band, so it covers long distances in few runs and makes small
+	// corrections near the band instead of stepping over it.
+	AdjustModeProportional = "proportional"
+)
+
+// maxProportionalSteps caps a proportional adjustment, in ThresholdSteps.
+const maxProportionalSteps = 4.0
+
+// withDefaults fills unset fields with the engine defaults.
+func (c Config) withDefaults() Config {
+	if c.DefaultThreshold <= 0 {
+		c.DefaultThreshold = 0.30
+	}
+	if c.MinThreshold <= 0 {
+		c.MinThreshold = 0.15
+	}
+	if c.MaxThreshold <= 0 {
+		c.MaxThreshold = 0.60
+	}
+	if c.ThresholdStep <= 0 {
+		c.ThresholdStep = 0.05
+	}
+	if c.FreshnessHalfLife <= 0 {
+		c.FreshnessHalfLife = 24 * time.Hour
+	}
+	if c.AdjustUpperBound <= 0 {
+		c.AdjustUpperBound = 50
+	}
+	if c.AdjustLowerBound <= 0 {
+		c.AdjustLowerBound = 5
+	}
+	if c.AdjustMode == "" {
+		c.AdjustMode = AdjustModeStep
+	}
+	return c
+}
+
+// Validate reports settings NewEngine cannot work with.
+func (c Config) Validate() error {
+	c = c.withDefaults()
+	if c.AdjustLowerBound > c.AdjustUpperBound {
+		return fmt.Errorf("threshold adjust lower bound %d is above upper bound %d", c.AdjustLowerBound, c.AdjustUpperBound)
+	}
+	if c.AdjustMode != AdjustModeStep && c.AdjustMode != AdjustModeProportional {
+		return fmt.Errorf("unknown threshold adjust mode %q (want %s or %s)", c.AdjustMode, AdjustModeStep, AdjustModeProportional)
+	}
+	if c.EmbeddingDim < 0 {
+		return fmt.Errorf("embedding dimension %d is negative", c.EmbeddingDim)
+	}
+	return nil
+}
+
+// checkDimension reports an embedding whose length is not EmbeddingDim.
+// Empty vectors are left to the callers, which treat them as missing.
+func (c Config) checkDimension(what string, v []float32) error {
+	if c.EmbeddingDim <= 0 || len(v) == 0 || len(v) == c.EmbeddingDim {
+		return nil
+	}
+	return fmt.Errorf("%s has %d dimensions, want %d (EMBEDDINGS_STORE_DIMENSION)", what, len(v), c.EmbeddingDim)
+}
+
+// adjustedThreshold is the threshold AdjustThreshold moves to when pending
+// articles pass current: up above AdjustUpperBound, down below
+// AdjustLowerBound, clamped to the configured range. Step mode moves one
+// ThresholdStep; proportional mode moves log2(count/bound) steps, capped at
+// maxProportionalSteps.
+func (c Config) adjustedThreshold(current float64, pending int) float64 {
+	var steps float64
+	switch {
+	case pending > c.AdjustUpperBound:
+		steps = 1
+		if c.AdjustMode == AdjustModeProportional {
+			steps = proportionalSteps(float64(pending) / float64(c.AdjustUpperBound))
+		}
+	case pending < c.AdjustLowerBound:
+		steps = -1
+		if c.AdjustMode == AdjustModeProportional {
+			steps = -proportionalSteps(float64(c.AdjustLowerBound) / math.Max(float64(pending), 1))
+		}
+	default:
+		return current
+	}
+	return clamp(current+steps*c.ThresholdStep, c.MinThreshold, c.MaxThreshold)
+}
+
+// proportionalSteps converts how many times too many (or too few) articles
+// pass the threshold into a number of ThresholdSteps. The log keeps moves
+// small near the band, where the step mode tends to overshoot.
+func proportionalSteps(ratio float64) float64 {
+	return math.Min(math.Log2(ratio), maxProportionalSteps)
 }
 
 // Result is the output of relevance evaluation for a single article.
@@ -34,11 +151,31 @@ type Result struct {
 	Threshold      float64
 	Status         string
 	SourceID       string
+	// SectionCandidates holds the chosen section and the runner-up, best
+	// first, when the article's source is linked to several sections.
+	SectionCandidates []SectionCandidate
+}
+
+// Explanation breaks a Result down into the terms of
+// RelevanceScore = PositiveScore - 0.5*NegativeScore + SourceBoost + FreshnessBoost.
+type Explanation struct {
+	Result
+	PositiveScore  float64
+	NegativeScore  float64
+	SourceBoost    float64
+	FreshnessBoost float64
+	// PositiveSource is "profile" when the learned profile was used and
+	// "seed" when the section's seed keywords stood in for it.
+	PositiveSource string
 }
 
 type sectionState struct {
-	section       *models.Section
-	seedEmbedding []float32
+	section *models.Section
+	// seeds holds one centroid per seed keyword group.
+	seeds []seedVector
+	// negativeSeed is the centroid of config.negative_keywords, a cold-start
+	// baseline for the negative profile.
+	negativeSeed []float32
 }
 
 // Engine encapsulates section assignment and relevance scoring.
@@ -61,18 +198,10 @@ type Engine struct {
 
 // NewEngine initializes section/seed caches and source mappings.
 func NewEngine(ctx context.Context, st *store.Store, embedClient *embeddings.Client, cfg Config) (*Engine, error) {
-	if cfg.DefaultThreshold <= 0 {
-		cfg.DefaultThreshold = 0.30
-	}
-	if cfg.MinThreshold <= 0 {
-		cfg.MinThreshold = 0.15
-	}
-	if cfg.MaxThreshold <= 0 {
-		cfg.MaxThreshold = 0.60
-	}
-	if cfg.ThresholdStep <= 0 {
-		cfg.ThresholdStep = 0.05
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
+	cfg = cfg.withDefaults()
 
 	engine := &Engine{
 		store:          st,
@@ -102,9 +231,12 @@ func (e *Engine) loadSections(ctx context.Context) error {
 		return fmt.Errorf("listing sections: %w", err)
 	}
 
+	// group indexes state.seeds; negativeGroup marks negative_keywords.
 	type keywordRef struct {
 		sectionID string
+		group     int
 	}
+	const negativeGroup = -1
 	var allKeywords []string
 	var refs []keywordRef
 
@@ -118,13 +250,18 @@ func (e *Engine) loadSections(ctx context.Context) error {
 		e.sectionOrder = append(e.sectionOrder, sec.ID)
 		e.thresholds[sec.ID] = e.thresholdFromConfig(sec.Config)
 
-		for _, keyword := range sec.SeedKeywords {
-			keyword = strings.TrimSpace(keyword)
-			if keyword == "" {
-				continue
+		groups := SeedGroups(sec)
+		state.seeds = make([]seedVector, len(groups))
+		for g, group := range groups {
+			state.seeds[g].weight = group.Weight
+			for _, keyword := range group.Keywords {
+				allKeywords = append(allKeywords, keyword)
+				refs = append(refs, keywordRef{sectionID: sec.ID, group: g})
 			}
+		}
+		for _, keyword := range NegativeKeywords(sec) {
 			allKeywords = append(allKeywords, keyword)
-			refs = append(refs, keywordRef{sectionID: sec.ID})
+			refs = append(refs, keywordRef{sectionID: sec.ID, group: negativeGroup})
 		}
 	}
 
@@ -145,17 +282,28 @@ func (e *Engine) loadSections(ctx context.Context) error {
 	if len(embs) != len(allKeywords) {
 		return fmt.Errorf("seed embeddings count mismatch: expected=%d got=%d", len(allKeywords), len(embs))
 	}
+	for i, emb := range embs {
+		if err := e.cfg.checkDimension(fmt.Sprintf("seed keyword %q embedding", allKeywords[i]), emb); err != nil {
+			return err
+		}
+	}
 
-	bySection := make(map[string][][]float32)
-	for i := range refs {
-		bySection[refs[i].sectionID] = append(bySection[refs[i].sectionID], embs[i])
+	byGroup := make(map[keywordRef][][]float32)
+	for i, ref := range refs {
+		byGroup[ref] = append(byGroup[ref], embs[i])
 	}
-	for sectionID, vectors := range bySection {
-		state := e.sectionsByID[sectionID]
+	for ref, vectors := range byGroup {
+		state := e.sectionsByID[ref.sectionID]
 		if state == nil {
 			continue
 		}
-		state.seedEmbedding = averageVector(vectors)
+		if ref.group == negativeGroup {
+			state.negativeSeed = averageVector(vectors)
+			continue
+		}
+		seed := &state.seeds[ref.group]
+		seed.embedding = averageVector(vectors)
+		seed.norm = embeddings.VectorNorm(seed.embedding)
 	}
 
 	return nil
@@ -187,7 +335,20 @@ func (e *Engine) loadSources(ctx context.Context) error {
 
 // EvaluateArticle assigns section + relevance score for an article embedding.
 func (e *Engine) EvaluateArticle(ctx context.Context, article *models.Article, articleEmbedding []float32) (*Result, error) {
-	sectionID, sourceID, err := e.assignSection(article, articleEmbedding)
+	exp, err := e.ExplainArticle(ctx, article, articleEmbedding)
+	if err != nil {
+		return nil, err
+	}
+	return &exp.Result, nil
+}
+
+// ExplainArticle runs the same evaluation as EvaluateArticle and also returns
+// the component scores behind the result.
+func (e *Engine) ExplainArticle(ctx context.Context, article *models.Article, articleEmbedding []float32) (*Explanation, error) {
+	if err := e.cfg.checkDimension("article embedding", articleEmbedding); err != nil {
+		return nil, err
+	}
+	sectionID, sourceID, candidates, err := e.assignSection(article, articleEmbedding)
 	if err != nil {
 		return nil, err
 	}
@@ -201,23 +362,21 @@ func (e *Engine) EvaluateArticle(ctx context.Context, article *models.Article, a
 	if err != nil {
 		return nil, fmt.Errorf("loading section profile %s: %w", sectionID, err)
 	}
-
-	positiveEmbedding := state.seedEmbedding
-	var negativeEmbedding []float32
 	if profile != nil {
-		if len(profile.PositiveEmbedding) > 0 {
-			positiveEmbedding = profile.PositiveEmbedding
+		if err := e.cfg.checkDimension("positive profile of section "+sectionID, profile.PositiveEmbedding); err != nil {
+			return nil, err
 		}
-		if len(profile.NegativeEmbedding) > 0 {
-			negativeEmbedding = profile.NegativeEmbedding
+		if err := e.cfg.checkDimension("negative profile of section "+sectionID, profile.NegativeEmbedding); err != nil {
+			return nil, err
 		}
 	}
 
-	positiveScore := embeddings.CosineSimilarity(articleEmbedding, positiveEmbedding)
-	negativeScore := embeddings.CosineSimilarity(articleEmbedding, negativeEmbedding)
+	positiveScore, negativeScore := profileScores(state, profile, articleEmbedding)
 	sourceBoost := e.resolveSourceBoost(sourceID, article.SourceType)
 
-	relevanceScore := positiveScore - (negativeScore * 0.5) + sourceBoost
+	freshness := e.freshnessBoost(article, time.Now())
+
+	relevanceScore := positiveScore - (negativeScore * 0.5) + sourceBoost + freshness
 	threshold := e.ThresholdBySectionID(sectionID)
 
 	status := models.StatusPending
@@ -225,17 +384,63 @@ func (e *Engine) EvaluateArticle(ctx context.Context, article *models.Article, a
 		status = models.StatusArchived
 	}
 
-	return &Result{
-		SectionID:      sectionID,
-		SectionName:    state.section.Name,
-		RelevanceScore: relevanceScore,
-		Threshold:      threshold,
-		Status:         status,
-		SourceID:       sourceID,
+	positiveSource := "seed"
+	if profile != nil && len(profile.PositiveEmbedding) > 0 {
+		positiveSource = "profile"
+	}
+
+	return &Explanation{
+		Result: Result{
+			SectionID:         sectionID,
+			SectionName:       state.section.Name,
+			RelevanceScore:    relevanceScore,
+			Threshold:         threshold,
+			Status:            status,
+			SourceID:          sourceID,
+			SectionCandidates: candidates,
+		},
+		PositiveScore:  positiveScore,
+		NegativeScore:  negativeScore,
+		SourceBoost:    sourceBoost,
+		FreshnessBoost: freshness,
+		PositiveSource: positiveSource,
 	}, nil
 }
 
-func (e *Engine) assignSection(article *models.Article, articleEmbedding []float32) (sectionID, sourceID string, err error) {
+// profileScores returns the article's similarity to the section's positive
+// and negative profiles. Until feedback builds a learned profile, the positive
+// side uses the best matching seed group; the negative_keywords baseline is
+// averaged with any learned negative profile.
+func profileScores(state *sectionState, profile *models.SectionProfile, articleEmbedding []float32) (positive, negative float64) {
+	var positiveEmbedding, negativeEmbedding []float32
+	if profile != nil {
+		positiveEmbedding = profile.PositiveEmbedding
+		negativeEmbedding = profile.NegativeEmbedding
+	}
+
+	if len(positiveEmbedding) > 0 {
+		positive = embeddings.CosineSimilarity(articleEmbedding, positiveEmbedding)
+	} else {
+		positive, _ = seedScore(articleEmbedding, embeddings.VectorNorm(articleEmbedding), state.seeds)
+	}
+
+	switch {
+	case len(state.negativeSeed) == 0:
+	case len(negativeEmbedding) == len(state.negativeSeed):
+		negativeEmbedding = averageVector([][]float32{state.negativeSeed, negativeEmbedding})
+	default:
+		negativeEmbedding = state.negativeSeed
+	}
+	negative = embeddings.CosineSimilarity(articleEmbedding, negativeEmbedding)
+	return positive, negative
+}
+
+// assignSection picks the section whose seeds best match the article among
+// those linked to its source, or among all sections when the source has no
+// links. For a source linked to several sections it also returns the winner
+// and runner-up with their seed scores, so the briefing classifier can see
+// how close the call was.
+func (e *Engine) assignSection(article *models.Article, articleEmbedding []float32) (sectionID, sourceID string, candidates []SectionCandidate, err error) {
 	sourceID = e.resolveSourceID(article)
 	var candidateSectionIDs []string
 	if sourceID != "" {
@@ -243,34 +448,47 @@ func (e *Engine) assignSection(article *models.Article, articleEmbedding []float
 	}
 
 	if len(candidateSectionIDs) == 1 {
-		return candidateSectionIDs[0], sourceID, nil
+		return candidateSectionIDs[0], sourceID, nil, nil
 	}
+	multiLinked := len(candidateSectionIDs) > 1
 	if len(candidateSectionIDs) == 0 {
 		candidateSectionIDs = append(candidateSectionIDs, e.sectionOrder...)
 	}
 
-	bestSectionID := ""
-	bestScore := -2.0
+	var best, runnerUp *SectionCandidate
+	articleNorm := embeddings.VectorNorm(articleEmbedding)
 	for _, secID := range candidateSectionIDs {
 		state := e.sectionsByID[secID]
 		if state == nil {
 			continue
 		}
-		score := embeddings.CosineSimilarity(articleEmbedding, state.seedEmbedding)
-		if score > bestScore {
-			bestScore = score
-			bestSectionID = secID
+		score, ok := seedScore(articleEmbedding, articleNorm, state.seeds)
+		if !ok {
+			score = 0
+		}
+		c := &SectionCandidate{SectionID: secID, SectionName: state.section.Name, Score: score}
+		switch {
+		case best == nil || score > best.Score:
+			best, runnerUp = c, best
+		case runnerUp == nil || score > runnerUp.Score:
+			runnerUp = c
 		}
 	}
 
-	if bestSectionID == "" {
+	if best == nil {
 		if len(e.sectionOrder) == 0 {
-			return "", sourceID, fmt.Errorf("no enabled sections available")
+			return "", sourceID, nil, fmt.Errorf("no enabled sections available")
 		}
-		bestSectionID = e.sectionOrder[0]
+		return e.sectionOrder[0], sourceID, nil, nil
 	}
 
-	return bestSectionID, sourceID, nil
+	if multiLinked {
+		candidates = append(candidates, *best)
+		if runnerUp != nil {
+			candidates = append(candidates, *runnerUp)
+		}
+	}
+	return best.SectionID, sourceID, candidates, nil
 }
 
 func (e *Engine) resolveSourceID(article *models.Article) string {
@@ -286,19 +504,29 @@ func (e *Engine) resolveSourceID(article *models.Article) string {
 
 // AdjustThreshold applies the dynamic threshold rules and persists changes.
 func (e *Engine) AdjustThreshold(ctx context.Context, sectionID string) (float64, bool, error) {
+	// Re-read the section so thresholds set via the API, pinned or not, take
+	// effect without a restart and are the base of the next adjustment.
+	sec, err := e.store.GetSectionByID(ctx, sectionID)
+	if err != nil {
+		return e.ThresholdBySectionID(sectionID), false, err
+	}
+	if sec != nil {
+		stored := e.thresholdFromConfig(sec.Config)
+		e.mu.Lock()
+		e.thresholds[sectionID] = stored
+		e.mu.Unlock()
+		if thresholdLocked(sec.Config) {
+			return stored, false, nil
+		}
+	}
+
 	current := e.ThresholdBySectionID(sectionID)
 	count, err := e.store.CountPendingAboveThreshold(ctx, sectionID, current, 0)
 	if err != nil {
 		return current, false, err
 	}
 
-	next := current
-	if count > 50 {
-		next = clamp(current+e.cfg.ThresholdStep, e.cfg.MinThreshold, e.cfg.MaxThreshold)
-	} else if count < 5 {
-		next = clamp(current-e.cfg.ThresholdStep, e.cfg.MinThreshold, e.cfg.MaxThreshold)
-	}
-
+	next := e.cfg.adjustedThreshold(current, count)
 	if next == current {
 		return current, false, nil
 	}
@@ -364,6 +592,30 @@ func (e *Engine) SectionByName(name string) *models.Section {
 	return state.section
 }
 
+// freshnessBoost decays FreshnessWeight by the article's age, taken from
+// PublishedAt or, failing that, IngestedAt. Undated articles get nothing.
+func (e *Engine) freshnessBoost(article *models.Article, now time.Time) float64 {
+	if e.cfg.FreshnessWeight == 0 || e.cfg.FreshnessHalfLife <= 0 {
+		return 0
+	}
+
+	var ts time.Time
+	switch {
+	case article.PublishedAt != nil && !article.PublishedAt.IsZero():
+		ts = *article.PublishedAt
+	case !article.IngestedAt.IsZero():
+		ts = article.IngestedAt
+	default:
+		return 0
+	}
+
+	age := now.Sub(ts)
+	if age < 0 {
+		age = 0
+	}
+	return e.cfg.FreshnessWeight * math.Exp2(-age.Hours()/e.cfg.FreshnessHalfLife.Hours())
+}
+
 func (e *Engine) resolveSourceBoost(sourceID, sourceType string) float64 {
 	if len(e.cfg.SourceBoosts) == 0 {
 		return 0
@@ -419,6 +671,19 @@ func (e *Engine) thresholdFromConfig(raw json.RawMessage) float64 {
 	return clamp(threshold, e.cfg.MinThreshold, e.cfg.MaxThreshold)
 }
 
+// thresholdLocked reports whether a section config pins its threshold.
+func thresholdLocked(raw json.RawMessage) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return false
+	}
+	locked, _ := cfg[sectionThresholdLockedKey].(bool)
+	return locked
+}
+
 func averageVector(vectors [][]float32) []float32 {
 	if len(vectors) == 0 {
 		return nil
@@ -0,0 +1,293 @@
+package relevance
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/models"
+)
+
+func TestThresholdLocked(t *testing.T) {
+	assert.False(t, thresholdLocked(nil))
+	assert.False(t, thresholdLocked(json.RawMessage(`{"relevance_threshold":0.3}`)))
+	assert.False(t, thresholdLocked(json.RawMessage(`{"threshold_locked":"yes"}`)))
+	assert.True(t, thresholdLocked(json.RawMessage(`{"relevance_threshold":0.3,"threshold_locked":true}`)))
+}
+
+func TestThresholdFromConfigClamps(t *testing.T) {
+	e := &Engine{cfg: Config{DefaultThreshold: 0.3, MinThreshold: 0.15, MaxThreshold: 0.6}}
+	assert.Equal(t, 0.3, e.thresholdFromConfig(nil))
+	assert.Equal(t, 0.45, e.thresholdFromConfig(json.RawMessage(`{"relevance_threshold":0.45}`)))
+	assert.Equal(t, 0.6, e.thresholdFromConfig(json.RawMessage(`{"relevance_threshold":0.9}`)))
+}
+
+func TestAdjustedThreshold(t *testing.T) {
+	defaults := Config{}.withDefaults()
+	assert.Equal(t, 50, defaults.AdjustUpperBound)
+	assert.Equal(t, 5, defaults.AdjustLowerBound)
+
+	cfg := Config{MinThreshold: 0.15, MaxThreshold: 0.6, ThresholdStep: 0.05, AdjustUpperBound: 200, AdjustLowerBound: 20}
+	tests := []struct {
+		name    string
+		current float64
+		pending int
+		want    float64
+	}{
+		{name: "raises above upper bound", current: 0.3, pending: 201, want: 0.35},
+		{name: "holds at upper bound", current: 0.3, pending: 200, want: 0.3},
+		{name: "holds inside band", current: 0.3, pending: 60, want: 0.3},
+		{name: "holds at lower bound", current: 0.3, pending: 20, want: 0.3},
+		{name: "lowers below lower bound", current: 0.3, pending: 19, want: 0.25},
+		{name: "raise clamps to max", current: 0.58, pending: 500, want: 0.6},
+		{name: "lower clamps to min", current: 0.17, pending: 0, want: 0.15},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, cfg.adjustedThreshold(tc.current, tc.pending), 1e-9)
+		})
+	}
+}
+
+func TestAdjustedThresholdProportional(t *testing.T) {
+	cfg := Config{MinThreshold: 0.15, MaxThreshold: 0.6, ThresholdStep: 0.05, AdjustUpperBound: 50, AdjustLowerBound: 5, AdjustMode: AdjustModeProportional}
+	assert.InDelta(t, 0.35, cfg.adjustedThreshold(0.3, 100), 1e-9, "twice the upper bound moves one step")
+	assert.InDelta(t, 0.4, cfg.adjustedThreshold(0.3, 200), 1e-9)
+	assert.InDelta(t, 0.5, cfg.adjustedThreshold(0.3, 1_000_000), 1e-9, "capped at maxProportionalSteps")
+	assert.InDelta(t, 0.3+0.05*math.Log2(55.0/50), cfg.adjustedThreshold(0.3, 55), 1e-9, "small excess, small move")
+	assert.Equal(t, 0.3, cfg.adjustedThreshold(0.3, 30))
+	assert.InDelta(t, 0.3-0.05*math.Log2(5), cfg.adjustedThreshold(0.3, 0), 1e-9)
+	assert.InDelta(t, 0.3-0.05*math.Log2(5.0/4), cfg.adjustedThreshold(0.3, 4), 1e-9)
+}
+
+func TestProportionalAdjustConvergesFasterThanStep(t *testing.T) {
+	// 1000 pending articles with scores spread evenly over [0, 1): the band
+	// of 5..50 passing articles sits at thresholds 0.95..0.995.
+	pendingAbove := func(threshold float64) int {
+		return int(math.Round(1000 * (1 - threshold)))
+	}
+	runsToBand := func(mode string) int {
+		cfg := Config{MinThreshold: 0.05, MaxThreshold: 0.99, ThresholdStep: 0.05, AdjustUpperBound: 50, AdjustLowerBound: 5, AdjustMode: mode}
+		threshold := 0.3
+		for run := 1; run <= 100; run++ {
+			next := cfg.adjustedThreshold(threshold, pendingAbove(threshold))
+			if next == threshold {
+				return run
+			}
+			threshold = next
+		}
+		t.Fatalf("%s mode did not settle in 100 runs", mode)
+		return 0
+	}
+
+	step := runsToBand(AdjustModeStep)
+	proportional := runsToBand(AdjustModeProportional)
+	assert.Less(t, proportional, step)
+	t.Logf("runs to settle: step=%d proportional=%d", step, proportional)
+}
+
+func TestConfigValidateAdjustBounds(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{AdjustUpperBound: 10, AdjustLowerBound: 10}.Validate())
+	assert.Error(t, Config{AdjustUpperBound: 10, AdjustLowerBound: 20}.Validate())
+	// An unset upper bound defaults to 50, below this lower bound.
+	assert.Error(t, Config{AdjustLowerBound: 80}.Validate())
+	assert.NoError(t, Config{AdjustMode: AdjustModeProportional}.Validate())
+	assert.Error(t, Config{AdjustMode: "pid"}.Validate())
+}
+
+func TestExplainArticleRejectsWrongEmbeddingDimension(t *testing.T) {
+	e := &Engine{cfg: Config{EmbeddingDim: 3}}
+	_, err := e.ExplainArticle(context.Background(), &models.Article{}, []float32{1, 0})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "article embedding has 2 dimensions, want 3")
+
+	cfg := Config{EmbeddingDim: 3}
+	assert.NoError(t, cfg.checkDimension("profile", []float32{1, 0, 0}))
+	assert.NoError(t, cfg.checkDimension("profile", nil))
+	assert.Error(t, cfg.checkDimension("profile", make([]float32, 384)))
+	assert.NoError(t, Config{}.checkDimension("profile", []float32{1}))
+	assert.Error(t, Config{EmbeddingDim: -1}.Validate())
+}
+
+func TestFreshnessBoost(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	e := &Engine{cfg: Config{FreshnessWeight: 0.2, FreshnessHalfLife: 24 * time.Hour}}
+
+	fresh := now.Add(-time.Hour)
+	stale := now.Add(-72 * time.Hour)
+	newer := &models.Article{Title: "Same story", PublishedAt: &fresh}
+	older := &models.Article{Title: "Same story", PublishedAt: &stale}
+	assert.Greater(t, e.freshnessBoost(newer, now), e.freshnessBoost(older, now))
+	assert.InDelta(t, 0.2*0.125, e.freshnessBoost(older, now), 1e-9, "three half-lives leave an eighth")
+
+	// IngestedAt is the fallback; undated and future articles are handled.
+	assert.InDelta(t, 0.2, e.freshnessBoost(&models.Article{IngestedAt: now}, now), 1e-9)
+	future := now.Add(time.Hour)
+	assert.InDelta(t, 0.2, e.freshnessBoost(&models.Article{PublishedAt: &future}, now), 1e-9)
+	assert.Zero(t, e.freshnessBoost(&models.Article{}, now))
+
+	disabled := &Engine{cfg: Config{FreshnessHalfLife: 24 * time.Hour}}
+	assert.Zero(t, disabled.freshnessBoost(newer, now))
+}
+
+func TestSeedGroups(t *testing.T) {
+	sec := &models.Section{
+		SeedKeywords: []string{" golang ", ""},
+		Config: json.RawMessage(`{"seed_groups":[
+			{"keywords":["kubernetes","docker"],"weight":0.5},
+			{"keywords":["  "]},
+			{"keywords":["rust"]}
+		]}`),
+	}
+	assert.Equal(t, []SeedGroup{
+		{Keywords: []string{"golang"}, Weight: 1},
+		{Keywords: []string{"kubernetes", "docker"}, Weight: 0.5},
+		{Keywords: []string{"rust"}, Weight: 1},
+	}, SeedGroups(sec))
+
+	// Flat seed_keywords alone still form a single group.
+	legacy := &models.Section{SeedKeywords: []string{"cve"}, Config: json.RawMessage(`{"relevance_threshold":0.3}`)}
+	assert.Equal(t, []SeedGroup{{Keywords: []string{"cve"}, Weight: 1}}, SeedGroups(legacy))
+	assert.Empty(t, SeedGroups(&models.Section{}))
+
+	// Weights above 1 are clamped so no group outscores a plain match.
+	heavy := &models.Section{Config: json.RawMessage(`{"seed_groups":[{"keywords":["ai"],"weight":3}]}`)}
+	assert.Equal(t, []SeedGroup{{Keywords: []string{"ai"}, Weight: 1}}, SeedGroups(heavy))
+}
+
+func TestValidateSeedGroups(t *testing.T) {
+	assert.NoError(t, ValidateSeedGroups(nil))
+	assert.NoError(t, ValidateSeedGroups(json.RawMessage(`{"seed_groups":[{"keywords":["a"]},{"keywords":["b"],"weight":0.5},{"keywords":["c"],"weight":1}]}`)))
+	assert.Error(t, ValidateSeedGroups(json.RawMessage(`{"seed_groups":[{"keywords":["a"],"weight":1.5}]}`)))
+	assert.Error(t, ValidateSeedGroups(json.RawMessage(`{"seed_groups":[{"keywords":["a"],"weight":0}]}`)))
+	assert.Error(t, ValidateSeedGroups(json.RawMessage(`{"seed_groups":"tech"}`)))
+}
+
+func TestAssignSectionScoresBestSeedGroup(t *testing.T) {
+	seed := func(weight float64, v ...float32) seedVector {
+		return seedVector{embedding: v, norm: embeddings.VectorNorm(v), weight: weight}
+	}
+	// "tech" covers two distinct subtopics; their average would sit between
+	// both axes and lose to the narrower "science" section.
+	tech := &sectionState{section: &models.Section{ID: "tech"}, seeds: []seedVector{
+		seed(1, 1, 0, 0),
+		seed(1, 0, 1, 0),
+	}}
+	science := &sectionState{section: &models.Section{ID: "science"}, seeds: []seedVector{
+		seed(1, 0, 0.8, 0.6),
+	}}
+	e := &Engine{
+		sectionsByID: map[string]*sectionState{"tech": tech, "science": science},
+		sectionOrder: []string{"tech", "science"},
+	}
+
+	sectionID, _, _, err := e.assignSection(&models.Article{}, []float32{0, 1, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "tech", sectionID)
+
+	// A down-weighted group no longer wins the article.
+	tech.seeds[1].weight = 0.5
+	sectionID, _, _, err = e.assignSection(&models.Article{}, []float32{0, 1, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "science", sectionID)
+
+	score, ok := seedScore([]float32{1, 0, 0}, 1, tech.seeds)
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0, score, 1e-9)
+	_, ok = seedScore([]float32{1, 0, 0}, 1, nil)
+	assert.False(t, ok)
+}
+
+func TestNegativeKeywordsLowerScore(t *testing.T) {
+	sec := &models.Section{Config: json.RawMessage(`{"negative_keywords":[" celebrity gossip ",""]}`)}
+	assert.Equal(t, []string{"celebrity gossip"}, NegativeKeywords(sec))
+	assert.Empty(t, NegativeKeywords(&models.Section{}))
+
+	seed := []float32{1, 0, 0}
+	plain := &sectionState{seeds: []seedVector{{embedding: seed, norm: 1, weight: 1}}}
+	guarded := &sectionState{seeds: plain.seeds, negativeSeed: []float32{0, 1, 0}}
+
+	// An article leaning towards the rejected topic loses score on the
+	// guarded section while the on-topic one is unaffected.
+	offTopic := []float32{0.6, 0.8, 0}
+	plainPos, plainNeg := profileScores(plain, nil, offTopic)
+	guardedPos, guardedNeg := profileScores(guarded, nil, offTopic)
+	assert.Equal(t, plainPos, guardedPos)
+	assert.Zero(t, plainNeg)
+	assert.InDelta(t, 0.8, guardedNeg, 1e-6)
+
+	_, onTopicNeg := profileScores(guarded, nil, []float32{1, 0, 0})
+	assert.Zero(t, onTopicNeg)
+
+	// Learned dislikes are blended with the baseline, not replacing it.
+	learned := &models.SectionProfile{NegativeEmbedding: []float32{0, 0, 1}}
+	_, blendedNeg := profileScores(guarded, learned, offTopic)
+	assert.InDelta(t, 0.8*0.5/math.Sqrt(0.5), blendedNeg, 1e-6)
+}
+
+func TestAssignSectionMultiSectionSource(t *testing.T) {
+	seed := func(v ...float32) []seedVector {
+		return []seedVector{{embedding: v, norm: embeddings.VectorNorm(v), weight: 1}}
+	}
+	e := &Engine{
+		sectionsByID: map[string]*sectionState{
+			"security": {section: &models.Section{ID: "security", Name: "cybersecurity"}, seeds: seed(1, 0, 0)},
+			"economy":  {section: &models.Section{ID: "economy", Name: "economy"}, seeds: seed(0, 1, 0)},
+			"world":    {section: &models.Section{ID: "world", Name: "world"}, seeds: seed(0, 0, 1)},
+		},
+		sectionOrder:   []string{"security", "economy", "world"},
+		sourceSections: map[string][]string{"src-1": {"security", "economy"}},
+	}
+	fromSource := &models.Article{Metadata: json.RawMessage(`{"source_ref":"src-1"}`)}
+
+	sectionID, sourceID, candidates, err := e.assignSection(fromSource, []float32{0.9, 0.3, 0.8})
+	require.NoError(t, err)
+	assert.Equal(t, "security", sectionID, "world scores higher but is not linked to the source")
+	assert.Equal(t, "src-1", sourceID)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "security", candidates[0].SectionID)
+	assert.Equal(t, "economy", candidates[1].SectionID)
+	assert.Greater(t, candidates[0].Score, candidates[1].Score)
+
+	sectionID, _, candidates, err = e.assignSection(fromSource, []float32{0.1, 0.95, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "economy", sectionID)
+	require.Len(t, candidates, 2)
+	assert.Equal(t, "cybersecurity", candidates[1].SectionName)
+
+	// Single-link and unlinked sources record no candidates.
+	e.sourceSections["src-2"] = []string{"world"}
+	sectionID, _, candidates, err = e.assignSection(&models.Article{Metadata: json.RawMessage(`{"source_ref":"src-2"}`)}, []float32{1, 0, 0})
+	require.NoError(t, err)
+	assert.Equal(t, "world", sectionID)
+	assert.Nil(t, candidates)
+	_, _, candidates, err = e.assignSection(&models.Article{}, []float32{1, 0, 0})
+	require.NoError(t, err)
+	assert.Nil(t, candidates)
+}
+
+func TestSectionCandidatesMetadata(t *testing.T) {
+	candidates := []SectionCandidate{
+		{SectionID: "security", SectionName: "cybersecurity", Score: 0.71},
+		{SectionID: "economy", SectionName: "economy", Score: 0.42},
+	}
+	metadata, err := WithSectionCandidates(json.RawMessage(`{"source_ref":"src-1","hn_score":12}`), candidates)
+	require.NoError(t, err)
+	assert.Equal(t, "src-1", sourceRefFromMetadata(metadata))
+	assert.Equal(t, candidates, SectionCandidatesFromMetadata(metadata))
+
+	metadata, err = WithSectionCandidates(nil, candidates[:1])
+	require.NoError(t, err)
+	assert.Equal(t, candidates[:1], SectionCandidatesFromMetadata(metadata))
+
+	assert.Nil(t, SectionCandidatesFromMetadata(json.RawMessage(`{"source_ref":"src-1"}`)))
+	assert.Nil(t, SectionCandidatesFromMetadata(nil))
+	_, err = WithSectionCandidates(json.RawMessage(`[1,2]`), candidates)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package relevance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/zyrak/flux/internal/embeddings"
+	"github.com/zyrak/flux/internal/models"
+)
+
+// SeedGroup is a weighted set of keywords describing one subtopic of a
+// section. A broad section such as "tech" can list several groups so its
+// subtopics are not averaged into a single diluted vector.
+type SeedGroup struct {
+	Keywords []string `json:"keywords"`
+	Weight   float64  `json:"weight"`
+}
+
+// SeedGroups returns the keyword groups of a section. The flat
+// seed_keywords list is one group of weight 1; config.seed_groups adds
+// further groups. Blank keywords and empty groups are dropped. Weights are
+// clamped to (0, 1] so a group can be down-weighted but never lift a score
+// past a plain match; a missing or non-positive weight means 1.
+func SeedGroups(sec *models.Section) []SeedGroup {
+	var groups []SeedGroup
+	if flat := cleanKeywords(sec.SeedKeywords); len(flat) > 0 {
+		groups = append(groups, SeedGroup{Keywords: flat, Weight: 1})
+	}
+
+	if len(sec.Config) == 0 || string(sec.Config) == "null" {
+		return groups
+	}
+	var cfg struct {
+		SeedGroups []SeedGroup `json:"seed_groups"`
+	}
+	if err := json.Unmarshal(sec.Config, &cfg); err != nil {
+		return groups
+	}
+	for _, g := range cfg.SeedGroups {
+		keywords := cleanKeywords(g.Keywords)
+		if len(keywords) == 0 {
+			continue
+		}
+		weight := g.Weight
+		if weight <= 0 || weight > 1 {
+			weight = 1
+		}
+		groups = append(groups, SeedGroup{Keywords: keywords, Weight: weight})
+	}
+	return groups
+}
+
+// ValidateSeedGroups checks config.seed_groups of a section config, so the
+// API can reject weights SeedGroups would otherwise clamp.
+func ValidateSeedGroups(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var cfg struct {
+		SeedGroups []struct {
+			Weight *float64 `json:"weight"`
+		} `json:"seed_groups"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return fmt.Errorf("config.seed_groups: %w", err)
+	}
+	for i, g := range cfg.SeedGroups {
+		if g.Weight != nil && (*g.Weight <= 0 || *g.Weight > 1) {
+			return fmt.Errorf("config.seed_groups[%d].weight must be in (0, 1], got %g", i, *g.Weight)
+		}
+	}
+	return nil
+}
+
+// NegativeKeywords returns config.negative_keywords: topics a section rejects
+// before any dislikes have been recorded.
+func NegativeKeywords(sec *models.Section) []string {
+	if len(sec.Config) == 0 || string(sec.Config) == "null" {
+		return nil
+	}
+	var cfg struct {
+		NegativeKeywords []string `json:"negative_keywords"`
+	}
+	if err := json.Unmarshal(sec.Config, &cfg); err != nil {
+		return nil
+	}
+	return cleanKeywords(cfg.NegativeKeywords)
+}
+
+func cleanKeywords(keywords []string) []string {
+	out := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		kw = strings.TrimSpace(kw)
+		if kw != "" {
+			out = append(out, kw)
+		}
+	}
+	return out
+}
+
+// seedVector is the embedded centroid of one seed group.
+type seedVector struct {
+	embedding []float32
+	// norm caches VectorNorm(embedding) for assignSection.
+	norm   float64
+	weight float64
+}
+
+// seedScore is the best weighted similarity between an article and any of a
+// section's seed groups, or ok=false when the section has no seeds.
+func seedScore(articleEmbedding []float32, articleNorm float64, seeds []seedVector) (score float64, ok bool) {
+	for _, seed := range seeds {
+		s := seed.weight * embeddings.CosineSimilarityPrenorm(articleEmbedding, articleNorm, seed.embedding, seed.norm)
+		if !ok || s > score {
+			score = s
+			ok = true
+		}
+	}
+	return score, ok
+}
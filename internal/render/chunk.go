@@ -0,0 +1,128 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// block is one converted line; heading marks section titles so a chunk
+// never ends on a title whose content landed in the next message.
+type block struct {
+	text    string
+	heading bool
+}
+
+// chunk joins blocks into messages of at most limit, preferring to break
+// between paragraphs, then between lines, and only splitting a single line
+// that is longer than limit on its own. Runs of blank lines collapse to one.
+func chunk(blocks []block, limit int) []string {
+	var (
+		messages []string
+		current  []block
+		size     int
+	)
+	flush := func() {
+		// Carry trailing headings (and the blank lines around them) over
+		// to the next message.
+		cut := len(current)
+		for cut > 0 && (current[cut-1].heading || current[cut-1].text == "") {
+			cut--
+		}
+		if cut == 0 {
+			cut = len(current)
+		}
+		if text := joinBlocks(current[:cut]); text != "" {
+			messages = append(messages, text)
+		}
+		current = append([]block(nil), current[cut:]...)
+		size = textLen(joinBlocks(current))
+	}
+
+	for _, b := range blocks {
+		if b.text == "" && (len(current) == 0 || current[len(current)-1].text == "") {
+			continue
+		}
+		for _, piece := range splitLong(b, limit) {
+			// A paragraph break is the preferred place to start a new
+			// message once the current one is reasonably full.
+			if piece.text == "" && size > limit/2 {
+				flush()
+				continue
+			}
+			if len(current) > 0 && size+1+textLen(piece.text) > limit {
+				flush()
+				if len(current) > 0 && size+1+textLen(piece.text) > limit {
+					// The carried headings cannot share a message with
+					// piece; send them on their own.
+					messages = append(messages, joinBlocks(current))
+					current, size = nil, 0
+				}
+			}
+			if len(current) > 0 {
+				size++
+			}
+			current = append(current, piece)
+			size += textLen(piece.text)
+		}
+	}
+	if text := joinBlocks(current); text != "" {
+		messages = append(messages, text)
+	}
+	return messages
+}
+
+func joinBlocks(blocks []block) string {
+	lines := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		lines = append(lines, b.text)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// splitLong breaks a block longer than limit at spaces where it can, or
+// mid-word when a single word exceeds limit.
+func splitLong(b block, limit int) []block {
+	if textLen(b.text) <= limit {
+		return []block{b}
+	}
+	var (
+		pieces []block
+		rest   = b.text
+	)
+	for textLen(rest) > limit {
+		cut := prefixWithin(rest, limit)
+		if space := strings.LastIndexByte(rest[:cut], ' '); space > 0 {
+			cut = space
+		}
+		pieces = append(pieces, block{text: strings.TrimRight(rest[:cut], " "), heading: b.heading})
+		rest = strings.TrimLeft(rest[cut:], " ")
+	}
+	if rest != "" {
+		pieces = append(pieces, block{text: rest, heading: b.heading})
+	}
+	return pieces
+}
+
+// prefixWithin returns the byte length of the longest prefix of s that is at
+// most limit long and ends on a rune boundary.
+func prefixWithin(s string, limit int) int {
+	n := 0
+	for i, r := range s {
+		n += utf16.RuneLen(r)
+		if n > limit {
+			return max(i, utf8.RuneLen(r))
+		}
+	}
+	return len(s)
+}
+
+// textLen measures s the way Slack and Discord do, in UTF-16 code units, so
+// emoji count as two.
+func textLen(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
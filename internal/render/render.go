@@ -0,0 +1,136 @@
+// Package render converts briefing markdown into the flavors chat platforms
+// accept, split into messages that fit each platform's length limit.
+package render
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Message length limits, in UTF-16 code units as the platforms count them.
+const (
+	// SlackMessageLimit is the most text a Slack section block holds.
+	SlackMessageLimit = 3000
+	// DiscordMessageLimit is Discord's hard cap on message content.
+	DiscordMessageLimit = 2000
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletPattern   = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	rulePattern     = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(<?([^)\s>]+)>?\)`)
+	autolinkPattern = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	boldPattern     = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	italicPattern   = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	strikePattern   = regexp.MustCompile(`~~(.+?)~~`)
+)
+
+// ToSlack converts briefing markdown to Slack mrkdwn: headers become bold
+// lines, **bold** becomes *bold*, *italic* becomes _italic_, links become
+// <url|text> and bullets become "•". The result is split into messages of at
+// most SlackMessageLimit.
+func ToSlack(markdown string) []string {
+	return convert(markdown, SlackMessageLimit, slackLine)
+}
+
+// ToDiscord converts briefing markdown to Discord markdown: headers deeper
+// than ### become bold lines, horizontal rules are dropped and link targets
+// are wrapped in <> so Discord does not embed a preview for every article.
+// The result is split into messages of at most DiscordMessageLimit.
+func ToDiscord(markdown string) []string {
+	return convert(markdown, DiscordMessageLimit, discordLine)
+}
+
+// convert rewrites markdown line by line, outside fenced code blocks, and
+// chunks the result.
+func convert(markdown string, limit int, convertLine func(string) (string, bool)) []string {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	out := make([]block, 0, len(lines))
+	inFence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			out = append(out, block{text: line})
+			continue
+		}
+		if inFence {
+			out = append(out, block{text: line})
+			continue
+		}
+		text, heading := convertLine(line)
+		out = append(out, block{text: text, heading: heading})
+	}
+	return chunk(out, limit)
+}
+
+func slackLine(line string) (string, bool) {
+	if rulePattern.MatchString(line) {
+		return "", false
+	}
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		title := stripEmphasis(slackInline(m[2]))
+		if title == "" {
+			return "", false
+		}
+		return "*" + title + "*", true
+	}
+	indent := ""
+	if m := bulletPattern.FindStringSubmatch(line); m != nil {
+		indent = m[1] + "• "
+		line = line[len(m[0]):]
+	}
+	return indent + slackInline(line), false
+}
+
+// slackInline converts inline markdown to mrkdwn. Links are swapped for
+// placeholders while emphasis is rewritten so URLs containing * or _ survive.
+func slackInline(text string) string {
+	text = autolinkPattern.ReplaceAllString(text, "$1")
+	text = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+
+	var links []string
+	text = linkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := linkPattern.FindStringSubmatch(match)
+		links = append(links, "<"+m[2]+"|"+stripEmphasis(m[1])+">")
+		return fmt.Sprintf("\x00%d\x00", len(links)-1)
+	})
+
+	text = boldPattern.ReplaceAllString(text, "\x01$1$2\x01")
+	text = italicPattern.ReplaceAllString(text, "_${1}_")
+	text = strings.ReplaceAll(text, "\x01", "*")
+	text = strikePattern.ReplaceAllString(text, "~$1~")
+
+	for i, link := range links {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), link, 1)
+	}
+	return text
+}
+
+func discordLine(line string) (string, bool) {
+	if rulePattern.MatchString(line) {
+		return "", false
+	}
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		title := discordInline(m[2])
+		if title == "" {
+			return "", false
+		}
+		if len(m[1]) > 3 {
+			return "**" + stripEmphasis(title) + "**", true
+		}
+		return m[1] + " " + title, true
+	}
+	return discordInline(line), false
+}
+
+func discordInline(text string) string {
+	return linkPattern.ReplaceAllString(text, "[$1](<$2>)")
+}
+
+// stripEmphasis drops markdown emphasis markers from text that is about to
+// be wrapped in emphasis of its own, since neither platform nests them.
+func stripEmphasis(text string) string {
+	return strings.TrimSpace(strings.NewReplacer("**", "", "__", "", "*", "").Replace(text))
+}
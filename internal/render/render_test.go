@@ -0,0 +1,99 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleBriefing = `# Briefing 2026-10-14
+
+## Ciberseguridad
+
+- **CVE-2026-1234 in OpenSSH**: a *critical* flaw, see [the advisory](https://example.com/a_b?x=1&y=2).
+  📡 Seen in: HN, r/netsec
+
+---
+
+#### Other
+
+Plain <https://example.com/raw> link and ~~old~~ news.
+`
+
+func TestToSlack(t *testing.T) {
+	messages := ToSlack(sampleBriefing)
+	require.Len(t, messages, 1)
+	assert.Equal(t, `*Briefing 2026-10-14*
+
+*Ciberseguridad*
+
+• *CVE-2026-1234 in OpenSSH*: a _critical_ flaw, see <https://example.com/a_b?x=1&amp;y=2|the advisory>.
+  📡 Seen in: HN, r/netsec
+
+*Other*
+
+Plain https://example.com/raw link and ~old~ news.`, messages[0])
+}
+
+func TestToDiscord(t *testing.T) {
+	messages := ToDiscord(sampleBriefing)
+	require.Len(t, messages, 1)
+	assert.Equal(t, `# Briefing 2026-10-14
+
+## Ciberseguridad
+
+- **CVE-2026-1234 in OpenSSH**: a *critical* flaw, see [the advisory](<https://example.com/a_b?x=1&y=2>).
+  📡 Seen in: HN, r/netsec
+
+**Other**
+
+Plain <https://example.com/raw> link and ~~old~~ news.`, messages[0])
+}
+
+func TestChunkRespectsLimitAndKeepsHeadingsWithContent(t *testing.T) {
+	var sb strings.Builder
+	for section := 0; section < 6; section++ {
+		sb.WriteString("## Section\n\n")
+		for item := 0; item < 5; item++ {
+			sb.WriteString("- " + strings.Repeat("word ", 20) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	messages := ToDiscord(sb.String())
+	require.Greater(t, len(messages), 1)
+	for _, msg := range messages {
+		assert.LessOrEqual(t, textLen(msg), DiscordMessageLimit)
+		assert.False(t, strings.HasSuffix(msg, "## Section"), "message ends on a dangling heading")
+	}
+	assert.Equal(t, 6, strings.Count(strings.Join(messages, "\n"), "## Section"))
+}
+
+func TestChunkSplitsOversizedLines(t *testing.T) {
+	line := strings.Repeat("📡", 1500) // 3000 UTF-16 units
+	messages := chunk([]block{{text: "intro"}, {text: line}}, 1000)
+	require.Len(t, messages, 4)
+	assert.Equal(t, "intro", messages[0])
+	for _, msg := range messages[1:] {
+		assert.Equal(t, 1000, textLen(msg))
+	}
+
+	words := strings.TrimSpace(strings.Repeat("abcd ", 50))
+	messages = chunk([]block{{text: words}}, 22)
+	for _, msg := range messages {
+		assert.LessOrEqual(t, textLen(msg), 22)
+		assert.False(t, strings.HasPrefix(msg, " ") || strings.HasSuffix(msg, " "))
+	}
+	assert.Equal(t, words, strings.Join(messages, " "))
+}
+
+func TestChunkSendsLoneHeadingWhenItCannotFitWithContent(t *testing.T) {
+	messages := chunk([]block{
+		{text: "first"},
+		{text: "## Title", heading: true},
+		{text: strings.Repeat("x", 20)},
+	}, 20)
+	assert.Equal(t, []string{"first", "## Title", strings.Repeat("x", 20)}, messages)
+}
@@ -0,0 +1,73 @@
+// Package retry holds the backoff and retryable-status rules shared by the
+// HTTP clients that call model providers (embeddings and LLM), and the
+// Retry-After parsing the outbound rate limiter also uses.
+package retry
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Backoff is an exponential wait between attempts: Initial first, doubling
+// on every call to Next up to Max. A zero Max leaves it uncapped.
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+
+	next time.Duration
+}
+
+// Next returns the wait before the next attempt and doubles the one after.
+func (b *Backoff) Next() time.Duration {
+	if b.next == 0 {
+		b.next = b.Initial
+	}
+	wait := b.next
+	b.next *= 2
+	if b.Max > 0 {
+		wait = min(wait, b.Max)
+		b.next = min(b.next, b.Max)
+	}
+	return wait
+}
+
+// RetryableStatus reports whether a response status is worth retrying:
+// 429 and every 5xx.
+func RetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// ParseRetryAfter reads a Retry-After header given either in seconds or as
+// an HTTP date; it returns 0 when the header is missing or unusable.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// Sleep waits for d, returning early with the context's error when ctx is
+// done first.
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
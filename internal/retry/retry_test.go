@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 5 * time.Second}
+	var waits []time.Duration
+	for i := 0; i < 5; i++ {
+		waits = append(waits, b.Next())
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, waits)
+
+	uncapped := Backoff{Initial: time.Second}
+	uncapped.Next()
+	uncapped.Next()
+	assert.Equal(t, 4*time.Second, uncapped.Next())
+}
+
+func TestRetryableStatus(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		assert.True(t, RetryableStatus(status), status)
+	}
+	for _, status := range []int{http.StatusOK, http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		assert.False(t, RetryableStatus(status), status)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 5*time.Second, ParseRetryAfter("5", now))
+	assert.Equal(t, 5*time.Second, ParseRetryAfter("  5 ", now))
+	assert.Equal(t, 90*time.Second, ParseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, ParseRetryAfter("", now))
+	assert.Zero(t, ParseRetryAfter("-3", now))
+	for _, malformed := range []string{"soon", "0", "12.5", "2026-01-01"} {
+		assert.Zero(t, ParseRetryAfter(malformed, now), malformed)
+	}
+	assert.Zero(t, ParseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+}
+
+func TestSleepStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Sleep(ctx, time.Hour), context.Canceled)
+	assert.NoError(t, Sleep(context.Background(), time.Millisecond))
+}
@@ -13,43 +13,82 @@ import (
 
 // ArticleListQuery holds filters and pagination for listing articles.
 type ArticleListQuery struct {
-	SectionName  *string
-	SectionNames []string
-	SourceType   *string
-	SourceRef    *string
-	Status       *string
-	LikedOnly    bool
+	SectionName       *string
+	SectionNames      []string
+	SourceType        *string
+	SourceRef         *string
+	SourceRefs        []string // matches any of these sources
+	ExcludeSourceRefs []string // drops these sources
+	Langs             []string // metadata.lang codes; undetected articles never match
+	Status            *string
+	Statuses          []string // matches any of the listed statuses
+	Category          *string
+	LikedOnly         bool
+	SavedOnly         bool
+	// HideDisliked and DislikedOnly are mutually exclusive; neither set shows everything.
+	HideDisliked bool
+	DislikedOnly bool
 	From         *time.Time
 	To           *time.Time
-	Limit        int
-	Offset       int
+	// SortBy is one of the ArticleSort* fields; empty means ingested_at.
+	SortBy  string
+	SortAsc bool
+	Limit   int
+	Offset  int
+}
+
+// Sort fields accepted by ArticleListQuery.SortBy.
+const (
+	ArticleSortIngestedAt  = "ingested_at"
+	ArticleSortPublishedAt = "published_at"
+	ArticleSortRelevance   = "relevance_score"
+)
+
+// articleSortColumns whitelists the SQL expression behind each sort field so
+// user input never reaches the query text.
+var articleSortColumns = map[string]string{
+	ArticleSortIngestedAt:  "a.ingested_at",
+	ArticleSortPublishedAt: "a.published_at",
+	ArticleSortRelevance:   "a.relevance_score",
+}
+
+// articleOrderBy builds the ORDER BY list for an article listing. Unknown
+// fields fall back to ingested_at; NULLs always sort last and a.id breaks
+// ties so pagination is stable.
+func articleOrderBy(sortBy string, asc bool) string {
+	column, ok := articleSortColumns[sortBy]
+	if !ok {
+		column = articleSortColumns[ArticleSortIngestedAt]
+	}
+	dir := "DESC"
+	if asc {
+		dir = "ASC"
+	}
+	return fmt.Sprintf("%s %s NULLS LAST, a.id %s", column, dir, dir)
 }
 
 // ArticleWithRelations contains article data plus section/source labels for API responses.
 type ArticleWithRelations struct {
 	models.Article
-	SectionName        *string `json:"section_name,omitempty"`
-	SectionDisplayName *string `json:"section_display_name,omitempty"`
-	SourceName         string  `json:"source_name"`
-	SourceRef          *string `json:"source_ref,omitempty"`
-	LikeCount          int     `json:"like_count"`
-	DislikeCount       int     `json:"dislike_count"`
-	SaveCount          int     `json:"save_count"`
-	Liked              bool    `json:"liked"`
-	Disliked           bool    `json:"disliked"`
-	Saved              bool    `json:"saved"`
-	LatestLikeID       *string `json:"latest_like_id,omitempty"`
-	LatestDislikeID    *string `json:"latest_dislike_id,omitempty"`
-	LatestSaveID       *string `json:"latest_save_id,omitempty"`
+	SectionName        *string      `json:"section_name,omitempty"`
+	SectionDisplayName *string      `json:"section_display_name,omitempty"`
+	SourceName         string       `json:"source_name"`
+	SourceRef          *string      `json:"source_ref,omitempty"`
+	LikeCount          int          `json:"like_count"`
+	DislikeCount       int          `json:"dislike_count"`
+	SaveCount          int          `json:"save_count"`
+	Liked              bool         `json:"liked"`
+	Disliked           bool         `json:"disliked"`
+	Saved              bool         `json:"saved"`
+	LatestLikeID       *string      `json:"latest_like_id,omitempty"`
+	LatestDislikeID    *string      `json:"latest_dislike_id,omitempty"`
+	LatestSaveID       *string      `json:"latest_save_id,omitempty"`
+	Note               *ArticleNote `json:"note,omitempty"`
 }
 
-// ListArticlesWithRelations returns paginated articles and total count with section/source labels.
-func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuery) ([]*ArticleWithRelations, int, error) {
-	limit := q.Limit
-	if limit <= 0 {
-		limit = 20
-	}
-
+// articleListFilter builds the WHERE clause and its positional arguments for
+// an article listing; every filter is ANDed with the others.
+func articleListFilter(q ArticleListQuery) (string, []interface{}, error) {
 	conditions := []string{}
 	args := []interface{}{}
 	argIdx := 1
@@ -74,14 +113,52 @@ func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuer
 		args = append(args, *q.SourceRef)
 		argIdx++
 	}
+	if len(q.SourceRefs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("a.metadata->>'source_ref' = ANY($%d)", argIdx))
+		args = append(args, q.SourceRefs)
+		argIdx++
+	}
+	if len(q.ExcludeSourceRefs) > 0 {
+		// Articles without a source_ref are never excluded.
+		conditions = append(conditions, fmt.Sprintf("(a.metadata->>'source_ref' IS NULL OR NOT (a.metadata->>'source_ref' = ANY($%d)))", argIdx))
+		args = append(args, q.ExcludeSourceRefs)
+		argIdx++
+	}
+	if len(q.Langs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("a.metadata->>'lang' = ANY($%d)", argIdx))
+		args = append(args, q.Langs)
+		argIdx++
+	}
 	if q.Status != nil {
 		conditions = append(conditions, fmt.Sprintf("a.status = $%d", argIdx))
 		args = append(args, *q.Status)
 		argIdx++
 	}
+	if len(q.Statuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("a.status = ANY($%d)", argIdx))
+		args = append(args, q.Statuses)
+		argIdx++
+	}
+	if q.Category != nil {
+		conditions = append(conditions, fmt.Sprintf("a.categories && ARRAY[$%d]::text[]", argIdx))
+		args = append(args, *q.Category)
+		argIdx++
+	}
 	if q.LikedOnly {
 		conditions = append(conditions, "EXISTS (SELECT 1 FROM feedback f WHERE f.article_id = a.id AND f.action = 'like')")
 	}
+	if q.SavedOnly {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM feedback f WHERE f.article_id = a.id AND f.action = 'save')")
+	}
+	if q.HideDisliked && q.DislikedOnly {
+		return "", nil, fmt.Errorf("hide_disliked and disliked_only are mutually exclusive")
+	}
+	if q.HideDisliked {
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM feedback f WHERE f.article_id = a.id AND f.action = 'dislike')")
+	}
+	if q.DislikedOnly {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM feedback f WHERE f.article_id = a.id AND f.action = 'dislike')")
+	}
 	if q.From != nil {
 		conditions = append(conditions, fmt.Sprintf("a.ingested_at >= $%d", argIdx))
 		args = append(args, *q.From)
@@ -97,18 +174,43 @@ func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuer
 	if len(conditions) > 0 {
 		where = " WHERE " + strings.Join(conditions, " AND ")
 	}
+	return where, args, nil
+}
 
-	countQuery := `
-		SELECT COUNT(*)
-		FROM articles a
-		LEFT JOIN sections sec ON sec.id = a.section_id` + where
+// CategoryCount is one article category and how many articles carry it.
+type CategoryCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
 
-	var total int
-	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
-		return nil, 0, fmt.Errorf("counting articles: %w", err)
+// ListCategoriesWithCounts returns every category assigned to an article,
+// most used first.
+func (s *Store) ListCategoriesWithCounts(ctx context.Context) ([]CategoryCount, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT c, COUNT(*)
+		FROM articles a, unnest(a.categories) AS c
+		GROUP BY c
+		ORDER BY COUNT(*) DESC, c`)
+	if err != nil {
+		return nil, fmt.Errorf("listing categories: %w", err)
 	}
+	defer rows.Close()
+
+	categories := []CategoryCount{}
+	for rows.Next() {
+		var c CategoryCount
+		if err := rows.Scan(&c.Name, &c.Count); err != nil {
+			return nil, fmt.Errorf("scanning category: %w", err)
+		}
+		categories = append(categories, c)
+	}
+	return categories, rows.Err()
+}
 
-	query := fmt.Sprintf(`
+// articleWithRelationsSelect selects the columns scanArticleWithRelations
+// reads, from articles aliased a joined to their section and feedback
+// stats; callers append the WHERE clause and ordering.
+const articleWithRelationsSelect = `
 		SELECT
 			a.id, a.source_type, a.source_id, a.section_id, a.url, a.title, a.content, a.summary,
 			a.author, a.published_at, a.ingested_at, a.processed_at, a.relevance_score,
@@ -124,9 +226,11 @@ func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuer
 			COALESCE(fstats.saved, FALSE) AS saved,
 			fstats.latest_like_id,
 			fstats.latest_dislike_id,
-			fstats.latest_save_id
+			fstats.latest_save_id,
+			n.note, n.created_at, n.updated_at
 		FROM articles a
 		LEFT JOIN sections sec ON sec.id = a.section_id
+		LEFT JOIN article_notes n ON n.article_id = a.id
 		LEFT JOIN LATERAL (
 			SELECT
 				COUNT(*) FILTER (WHERE action = 'like') AS like_count,
@@ -158,10 +262,58 @@ func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuer
 				) AS latest_save_id
 			FROM feedback f
 			WHERE f.article_id = a.id
-		) fstats ON TRUE
+		) fstats ON TRUE`
+
+func scanArticleWithRelations(row pgx.Row) (*ArticleWithRelations, error) {
+	a := &ArticleWithRelations{}
+	var note *string
+	var noteCreated, noteUpdated *time.Time
+	err := row.Scan(
+		&a.ID, &a.SourceType, &a.SourceID, &a.SectionID, &a.URL, &a.Title, &a.Content, &a.Summary,
+		&a.Author, &a.PublishedAt, &a.IngestedAt, &a.ProcessedAt, &a.RelevanceScore,
+		&a.Categories, &a.Status, &a.Metadata,
+		&a.SectionName, &a.SectionDisplayName,
+		&a.SourceName, &a.SourceRef,
+		&a.LikeCount, &a.DislikeCount, &a.SaveCount, &a.Liked, &a.Disliked, &a.Saved,
+		&a.LatestLikeID, &a.LatestDislikeID, &a.LatestSaveID,
+		&note, &noteCreated, &noteUpdated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if note != nil {
+		a.Note = &ArticleNote{ArticleID: a.ID, Note: *note, CreatedAt: *noteCreated, UpdatedAt: *noteUpdated}
+	}
+	return a, nil
+}
+
+// ListArticlesWithRelations returns paginated articles and total count with section/source labels.
+func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuery) ([]*ArticleWithRelations, int, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	where, args, err := articleListFilter(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	argIdx := len(args) + 1
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM articles a
+		LEFT JOIN sections sec ON sec.id = a.section_id` + where
+
+	var total int
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting articles: %w", err)
+	}
+
+	query := fmt.Sprintf(articleWithRelationsSelect+`
 		%s
-		ORDER BY a.ingested_at DESC
-		LIMIT $%d OFFSET $%d`, where, argIdx, argIdx+1)
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, where, articleOrderBy(q.SortBy, q.SortAsc), argIdx, argIdx+1)
 
 	args = append(args, limit, q.Offset)
 
@@ -173,16 +325,8 @@ func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuer
 
 	var out []*ArticleWithRelations
 	for rows.Next() {
-		a := &ArticleWithRelations{}
-		if err := rows.Scan(
-			&a.ID, &a.SourceType, &a.SourceID, &a.SectionID, &a.URL, &a.Title, &a.Content, &a.Summary,
-			&a.Author, &a.PublishedAt, &a.IngestedAt, &a.ProcessedAt, &a.RelevanceScore,
-			&a.Categories, &a.Status, &a.Metadata,
-			&a.SectionName, &a.SectionDisplayName,
-			&a.SourceName, &a.SourceRef,
-			&a.LikeCount, &a.DislikeCount, &a.SaveCount, &a.Liked, &a.Disliked, &a.Saved,
-			&a.LatestLikeID, &a.LatestDislikeID, &a.LatestSaveID,
-		); err != nil {
+		a, err := scanArticleWithRelations(rows)
+		if err != nil {
 			return nil, 0, fmt.Errorf("scanning article with relations: %w", err)
 		}
 		out = append(out, a)
@@ -191,71 +335,138 @@ func (s *Store) ListArticlesWithRelations(ctx context.Context, q ArticleListQuer
 	return out, total, rows.Err()
 }
 
+// ArticleCluster is every article in one semantic dedup cluster, oldest
+// first. An article never clustered with another forms a cluster of one
+// keyed by its own ID.
+type ArticleCluster struct {
+	ClusterID string
+	Articles  []*ArticleWithRelations
+}
+
+// articleClusterKey mirrors dedup.ClusterKey in SQL.
+const articleClusterKey = `COALESCE(NULLIF(a.metadata->>'cluster_id', ''), a.id::text)`
+
+// ListClusteredArticles pages through the clusters with at least one article
+// matching q, most recently ingested first, and returns the total number of
+// such clusters. Each cluster carries all of its members, including those q
+// filters out, since they are still coverage of the same story. q's sort is
+// ignored.
+func (s *Store) ListClusteredArticles(ctx context.Context, q ArticleListQuery) ([]ArticleCluster, int, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	where, args, err := articleListFilter(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	argIdx := len(args) + 1
+
+	countQuery := `
+		SELECT COUNT(DISTINCT ` + articleClusterKey + `)
+		FROM articles a
+		LEFT JOIN sections sec ON sec.id = a.section_id` + where
+
+	var total int
+	if err := s.pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting article clusters: %w", err)
+	}
+
+	pageQuery := fmt.Sprintf(`
+		SELECT %s AS cluster_key, BOOL_OR(COALESCE(a.metadata->>'cluster_id', '') <> '')
+		FROM articles a
+		LEFT JOIN sections sec ON sec.id = a.section_id%s
+		GROUP BY cluster_key
+		ORDER BY MAX(a.ingested_at) DESC, cluster_key
+		LIMIT $%d OFFSET $%d`, articleClusterKey, where, argIdx, argIdx+1)
+
+	rows, err := s.pool.Query(ctx, pageQuery, append(args, limit, q.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing article clusters: %w", err)
+	}
+	var (
+		keys       []string
+		clusterIDs []string
+		articleIDs []string
+	)
+	for rows.Next() {
+		var (
+			key       string
+			clustered bool
+		)
+		if err := rows.Scan(&key, &clustered); err != nil {
+			rows.Close()
+			return nil, 0, fmt.Errorf("scanning article cluster: %w", err)
+		}
+		keys = append(keys, key)
+		if clustered {
+			clusterIDs = append(clusterIDs, key)
+		} else {
+			articleIDs = append(articleIDs, key)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("listing article clusters: %w", err)
+	}
+	if len(keys) == 0 {
+		return []ArticleCluster{}, total, nil
+	}
+
+	// Split by kind so each side can use its index: the cluster_id
+	// expression index and the primary key.
+	membersQuery := articleWithRelationsSelect + `
+		WHERE a.metadata->>'cluster_id' = ANY($1) OR a.id = ANY($2::uuid[])
+		ORDER BY a.ingested_at, a.id`
+	rows, err = s.pool.Query(ctx, membersQuery, clusterIDs, articleIDs)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing article cluster members: %w", err)
+	}
+	defer rows.Close()
+
+	members := make(map[string][]*ArticleWithRelations, len(keys))
+	for rows.Next() {
+		a, err := scanArticleWithRelations(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scanning article cluster member: %w", err)
+		}
+		key := a.ID
+		if clusterID := articleClusterID(a.Metadata); clusterID != "" {
+			key = clusterID
+		}
+		members[key] = append(members[key], a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("listing article cluster members: %w", err)
+	}
+
+	clusters := make([]ArticleCluster, 0, len(keys))
+	for _, key := range keys {
+		if len(members[key]) == 0 {
+			continue // deleted between the two queries
+		}
+		clusters = append(clusters, ArticleCluster{ClusterID: key, Articles: members[key]})
+	}
+	return clusters, total, nil
+}
+
+func articleClusterID(metadata json.RawMessage) string {
+	var meta struct {
+		ClusterID string `json:"cluster_id"`
+	}
+	if len(metadata) == 0 || json.Unmarshal(metadata, &meta) != nil {
+		return ""
+	}
+	return meta.ClusterID
+}
+
 // GetArticleWithRelationsByID returns a single article enriched with section/source labels.
 func (s *Store) GetArticleWithRelationsByID(ctx context.Context, id string) (*ArticleWithRelations, error) {
-	query := `
-		SELECT
-			a.id, a.source_type, a.source_id, a.section_id, a.url, a.title, a.content, a.summary,
-			a.author, a.published_at, a.ingested_at, a.processed_at, a.relevance_score,
-			a.categories, a.status, a.metadata,
-			sec.name, sec.display_name,
-			COALESCE(NULLIF(a.metadata->>'source_name', ''), CASE WHEN a.source_type = 'hn' THEN 'Hacker News' ELSE a.source_type END) AS source_name,
-			NULLIF(a.metadata->>'source_ref', '') AS source_ref,
-			COALESCE(fstats.like_count, 0) AS like_count,
-			COALESCE(fstats.dislike_count, 0) AS dislike_count,
-			COALESCE(fstats.save_count, 0) AS save_count,
-			COALESCE(fstats.liked, FALSE) AS liked,
-			COALESCE(fstats.disliked, FALSE) AS disliked,
-			COALESCE(fstats.saved, FALSE) AS saved,
-			fstats.latest_like_id,
-			fstats.latest_dislike_id,
-			fstats.latest_save_id
-		FROM articles a
-		LEFT JOIN sections sec ON sec.id = a.section_id
-		LEFT JOIN LATERAL (
-			SELECT
-				COUNT(*) FILTER (WHERE action = 'like') AS like_count,
-				COUNT(*) FILTER (WHERE action = 'dislike') AS dislike_count,
-				COUNT(*) FILTER (WHERE action = 'save') AS save_count,
-				BOOL_OR(action = 'like') AS liked,
-				BOOL_OR(action = 'dislike') AS disliked,
-				BOOL_OR(action = 'save') AS saved,
-				(
-					SELECT id::text
-					FROM feedback f2
-					WHERE f2.article_id = a.id AND f2.action = 'like'
-					ORDER BY f2.created_at DESC
-					LIMIT 1
-				) AS latest_like_id,
-				(
-					SELECT id::text
-					FROM feedback f3
-					WHERE f3.article_id = a.id AND f3.action = 'dislike'
-					ORDER BY f3.created_at DESC
-					LIMIT 1
-				) AS latest_dislike_id,
-				(
-					SELECT id::text
-					FROM feedback f4
-					WHERE f4.article_id = a.id AND f4.action = 'save'
-					ORDER BY f4.created_at DESC
-					LIMIT 1
-				) AS latest_save_id
-			FROM feedback f
-			WHERE f.article_id = a.id
-		) fstats ON TRUE
+	query := articleWithRelationsSelect + `
 		WHERE a.id = $1`
 
-	a := &ArticleWithRelations{}
-	err := s.pool.QueryRow(ctx, query, id).Scan(
-		&a.ID, &a.SourceType, &a.SourceID, &a.SectionID, &a.URL, &a.Title, &a.Content, &a.Summary,
-		&a.Author, &a.PublishedAt, &a.IngestedAt, &a.ProcessedAt, &a.RelevanceScore,
-		&a.Categories, &a.Status, &a.Metadata,
-		&a.SectionName, &a.SectionDisplayName,
-		&a.SourceName, &a.SourceRef,
-		&a.LikeCount, &a.DislikeCount, &a.SaveCount, &a.Liked, &a.Disliked, &a.Saved,
-		&a.LatestLikeID, &a.LatestDislikeID, &a.LatestSaveID,
-	)
+	a, err := scanArticleWithRelations(s.pool.QueryRow(ctx, query, id))
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -543,6 +754,29 @@ type SectionStats struct {
 	models.Section
 	ArticleCount  int `json:"article_count"`
 	ActiveSources int `json:"active_sources"`
+	// RelevanceThreshold is nil when the section config has no stored threshold.
+	RelevanceThreshold *float64 `json:"relevance_threshold"`
+	ThresholdLocked    bool     `json:"threshold_locked"`
+}
+
+// sectionThresholdFromConfig reads relevance_threshold (or legacy threshold)
+// and threshold_locked from a section config.
+func sectionThresholdFromConfig(raw json.RawMessage) (*float64, bool) {
+	if len(raw) == 0 {
+		return nil, false
+	}
+	var cfg struct {
+		RelevanceThreshold *float64 `json:"relevance_threshold"`
+		Threshold          *float64 `json:"threshold"`
+		ThresholdLocked    bool     `json:"threshold_locked"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, false
+	}
+	if cfg.RelevanceThreshold != nil {
+		return cfg.RelevanceThreshold, cfg.ThresholdLocked
+	}
+	return cfg.Threshold, cfg.ThresholdLocked
 }
 
 // ListSectionsWithStats returns sections with article/source counters.
@@ -585,8 +819,95 @@ func (s *Store) ListSectionsWithStats(ctx context.Context) ([]*SectionStats, err
 			return nil, fmt.Errorf("scanning section stats: %w", err)
 		}
 		sec.Config = cfg
+		sec.RelevanceThreshold, sec.ThresholdLocked = sectionThresholdFromConfig(cfg)
 		out = append(out, sec)
 	}
 
 	return out, rows.Err()
 }
+
+// ScoreHistogramBucket counts pending articles whose relevance score falls in
+// [Min, Max). The last bucket also holds scores at or above 1 and the first
+// one scores below 0.
+type ScoreHistogramBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// SectionScoreHistogram is the relevance score distribution of a section's
+// pending articles relative to its threshold.
+type SectionScoreHistogram struct {
+	SectionID       string                 `json:"section_id"`
+	Threshold       float64                `json:"threshold"`
+	ThresholdLocked bool                   `json:"threshold_locked"`
+	Total           int                    `json:"total"`
+	AboveThreshold  int                    `json:"above_threshold"`
+	Buckets         []ScoreHistogramBucket `json:"buckets"`
+}
+
+// scoreHistogramBuckets returns n empty buckets of equal width covering [0, 1).
+func scoreHistogramBuckets(n int) []ScoreHistogramBucket {
+	buckets := make([]ScoreHistogramBucket, n)
+	for i := range buckets {
+		buckets[i].Min = float64(i) / float64(n)
+		buckets[i].Max = float64(i+1) / float64(n)
+	}
+	return buckets
+}
+
+// SectionScoreHistogram buckets the relevance scores of a section's scored
+// pending articles into that many equal-width bins over [0, 1). The threshold
+// comes from the section config, or defaultThreshold when none is stored.
+// Returns nil, nil when the section does not exist; a section without scored
+// articles gets empty buckets and a zero total.
+func (s *Store) SectionScoreHistogram(ctx context.Context, sectionID string, buckets int, defaultThreshold float64) (*SectionScoreHistogram, error) {
+	if buckets <= 0 {
+		buckets = 20
+	}
+
+	sec, err := s.GetSectionByID(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	if sec == nil {
+		return nil, nil
+	}
+
+	out := &SectionScoreHistogram{
+		SectionID: sectionID,
+		Threshold: defaultThreshold,
+		Buckets:   scoreHistogramBuckets(buckets),
+	}
+	threshold, locked := sectionThresholdFromConfig(sec.Config)
+	if threshold != nil {
+		out.Threshold = *threshold
+	}
+	out.ThresholdLocked = locked
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT
+			LEAST(GREATEST(width_bucket(relevance_score, 0, 1, $2::int), 1), $2::int) AS bucket,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE relevance_score >= $3)
+		FROM articles
+		WHERE section_id = $1
+			AND status = 'pending'
+			AND relevance_score IS NOT NULL
+		GROUP BY bucket`, sectionID, buckets, out.Threshold)
+	if err != nil {
+		return nil, fmt.Errorf("building score histogram for section %s: %w", sectionID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bucket, count, above int
+		if err := rows.Scan(&bucket, &count, &above); err != nil {
+			return nil, fmt.Errorf("scanning score histogram bucket: %w", err)
+		}
+		out.Buckets[bucket-1].Count = count
+		out.Total += count
+		out.AboveThreshold += above
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleListFilterSourceRefs(t *testing.T) {
+	section := "tech"
+	status := "pending"
+	where, args, err := articleListFilter(ArticleListQuery{
+		SectionName:       &section,
+		SourceRefs:        []string{"feed-a", "feed-b"},
+		ExcludeSourceRefs: []string{"noisy"},
+		Status:            &status,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, " WHERE sec.name = $1"+
+		" AND a.metadata->>'source_ref' = ANY($2)"+
+		" AND (a.metadata->>'source_ref' IS NULL OR NOT (a.metadata->>'source_ref' = ANY($3)))"+
+		" AND a.status = $4", where)
+	assert.Equal(t, []interface{}{"tech", []string{"feed-a", "feed-b"}, []string{"noisy"}, "pending"}, args)
+
+	category := "kubernetes"
+	where, args, err = articleListFilter(ArticleListQuery{Category: &category, SavedOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, " WHERE a.categories && ARRAY[$1]::text[]"+
+		" AND EXISTS (SELECT 1 FROM feedback f WHERE f.article_id = a.id AND f.action = 'save')", where)
+	assert.Equal(t, []interface{}{"kubernetes"}, args)
+
+	where, args, err = articleListFilter(ArticleListQuery{Langs: []string{"en", "es"}, Status: &status})
+	require.NoError(t, err)
+	assert.Equal(t, " WHERE a.metadata->>'lang' = ANY($1) AND a.status = $2", where)
+	assert.Equal(t, []interface{}{[]string{"en", "es"}, "pending"}, args)
+
+	where, args, err = articleListFilter(ArticleListQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+
+	_, _, err = articleListFilter(ArticleListQuery{HideDisliked: true, DislikedOnly: true})
+	assert.Error(t, err)
+}
+
+func TestArticleOrderBy(t *testing.T) {
+	assert.Equal(t, "a.ingested_at DESC NULLS LAST, a.id DESC", articleOrderBy("", false))
+	assert.Equal(t, "a.published_at ASC NULLS LAST, a.id ASC", articleOrderBy(ArticleSortPublishedAt, true))
+	assert.Equal(t, "a.ingested_at DESC NULLS LAST, a.id DESC", articleOrderBy("title; --", false))
+}
+
+func TestArticleClusterID(t *testing.T) {
+	assert.Equal(t, "c1", articleClusterID([]byte(`{"cluster_id":"c1","hn_score":10}`)))
+	assert.Equal(t, "", articleClusterID([]byte(`{"hn_score":10}`)))
+	assert.Equal(t, "", articleClusterID(nil))
+	assert.Equal(t, "", articleClusterID([]byte(`not json`)))
+}
+
+func TestScoreHistogramBuckets(t *testing.T) {
+	buckets := scoreHistogramBuckets(4)
+	assert.Equal(t, []ScoreHistogramBucket{
+		{Min: 0, Max: 0.25},
+		{Min: 0.25, Max: 0.5},
+		{Min: 0.5, Max: 0.75},
+		{Min: 0.75, Max: 1},
+	}, buckets)
+	assert.InDelta(t, 0.95, scoreHistogramBuckets(20)[19].Min, 1e-9)
+}
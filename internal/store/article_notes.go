@@ -0,0 +1,65 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ArticleNote is a free-text note a user attached to an article. Notes are
+// for the reader only; they are never embedded or fed into relevance
+// profiles.
+type ArticleNote struct {
+	ArticleID string    `json:"article_id"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GetArticleNote returns the note on an article, or nil when it has none.
+func (s *Store) GetArticleNote(ctx context.Context, articleID string) (*ArticleNote, error) {
+	n := &ArticleNote{}
+	err := s.pool.QueryRow(ctx, `
+		SELECT article_id, note, created_at, updated_at
+		FROM article_notes WHERE article_id = $1`, articleID).
+		Scan(&n.ArticleID, &n.Note, &n.CreatedAt, &n.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting note for article %s: %w", articleID, err)
+	}
+	return n, nil
+}
+
+// SetArticleNote creates or replaces the note on an article. It returns nil
+// when the article does not exist.
+func (s *Store) SetArticleNote(ctx context.Context, articleID, note string) (*ArticleNote, error) {
+	n := &ArticleNote{}
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO article_notes (article_id, note)
+		SELECT a.id, $2 FROM articles a WHERE a.id = $1
+		ON CONFLICT (article_id)
+		DO UPDATE SET note = EXCLUDED.note, updated_at = NOW()
+		RETURNING article_id, note, created_at, updated_at`, articleID, note).
+		Scan(&n.ArticleID, &n.Note, &n.CreatedAt, &n.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("setting note for article %s: %w", articleID, err)
+	}
+	return n, nil
+}
+
+// DeleteArticleNote removes the note on an article, reporting whether there
+// was one.
+func (s *Store) DeleteArticleNote(ctx context.Context, articleID string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM article_notes WHERE article_id = $1`, articleID)
+	if err != nil {
+		return false, fmt.Errorf("deleting note for article %s: %w", articleID, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -13,6 +13,10 @@ import (
 
 // CreateArticle inserts a new article.
 func (s *Store) CreateArticle(ctx context.Context, a *models.Article) error {
+	if err := s.checkEmbeddingDim("creating article "+a.URL, a.Embedding); err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO articles (source_type, source_id, section_id, url, title, content, summary,
 			author, published_at, embedding, relevance_score, categories, status, metadata)
@@ -145,14 +149,139 @@ func (s *Store) UpdateArticleStatus(ctx context.Context, id, status string) erro
 	return err
 }
 
+// UpdateArticleStatusBatch sets status on every article in ids in a single
+// statement and returns how many rows changed. Unknown ids are ignored.
+func (s *Store) UpdateArticleStatusBatch(ctx context.Context, ids []string, status string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	var processedAt *time.Time
+	if status == models.StatusProcessed || status == models.StatusBriefed {
+		now := time.Now()
+		processedAt = &now
+	}
+
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE articles SET status = $1, processed_at = COALESCE($2, processed_at) WHERE id = ANY($3::uuid[])`,
+		status, processedAt, ids)
+	if err != nil {
+		return 0, fmt.Errorf("updating status for %d articles: %w", len(ids), err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RequeueArticlesNeedingEmbedding locks up to limit needs_embedding
+// articles, oldest first, hands each ID to publish and flips the published
+// ones back to pending in the same transaction. The rows stay locked while
+// publishing, so concurrent callers never requeue the same article, and a
+// failed publish or a crash before commit leaves the unpublished articles
+// parked rather than pending with no event to process them. It returns the
+// requeued IDs along with the publish error, if any.
+func (s *Store) RequeueArticlesNeedingEmbedding(ctx context.Context, limit int, publish func(id string) error) ([]string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting needs_embedding requeue: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT id FROM articles
+		WHERE status = $1
+		ORDER BY ingested_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`,
+		models.StatusNeedsEmbedding, limit)
+	if err != nil {
+		return nil, fmt.Errorf("claiming articles needing embedding: %w", err)
+	}
+	var claimed []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning claimed article: %w", err)
+		}
+		claimed = append(claimed, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claiming articles needing embedding: %w", err)
+	}
+
+	var published []string
+	var publishErr error
+	for _, id := range claimed {
+		if publishErr = publish(id); publishErr != nil {
+			break
+		}
+		published = append(published, id)
+	}
+	if len(published) == 0 {
+		return nil, publishErr
+	}
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE articles SET status = $1 WHERE id = ANY($2::uuid[])`,
+		models.StatusPending, published); err != nil {
+		return nil, fmt.Errorf("requeueing articles needing embedding: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("committing needs_embedding requeue: %w", err)
+	}
+	return published, publishErr
+}
+
 // UpdateArticleEmbedding sets the embedding vector for an article.
 func (s *Store) UpdateArticleEmbedding(ctx context.Context, id string, embedding []float32) error {
+	if err := s.checkEmbeddingDim("updating embedding of article "+id, embedding); err != nil {
+		return err
+	}
 	v := pgvector.NewVector(embedding)
 	_, err := s.pool.Exec(ctx,
 		`UPDATE articles SET embedding = $1 WHERE id = $2`, v, id)
 	return err
 }
 
+// ListArticlesMissingEmbedding returns up to limit articles without an
+// embedding, ordered by id and starting after afterID, for keyset-paginated
+// backfills. Only the fields embeddings.TextOptions.BuildText reads are
+// populated: ID, SourceType, Title, Content and Summary.
+func (s *Store) ListArticlesMissingEmbedding(ctx context.Context, afterID string, limit int) ([]*models.Article, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, source_type, title, content, summary
+		FROM articles
+		WHERE embedding IS NULL
+			AND ($1 = '' OR id > $1::uuid)
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing articles missing embedding: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []*models.Article
+	for rows.Next() {
+		a := &models.Article{}
+		if err := rows.Scan(&a.ID, &a.SourceType, &a.Title, &a.Content, &a.Summary); err != nil {
+			return nil, fmt.Errorf("scanning article missing embedding: %w", err)
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// CountArticlesMissingEmbedding returns how many articles have no embedding.
+func (s *Store) CountArticlesMissingEmbedding(ctx context.Context) (int, error) {
+	var n int
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM articles WHERE embedding IS NULL`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting articles missing embedding: %w", err)
+	}
+	return n, nil
+}
+
 // UpdateArticleSection assigns an article to a section with a relevance score.
 func (s *Store) UpdateArticleSection(ctx context.Context, id, sectionID string, score float64) error {
 	_, err := s.pool.Exec(ctx,
@@ -178,6 +307,30 @@ func (s *Store) UpdateArticleSectionAndStatus(ctx context.Context, id, sectionID
 	return err
 }
 
+// CountSectionPending returns how many pending articles a section holds.
+func (s *Store) CountSectionPending(ctx context.Context, sectionID string) (int, error) {
+	var n int
+	if err := s.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM articles WHERE section_id = $1 AND status = $2`,
+		sectionID, models.StatusPending,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("counting pending articles in section %s: %w", sectionID, err)
+	}
+	return n, nil
+}
+
+// MarkSectionProcessed moves every pending article in a section to
+// processed and returns how many changed.
+func (s *Store) MarkSectionProcessed(ctx context.Context, sectionID string) (int, error) {
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE articles SET status = $1, processed_at = NOW() WHERE section_id = $2 AND status = $3`,
+		models.StatusProcessed, sectionID, models.StatusPending)
+	if err != nil {
+		return 0, fmt.Errorf("marking section %s processed: %w", sectionID, err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
 // CountPendingAboveThreshold returns pending article count above threshold in one section.
 func (s *Store) CountPendingAboveThreshold(ctx context.Context, sectionID string, threshold float64, maxAge time.Duration) (int, error) {
 	query := `
@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/zyrak/flux/internal/models"
@@ -18,12 +20,14 @@ func (s *Store) CreateBriefing(ctx context.Context, b *models.Briefing) error {
 	).Scan(&b.ID, &b.GeneratedAt)
 }
 
-// GetLatestBriefing returns the most recently generated briefing.
-func (s *Store) GetLatestBriefing(ctx context.Context) (*models.Briefing, error) {
+// GetLatestBriefing returns the most recently generated briefing of the
+// given scope (a models.BriefingScope value), or of any scope when empty.
+func (s *Store) GetLatestBriefing(ctx context.Context, scope string) (*models.Briefing, error) {
+	where, args := briefingFilter(nil, nil, scope)
 	b := &models.Briefing{}
 	err := s.pool.QueryRow(ctx, `
 		SELECT id, generated_at, content, article_ids, metadata
-		FROM briefings ORDER BY generated_at DESC LIMIT 1`).
+		FROM briefings`+where+` ORDER BY generated_at DESC LIMIT 1`, args...).
 		Scan(&b.ID, &b.GeneratedAt, &b.Content, &b.ArticleIDs, &b.Metadata)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -34,14 +38,48 @@ func (s *Store) GetLatestBriefing(ctx context.Context) (*models.Briefing, error)
 	return b, nil
 }
 
+// ClusterIDsBriefedSince returns the cluster ids of articles included in
+// briefings generated at or after since.
+func (s *Store) ClusterIDsBriefedSince(ctx context.Context, since time.Time) (map[string]struct{}, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT a.metadata->>'cluster_id'
+		FROM briefings b
+		JOIN articles a ON a.id = ANY(b.article_ids)
+		WHERE b.generated_at >= $1
+		  AND COALESCE(a.metadata->>'cluster_id', '') <> ''`, since)
+	if err != nil {
+		return nil, fmt.Errorf("listing briefed clusters: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]struct{})
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning briefed cluster: %w", err)
+		}
+		ids[id] = struct{}{}
+	}
+	return ids, rows.Err()
+}
+
 // ListBriefings returns briefings ordered by date, with pagination.
 func (s *Store) ListBriefings(ctx context.Context, limit, offset int) ([]*models.Briefing, error) {
+	return s.ListBriefingsInRange(ctx, nil, nil, "", limit, offset)
+}
+
+// ListBriefingsInRange returns briefings generated within [from, to], newest
+// first, with pagination. A nil bound leaves that side of the range open; an
+// empty scope lists every scope.
+func (s *Store) ListBriefingsInRange(ctx context.Context, from, to *time.Time, scope string, limit, offset int) ([]*models.Briefing, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	rows, err := s.pool.Query(ctx, `
+	where, args := briefingFilter(from, to, scope)
+	args = append(args, limit, offset)
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
 		SELECT id, generated_at, content, article_ids, metadata
-		FROM briefings ORDER BY generated_at DESC LIMIT $1 OFFSET $2`, limit, offset)
+		FROM briefings%s ORDER BY generated_at DESC LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args)), args...)
 	if err != nil {
 		return nil, fmt.Errorf("listing briefings: %w", err)
 	}
@@ -58,6 +96,27 @@ func (s *Store) ListBriefings(ctx context.Context, limit, offset int) ([]*models
 	return briefings, rows.Err()
 }
 
+func briefingFilter(from, to *time.Time, scope string) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if from != nil {
+		args = append(args, *from)
+		conditions = append(conditions, fmt.Sprintf("generated_at >= $%d", len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		conditions = append(conditions, fmt.Sprintf("generated_at <= $%d", len(args)))
+	}
+	if scope != "" {
+		args = append(args, scope)
+		conditions = append(conditions, fmt.Sprintf("COALESCE(metadata->>'scope', '%s') = $%d", models.BriefingScopeFull, len(args)))
+	}
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
 // GetBriefingByID returns one briefing by id.
 func (s *Store) GetBriefingByID(ctx context.Context, id string) (*models.Briefing, error) {
 	b := &models.Briefing{}
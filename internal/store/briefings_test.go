@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zyrak/flux/internal/models"
+)
+
+func TestBriefingFilter(t *testing.T) {
+	from := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	where, args := briefingFilter(&from, &to, "")
+	assert.Equal(t, " WHERE generated_at >= $1 AND generated_at <= $2", where)
+	assert.Equal(t, []interface{}{from, to}, args)
+
+	where, args = briefingFilter(nil, &to, "")
+	assert.Equal(t, " WHERE generated_at <= $1", where)
+	assert.Equal(t, []interface{}{to}, args)
+
+	where, args = briefingFilter(nil, nil, "")
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+
+	where, args = briefingFilter(&from, nil, models.BriefingScopeSections)
+	assert.Equal(t, " WHERE generated_at >= $1 AND COALESCE(metadata->>'scope', 'full') = $2", where)
+	assert.Equal(t, []interface{}{from, models.BriefingScopeSections}, args)
+}
@@ -59,11 +59,84 @@ func (s *Store) FindSimilarArticlesLast48h(ctx context.Context, embedding []floa
 	return out, nil
 }
 
-// UpdateArticleMetadata replaces article metadata JSON.
+// UpdateArticleMetadata merges metadata's top-level keys into the article's
+// metadata JSON. Keys it leaves out are kept, so concurrent writers of
+// different keys (language, section candidates, clustering) do not undo each
+// other.
 func (s *Store) UpdateArticleMetadata(ctx context.Context, id string, metadata json.RawMessage) error {
-	_, err := s.pool.Exec(ctx, `UPDATE articles SET metadata = $1 WHERE id = $2`, metadata, id)
+	_, err := s.pool.Exec(ctx, `
+		UPDATE articles
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || $1::jsonb
+		WHERE id = $2`,
+		metadata, id,
+	)
 	if err != nil {
 		return fmt.Errorf("updating article metadata %s: %w", id, err)
 	}
 	return nil
 }
+
+// MarkURLSeen records a dedup URL hash and reports whether it was new.
+func (s *Store) MarkURLSeen(ctx context.Context, urlHash string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `
+		INSERT INTO seen_urls (url_hash)
+		VALUES ($1)
+		ON CONFLICT (url_hash) DO NOTHING`,
+		urlHash,
+	)
+	if err != nil {
+		return false, fmt.Errorf("marking url seen: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ExistingArticleSourceIDs returns which of the source items sourceIDs of
+// type sourceType are already stored, so a worker can skip refetching them
+// with one query per run.
+func (s *Store) ExistingArticleSourceIDs(ctx context.Context, sourceType string, sourceIDs []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(sourceIDs) == 0 {
+		return existing, nil
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT source_id
+		FROM articles
+		WHERE source_type = $1 AND source_id = ANY($2)`,
+		sourceType, sourceIDs,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("checking articles by source: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning article source id: %w", err)
+		}
+		existing[id] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating article source ids: %w", err)
+	}
+	return existing, nil
+}
+
+// ExistsRecentSimilarTitle reports whether an article with the same title key
+// (see dedup.TitleKey) was ingested after since.
+func (s *Store) ExistsRecentSimilarTitle(ctx context.Context, titleKey string, since time.Time) (bool, error) {
+	var exists bool
+	err := s.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM articles
+			WHERE metadata->>'title_key' = $1
+			  AND ingested_at > $2
+		)`,
+		titleKey, since,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("checking recent similar title: %w", err)
+	}
+	return exists, nil
+}
@@ -3,6 +3,8 @@ package store
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/pgvector/pgvector-go"
@@ -51,6 +53,21 @@ func (s *Store) DeleteFeedbackByID(ctx context.Context, id string) (*models.Feed
 	return f, nil
 }
 
+// DeleteSectionTrainingFeedback removes the like and dislike feedback on a
+// section's articles, the signal its profile is trained on; saves are kept.
+func (s *Store) DeleteSectionTrainingFeedback(ctx context.Context, sectionID string) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM feedback f
+		USING articles a
+		WHERE a.id = f.article_id
+		  AND a.section_id = $1
+		  AND f.action IN ('like', 'dislike')`, sectionID)
+	if err != nil {
+		return 0, fmt.Errorf("deleting feedback for section %s: %w", sectionID, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 // GetFeedbackByArticle returns all feedback for a specific article.
 func (s *Store) GetFeedbackByArticle(ctx context.Context, articleID string) ([]*models.Feedback, error) {
 	rows, err := s.pool.Query(ctx, `
@@ -96,6 +113,95 @@ func (s *Store) GetFeedbackBySection(ctx context.Context, sectionID string) ([]*
 	return feedbacks, rows.Err()
 }
 
+// FeedbackListQuery filters a feedback history listing; nil fields match
+// everything.
+type FeedbackListQuery struct {
+	ArticleID   *string
+	SectionName *string
+	Action      *string
+	From        *time.Time
+	To          *time.Time
+	// Limit caps the rows returned; 0 returns every matching row.
+	Limit int
+}
+
+// FeedbackRecord is a feedback row with the article it was given on.
+type FeedbackRecord struct {
+	models.Feedback
+	ArticleTitle string  `json:"article_title"`
+	ArticleURL   string  `json:"article_url"`
+	SectionName  *string `json:"section_name,omitempty"`
+}
+
+// feedbackListFilter builds the WHERE clause and its positional arguments
+// for a feedback listing.
+func feedbackListFilter(q FeedbackListQuery) (string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+	argIdx := 1
+
+	if q.ArticleID != nil {
+		conditions = append(conditions, fmt.Sprintf("f.article_id = $%d", argIdx))
+		args = append(args, *q.ArticleID)
+		argIdx++
+	}
+	if q.SectionName != nil {
+		conditions = append(conditions, fmt.Sprintf("sec.name = $%d", argIdx))
+		args = append(args, *q.SectionName)
+		argIdx++
+	}
+	if q.Action != nil {
+		conditions = append(conditions, fmt.Sprintf("f.action = $%d", argIdx))
+		args = append(args, *q.Action)
+		argIdx++
+	}
+	if q.From != nil {
+		conditions = append(conditions, fmt.Sprintf("f.created_at >= $%d", argIdx))
+		args = append(args, *q.From)
+		argIdx++
+	}
+	if q.To != nil {
+		conditions = append(conditions, fmt.Sprintf("f.created_at <= $%d", argIdx))
+		args = append(args, *q.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListFeedback returns feedback rows matching q, newest first.
+func (s *Store) ListFeedback(ctx context.Context, q FeedbackListQuery) ([]FeedbackRecord, error) {
+	where, args := feedbackListFilter(q)
+	query := `
+		SELECT f.id, f.article_id, f.action, f.created_at, a.title, a.url, sec.name
+		FROM feedback f
+		JOIN articles a ON a.id = f.article_id
+		LEFT JOIN sections sec ON sec.id = a.section_id` + where + `
+		ORDER BY f.created_at DESC, f.id DESC`
+	if q.Limit > 0 {
+		args = append(args, q.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing feedback: %w", err)
+	}
+	defer rows.Close()
+
+	records := []FeedbackRecord{}
+	for rows.Next() {
+		var rec FeedbackRecord
+		if err := rows.Scan(&rec.ID, &rec.ArticleID, &rec.Action, &rec.CreatedAt, &rec.ArticleTitle, &rec.ArticleURL, &rec.SectionName); err != nil {
+			return nil, fmt.Errorf("scanning feedback: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, rows.Err()
+}
+
 // CountFeedbackBySection returns like and dislike counts for a section.
 func (s *Store) CountFeedbackBySection(ctx context.Context, sectionID string) (likes, dislikes int, err error) {
 	err = s.pool.QueryRow(ctx, `
@@ -108,15 +214,24 @@ func (s *Store) CountFeedbackBySection(ctx context.Context, sectionID string) (l
 	return
 }
 
-// ListSectionEmbeddingsByFeedbackAction returns article embeddings for one section/action.
-func (s *Store) ListSectionEmbeddingsByFeedbackAction(ctx context.Context, sectionID, action string) ([][]float32, error) {
+// FeedbackEmbedding is a feedback article's embedding and when the feedback
+// was given.
+type FeedbackEmbedding struct {
+	Embedding []float32
+	CreatedAt time.Time
+}
+
+// ListSectionFeedbackEmbeddings returns article embeddings for one
+// section/action, each with its most recent feedback timestamp.
+func (s *Store) ListSectionFeedbackEmbeddings(ctx context.Context, sectionID, action string) ([]FeedbackEmbedding, error) {
 	rows, err := s.pool.Query(ctx, `
-		SELECT a.embedding
+		SELECT a.embedding, f.created_at
 		FROM articles a
 		JOIN (
-			SELECT DISTINCT article_id
+			SELECT article_id, MAX(created_at) AS created_at
 			FROM feedback
 			WHERE action = $2
+			GROUP BY article_id
 		) f ON f.article_id = a.id
 		WHERE a.section_id = $1
 			AND a.embedding IS NOT NULL`, sectionID, action)
@@ -125,13 +240,14 @@ func (s *Store) ListSectionEmbeddingsByFeedbackAction(ctx context.Context, secti
 	}
 	defer rows.Close()
 
-	out := make([][]float32, 0, 64)
+	out := make([]FeedbackEmbedding, 0, 64)
 	for rows.Next() {
 		var emb pgvector.Vector
-		if err := rows.Scan(&emb); err != nil {
+		var createdAt time.Time
+		if err := rows.Scan(&emb, &createdAt); err != nil {
 			return nil, fmt.Errorf("scanning feedback embedding: %w", err)
 		}
-		out = append(out, emb.Slice())
+		out = append(out, FeedbackEmbedding{Embedding: emb.Slice(), CreatedAt: createdAt})
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -0,0 +1,22 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedbackListFilter(t *testing.T) {
+	section := "tech"
+	action := "like"
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	where, args := feedbackListFilter(FeedbackListQuery{SectionName: &section, Action: &action, From: &from})
+	assert.Equal(t, " WHERE sec.name = $1 AND f.action = $2 AND f.created_at >= $3", where)
+	assert.Equal(t, []interface{}{"tech", "like", from}, args)
+
+	where, args = feedbackListFilter(FeedbackListQuery{})
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+}
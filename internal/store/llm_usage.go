@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LLMUsageRun is the provider-reported token usage of one briefing run.
+type LLMUsageRun struct {
+	ID               string
+	BriefingID       *string
+	Provider         string
+	Model            string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+	EstimatedTokens  int
+	CreatedAt        time.Time
+}
+
+// LLMUsageDay aggregates LLM usage for one UTC day.
+type LLMUsageDay struct {
+	Date             string `json:"date"`
+	Runs             int    `json:"runs"`
+	Calls            int    `json:"calls"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+	EstimatedTokens  int    `json:"estimated_tokens"`
+}
+
+// CreateLLMUsage records the token usage of a briefing run.
+func (s *Store) CreateLLMUsage(ctx context.Context, u *LLMUsageRun) error {
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO llm_usage (briefing_id, provider, model, calls, prompt_tokens, completion_tokens, estimated_tokens)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at`,
+		u.BriefingID, u.Provider, u.Model, u.Calls, u.PromptTokens, u.CompletionTokens, u.EstimatedTokens,
+	).Scan(&u.ID, &u.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("creating llm usage: %w", err)
+	}
+	return nil
+}
+
+// ListLLMUsageByDay returns daily LLM usage totals since the given time,
+// oldest first. Days without runs are omitted.
+func (s *Store) ListLLMUsageByDay(ctx context.Context, since time.Time) ([]LLMUsageDay, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT
+			TO_CHAR(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day,
+			COUNT(*),
+			COALESCE(SUM(calls), 0),
+			COALESCE(SUM(prompt_tokens), 0),
+			COALESCE(SUM(completion_tokens), 0),
+			COALESCE(SUM(estimated_tokens), 0)
+		FROM llm_usage
+		WHERE created_at >= $1
+		GROUP BY day
+		ORDER BY day`, since)
+	if err != nil {
+		return nil, fmt.Errorf("listing llm usage: %w", err)
+	}
+	defer rows.Close()
+
+	days := []LLMUsageDay{}
+	for rows.Next() {
+		var d LLMUsageDay
+		if err := rows.Scan(&d.Date, &d.Runs, &d.Calls, &d.PromptTokens, &d.CompletionTokens, &d.EstimatedTokens); err != nil {
+			return nil, fmt.Errorf("scanning llm usage: %w", err)
+		}
+		d.TotalTokens = d.PromptTokens + d.CompletionTokens
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
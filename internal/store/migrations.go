@@ -0,0 +1,345 @@
+package store
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+)
+
+// Migration file suffixes; a version is the file name without its suffix,
+// e.g. 000012_article_notes.
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+// MigrationStatus describes one migration version, found on disk, recorded
+// in schema_migrations, or both.
+type MigrationStatus struct {
+	Version   string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified reports an applied version whose up file no longer matches
+	// the checksum recorded when it was applied.
+	Modified bool
+	// HasUp and HasDown report whether the version's files exist on disk.
+	HasUp   bool
+	HasDown bool
+}
+
+// migrationFiles lists the versions in migrationsDir with an up file, sorted,
+// and the set of versions with a down file.
+func migrationFiles(migrationsDir string) ([]string, map[string]bool, error) {
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading migrations dir %s: %w", migrationsDir, err)
+	}
+
+	var ups []string
+	downs := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		switch name := e.Name(); {
+		case strings.HasSuffix(name, upSuffix):
+			ups = append(ups, strings.TrimSuffix(name, upSuffix))
+		case strings.HasSuffix(name, downSuffix):
+			downs[strings.TrimSuffix(name, downSuffix)] = true
+		}
+	}
+	sort.Strings(ups)
+	return ups, downs, nil
+}
+
+// execer is what ensureMigrationsTable needs from a connection or transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func ensureMigrationsTable(ctx context.Context, db execer) error {
+	_, err := db.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations table: %w", err)
+	}
+	// Versions applied before checksums were tracked have NULL here until
+	// the next RunMigrations records their current file.
+	if _, err := db.Exec(ctx, `ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum TEXT`); err != nil {
+		return fmt.Errorf("adding schema_migrations checksum: %w", err)
+	}
+	return nil
+}
+
+// upMigration is an up file read from disk.
+type upMigration struct {
+	version  string
+	sql      []byte
+	checksum string
+}
+
+// migrationChecksum is the hex SHA-256 of a migration file's contents.
+func migrationChecksum(sql []byte) string {
+	sum := sha256.Sum256(sql)
+	return hex.EncodeToString(sum[:])
+}
+
+// loadUpMigrations reads every up file in migrationsDir, in version order.
+func loadUpMigrations(migrationsDir string) ([]upMigration, error) {
+	versions, _, err := migrationFiles(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]upMigration, 0, len(versions))
+	for _, version := range versions {
+		fname := version + upSuffix
+		sql, err := os.ReadFile(filepath.Join(migrationsDir, fname))
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", fname, err)
+		}
+		out = append(out, upMigration{version: version, sql: sql, checksum: migrationChecksum(sql)})
+	}
+	return out, nil
+}
+
+// appliedMigration is a schema_migrations row.
+type appliedMigration struct {
+	appliedAt *time.Time
+	// checksum is nil for versions applied before checksums were recorded.
+	checksum *string
+}
+
+// querier is what appliedMigrations needs from a pool or connection.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// appliedMigrations reads schema_migrations keyed by version.
+func appliedMigrations(ctx context.Context, db querier) (map[string]appliedMigration, error) {
+	rows, err := db.Query(ctx, `SELECT version, applied_at, checksum FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("listing applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]appliedMigration)
+	for rows.Next() {
+		var version string
+		var m appliedMigration
+		if err := rows.Scan(&version, &m.appliedAt, &m.checksum); err != nil {
+			return nil, fmt.Errorf("scanning applied migration: %w", err)
+		}
+		applied[version] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("listing applied migrations: %w", err)
+	}
+	return applied, nil
+}
+
+// modifiedMigrations returns the applied versions whose file on disk no
+// longer matches the recorded checksum. Versions without a recorded checksum
+// are not reported.
+func modifiedMigrations(files []upMigration, applied map[string]appliedMigration) []string {
+	var modified []string
+	for _, m := range files {
+		if recorded := applied[m.version].checksum; recorded != nil && *recorded != m.checksum {
+			modified = append(modified, m.version)
+		}
+	}
+	return modified
+}
+
+// RunMigrations executes all *.up.sql files from the given directory in order.
+// Migrations run on one connection with the statement timeout lifted, since
+// building an index on a large table may legitimately take a while.
+//
+// Each applied version records the SHA-256 of its file. Applied files that
+// were edited since are logged as errors, or fail the run before anything is
+// applied when strictChecksums is set; editing an applied migration never
+// changes a database that already ran it.
+func (s *Store) RunMigrations(ctx context.Context, migrationsDir string, strictChecksums bool) error {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring migration connection: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "RESET statement_timeout"); err != nil {
+			// Never hand a connection without the timeout back to the pool.
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}()
+	if _, err := conn.Exec(ctx, "SET statement_timeout = 0"); err != nil {
+		return fmt.Errorf("lifting statement timeout for migrations: %w", err)
+	}
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+
+	files, err := loadUpMigrations(migrationsDir)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if modified := modifiedMigrations(files, applied); len(modified) > 0 {
+		if strictChecksums {
+			return fmt.Errorf("applied migrations changed on disk: %s", strings.Join(modified, ", "))
+		}
+		for _, version := range modified {
+			log.WithField("version", version).Error("Applied migration changed on disk; the edit will not be applied, add a new migration instead")
+		}
+	}
+
+	for _, m := range files {
+		if recorded, ok := applied[m.version]; ok {
+			if recorded.checksum == nil {
+				if _, err := conn.Exec(ctx,
+					"UPDATE schema_migrations SET checksum = $2 WHERE version = $1", m.version, m.checksum); err != nil {
+					return fmt.Errorf("recording checksum of migration %s: %w", m.version, err)
+				}
+			}
+			log.WithField("version", m.version).Debug("Migration already applied, skipping")
+			continue
+		}
+
+		fname := m.version + upSuffix
+		if _, err := conn.Exec(ctx, string(m.sql)); err != nil {
+			return fmt.Errorf("executing migration %s: %w", fname, err)
+		}
+
+		if _, err := conn.Exec(ctx,
+			"INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)", m.version, m.checksum); err != nil {
+			return fmt.Errorf("recording migration %s: %w", fname, err)
+		}
+
+		log.WithField("version", m.version).Info("Applied migration")
+	}
+
+	return nil
+}
+
+// RollbackLastMigration reverts the most recently applied migration: it runs
+// the version's *.down.sql and deletes its schema_migrations row in a single
+// transaction, so a failing down file leaves both schema and bookkeeping
+// untouched. It returns the version rolled back, or "" when none is applied.
+func (s *Store) RollbackLastMigration(ctx context.Context, migrationsDir string) (string, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("starting rollback: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, "SET LOCAL statement_timeout = 0"); err != nil {
+		return "", fmt.Errorf("lifting statement timeout for rollback: %w", err)
+	}
+	if err := ensureMigrationsTable(ctx, tx); err != nil {
+		return "", err
+	}
+	// Serialize with other rollbacks and with RunMigrations recording a new
+	// version, so the row picked below is still the latest at commit.
+	if _, err := tx.Exec(ctx, "LOCK TABLE schema_migrations IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return "", fmt.Errorf("locking schema_migrations: %w", err)
+	}
+
+	var version string
+	err = tx.QueryRow(ctx, `
+		SELECT version FROM schema_migrations
+		ORDER BY applied_at DESC NULLS LAST, version DESC
+		LIMIT 1`).Scan(&version)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("finding last applied migration: %w", err)
+	}
+
+	fname := version + downSuffix
+	sql, err := os.ReadFile(filepath.Join(migrationsDir, fname))
+	if err != nil {
+		return "", fmt.Errorf("reading down migration for %s: %w", version, err)
+	}
+	if _, err := tx.Exec(ctx, string(sql)); err != nil {
+		return "", fmt.Errorf("executing migration %s: %w", fname, err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", version); err != nil {
+		return "", fmt.Errorf("forgetting migration %s: %w", version, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("committing rollback of %s: %w", version, err)
+	}
+	log.WithField("version", version).Info("Rolled back migration")
+	return version, nil
+}
+
+// MigrationStatuses lists every migration on disk or in schema_migrations,
+// ordered by version.
+func (s *Store) MigrationStatuses(ctx context.Context, migrationsDir string) ([]MigrationStatus, error) {
+	files, err := loadUpMigrations(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	_, downs, err := migrationFiles(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(ctx, s.pool); err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, s.pool)
+	if err != nil {
+		return nil, err
+	}
+	return mergeMigrationStatus(files, downs, applied), nil
+}
+
+// mergeMigrationStatus combines the up files found on disk with the
+// versions recorded as applied.
+func mergeMigrationStatus(files []upMigration, downs map[string]bool, applied map[string]appliedMigration) []MigrationStatus {
+	byVersion := make(map[string]*MigrationStatus, len(files)+len(applied))
+	get := func(version string) *MigrationStatus {
+		st, ok := byVersion[version]
+		if !ok {
+			st = &MigrationStatus{Version: version, HasDown: downs[version]}
+			byVersion[version] = st
+		}
+		return st
+	}
+	for _, m := range files {
+		get(m.version).HasUp = true
+	}
+	for version, m := range applied {
+		st := get(version)
+		st.Applied = true
+		st.AppliedAt = m.appliedAt
+	}
+	for _, version := range modifiedMigrations(files, applied) {
+		get(version).Modified = true
+	}
+
+	out := make([]MigrationStatus, 0, len(byVersion))
+	for _, st := range byVersion {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrationFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000002_seed.up.sql",
+		"000001_init.up.sql",
+		"000001_init.down.sql",
+		"000003_notes.down.sql",
+		"README.md",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "old.up.sql"), 0o755))
+
+	ups, downs, err := migrationFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000001_init", "000002_seed"}, ups)
+	assert.Equal(t, map[string]bool{"000001_init": true, "000003_notes": true}, downs)
+
+	_, _, err = migrationFiles(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestModifiedMigrationsDetectsEditedFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, sql string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(sql), 0o644))
+	}
+	write("000001_init.up.sql", "CREATE TABLE a (id INT);")
+	write("000002_notes.up.sql", "CREATE TABLE b (id INT);")
+
+	files, err := loadUpMigrations(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	applied := map[string]appliedMigration{}
+	for _, m := range files {
+		applied[m.version] = appliedMigration{checksum: &m.checksum}
+	}
+	assert.Empty(t, modifiedMigrations(files, applied))
+
+	write("000002_notes.up.sql", "CREATE TABLE b (id BIGINT);")
+	write("000003_new.up.sql", "CREATE TABLE c (id INT);")
+	files, err = loadUpMigrations(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002_notes"}, modifiedMigrations(files, applied))
+
+	// Versions applied before checksums were recorded are not flagged.
+	applied["000002_notes"] = appliedMigration{}
+	assert.Empty(t, modifiedMigrations(files, applied))
+}
+
+func TestMergeMigrationStatus(t *testing.T) {
+	at := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+	stale := migrationChecksum([]byte("old"))
+	got := mergeMigrationStatus(
+		[]upMigration{
+			{version: "000001_init", checksum: migrationChecksum([]byte("init"))},
+			{version: "000003_notes", checksum: migrationChecksum([]byte("notes"))},
+			{version: "000004_edited", checksum: migrationChecksum([]byte("new"))},
+		},
+		map[string]bool{"000001_init": true},
+		map[string]appliedMigration{
+			"000001_init":   {appliedAt: &at},
+			"000002_gone":   {appliedAt: &at},
+			"000004_edited": {appliedAt: &at, checksum: &stale},
+		},
+	)
+	assert.Equal(t, []MigrationStatus{
+		{Version: "000001_init", Applied: true, AppliedAt: &at, HasUp: true, HasDown: true},
+		{Version: "000002_gone", Applied: true, AppliedAt: &at},
+		{Version: "000003_notes", HasUp: true},
+		{Version: "000004_edited", Applied: true, AppliedAt: &at, Modified: true, HasUp: true},
+	}, got)
+}
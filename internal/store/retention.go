@@ -0,0 +1,103 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// retentionBatchSize bounds how many articles a single delete transaction
+// touches so row locks stay short.
+const retentionBatchSize = 500
+
+// DeleteArticlesOlderThan removes articles ingested before cutoff whose status
+// is one of statuses. Articles that appear in a briefing, carry feedback or
+// have a note are kept. Deletion runs in batches, one transaction each, and
+// returns the number of rows removed per status.
+//
+// Retention deliberately leaves the other growing tables alone: briefings and
+// llm_usage (one row per briefing run) are the history it keeps,
+// section_profiles holds one row per section, and source_fetch_log is already
+// trimmed to the latest runs of each source on every write.
+func (s *Store) DeleteArticlesOlderThan(ctx context.Context, cutoff time.Time, statuses []string) (map[string]int64, error) {
+	removed := make(map[string]int64)
+	if len(statuses) == 0 {
+		return removed, nil
+	}
+
+	for {
+		n, err := s.deleteArticleBatch(ctx, cutoff, statuses, removed)
+		if err != nil {
+			return removed, err
+		}
+		if n < retentionBatchSize {
+			return removed, nil
+		}
+	}
+}
+
+func (s *Store) deleteArticleBatch(ctx context.Context, cutoff time.Time, statuses []string, removed map[string]int64) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("starting retention batch: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, `
+		WITH doomed AS (
+			SELECT a.id
+			FROM articles a
+			WHERE a.ingested_at < $1
+			  AND a.status = ANY($2)
+			  AND NOT EXISTS (SELECT 1 FROM feedback f WHERE f.article_id = a.id)
+			  AND NOT EXISTS (SELECT 1 FROM briefings b WHERE a.id = ANY(b.article_ids))
+			  AND NOT EXISTS (SELECT 1 FROM article_notes n WHERE n.article_id = a.id)
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		DELETE FROM articles a
+		USING doomed d
+		WHERE a.id = d.id
+		RETURNING a.status`,
+		cutoff, statuses, retentionBatchSize,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("deleting old articles: %w", err)
+	}
+
+	batch := make(map[string]int64)
+	n := 0
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning deleted article: %w", err)
+		}
+		batch[status]++
+		n++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("deleting old articles: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("committing retention batch: %w", err)
+	}
+	for status, count := range batch {
+		removed[status] += count
+	}
+	return n, nil
+}
+
+// DeleteSeenURLsOlderThan removes dedup URL hashes first seen before cutoff,
+// so seen_urls is bounded by the same window as articles. A URL pruned here
+// that is still in a feed is ingested again, but the unique source id keeps
+// an article that was kept from being duplicated.
+func (s *Store) DeleteSeenURLsOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM seen_urls WHERE first_seen_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("deleting old seen urls: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -38,6 +38,12 @@ func (s *Store) GetSectionProfile(ctx context.Context, sectionID string) (*model
 
 // UpsertSectionProfile creates or updates the relevance profile for a section.
 func (s *Store) UpsertSectionProfile(ctx context.Context, sp *models.SectionProfile) error {
+	if err := s.checkEmbeddingDim("positive profile of section "+sp.SectionID, sp.PositiveEmbedding); err != nil {
+		return err
+	}
+	if err := s.checkEmbeddingDim("negative profile of section "+sp.SectionID, sp.NegativeEmbedding); err != nil {
+		return err
+	}
 	var posVec, negVec *pgvector.Vector
 	if len(sp.PositiveEmbedding) > 0 {
 		v := pgvector.NewVector(sp.PositiveEmbedding)
@@ -61,3 +67,13 @@ func (s *Store) UpsertSectionProfile(ctx context.Context, sp *models.SectionProf
 		sp.SectionID, posVec, negVec, sp.LikeCount, sp.DislikeCount)
 	return err
 }
+
+// DeleteSectionProfile removes a section's learned profile so relevance falls
+// back to its seed keywords. It reports whether a profile existed.
+func (s *Store) DeleteSectionProfile(ctx context.Context, sectionID string) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM section_profiles WHERE section_id = $1`, sectionID)
+	if err != nil {
+		return false, fmt.Errorf("deleting section profile %s: %w", sectionID, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -102,7 +102,9 @@ func (s *Store) ToggleSection(ctx context.Context, id string, enabled bool) erro
 	return err
 }
 
-// UpdateSectionThreshold stores the current relevance threshold in section config.
+// UpdateSectionThreshold stores an auto-adjusted relevance threshold in
+// section config. A threshold pinned with threshold_locked is left alone, so a
+// value set through the API between the read and this write survives.
 func (s *Store) UpdateSectionThreshold(ctx context.Context, sectionID string, threshold float64) error {
 	_, err := s.pool.Exec(ctx, `
 		UPDATE sections
@@ -112,7 +114,8 @@ func (s *Store) UpdateSectionThreshold(ctx context.Context, sectionID string, th
 			to_jsonb($1::float8),
 			true
 		)
-		WHERE id = $2`,
+		WHERE id = $2
+		  AND COALESCE((config->>'threshold_locked')::boolean, false) = false`,
 		threshold, sectionID,
 	)
 	if err != nil {
@@ -121,6 +124,39 @@ func (s *Store) UpdateSectionThreshold(ctx context.Context, sectionID string, th
 	return nil
 }
 
+// SetSectionThreshold stores config.relevance_threshold and
+// config.threshold_locked in one statement and returns the updated section,
+// or nil when it does not exist. A locked threshold is never auto-adjusted by
+// the processor.
+func (s *Store) SetSectionThreshold(ctx context.Context, sectionID string, threshold float64, locked bool) (*models.Section, error) {
+	sec := &models.Section{}
+	err := s.pool.QueryRow(ctx, `
+		UPDATE sections
+		SET config = jsonb_set(
+			jsonb_set(
+				COALESCE(config, '{}'::jsonb),
+				'{relevance_threshold}',
+				to_jsonb($1::float8),
+				true
+			),
+			'{threshold_locked}',
+			to_jsonb($2::boolean),
+			true
+		)
+		WHERE id = $3
+		RETURNING id, name, display_name, enabled, sort_order, max_briefing_articles, seed_keywords, config`,
+		threshold, locked, sectionID,
+	).Scan(&sec.ID, &sec.Name, &sec.DisplayName, &sec.Enabled,
+		&sec.SortOrder, &sec.MaxBriefingArticles, &sec.SeedKeywords, &sec.Config)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("setting section threshold for %s: %w", sectionID, err)
+	}
+	return sec, nil
+}
+
 // ReorderSections sets section sort_order based on the given ordered section IDs.
 func (s *Store) ReorderSections(ctx context.Context, sectionIDs []string) error {
 	tx, err := s.pool.Begin(ctx)
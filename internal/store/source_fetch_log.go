@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// sourceFetchLogKeep is how many runs are kept per source; older rows are
+// pruned whenever a new run is recorded.
+const sourceFetchLogKeep = 500
+
+// SourceFetchRun is one recorded fetch of a source.
+type SourceFetchRun struct {
+	ID          string    `json:"id"`
+	SourceID    string    `json:"source_id"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	ItemsSeen   int       `json:"items_seen"`
+	NewArticles int       `json:"new_articles"`
+	Error       *string   `json:"error,omitempty"`
+}
+
+// RecordSourceFetch appends a run to the source's fetch log and prunes the
+// log down to the most recent sourceFetchLogKeep runs.
+func (s *Store) RecordSourceFetch(ctx context.Context, sourceID string, itemsSeen, newArticles int, fetchErr error) error {
+	var errText *string
+	if fetchErr != nil {
+		msg := fetchErr.Error()
+		errText = &msg
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO source_fetch_log (source_id, items_seen, new_articles, error)
+		VALUES ($1, $2, $3, $4)`,
+		sourceID, itemsSeen, newArticles, errText,
+	); err != nil {
+		return fmt.Errorf("recording source fetch: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM source_fetch_log
+		WHERE id IN (
+			SELECT id FROM source_fetch_log
+			WHERE source_id = $1
+			ORDER BY fetched_at DESC
+			OFFSET $2
+		)`,
+		sourceID, sourceFetchLogKeep,
+	); err != nil {
+		return fmt.Errorf("pruning source fetch log: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+	return nil
+}
+
+// ListSourceFetchLog returns the most recent fetch runs of a source, newest
+// first.
+func (s *Store) ListSourceFetchLog(ctx context.Context, sourceID string, limit int) ([]SourceFetchRun, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, source_id, fetched_at, items_seen, new_articles, error
+		FROM source_fetch_log
+		WHERE source_id = $1
+		ORDER BY fetched_at DESC
+		LIMIT $2`, sourceID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("listing source fetch log: %w", err)
+	}
+	defer rows.Close()
+
+	runs := []SourceFetchRun{}
+	for rows.Next() {
+		var run SourceFetchRun
+		if err := rows.Scan(&run.ID, &run.SourceID, &run.FetchedAt, &run.ItemsSeen, &run.NewArticles, &run.Error); err != nil {
+			return nil, fmt.Errorf("scanning source fetch run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -93,6 +94,52 @@ func (s *Store) UpdateSource(ctx context.Context, src *models.Source) error {
 	return err
 }
 
+// SourceSelector picks the sources a bulk change applies to. Set fields are
+// ANDed; an empty selector matches nothing rather than every source.
+type SourceSelector struct {
+	SourceType *string
+	IDs        []string
+}
+
+// sourceSelectorFilter builds the WHERE conditions for sel, numbering its
+// arguments from argIdx.
+func sourceSelectorFilter(sel SourceSelector, argIdx int) (string, []interface{}, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if sel.SourceType != nil {
+		conditions = append(conditions, fmt.Sprintf("source_type = $%d", argIdx))
+		args = append(args, *sel.SourceType)
+		argIdx++
+	}
+	if len(sel.IDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("id = ANY($%d::uuid[])", argIdx))
+		args = append(args, sel.IDs)
+	}
+	if len(conditions) == 0 {
+		return "", nil, fmt.Errorf("source selector needs a source type or ids")
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// SetSourcesEnabled enables or disables every source sel matches in one
+// statement and returns how many changed; sources already in that state are
+// not counted.
+func (s *Store) SetSourcesEnabled(ctx context.Context, sel SourceSelector, enabled bool) (int64, error) {
+	where, args, err := sourceSelectorFilter(sel, 2)
+	if err != nil {
+		return 0, err
+	}
+	tag, err := s.pool.Exec(ctx,
+		`UPDATE sources SET enabled = $1 WHERE enabled IS DISTINCT FROM $1 AND `+where,
+		append([]interface{}{enabled}, args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("setting sources enabled: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
 // GetSourceByID returns a source by ID.
 func (s *Store) GetSourceByID(ctx context.Context, id string) (*models.Source, error) {
 	src := &models.Source{}
@@ -200,3 +247,44 @@ func (s *Store) ListSourcesByTypeWithSectionIDs(ctx context.Context, sourceType
 
 	return out, rows.Err()
 }
+
+// SourceHTTPCache holds the conditional GET validators from a source's last fetch.
+type SourceHTTPCache struct {
+	ETag         string
+	LastModified string
+}
+
+// GetSourceHTTPCache returns the stored ETag/Last-Modified validators for a source.
+func (s *Store) GetSourceHTTPCache(ctx context.Context, id string) (*SourceHTTPCache, error) {
+	var etag, lastModified *string
+	err := s.pool.QueryRow(ctx, `
+		SELECT http_etag, http_last_modified FROM sources WHERE id = $1`, id).
+		Scan(&etag, &lastModified)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting http cache for source %s: %w", id, err)
+	}
+
+	cache := &SourceHTTPCache{}
+	if etag != nil {
+		cache.ETag = *etag
+	}
+	if lastModified != nil {
+		cache.LastModified = *lastModified
+	}
+	return cache, nil
+}
+
+// UpdateSourceHTTPCache stores the ETag/Last-Modified validators for a source.
+// Empty values clear the corresponding column.
+func (s *Store) UpdateSourceHTTPCache(ctx context.Context, id string, cache SourceHTTPCache) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE sources SET http_etag = NULLIF($1, ''), http_last_modified = NULLIF($2, '') WHERE id = $3`,
+		cache.ETag, cache.LastModified, id)
+	if err != nil {
+		return fmt.Errorf("updating http cache for source %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceSelectorFilter(t *testing.T) {
+	reddit := "reddit"
+	where, args, err := sourceSelectorFilter(SourceSelector{SourceType: &reddit, IDs: []string{"s1", "s2"}}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "source_type = $2 AND id = ANY($3::uuid[])", where)
+	assert.Equal(t, []interface{}{"reddit", []string{"s1", "s2"}}, args)
+
+	where, args, err = sourceSelectorFilter(SourceSelector{IDs: []string{"s1"}}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "id = ANY($1::uuid[])", where)
+	assert.Equal(t, []interface{}{[]string{"s1"}}, args)
+
+	_, _, err = sourceSelectorFilter(SourceSelector{}, 1)
+	assert.Error(t, err)
+}
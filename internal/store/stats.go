@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// IngestionCounts holds article ingestion counts over recent windows.
+type IngestionCounts struct {
+	Last24h int `json:"last_24h"`
+	Last7d  int `json:"last_7d"`
+}
+
+// SectionLikes is a section ranked by the likes its articles received.
+type SectionLikes struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Likes       int    `json:"likes"`
+}
+
+// DashboardStats aggregates the counters shown on the dashboard.
+type DashboardStats struct {
+	ArticlesByStatus     map[string]int             `json:"articles_by_status"`
+	TotalArticles        int                        `json:"total_articles"`
+	IngestedBySourceType map[string]IngestionCounts `json:"ingested_by_source_type"`
+	EnabledSources       int                        `json:"enabled_sources"`
+	EnabledSections      int                        `json:"enabled_sections"`
+	BriefingsThisWeek    int                        `json:"briefings_this_week"`
+	TopLikedSections     []SectionLikes             `json:"top_liked_sections"`
+}
+
+// GetDashboardStats computes the dashboard aggregate with a handful of grouped queries.
+func (s *Store) GetDashboardStats(ctx context.Context) (*DashboardStats, error) {
+	stats := &DashboardStats{
+		ArticlesByStatus:     map[string]int{},
+		IngestedBySourceType: map[string]IngestionCounts{},
+		TopLikedSections:     []SectionLikes{},
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT status, COUNT(*) FROM articles GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("counting articles by status: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning article status count: %w", err)
+		}
+		stats.ArticlesByStatus[status] = count
+		stats.TotalArticles += count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("counting articles by status: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT
+			source_type,
+			COUNT(*) FILTER (WHERE ingested_at >= NOW() - INTERVAL '24 hours'),
+			COUNT(*)
+		FROM articles
+		WHERE ingested_at >= NOW() - INTERVAL '7 days'
+		GROUP BY source_type`)
+	if err != nil {
+		return nil, fmt.Errorf("counting ingestion by source type: %w", err)
+	}
+	for rows.Next() {
+		var sourceType string
+		var counts IngestionCounts
+		if err := rows.Scan(&sourceType, &counts.Last24h, &counts.Last7d); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scanning ingestion count: %w", err)
+		}
+		stats.IngestedBySourceType[sourceType] = counts
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("counting ingestion by source type: %w", err)
+	}
+
+	err = s.pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM sources WHERE enabled = TRUE),
+			(SELECT COUNT(*) FROM sections WHERE enabled = TRUE),
+			(SELECT COUNT(*) FROM briefings WHERE generated_at >= date_trunc('week', NOW()))`).
+		Scan(&stats.EnabledSources, &stats.EnabledSections, &stats.BriefingsThisWeek)
+	if err != nil {
+		return nil, fmt.Errorf("counting sources, sections and briefings: %w", err)
+	}
+
+	rows, err = s.pool.Query(ctx, `
+		SELECT sec.name, sec.display_name, COUNT(*) AS likes
+		FROM feedback f
+		JOIN articles a ON a.id = f.article_id
+		JOIN sections sec ON sec.id = a.section_id
+		WHERE f.action = 'like'
+		GROUP BY sec.id, sec.name, sec.display_name
+		ORDER BY likes DESC, sec.name
+		LIMIT 5`)
+	if err != nil {
+		return nil, fmt.Errorf("ranking liked sections: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sl SectionLikes
+		if err := rows.Scan(&sl.Name, &sl.DisplayName, &sl.Likes); err != nil {
+			return nil, fmt.Errorf("scanning liked section: %w", err)
+		}
+		stats.TopLikedSections = append(stats.TopLikedSections, sl)
+	}
+	return stats, rows.Err()
+}
@@ -2,31 +2,65 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"sort"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	log "github.com/sirupsen/logrus"
+	"github.com/zyrak/flux/internal/config"
 )
 
 // Store provides access to the PostgreSQL database.
 type Store struct {
 	pool *pgxpool.Pool
+	// embeddingDim is the vector length writes must have; 0 skips the check.
+	embeddingDim int
 }
 
+// Pool defaults used when Options leaves a size unset.
+const (
+	defaultMaxConns = 20
+	defaultMinConns = 2
+)
+
+// Options tunes the Store New opens. Zero values keep the pool defaults; a
+// zero StatementTimeout leaves queries unbounded and a zero EmbeddingDim
+// skips the dimension check.
+type Options struct {
+	MaxConns int
+	MinConns int
+	// StatementTimeout makes PostgreSQL cancel any statement running longer,
+	// so a runaway query cannot hold a connection indefinitely.
+	StatementTimeout time.Duration
+	// EmbeddingDim is the length every stored embedding must have, matching
+	// the vector(N) columns.
+	EmbeddingDim int
+}
+
+// OptionsFromConfig returns the connection pool settings every binary opens
+// the database with.
+func OptionsFromConfig(cfg *config.Config) Options {
+	return Options{
+		MaxConns:         cfg.DBMaxConns,
+		MinConns:         cfg.DBMinConns,
+		StatementTimeout: cfg.DBStatementTimeout,
+		EmbeddingDim:     cfg.EmbeddingsStoreDimension,
+	}
+}
+
+// ErrEmbeddingDimension is returned when a vector about to be written does
+// not have the configured dimension.
+var ErrEmbeddingDimension = errors.New("embedding dimension mismatch")
+
 // New creates a new Store with a connection pool.
-func New(ctx context.Context, connString string) (*Store, error) {
-	config, err := pgxpool.ParseConfig(connString)
+func New(ctx context.Context, connString string, opts Options) (*Store, error) {
+	config, err := poolConfig(connString, opts)
 	if err != nil {
-		return nil, fmt.Errorf("parsing connection string: %w", err)
+		return nil, err
 	}
 
-	config.MaxConns = 20
-	config.MinConns = 2
-
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("creating connection pool: %w", err)
@@ -38,67 +72,43 @@ func New(ctx context.Context, connString string) (*Store, error) {
 	}
 
 	log.Info("Connected to PostgreSQL")
-	return &Store{pool: pool}, nil
+	return &Store{pool: pool, embeddingDim: opts.EmbeddingDim}, nil
 }
 
-// RunMigrations executes all *.up.sql files from the given directory in order.
-func (s *Store) RunMigrations(ctx context.Context, migrationsDir string) error {
-	// Create migrations tracking table
-	_, err := s.pool.Exec(ctx, `
-		CREATE TABLE IF NOT EXISTS schema_migrations (
-			version TEXT PRIMARY KEY,
-			applied_at TIMESTAMPTZ DEFAULT NOW()
-		)`)
-	if err != nil {
-		return fmt.Errorf("creating schema_migrations table: %w", err)
+// checkEmbeddingDim rejects a non-empty vector whose length is not the
+// configured dimension, before pgvector fails with a less helpful error.
+func (s *Store) checkEmbeddingDim(what string, v []float32) error {
+	if s.embeddingDim <= 0 || len(v) == 0 || len(v) == s.embeddingDim {
+		return nil
 	}
+	return fmt.Errorf("%s: %w: got %d, want %d (EMBEDDINGS_STORE_DIMENSION)", what, ErrEmbeddingDimension, len(v), s.embeddingDim)
+}
 
-	entries, err := os.ReadDir(migrationsDir)
+// poolConfig parses connString and applies opts to it.
+func poolConfig(connString string, opts Options) (*pgxpool.Config, error) {
+	config, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		return fmt.Errorf("reading migrations dir %s: %w", migrationsDir, err)
+		return nil, fmt.Errorf("parsing connection string: %w", err)
 	}
 
-	var upFiles []string
-	for _, e := range entries {
-		if !e.IsDir() && strings.HasSuffix(e.Name(), ".up.sql") {
-			upFiles = append(upFiles, e.Name())
-		}
+	maxConns, minConns := opts.MaxConns, opts.MinConns
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
 	}
-	sort.Strings(upFiles)
-
-	for _, fname := range upFiles {
-		version := strings.TrimSuffix(fname, ".up.sql")
-
-		// Check if already applied
-		var exists bool
-		err := s.pool.QueryRow(ctx,
-			"SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&exists)
-		if err != nil {
-			return fmt.Errorf("checking migration %s: %w", version, err)
-		}
-		if exists {
-			log.WithField("version", version).Debug("Migration already applied, skipping")
-			continue
-		}
-
-		sql, err := os.ReadFile(filepath.Join(migrationsDir, fname))
-		if err != nil {
-			return fmt.Errorf("reading migration %s: %w", fname, err)
-		}
-
-		if _, err := s.pool.Exec(ctx, string(sql)); err != nil {
-			return fmt.Errorf("executing migration %s: %w", fname, err)
-		}
-
-		if _, err := s.pool.Exec(ctx,
-			"INSERT INTO schema_migrations (version) VALUES ($1)", version); err != nil {
-			return fmt.Errorf("recording migration %s: %w", fname, err)
-		}
-
-		log.WithField("version", version).Info("Applied migration")
+	if minConns <= 0 {
+		minConns = min(defaultMinConns, maxConns)
 	}
+	if minConns > maxConns {
+		return nil, fmt.Errorf("min connections (%d) exceed max connections (%d)", minConns, maxConns)
+	}
+	config.MaxConns = int32(maxConns)
+	config.MinConns = int32(minConns)
 
-	return nil
+	if opts.StatementTimeout > 0 {
+		ms := max(opts.StatementTimeout.Milliseconds(), 1) // 0 would disable it
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(ms, 10)
+	}
+	return config, nil
 }
 
 // Close shuts down the connection pool.
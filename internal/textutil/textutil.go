@@ -0,0 +1,19 @@
+// Package textutil holds small string helpers shared by the prompt,
+// embedding and briefing code.
+package textutil
+
+// TruncateRunes shortens s to at most maxRunes runes without splitting a
+// multibyte UTF-8 sequence.
+func TruncateRunes(s string, maxRunes int) string {
+	if maxRunes <= 0 {
+		return ""
+	}
+	count := 0
+	for i := range s {
+		if count == maxRunes {
+			return s[:i]
+		}
+		count++
+	}
+	return s
+}
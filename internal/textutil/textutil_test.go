@@ -0,0 +1,13 @@
+package textutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateRunes(t *testing.T) {
+	assert.Equal(t, "añ", TruncateRunes("año", 2))
+	assert.Equal(t, "año", TruncateRunes("año", 10))
+	assert.Equal(t, "", TruncateRunes("año", 0))
+}
@@ -0,0 +1,113 @@
+// Package tracing wires OpenTelemetry through the ingestion pipeline. Spans
+// are exported over OTLP/HTTP when an endpoint is configured, and the W3C
+// trace context travels inside NATS event payloads so the processor continues
+// the trace a worker started.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/zyrak/flux"
+
+// Setup installs the global tracer provider for service, exporting to the
+// OTLP/HTTP endpoint (e.g. http://otel-collector:4318). With an empty
+// endpoint spans are dropped and the returned shutdown does nothing. The
+// sampler follows OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG.
+func Setup(ctx context.Context, service, endpoint string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	if endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithAttributes(attribute.String("service.name", service)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("building trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the pipeline tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartArticle starts the root span of one article's trace. The span is
+// linked to the ingestion run in ctx rather than parented by it, so each
+// article gets its own trace from worker to processor.
+func StartArticle(ctx context.Context, name, sourceType, articleID string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name,
+		trace.WithNewRoot(),
+		trace.WithLinks(trace.LinkFromContext(ctx)),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("flux.source_type", sourceType),
+			attribute.String("flux.article_id", articleID),
+		),
+	)
+}
+
+// Inject returns the trace context of ctx for an event payload, or nil when
+// ctx carries no sampled span.
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns ctx carrying the remote span context from an event
+// payload. A nil or malformed carrier leaves ctx unchanged.
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Flush runs the shutdown returned by Setup with a short deadline, so
+// buffered spans are exported before a process exits. Failures are logged.
+func Flush(shutdown func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(ctx); err != nil {
+		log.WithError(err).Warn("Failed to flush traces")
+	}
+}
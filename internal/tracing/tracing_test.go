@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContextRoundTrip(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(trace.NewNoopTracerProvider()) })
+
+	runCtx, run := Tracer().Start(context.Background(), "worker-rss.run")
+	articleCtx, article := StartArticle(runCtx, "worker-rss.publish", "rss", "a1")
+	carrier := Inject(articleCtx)
+	require.Contains(t, carrier, "traceparent")
+	End(article, nil)
+	run.End()
+
+	// The processor side continues the article's trace, not the run's.
+	processCtx := Extract(context.Background(), carrier)
+	_, process := Tracer().Start(processCtx, "processor.handle_article")
+	End(process, errors.New("embeddings unavailable"))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	runSpan, articleSpan, processSpan := spans[1], spans[0], spans[2]
+	assert.NotEqual(t, runSpan.SpanContext().TraceID(), articleSpan.SpanContext().TraceID())
+	require.Len(t, articleSpan.Links(), 1)
+	assert.Equal(t, runSpan.SpanContext().SpanID(), articleSpan.Links()[0].SpanContext.SpanID())
+	assert.Equal(t, articleSpan.SpanContext().TraceID(), processSpan.SpanContext().TraceID())
+	assert.Equal(t, articleSpan.SpanContext().SpanID(), processSpan.Parent().SpanID())
+	assert.Equal(t, codes.Error, processSpan.Status().Code)
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	assert.Nil(t, Inject(context.Background()))
+
+	ctx := context.Background()
+	assert.Equal(t, ctx, Extract(ctx, nil))
+}
+
+func TestSetupWithoutEndpointIsNoop(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "test", "")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
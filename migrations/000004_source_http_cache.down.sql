@@ -0,0 +1,2 @@
+ALTER TABLE sources DROP COLUMN IF EXISTS http_last_modified;
+ALTER TABLE sources DROP COLUMN IF EXISTS http_etag;
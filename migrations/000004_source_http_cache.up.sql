@@ -0,0 +1,3 @@
+-- Conditional GET validators remembered per source
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS http_etag TEXT;
+ALTER TABLE sources ADD COLUMN IF NOT EXISTS http_last_modified TEXT;
@@ -0,0 +1,2 @@
+-- Support per-source-type ingestion counts for /api/stats
+CREATE INDEX IF NOT EXISTS idx_articles_source_type_ingested ON articles(source_type, ingested_at DESC);
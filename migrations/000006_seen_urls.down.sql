@@ -0,0 +1 @@
+DROP TABLE IF EXISTS seen_urls;
@@ -0,0 +1,5 @@
+-- Durable dedup entries; Redis remains the fast path
+CREATE TABLE IF NOT EXISTS seen_urls (
+    url_hash TEXT PRIMARY KEY,
+    first_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
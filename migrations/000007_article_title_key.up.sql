@@ -0,0 +1,2 @@
+-- Lookup for opt-in title near-duplicate detection
+CREATE INDEX IF NOT EXISTS idx_articles_title_key ON articles ((metadata->>'title_key'), ingested_at DESC);
@@ -0,0 +1,12 @@
+-- One row per source fetch run, so a feed that has been quietly failing for
+-- days is visible. Workers keep only the most recent runs per source.
+CREATE TABLE IF NOT EXISTS source_fetch_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_id UUID NOT NULL REFERENCES sources(id) ON DELETE CASCADE,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    items_seen INT NOT NULL DEFAULT 0,
+    new_articles INT NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_source_fetch_log_source_time ON source_fetch_log(source_id, fetched_at DESC);
@@ -0,0 +1,2 @@
+-- Backs the array-overlap category filter on the article list.
+CREATE INDEX IF NOT EXISTS idx_articles_categories ON articles USING GIN (categories);
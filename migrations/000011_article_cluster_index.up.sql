@@ -0,0 +1,2 @@
+-- Backs the member lookup of the clustered article listing.
+CREATE INDEX IF NOT EXISTS idx_articles_cluster_id ON articles ((metadata->>'cluster_id'));
@@ -0,0 +1,8 @@
+-- Free-text notes users attach to articles, e.g. why something was saved.
+-- Kept apart from feedback so they never feed relevance profiles.
+CREATE TABLE IF NOT EXISTS article_notes (
+    article_id UUID PRIMARY KEY REFERENCES articles(id) ON DELETE CASCADE,
+    note TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -49,8 +49,21 @@ export async function apiFetch(path: string, init: RequestInit = {}): Promise<Re
 export async function apiJSON<T>(path: string, init: RequestInit = {}): Promise<T> {
 	const response = await apiFetch(path, init);
 	if (!response.ok) {
-		const errorText = (await response.text()) || `HTTP ${response.status}`;
-		throw new Error(errorText);
+		throw new Error(await errorMessage(response));
 	}
 	return (await response.json()) as T;
 }
+
+// The API answers errors with {"error":{"code","message"}}; fall back to the raw body.
+async function errorMessage(response: Response): Promise<string> {
+	const text = await response.text();
+	try {
+		const body = JSON.parse(text) as { error?: { message?: string } };
+		if (body.error?.message) {
+			return body.error.message;
+		}
+	} catch {
+		// not JSON
+	}
+	return text || `HTTP ${response.status}`;
+}